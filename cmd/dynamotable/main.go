@@ -0,0 +1,43 @@
+// DynamoDBのドキュメント用テーブルとインデックスを作成するコマンドを提供するパッケージ
+// テーブルが既に存在する場合は不足しているインデックスだけを追加し、以前の形式の作成日時を書き換えるため、何度実行してもよい
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/OICjangirrahul/students/internal/adapters/storage"
+	"github.com/OICjangirrahul/students/internal/config"
+)
+
+func main() {
+	// 設定をロード
+	cfg, err := config.LoadConfig("config/local.yaml")
+	if err != nil {
+		slog.Error("failed to load configuration", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// DynamoDBクライアントを初期化
+	awsCfg, err := config.LoadAWSConfig(cfg)
+	if err != nil {
+		slog.Error("failed to load AWS configuration", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	documents := storage.NewDynamoDBStorage(config.NewDynamoDBClient(cfg, awsCfg), cfg.AWS.DynamoTable)
+
+	// テーブルとインデックスを作成し、利用可能になるまで待つ
+	if err := documents.EnsureTable(context.Background()); err != nil {
+		slog.Error("failed to create DynamoDB table", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// 以前の形式で保存された作成日時をインデックスで並べ替えられる形式に書き換える
+	rewritten, err := documents.BackfillCreatedAt(context.Background())
+	if err != nil {
+		slog.Error("failed to rewrite document creation times", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	slog.Info("DynamoDB table is ready", slog.String("table", cfg.AWS.DynamoTable), slog.String("index", storage.TypeCreatedAtIndex), slog.Int("rewritten", rewritten))
+}
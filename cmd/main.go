@@ -2,6 +2,7 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"os"
 
@@ -39,6 +40,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	// ゴミ箱の保持期間を過ぎた項目をバックグラウンドで完全に削除
+	go handlers.Trash.RunPurger(context.Background(), cfg.Storage.TrashPurgeInterval)
+	// アップロードされた画像ファイルのサムネイルをバックグラウンドで作成
+	go handlers.Thumbnails.Run(context.Background(), cfg.Storage.ThumbnailWorkers)
+	// アップロードされた内容のマルウェア検査をバックグラウンドで実行
+	go handlers.ContentScans.Run(context.Background(), cfg.Storage.ScanWorkers, cfg.Storage.ScanInterval)
+
 	// Ginルーターを初期化
 	r := gin.Default()
 
@@ -71,6 +79,58 @@ func main() {
 				studentManagement.GET("", handlers.Teacher.GetStudents())               // 担当学生一覧取得
 				studentManagement.POST("/:studentId", handlers.Teacher.AssignStudent()) // 学生を担当に追加
 			}
+
+			// 課題管理ルート
+			assignmentManagement := protected.Group("/assignments")
+			assignmentManagement.Use(middleware.SelfOnlyMiddleware()) // 本人確認
+			{
+				assignmentManagement.POST("", handlers.Assignment.Create())                                   // 課題作成
+				assignmentManagement.GET("", handlers.Assignment.ListByTeacher())                             // 課題一覧取得
+				assignmentManagement.POST("/:assignmentId/files", handlers.Assignment.AttachFile())           // 課題へのファイル添付
+				assignmentManagement.GET("/:assignmentId/submissions", handlers.Assignment.ListSubmissions()) // 提出物一覧取得
+			}
+
+			// 成績簿ルート
+			gradebook := protected.Group("/gradebook")
+			gradebook.Use(middleware.SelfOnlyMiddleware()) // 本人確認
+			{
+				gradebook.GET("", handlers.Gradebook.GetGradebook())               // 成績簿取得
+				gradebook.POST("/categories", handlers.Gradebook.CreateCategory()) // 成績カテゴリ作成
+				gradebook.GET("/categories", handlers.Gradebook.ListCategories())  // 成績カテゴリ一覧取得
+				gradebook.PUT("/scores", handlers.Gradebook.RecordScore())         // 得点の記録
+				gradebook.PUT("/scale", handlers.Gradebook.SetScale())             // 評定基準の設定
+			}
+
+			// 通知表ルート
+			reportCards := protected.Group("/report-cards")
+			reportCards.Use(middleware.SelfOnlyMiddleware()) // 本人確認
+			{
+				reportCards.POST("", handlers.ReportCard.GenerateForClass())                         // クラス全員分の通知表生成
+				reportCards.GET("", handlers.ReportCard.ListByTeacher())                             // 通知表一覧取得
+				reportCards.PUT("/remarks", handlers.ReportCard.SaveRemark())                        // 所見（コメントと出欠）の記入
+				reportCards.POST("/students/:studentId", handlers.ReportCard.Generate())             // 学生1人分の通知表生成
+				reportCards.GET("/:reportCardId/download", handlers.ReportCard.DownloadForTeacher()) // 通知表ダウンロード
+			}
+
+			// 小テスト管理ルート
+			quizManagement := protected.Group("/quizzes")
+			quizManagement.Use(middleware.SelfOnlyMiddleware()) // 本人確認
+			{
+				quizManagement.POST("", handlers.Quiz.CreateQuiz())                   // 小テスト作成
+				quizManagement.GET("", handlers.Quiz.ListByTeacher())                 // 小テスト一覧取得
+				quizManagement.GET("/:quizId", handlers.Quiz.GetQuiz())               // 小テスト取得（正答付き）
+				quizManagement.GET("/:quizId/attempts", handlers.Quiz.ListAttempts()) // 受験記録一覧取得
+			}
+
+			// 問題バンク管理ルート
+			questionBank := protected.Group("/question-bank")
+			questionBank.Use(middleware.SelfOnlyMiddleware()) // 本人確認
+			{
+				questionBank.GET("", handlers.QuestionBank.ListItems())             // 問題バンク一覧取得
+				questionBank.DELETE("/:itemId", handlers.QuestionBank.DeleteItem()) // 問題バンク項目削除
+				questionBank.POST("/import", handlers.QuestionBank.ImportQTI())     // QTIパッケージ取り込み
+				questionBank.GET("/export", handlers.QuestionBank.ExportQTI())      // QTIパッケージ書き出し
+			}
 		}
 	}
 
@@ -95,6 +155,55 @@ func main() {
 		}
 	}
 
+	// 学生向けの課題ルート（学生ロールが必要）
+	assignments := v1.Group("/assignments")
+	assignments.Use(middleware.AuthMiddleware(cfg))       // JWT認証
+	assignments.Use(middleware.RoleMiddleware("student")) // 学生ロール確認
+	{
+		assignments.GET("", handlers.Assignment.ListForStudent())                        // 担当教師の課題一覧取得
+		assignments.POST("/:id/submissions", handlers.Assignment.Submit())               // 課題提出
+		assignments.GET("/:id/files/:fileId", handlers.Storage.DownloadAssignmentFile()) // 課題の添付ファイルのダウンロード
+	}
+
+	// 学生向けの小テストルート（学生ロールが必要）
+	quizzes := v1.Group("/quizzes")
+	quizzes.Use(middleware.AuthMiddleware(cfg))       // JWT認証
+	quizzes.Use(middleware.RoleMiddleware("student")) // 学生ロール確認
+	{
+		quizzes.GET("", handlers.Quiz.ListForStudent())                                // 担当教師の小テスト一覧取得
+		quizzes.POST("/:id/attempts", handlers.Quiz.StartAttempt())                    // 受験開始（受験中の場合は再開）
+		quizzes.GET("/:id/attempts", handlers.Quiz.ListMyAttempts())                   // 自分の受験記録取得
+		quizzes.POST("/:id/attempts/:attemptId/submit", handlers.Quiz.SubmitAttempt()) // 回答提出と自動採点
+	}
+
+	// ログイン中のユーザー自身に関するルート
+	me := v1.Group("/me")
+	me.Use(middleware.AuthMiddleware(cfg)) // JWT認証
+	{
+		me.GET("/grades", middleware.RoleMiddleware("student"), handlers.Gradebook.MyGrades())                                         // 自分の成績取得
+		me.GET("/report-cards", middleware.RoleMiddleware("student"), handlers.ReportCard.ListForStudent())                            // 自分の通知表一覧取得
+		me.GET("/report-cards/:reportCardId/download", middleware.RoleMiddleware("student"), handlers.ReportCard.DownloadForStudent()) // 自分の通知表ダウンロード
+		me.GET("/storage", middleware.RoleMiddleware("teacher", "student"), handlers.StorageUsage.MyUsage())                           // 自分のストレージ使用量取得
+		me.GET("/files", middleware.RoleMiddleware("student"), handlers.FileShare.SharedWithMe())                                      // 自分と共有されているファイル一覧取得
+		me.GET("/files/:id", middleware.RoleMiddleware("student"), handlers.Storage.DownloadSharedFile())                              // 自分と共有されているファイルのダウンロード
+		me.GET("/notifications", middleware.RoleMiddleware("teacher", "student"), handlers.Notification.MyNotifications())             // 自分への通知一覧取得
+	}
+
+	// 管理者向けのルート（管理者として設定された教師のみ）
+	admin := v1.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(cfg))  // JWT認証
+	admin.Use(middleware.AdminMiddleware(cfg)) // 管理者確認
+	{
+		admin.GET("/storage/top-consumers", handlers.StorageUsage.TopConsumers())          // ストレージ使用量の多いユーザー一覧
+		admin.GET("/quarantine", handlers.Notification.ListQuarantinedFiles())             // 隔離されたファイル一覧
+		admin.GET("/document-schemas", handlers.DocumentSchema.ListSchemas())              // ドキュメントの種類ごとの最新のスキーマ一覧
+		admin.POST("/document-schemas/:type", handlers.DocumentSchema.RegisterSchema())    // ドキュメントのスキーマの登録
+		admin.GET("/document-schemas/:type", handlers.DocumentSchema.ListSchemaVersions()) // ドキュメントのスキーマのバージョン一覧
+	}
+
+	// 共有リンクのルート（認証不要、リンクのトークンで確認）
+	v1.GET("/shared/:token", handlers.Storage.DownloadLinkedFile()) // 共有リンクのファイルダウンロード
+
 	// ストレージ関連のルート（全て認証が必要）
 	storage := v1.Group("")
 	storage.Use(middleware.AuthMiddleware(cfg)) // JWT認証
@@ -103,28 +212,59 @@ func main() {
 		files := storage.Group("/files")
 		files.Use(middleware.RoleMiddleware("teacher")) // 教師ロール確認
 		{
-			files.POST("", handlers.Storage.UploadFile()) // ファイルアップロード
-			files.GET("", handlers.Storage.ListFiles())   // ファイル一覧取得
+			files.POST("", handlers.Storage.UploadFile())                         // ファイルアップロード
+			files.GET("", handlers.Folder.ListFiles())                            // ファイル一覧取得（フォルダとタグで絞り込み）
+			files.POST("/upload-url", handlers.Storage.CreateUploadURL())         // 直接アップロード用の署名付きURL発行
+			files.POST("/upload-url/complete", handlers.Storage.CompleteUpload()) // 直接アップロードの完了通知
+			files.GET("/trash", handlers.Storage.ListTrashedFiles())              // ゴミ箱のファイル一覧取得
+			files.POST("/archive", handlers.FileArchive.DownloadArchive())        // 複数ファイルのZIPダウンロード
 
 			fileManagement := files.Group("/:id")
 			{
-				fileManagement.GET("", handlers.Storage.DownloadFile())  // ファイルダウンロード
-				fileManagement.DELETE("", handlers.Storage.DeleteFile()) // ファイル削除
+				fileManagement.GET("", handlers.Storage.DownloadFile())                                  // ファイルダウンロード
+				fileManagement.PUT("", handlers.Storage.UploadFileVersion())                             // 新しいバージョンのアップロード
+				fileManagement.DELETE("", handlers.Storage.DeleteFile())                                 // ファイルをゴミ箱に移動
+				fileManagement.POST("/restore", handlers.Storage.RestoreFile())                          // ゴミ箱のファイルを元に戻す
+				fileManagement.PUT("/folder", handlers.Folder.MoveFile())                                // フォルダへの移動
+				fileManagement.PUT("/tags", handlers.Folder.SetFileTags())                               // タグの設定
+				fileManagement.POST("/shares", handlers.FileShare.ShareFile())                           // ファイルの共有
+				fileManagement.GET("/shares", handlers.FileShare.ListShares())                           // 共有一覧取得
+				fileManagement.DELETE("/shares/:shareId", handlers.FileShare.RevokeShare())              // 共有の取り消し
+				fileManagement.GET("/thumbnail", handlers.Storage.DownloadThumbnail())                   // サムネイルのダウンロード
+				fileManagement.GET("/download-url", handlers.Storage.CreateDownloadURL())                // 直接ダウンロード用の署名付きURL発行
+				fileManagement.GET("/versions", handlers.Storage.ListFileVersions())                     // バージョン一覧取得
+				fileManagement.GET("/versions/:version", handlers.Storage.DownloadFileVersion())         // 指定されたバージョンのダウンロード
+				fileManagement.POST("/versions/:version/restore", handlers.Storage.RestoreFileVersion()) // 指定されたバージョンの復元
 			}
 		}
 
+		// フォルダ関連のルート（教師のみ）
+		folders := storage.Group("/folders")
+		folders.Use(middleware.RoleMiddleware("teacher")) // 教師ロール確認
+		{
+			folders.POST("", handlers.Folder.CreateFolder())         // フォルダ作成
+			folders.GET("", handlers.Folder.ListFolders())           // フォルダ一覧取得
+			folders.PATCH("/:id", handlers.Folder.RenameFolder())    // フォルダ名の変更
+			folders.PUT("/:id/parent", handlers.Folder.MoveFolder()) // フォルダの移動
+			folders.DELETE("/:id", handlers.Folder.DeleteFolder())   // フォルダ削除（中身ごと削除する場合は確認が必要）
+		}
+
 		// ドキュメント関連のルート（教師のみ）
 		documents := storage.Group("/documents")
 		documents.Use(middleware.RoleMiddleware("teacher")) // 教師ロール確認
 		{
-			documents.POST("", handlers.Storage.CreateDocument()) // ドキュメント作成
-			documents.GET("", handlers.Storage.ListDocuments())   // ドキュメント一覧取得
+			documents.POST("", handlers.Storage.CreateDocument())                // ドキュメント作成
+			documents.GET("", handlers.Storage.ListDocuments())                  // ドキュメント一覧取得
+			documents.GET("/trash", handlers.Storage.ListTrashedDocuments())     // ゴミ箱のドキュメント一覧取得
+			documents.GET("/schemas/:type", handlers.DocumentSchema.GetSchema()) // ドキュメントの種類のスキーマ取得
 
 			documentManagement := documents.Group("/:id")
 			{
-				documentManagement.GET("", handlers.Storage.GetDocument())       // ドキュメント取得
-				documentManagement.PUT("", handlers.Storage.UpdateDocument())    // ドキュメント更新
-				documentManagement.DELETE("", handlers.Storage.DeleteDocument()) // ドキュメント削除
+				documentManagement.GET("", handlers.Storage.GetDocument())              // ドキュメント取得
+				documentManagement.PUT("", handlers.Storage.UpdateDocument())           // ドキュメント更新
+				documentManagement.PATCH("", handlers.Storage.PatchDocument())          // ドキュメントの一部を変更
+				documentManagement.DELETE("", handlers.Storage.DeleteDocument())        // ドキュメントをゴミ箱に移動
+				documentManagement.POST("/restore", handlers.Storage.RestoreDocument()) // ゴミ箱のドキュメントを元に戻す
 			}
 		}
 	}
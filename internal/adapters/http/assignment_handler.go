@@ -0,0 +1,289 @@
+package http
+
+import (
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+	"github.com/OICjangirrahul/students/internal/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// 課題ハンドラー構造体：課題と提出物に関するHTTPリクエストを処理
+type AssignmentHandler struct {
+	// 課題サービスインターフェース
+	assignmentService ports.AssignmentService
+}
+
+// 新しい課題ハンドラーインスタンスを作成する
+func NewAssignmentHandler(assignmentService ports.AssignmentService) *AssignmentHandler {
+	return &AssignmentHandler{
+		assignmentService: assignmentService,
+	}
+}
+
+// 教師の課題を作成する
+// @Summary      Create an assignment
+// @Description  Create a new assignment for the teacher
+// @Tags         assignments
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        assignment body domain.AssignmentCreate true "Assignment to create"
+// @Success      201  {object}  response.Response{data=domain.Assignment}
+// @Failure      400  {object}  response.Response "Validation error"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Forbidden - Teacher role required or not the authenticated teacher"
+// @Router       /api/v1/teachers/{id}/assignments [post]
+func (h *AssignmentHandler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		// リクエストボディから課題データを取得
+		var input domain.AssignmentCreate
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		// 課題を作成
+		assignment, err := h.assignmentService.Create(c.Request.Context(), teacherID, &input)
+		if err != nil {
+			respondError(c, err, "failed to create assignment")
+			return
+		}
+
+		response.Success(c, http.StatusCreated, assignment)
+	}
+}
+
+// 教師が作成した課題一覧を取得する
+// @Summary      List teacher's assignments
+// @Description  List all assignments created by the teacher
+// @Tags         assignments
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Success      200  {object}  response.Response{data=[]domain.Assignment}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Forbidden - Teacher role required or not the authenticated teacher"
+// @Router       /api/v1/teachers/{id}/assignments [get]
+func (h *AssignmentHandler) ListByTeacher() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		assignments, err := h.assignmentService.ListByTeacher(c.Request.Context(), teacherID)
+		if err != nil {
+			respondError(c, err, "failed to list assignments")
+			return
+		}
+
+		response.Success(c, http.StatusOK, assignments)
+	}
+}
+
+// 課題にファイルを添付する
+// @Summary      Attach a file to an assignment
+// @Description  Upload a file and attach it to the assignment
+// @Tags         assignments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        assignmentId path int true "Assignment ID"
+// @Param        file formData file true "File to attach"
+// @Success      201  {object}  response.Response{data=domain.Assignment}
+// @Failure      400  {object}  response.Response "Invalid file"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Forbidden - not the assignment owner"
+// @Failure      404  {object}  response.Response "Assignment not found"
+// @Router       /api/v1/teachers/{id}/assignments/{assignmentId}/files [post]
+func (h *AssignmentHandler) AttachFile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDと課題IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+		assignmentID, err := strconv.ParseInt(c.Param("assignmentId"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid assignment id")
+			return
+		}
+
+		// マルチパートフォームからファイルを取得
+		header, err := c.FormFile("file")
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid file")
+			return
+		}
+		upload, closer, err := openFormFile(header)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "failed to read file")
+			return
+		}
+		defer closer.Close()
+
+		assignment, err := h.assignmentService.AttachFile(c.Request.Context(), teacherID, assignmentID, upload)
+		if err != nil {
+			respondError(c, err, "failed to attach file")
+			return
+		}
+
+		response.Success(c, http.StatusCreated, assignment)
+	}
+}
+
+// 課題の提出物一覧を取得する
+// @Summary      List submissions of an assignment
+// @Description  List all student submissions for the assignment, including late and resubmission status
+// @Tags         assignments
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        assignmentId path int true "Assignment ID"
+// @Success      200  {object}  response.Response{data=[]domain.Submission}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Forbidden - not the assignment owner"
+// @Failure      404  {object}  response.Response "Assignment not found"
+// @Router       /api/v1/teachers/{id}/assignments/{assignmentId}/submissions [get]
+func (h *AssignmentHandler) ListSubmissions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDと課題IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+		assignmentID, err := strconv.ParseInt(c.Param("assignmentId"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid assignment id")
+			return
+		}
+
+		submissions, err := h.assignmentService.ListSubmissions(c.Request.Context(), teacherID, assignmentID)
+		if err != nil {
+			respondError(c, err, "failed to list submissions")
+			return
+		}
+
+		response.Success(c, http.StatusOK, submissions)
+	}
+}
+
+// ログイン中の学生の課題一覧を取得する
+// @Summary      List my assignments
+// @Description  List assignments from the teachers the student is assigned to
+// @Tags         assignments
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response{data=[]domain.Assignment}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Forbidden - Student role required"
+// @Router       /api/v1/assignments [get]
+func (h *AssignmentHandler) ListForStudent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// トークンから学生IDを取得
+		studentID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		assignments, err := h.assignmentService.ListForStudent(c.Request.Context(), studentID)
+		if err != nil {
+			respondError(c, err, "failed to list assignments")
+			return
+		}
+
+		response.Success(c, http.StatusOK, assignments)
+	}
+}
+
+// ログイン中の学生が課題を提出する
+// @Summary      Submit an assignment
+// @Description  Submit text and/or files for an assignment. Submitting again replaces the previous submission.
+// @Tags         assignments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Assignment ID"
+// @Param        text formData string false "Text answer"
+// @Param        files formData file false "Files to submit"
+// @Success      201  {object}  response.Response{data=domain.Submission}
+// @Failure      400  {object}  response.Response "Empty submission"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Forbidden - assignment is not from the student's teacher"
+// @Failure      404  {object}  response.Response "Assignment not found"
+// @Router       /api/v1/assignments/{id}/submissions [post]
+func (h *AssignmentHandler) Submit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// トークンから学生IDを取得
+		studentID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		assignmentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid assignment id")
+			return
+		}
+
+		// マルチパートフォームからテキストとファイルを取得
+		form, err := c.MultipartForm()
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid multipart form")
+			return
+		}
+		input := &domain.SubmissionCreate{Text: c.PostForm("text")}
+		for _, header := range form.File["files"] {
+			upload, closer, err := openFormFile(header)
+			if err != nil {
+				response.Error(c, http.StatusInternalServerError, "failed to read file")
+				return
+			}
+			defer closer.Close()
+			input.Files = append(input.Files, upload)
+		}
+
+		submission, err := h.assignmentService.Submit(c.Request.Context(), studentID, assignmentID, input)
+		if err != nil {
+			respondError(c, err, "failed to submit assignment")
+			return
+		}
+
+		response.Success(c, http.StatusCreated, submission)
+	}
+}
+
+// マルチパートのファイルヘッダーを開き、内容を読み込むアップロード用のファイル構造体を作成する
+// 返されたCloserはアップロードの完了後に呼び出し側で閉じる必要がある
+func openFormFile(header *multipart.FileHeader) (*domain.FileUpload, io.Closer, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &domain.FileUpload{
+		Name:        header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Body:        file,
+		Size:        header.Size,
+	}, file, nil
+}
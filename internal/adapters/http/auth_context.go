@@ -0,0 +1,35 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 認証ミドルウェアが設定したユーザーIDをコンテキストから取得する
+// JWTのsubクレームは数値としてデコードされるため、int64に変換して返す
+func currentUserID(c *gin.Context) (int64, error) {
+	value, exists := c.Get("userID")
+	if !exists {
+		return 0, fmt.Errorf("user ID not found in token")
+	}
+
+	switch id := value.(type) {
+	case float64:
+		return int64(id), nil
+	case int64:
+		return id, nil
+	case string:
+		return strconv.ParseInt(id, 10, 64)
+	default:
+		return 0, fmt.Errorf("invalid user ID type: %T", value)
+	}
+}
+
+// 認証ミドルウェアが設定したユーザーの役割をコンテキストから取得する
+func currentUserRole(c *gin.Context) string {
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	return roleStr
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// If-Matchヘッダーをドキュメントのバージョンとして解釈できないことを示すエラー
+var errInvalidIfMatch = errors.New("If-Match must be \"*\" or a single ETag returned by this API")
+
+// ドキュメントのETagを返す（バージョンを引用符で囲んだ強いETag）
+func documentETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// If-Matchヘッダーから更新の前提となるドキュメントのバージョンを取得する
+// ヘッダーがない場合と"*"の場合は、バージョンを確認しないためnilを返す
+// If-Matchは強い比較のため、弱いETagや複数のETagは受け付けない
+func ifMatchVersion(header string) (*int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	if len(header) < 2 || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		return nil, errInvalidIfMatch
+	}
+	version, err := strconv.Atoi(header[1 : len(header)-1])
+	if err != nil || version < 0 {
+		return nil, errInvalidIfMatch
+	}
+	return &version, nil
+}
@@ -0,0 +1,44 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+)
+
+// ドキュメントのパッチのContent-Type
+const (
+	// JSON Merge Patch（RFC 7396）
+	mergePatchContentType = "application/merge-patch+json"
+	// JSON Patch（RFC 6902）
+	jsonPatchContentType = "application/json-patch+json"
+)
+
+// パッチのContent-Typeに対応していないことを示すエラー
+var errUnsupportedPatchType = errors.New("Content-Type must be " + mergePatchContentType + " or " + jsonPatchContentType)
+
+// Content-Typeに応じてリクエストボディをドキュメントのパッチとして読み込む
+// Content-Typeに対応していない場合はerrUnsupportedPatchType、本文が不正な場合はErrInvalidInputを返す
+func decodeDocumentPatch(contentType string, body io.Reader) (*domain.DocumentPatch, error) {
+	decoder := json.NewDecoder(body)
+	switch contentType {
+	case mergePatchContentType:
+		// 本文全体がオブジェクトでない場合はdataを置き換えることになるため受け付けない
+		var merge map[string]interface{}
+		if err := decoder.Decode(&merge); err != nil || merge == nil {
+			return nil, fmt.Errorf("%w: merge patch must be a JSON object", domain.ErrInvalidInput)
+		}
+		return &domain.DocumentPatch{Format: domain.PatchFormatMerge, Merge: merge}, nil
+	case jsonPatchContentType:
+		var operations []domain.PatchOperation
+		if err := decoder.Decode(&operations); err != nil || operations == nil {
+			return nil, fmt.Errorf("%w: JSON patch must be an array of operations", domain.ErrInvalidInput)
+		}
+		return &domain.DocumentPatch{Format: domain.PatchFormatJSON, Operations: operations}, nil
+	default:
+		return nil, errUnsupportedPatchType
+	}
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+	"github.com/OICjangirrahul/students/internal/utils/response"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ドキュメントスキーマハンドラー構造体：ドキュメントの種類ごとのJSON Schemaに関するHTTPリクエストを処理
+type DocumentSchemaHandler struct {
+	// ドキュメントサービスインターフェース
+	documentService ports.DocumentService
+	// バリデーター
+	validator *validator.Validate
+}
+
+// 新しいドキュメントスキーマハンドラーインスタンスを作成する
+func NewDocumentSchemaHandler(documentService ports.DocumentService) *DocumentSchemaHandler {
+	return &DocumentSchemaHandler{
+		documentService: documentService,
+		validator:       validator.New(),
+	}
+}
+
+// ドキュメントの種類に新しいバージョンのスキーマを登録する
+// @Summary      Register a document schema
+// @Description  Register a JSON Schema (draft 2020-12) for a document type. Each registration creates the next version; documents created or updated afterwards are validated against it. References to external schemas are not allowed. Administrators only.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type   path string                      true "Document type"
+// @Param        schema body domain.DocumentSchemaCreate true "Schema to register"
+// @Success      201  {object}  response.Response{data=domain.DocumentSchema}
+// @Failure      400  {object}  response.Response "Invalid schema"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Not an administrator"
+// @Router       /api/v1/admin/document-schemas/{type} [post]
+func (h *DocumentSchemaHandler) RegisterSchema() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		var input domain.DocumentSchemaCreate
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if err := h.validator.Struct(input); err != nil {
+			response.Error(c, http.StatusBadRequest, "validation failed")
+			return
+		}
+
+		schema, err := h.documentService.RegisterSchema(c.Request.Context(), c.Param("type"), &input, adminID)
+		if err != nil {
+			respondError(c, err, "failed to register document schema")
+			return
+		}
+
+		response.Success(c, http.StatusCreated, schema)
+	}
+}
+
+// ドキュメントの種類ごとの最新のスキーマの一覧を取得する
+// @Summary      List document schemas
+// @Description  List the latest schema of every document type that has one. Administrators only.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response{data=[]domain.DocumentSchema}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Not an administrator"
+// @Router       /api/v1/admin/document-schemas [get]
+func (h *DocumentSchemaHandler) ListSchemas() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schemas, err := h.documentService.ListSchemas(c.Request.Context())
+		if err != nil {
+			respondError(c, err, "failed to list document schemas")
+			return
+		}
+
+		response.Success(c, http.StatusOK, schemas)
+	}
+}
+
+// ドキュメントの種類のスキーマのすべてのバージョンを取得する
+// @Summary      List the versions of a document schema
+// @Description  List every version of the schema of a document type, newest first. Administrators only.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type path string true "Document type"
+// @Success      200  {object}  response.Response{data=[]domain.DocumentSchema}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Not an administrator"
+// @Failure      404  {object}  response.Response "No schema registered for the type"
+// @Router       /api/v1/admin/document-schemas/{type} [get]
+func (h *DocumentSchemaHandler) ListSchemaVersions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schemas, err := h.documentService.ListSchemaVersions(c.Request.Context(), c.Param("type"))
+		if err != nil {
+			respondError(c, err, "failed to list document schema versions")
+			return
+		}
+
+		response.Success(c, http.StatusOK, schemas)
+	}
+}
+
+// ドキュメントの種類のスキーマを取得する
+// @Summary      Get a document schema
+// @Description  Get the schema that documents of a type are validated against (the latest version unless version is given)
+// @Tags         documents
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type    path  string true  "Document type"
+// @Param        version query int    false "Schema version (default latest)"
+// @Success      200  {object}  response.Response{data=domain.DocumentSchema}
+// @Failure      400  {object}  response.Response "Invalid version"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      404  {object}  response.Response "No schema registered for the type"
+// @Router       /api/v1/documents/schemas/{type} [get]
+func (h *DocumentSchemaHandler) GetSchema() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version := 0
+		if value := c.Query("version"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 {
+				response.Error(c, http.StatusBadRequest, "invalid version")
+				return
+			}
+			version = parsed
+		}
+
+		schema, err := h.documentService.GetSchema(c.Request.Context(), c.Param("type"), version)
+		if err != nil {
+			respondError(c, err, "failed to get document schema")
+			return
+		}
+
+		response.Success(c, http.StatusOK, schema)
+	}
+}
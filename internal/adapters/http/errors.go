@@ -0,0 +1,74 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ドメインエラーを対応するHTTPステータスコードに変換する
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrAlreadyExists), errors.Is(err, domain.ErrConflict), errors.Is(err, domain.ErrScanPending):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrQuarantined):
+		return http.StatusGone
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, domain.ErrFileTooLarge), errors.Is(err, domain.ErrQuotaExceeded):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, domain.ErrUnsupportedMedia):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, domain.ErrNotSupported):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// サービスのエラーをログに記録し、対応するエラーレスポンスを送信する
+// クライアント起因のエラー（4xx）の場合のみ、エラーの詳細をメッセージに含める
+func respondError(c *gin.Context, err error, message string) {
+	status := errorStatus(err)
+	if status >= http.StatusInternalServerError {
+		slog.Error(message, slog.String("error", err.Error()))
+		response.Error(c, status, message)
+		return
+	}
+
+	// スキーマに適合しなかった場合は項目ごとのエラーを含める
+	var validationErr *domain.DocumentValidationError
+	if errors.As(err, &validationErr) {
+		c.JSON(status, gin.H{
+			"success":        false,
+			"error":          message + ": " + err.Error(),
+			"schema_version": validationErr.Version,
+			"fields":         validationErr.Fields,
+		})
+		return
+	}
+
+	// 他の更新と競合した場合は、現在のバージョンをETagと本文で返す
+	var conflictErr *domain.DocumentVersionConflictError
+	if errors.As(err, &conflictErr) {
+		c.Header("ETag", documentETag(conflictErr.CurrentVersion))
+		c.JSON(status, gin.H{
+			"success":         false,
+			"error":           message + ": " + err.Error(),
+			"current_version": conflictErr.CurrentVersion,
+		})
+		return
+	}
+
+	response.Error(c, status, message+": "+err.Error())
+}
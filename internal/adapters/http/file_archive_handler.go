@@ -0,0 +1,75 @@
+package http
+
+import (
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+	"github.com/OICjangirrahul/students/internal/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ファイルアーカイブハンドラー構造体：複数のファイルのZIPでのダウンロードに関するHTTPリクエストを処理
+type FileArchiveHandler struct {
+	// ファイルアーカイブサービスインターフェース
+	archiveService ports.FileArchiveService
+}
+
+// 新しいファイルアーカイブハンドラーインスタンスを作成する
+func NewFileArchiveHandler(archiveService ports.FileArchiveService) *FileArchiveHandler {
+	return &FileArchiveHandler{
+		archiveService: archiveService,
+	}
+}
+
+// 複数のファイルをZIPにまとめてダウンロードする
+// ZIPはストレージから読み込みながら送信し、見つからなかったファイルはX-Missing-FilesヘッダーとZIP内のMISSING.txtで通知する
+// @Summary      Download files as a ZIP archive
+// @Description  Stream a ZIP archive of the given files, or of a folder owned by the teacher including its subfolders. The archive is built on the fly from storage. Duplicate names get a numbered suffix. IDs of files that could not be found are listed in the X-Missing-Files header and in MISSING.txt inside the archive.
+// @Tags         files
+// @Accept       json
+// @Produce      application/zip
+// @Security     BearerAuth
+// @Param        request body domain.FileArchiveRequest true "Files or folder to archive"
+// @Success      200
+// @Failure      400  {object}  response.Response "Invalid request"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Folder belongs to another teacher"
+// @Failure      404  {object}  response.Response "Folder or all of the files not found"
+// @Router       /api/v1/files/archive [post]
+func (h *FileArchiveHandler) DownloadArchive() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		teacherID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		var input domain.FileArchiveRequest
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		archive, err := h.archiveService.PrepareArchive(c.Request.Context(), teacherID, &input)
+		if err != nil {
+			respondError(c, err, "failed to prepare archive")
+			return
+		}
+
+		if len(archive.Missing) > 0 {
+			c.Header("X-Missing-Files", strings.Join(archive.Missing, ","))
+		}
+		c.Header("Content-Type", "application/zip")
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": archive.Name}))
+		c.Status(http.StatusOK)
+
+		if err := h.archiveService.WriteArchive(c.Request.Context(), archive, c.Writer); err != nil {
+			// ステータスは送信済みのため、ログに記録して中断する
+			slog.Error("failed to send archive", slog.String("error", err.Error()))
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+)
+
+// 1回のリクエストで受け付けるバイト範囲の最大数（これを超える場合はRangeヘッダーを無視する）
+const maxByteRanges = 16
+
+// 要求された範囲がファイルの範囲外であることを示すエラー
+var errRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// ファイルのETagを返す（チェックサムが記録されていない場合は空文字列）
+func fileETag(file *domain.File) string {
+	if file.Checksum == "" {
+		return ""
+	}
+	return `"` + file.Checksum + `"`
+}
+
+// ファイルの検証用ヘッダー（ETag、Last-Modified、Accept-Ranges）を設定する
+func setFileValidators(header http.Header, file *domain.File) {
+	if etag := fileETag(file); etag != "" {
+		header.Set("ETag", etag)
+	}
+	if !file.UploadedAt.IsZero() {
+		header.Set("Last-Modified", file.UploadedAt.UTC().Format(http.TimeFormat))
+	}
+	header.Set("Accept-Ranges", "bytes")
+}
+
+// If-None-Match、If-Modified-Sinceの条件からクライアントのキャッシュが有効かどうかを判定する
+// If-None-Matchが指定されている場合はIf-Modified-Sinceより優先する
+func fileNotModified(r *http.Request, file *domain.File) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		etag := fileETag(file)
+		if etag == "" {
+			return false
+		}
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !file.UploadedAt.IsZero() {
+		since, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		return !file.UploadedAt.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// If-Rangeの条件を満たしているかどうかを判定する（ヘッダーがない場合は満たしている）
+// ETagは強い比較、日時はLast-Modifiedとの完全一致で判定する
+func ifRangeSatisfied(r *http.Request, file *domain.File) bool {
+	ir := r.Header.Get("If-Range")
+	if ir == "" {
+		return true
+	}
+	if strings.HasPrefix(ir, `"`) {
+		etag := fileETag(file)
+		return etag != "" && ir == etag
+	}
+	since, err := http.ParseTime(ir)
+	if err != nil {
+		return false
+	}
+	return file.UploadedAt.Truncate(time.Second).Equal(since)
+}
+
+// リクエストのRangeヘッダーを解析し、ファイルのバイト範囲に変換する
+// Rangeヘッダーがない、形式が不正、またはIf-Rangeの条件を満たさない場合はnilを返し、ファイル全体を送信する
+// すべての範囲がファイルの範囲外の場合はerrRangeNotSatisfiableを返す
+func requestedRanges(r *http.Request, file *domain.File) ([]domain.ByteRange, error) {
+	header := r.Header.Get("Range")
+	if header == "" || !ifRangeSatisfied(r, file) {
+		return nil, nil
+	}
+	return parseByteRanges(header, file.Size)
+}
+
+// bytes=形式のRangeヘッダーを解析する
+// ファイルの範囲外の範囲は除外し、末尾を超える範囲はファイルの末尾までに切り詰める
+func parseByteRanges(header string, size int64) ([]domain.ByteRange, error) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok {
+		return nil, nil
+	}
+
+	specs := strings.Split(spec, ",")
+	if len(specs) > maxByteRanges {
+		return nil, nil
+	}
+
+	ranges := make([]domain.ByteRange, 0, len(specs))
+	for _, s := range specs {
+		first, last, ok := strings.Cut(strings.TrimSpace(s), "-")
+		if !ok {
+			return nil, nil
+		}
+
+		if first == "" {
+			// bytes=-N：末尾のNバイト
+			n, err := strconv.ParseInt(last, 10, 64)
+			if err != nil || n < 0 {
+				return nil, nil
+			}
+			if n == 0 || size == 0 {
+				continue
+			}
+			if n > size {
+				n = size
+			}
+			ranges = append(ranges, domain.ByteRange{Offset: size - n, Length: n})
+			continue
+		}
+
+		start, err := strconv.ParseInt(first, 10, 64)
+		if err != nil || start < 0 {
+			return nil, nil
+		}
+		end := size - 1
+		if last != "" {
+			end, err = strconv.ParseInt(last, 10, 64)
+			if err != nil || end < start {
+				return nil, nil
+			}
+			if end >= size {
+				end = size - 1
+			}
+		}
+		if start >= size {
+			continue
+		}
+		ranges = append(ranges, domain.ByteRange{Offset: start, Length: end - start + 1})
+	}
+
+	if len(ranges) == 0 {
+		return nil, errRangeNotSatisfiable
+	}
+	return ranges, nil
+}
+
+// Content-Rangeヘッダーの値を作成する
+func contentRange(byteRange domain.ByteRange, size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", byteRange.Offset, byteRange.Offset+byteRange.Length-1, size)
+}
@@ -0,0 +1,167 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteRanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		size    int64
+		want    []domain.ByteRange
+		wantErr error
+	}{
+		{name: "closed range", header: "bytes=0-99", size: 1000, want: []domain.ByteRange{{Offset: 0, Length: 100}}},
+		{name: "open-ended range", header: "bytes=900-", size: 1000, want: []domain.ByteRange{{Offset: 900, Length: 100}}},
+		{name: "suffix range", header: "bytes=-200", size: 1000, want: []domain.ByteRange{{Offset: 800, Length: 200}}},
+		{name: "suffix longer than the file", header: "bytes=-5000", size: 1000, want: []domain.ByteRange{{Offset: 0, Length: 1000}}},
+		{name: "end past the file is truncated", header: "bytes=950-2000", size: 1000, want: []domain.ByteRange{{Offset: 950, Length: 50}}},
+		{
+			name:   "multiple ranges",
+			header: "bytes=0-9, 20-29,-5",
+			size:   100,
+			want:   []domain.ByteRange{{Offset: 0, Length: 10}, {Offset: 20, Length: 10}, {Offset: 95, Length: 5}},
+		},
+		{name: "unsatisfiable ranges are dropped", header: "bytes=0-9,5000-6000", size: 100, want: []domain.ByteRange{{Offset: 0, Length: 10}}},
+		{name: "ranges at the limit", header: "bytes=" + strings.Repeat("0-0,", maxByteRanges-1) + "0-0", size: 10, want: repeatRange(domain.ByteRange{Offset: 0, Length: 1}, maxByteRanges)},
+		{name: "too many ranges are ignored", header: "bytes=" + strings.Repeat("0-0,", maxByteRanges) + "0-0", size: 10},
+		{name: "start past the file", header: "bytes=1000-", size: 1000, wantErr: errRangeNotSatisfiable},
+		{name: "zero-length suffix", header: "bytes=-0", size: 1000, wantErr: errRangeNotSatisfiable},
+		{name: "empty file", header: "bytes=-10", size: 0, wantErr: errRangeNotSatisfiable},
+		{name: "other unit is ignored", header: "items=0-9", size: 1000},
+		{name: "missing dash is ignored", header: "bytes=10", size: 1000},
+		{name: "end before start is ignored", header: "bytes=50-10", size: 1000},
+		{name: "negative start is ignored", header: "bytes=--10", size: 1000},
+		{name: "non-numeric range is ignored", header: "bytes=a-b", size: 1000},
+		{name: "one malformed range ignores the header", header: "bytes=0-9,x-1", size: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			ranges, err := parseByteRanges(tt.header, tt.size)
+
+			// Assertions
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, ranges)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, ranges)
+		})
+	}
+}
+
+func TestRequestedRanges_IfRange(t *testing.T) {
+	uploadedAt := time.Date(2024, 4, 1, 9, 30, 15, 500, time.UTC)
+	file := &domain.File{Size: 1000, Checksum: "abc123", UploadedAt: uploadedAt}
+	lastModified := uploadedAt.Format(http.TimeFormat)
+
+	tests := []struct {
+		name    string
+		ifRange string
+		want    []domain.ByteRange
+	}{
+		{name: "no If-Range", want: []domain.ByteRange{{Offset: 0, Length: 10}}},
+		{name: "matching ETag", ifRange: `"abc123"`, want: []domain.ByteRange{{Offset: 0, Length: 10}}},
+		{name: "different ETag sends the whole file", ifRange: `"other"`},
+		{name: "weak ETag never matches", ifRange: `W/"abc123"`},
+		{name: "matching date", ifRange: lastModified, want: []domain.ByteRange{{Offset: 0, Length: 10}}},
+		{name: "older date sends the whole file", ifRange: uploadedAt.Add(-time.Hour).Format(http.TimeFormat)},
+		{name: "newer date sends the whole file", ifRange: uploadedAt.Add(time.Hour).Format(http.TimeFormat)},
+		{name: "malformed date sends the whole file", ifRange: "yesterday"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			r := httptest.NewRequest(http.MethodGet, "/files/abc", nil)
+			r.Header.Set("Range", "bytes=0-9")
+			if tt.ifRange != "" {
+				r.Header.Set("If-Range", tt.ifRange)
+			}
+
+			// Test
+			ranges, err := requestedRanges(r, file)
+
+			// Assertions
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, ranges)
+		})
+	}
+
+	t.Run("ETag without a checksum never matches", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/files/abc", nil)
+		r.Header.Set("Range", "bytes=0-9")
+		r.Header.Set("If-Range", `""`)
+
+		ranges, err := requestedRanges(r, &domain.File{Size: 1000, UploadedAt: uploadedAt})
+
+		assert.NoError(t, err)
+		assert.Nil(t, ranges)
+	})
+}
+
+func TestFileNotModified(t *testing.T) {
+	uploadedAt := time.Date(2024, 4, 1, 9, 30, 15, 500, time.UTC)
+	file := &domain.File{Checksum: "abc123", UploadedAt: uploadedAt}
+
+	tests := []struct {
+		name            string
+		file            *domain.File
+		ifNoneMatch     string
+		ifModifiedSince string
+		want            bool
+	}{
+		{name: "no conditions", file: file},
+		{name: "matching ETag", file: file, ifNoneMatch: `"abc123"`, want: true},
+		{name: "weak ETag matches", file: file, ifNoneMatch: `W/"abc123"`, want: true},
+		{name: "ETag in a list", file: file, ifNoneMatch: `"old", "abc123"`, want: true},
+		{name: "wildcard", file: file, ifNoneMatch: "*", want: true},
+		{name: "different ETag", file: file, ifNoneMatch: `"other"`},
+		{name: "no checksum", file: &domain.File{UploadedAt: uploadedAt}, ifNoneMatch: "*"},
+		{name: "If-None-Match takes precedence", file: file, ifNoneMatch: `"other"`, ifModifiedSince: uploadedAt.Format(http.TimeFormat)},
+		{name: "not modified since the upload", file: file, ifModifiedSince: uploadedAt.Format(http.TimeFormat), want: true},
+		{name: "modified after the date", file: file, ifModifiedSince: uploadedAt.Add(-time.Second).Format(http.TimeFormat)},
+		{name: "later date", file: file, ifModifiedSince: uploadedAt.Add(time.Hour).Format(http.TimeFormat), want: true},
+		{name: "malformed date", file: file, ifModifiedSince: "yesterday"},
+		{name: "unknown upload time", file: &domain.File{Checksum: "abc123"}, ifModifiedSince: uploadedAt.Format(http.TimeFormat)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			r := httptest.NewRequest(http.MethodGet, "/files/abc", nil)
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			if tt.ifModifiedSince != "" {
+				r.Header.Set("If-Modified-Since", tt.ifModifiedSince)
+			}
+
+			// Test
+			notModified := fileNotModified(r, tt.file)
+
+			// Assertions
+			assert.Equal(t, tt.want, notModified)
+		})
+	}
+}
+
+// 同じ範囲をn個並べたスライスを返す
+func repeatRange(byteRange domain.ByteRange, n int) []domain.ByteRange {
+	ranges := make([]domain.ByteRange, n)
+	for i := range ranges {
+		ranges[i] = byteRange
+	}
+	return ranges
+}
@@ -0,0 +1,163 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+	"github.com/OICjangirrahul/students/internal/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ファイル共有ハンドラー構造体：ファイルの共有の管理と共有されたファイルの一覧に関するHTTPリクエストを処理
+type FileShareHandler struct {
+	// ファイル共有サービスインターフェース
+	sharingService ports.FileSharingService
+}
+
+// 新しいファイル共有ハンドラーインスタンスを作成する
+func NewFileShareHandler(sharingService ports.FileSharingService) *FileShareHandler {
+	return &FileShareHandler{
+		sharingService: sharingService,
+	}
+}
+
+// ファイルを共有する
+// @Summary      Share a file
+// @Description  Share a file uploaded by the teacher with one of their students (kind=student), with all students assigned to them (kind=roster), or through a public link that expires (kind=link, expires_at required, at most 30 days). The link token is only returned once, when the share is created.
+// @Tags         files
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "File ID"
+// @Param        share body domain.FileShareCreate true "Share to create"
+// @Success      201  {object}  response.Response{data=domain.FileShare}
+// @Failure      400  {object}  response.Response "Invalid share"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "File belongs to another teacher or student is not assigned"
+// @Failure      404  {object}  response.Response "File not found"
+// @Failure      409  {object}  response.Response "File is already shared this way"
+// @Router       /api/v1/files/{id}/shares [post]
+func (h *FileShareHandler) ShareFile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		teacherID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		var input domain.FileShareCreate
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		share, err := h.sharingService.ShareFile(c.Request.Context(), teacherID, c.Param("id"), &input)
+		if err != nil {
+			respondError(c, err, "failed to share file")
+			return
+		}
+		if share.Token != "" {
+			share.URL = sharedLinkURL(share.Token)
+		}
+
+		response.Success(c, http.StatusCreated, share)
+	}
+}
+
+// ファイルの共有一覧を取得する
+// @Summary      List file shares
+// @Description  List who a file uploaded by the teacher is shared with. Link tokens are not returned.
+// @Tags         files
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "File ID"
+// @Success      200  {object}  response.Response{data=[]domain.FileShare}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "File belongs to another teacher"
+// @Failure      404  {object}  response.Response "File not found"
+// @Router       /api/v1/files/{id}/shares [get]
+func (h *FileShareHandler) ListShares() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		teacherID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		shares, err := h.sharingService.ListShares(c.Request.Context(), teacherID, c.Param("id"))
+		if err != nil {
+			respondError(c, err, "failed to list file shares")
+			return
+		}
+
+		response.Success(c, http.StatusOK, shares)
+	}
+}
+
+// ファイルの共有を取り消す
+// @Summary      Revoke a file share
+// @Description  Revoke a share of a file uploaded by the teacher. Revoking a link share makes the link stop working immediately.
+// @Tags         files
+// @Security     BearerAuth
+// @Param        id path string true "File ID"
+// @Param        shareId path int true "Share ID"
+// @Success      204
+// @Failure      400  {object}  response.Response "Invalid share id"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "File belongs to another teacher"
+// @Failure      404  {object}  response.Response "File or share not found"
+// @Router       /api/v1/files/{id}/shares/{shareId} [delete]
+func (h *FileShareHandler) RevokeShare() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		teacherID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		shareID, err := strconv.ParseInt(c.Param("shareId"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid share id")
+			return
+		}
+
+		if err := h.sharingService.RevokeShare(c.Request.Context(), teacherID, c.Param("id"), shareID); err != nil {
+			respondError(c, err, "failed to revoke file share")
+			return
+		}
+
+		response.Success(c, http.StatusNoContent, nil)
+	}
+}
+
+// ログイン中の学生と共有されているファイルの一覧を取得する
+// @Summary      List files shared with me
+// @Description  List the files teachers shared with the authenticated student, directly or with all of the teacher's students, newest first. Expired shares are left out.
+// @Tags         files
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response{data=[]domain.File}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Forbidden - Student role required"
+// @Router       /api/v1/me/files [get]
+func (h *FileShareHandler) SharedWithMe() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		studentID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		files, err := h.sharingService.ListSharedWithStudent(c.Request.Context(), studentID)
+		if err != nil {
+			respondError(c, err, "failed to list shared files")
+			return
+		}
+		for i := range files {
+			files[i].URL = sharedFileDownloadURL(files[i].ID)
+		}
+
+		response.Success(c, http.StatusOK, files)
+	}
+}
@@ -0,0 +1,336 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+	"github.com/OICjangirrahul/students/internal/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ファイル一覧でどのフォルダにも属さないファイルを指定するフォルダIDの値
+const unfiledFolderID = "root"
+
+// フォルダハンドラー構造体：フォルダとタグによるファイルの整理に関するHTTPリクエストを処理
+type FolderHandler struct {
+	// フォルダサービスインターフェース
+	folderService ports.FolderService
+}
+
+// 新しいフォルダハンドラーインスタンスを作成する
+func NewFolderHandler(folderService ports.FolderService) *FolderHandler {
+	return &FolderHandler{
+		folderService: folderService,
+	}
+}
+
+// フォルダを作成する
+// @Summary      Create a folder
+// @Description  Create a folder for organizing files, either at the top level or inside one of the teacher's folders. Folder names are unique within their parent (case-insensitive).
+// @Tags         folders
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        folder body domain.FolderCreate true "Folder to create"
+// @Success      201  {object}  response.Response{data=domain.Folder}
+// @Failure      400  {object}  response.Response "Invalid folder name"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Parent folder belongs to another teacher"
+// @Failure      404  {object}  response.Response "Parent folder not found"
+// @Failure      409  {object}  response.Response "Folder already exists"
+// @Router       /api/v1/folders [post]
+func (h *FolderHandler) CreateFolder() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ownerID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		var input domain.FolderCreate
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		folder, err := h.folderService.CreateFolder(c.Request.Context(), ownerID, &input)
+		if err != nil {
+			respondError(c, err, "failed to create folder")
+			return
+		}
+
+		response.Success(c, http.StatusCreated, folder)
+	}
+}
+
+// フォルダ一覧を取得する
+// @Summary      List folders
+// @Description  List all of the teacher's folders sorted by name. Use parent_id to build the folder tree.
+// @Tags         folders
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response{data=[]domain.Folder}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Router       /api/v1/folders [get]
+func (h *FolderHandler) ListFolders() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ownerID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		folders, err := h.folderService.ListFolders(c.Request.Context(), ownerID)
+		if err != nil {
+			respondError(c, err, "failed to list folders")
+			return
+		}
+
+		response.Success(c, http.StatusOK, folders)
+	}
+}
+
+// フォルダの名前を変更する
+// @Summary      Rename a folder
+// @Description  Rename one of the teacher's folders
+// @Tags         folders
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Folder ID"
+// @Param        folder body domain.FolderRename true "New name"
+// @Success      200  {object}  response.Response{data=domain.Folder}
+// @Failure      400  {object}  response.Response "Invalid folder name"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Folder belongs to another teacher"
+// @Failure      404  {object}  response.Response "Folder not found"
+// @Failure      409  {object}  response.Response "Folder already exists"
+// @Router       /api/v1/folders/{id} [patch]
+func (h *FolderHandler) RenameFolder() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ownerID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		var input domain.FolderRename
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		folder, err := h.folderService.RenameFolder(c.Request.Context(), ownerID, c.Param("id"), &input)
+		if err != nil {
+			respondError(c, err, "failed to rename folder")
+			return
+		}
+
+		response.Success(c, http.StatusOK, folder)
+	}
+}
+
+// フォルダを別の親フォルダへ移動する
+// @Summary      Move a folder
+// @Description  Move one of the teacher's folders, with everything in it, under another folder. A null parent_id moves it to the top level.
+// @Tags         folders
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Folder ID"
+// @Param        folder body domain.FolderMove true "New parent folder"
+// @Success      200  {object}  response.Response{data=domain.Folder}
+// @Failure      400  {object}  response.Response "Cannot move a folder into itself or its subfolders"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Folder belongs to another teacher"
+// @Failure      404  {object}  response.Response "Folder not found"
+// @Failure      409  {object}  response.Response "A folder with the same name already exists in the destination"
+// @Router       /api/v1/folders/{id}/parent [put]
+func (h *FolderHandler) MoveFolder() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ownerID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		var input domain.FolderMove
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		folder, err := h.folderService.MoveFolder(c.Request.Context(), ownerID, c.Param("id"), &input)
+		if err != nil {
+			respondError(c, err, "failed to move folder")
+			return
+		}
+
+		response.Success(c, http.StatusOK, folder)
+	}
+}
+
+// フォルダを削除する
+// @Summary      Delete a folder
+// @Description  Delete one of the teacher's folders. A folder that is not empty is only deleted with recursive=true and confirm set to the folder's name; its subfolders are deleted and its files are moved to the trash.
+// @Tags         folders
+// @Security     BearerAuth
+// @Param        id path string true "Folder ID"
+// @Param        recursive query bool false "Delete the folder together with its contents"
+// @Param        confirm query string false "Folder name, required when recursive is true"
+// @Success      204
+// @Failure      400  {object}  response.Response "Confirmation does not match the folder name"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Folder belongs to another teacher"
+// @Failure      404  {object}  response.Response "Folder not found"
+// @Failure      409  {object}  response.Response "Folder is not empty"
+// @Router       /api/v1/folders/{id} [delete]
+func (h *FolderHandler) DeleteFolder() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ownerID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		input := domain.FolderDelete{Confirm: c.Query("confirm")}
+		if value := c.Query("recursive"); value != "" {
+			input.Recursive, err = strconv.ParseBool(value)
+			if err != nil {
+				response.Error(c, http.StatusBadRequest, "invalid recursive")
+				return
+			}
+		}
+
+		if err := h.folderService.DeleteFolder(c.Request.Context(), ownerID, c.Param("id"), input); err != nil {
+			respondError(c, err, "failed to delete folder")
+			return
+		}
+
+		response.Success(c, http.StatusNoContent, nil)
+	}
+}
+
+// ファイルカタログに登録されているファイルの一覧をフォルダとタグで絞り込んで取得する
+// @Summary      List files
+// @Description  List the files the authenticated teacher uploaded, with their original names, folders and tags. Filter by folder_id (use "root" for files outside any folder) and by tag; files must carry every given tag.
+// @Tags         files
+// @Produce      json
+// @Security     BearerAuth
+// @Param        folder_id query string false "Folder ID, or root for files outside any folder"
+// @Param        tag query []string false "Tags the files must all carry" collectionFormat(multi)
+// @Success      200  {object}  response.Response{data=[]domain.File}
+// @Failure      400  {object}  response.Response "Invalid tag"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Folder belongs to another teacher"
+// @Failure      404  {object}  response.Response "Folder not found"
+// @Router       /api/v1/files [get]
+func (h *FolderHandler) ListFiles() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ownerID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		filter := domain.FileFilter{Tags: c.QueryArray("tag")}
+		if folderID := c.Query("folder_id"); folderID == unfiledFolderID {
+			filter.Unfiled = true
+		} else {
+			filter.FolderID = folderID
+		}
+
+		files, err := h.folderService.ListFiles(c.Request.Context(), ownerID, filter)
+		if err != nil {
+			respondError(c, err, "failed to list files")
+			return
+		}
+		for i := range files {
+			files[i].URL = fileDownloadURL(files[i].ID)
+		}
+
+		response.Success(c, http.StatusOK, files)
+	}
+}
+
+// ファイルを別のフォルダへ移動する
+// カタログのみを更新し、ファイルの内容は再アップロードしない
+// @Summary      Move a file to a folder
+// @Description  Move a file uploaded by the teacher into one of their folders. A null folder_id moves it out of any folder. Only the catalog is updated; the stored bytes are not copied.
+// @Tags         files
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "File ID"
+// @Param        move body domain.FileMove true "Destination folder"
+// @Success      200  {object}  response.Response{data=domain.File}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "File or folder belongs to another teacher"
+// @Failure      404  {object}  response.Response "File or folder not found"
+// @Router       /api/v1/files/{id}/folder [put]
+func (h *FolderHandler) MoveFile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ownerID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		var input domain.FileMove
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		file, err := h.folderService.MoveFile(c.Request.Context(), ownerID, c.Param("id"), &input)
+		if err != nil {
+			respondError(c, err, "failed to move file")
+			return
+		}
+		file.URL = fileDownloadURL(file.ID)
+
+		response.Success(c, http.StatusOK, file)
+	}
+}
+
+// ファイルのタグを置き換える
+// @Summary      Set file tags
+// @Description  Replace the tags of a file uploaded by the teacher. Tags are trimmed, lower-cased and deduplicated; an empty list removes all tags.
+// @Tags         files
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "File ID"
+// @Param        tags body domain.FileTagsUpdate true "New tags"
+// @Success      200  {object}  response.Response{data=domain.File}
+// @Failure      400  {object}  response.Response "Invalid tags"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "File belongs to another teacher"
+// @Failure      404  {object}  response.Response "File not found"
+// @Router       /api/v1/files/{id}/tags [put]
+func (h *FolderHandler) SetFileTags() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ownerID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		var input domain.FileTagsUpdate
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		file, err := h.folderService.SetFileTags(c.Request.Context(), ownerID, c.Param("id"), &input)
+		if err != nil {
+			respondError(c, err, "failed to set file tags")
+			return
+		}
+		file.URL = fileDownloadURL(file.ID)
+
+		response.Success(c, http.StatusOK, file)
+	}
+}
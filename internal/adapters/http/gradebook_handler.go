@@ -0,0 +1,231 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+	"github.com/OICjangirrahul/students/internal/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// 成績簿ハンドラー構造体：成績の記録と参照に関するHTTPリクエストを処理
+type GradebookHandler struct {
+	// 成績簿サービスインターフェース
+	gradebookService ports.GradebookService
+}
+
+// 新しい成績簿ハンドラーインスタンスを作成する
+func NewGradebookHandler(gradebookService ports.GradebookService) *GradebookHandler {
+	return &GradebookHandler{
+		gradebookService: gradebookService,
+	}
+}
+
+// 教師の成績簿を取得する
+// @Summary      Get teacher's gradebook
+// @Description  Get categories, grade scale and computed grades of every student assigned to the teacher
+// @Tags         gradebook
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Success      200  {object}  response.Response{data=domain.Gradebook}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Forbidden - Teacher role required or not the authenticated teacher"
+// @Router       /api/v1/teachers/{id}/gradebook [get]
+func (h *GradebookHandler) GetGradebook() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		gradebook, err := h.gradebookService.GetGradebook(c.Request.Context(), teacherID)
+		if err != nil {
+			respondError(c, err, "failed to get gradebook")
+			return
+		}
+
+		response.Success(c, http.StatusOK, gradebook)
+	}
+}
+
+// 成績カテゴリを作成する
+// @Summary      Create a grade category
+// @Description  Create a weighted grade category (e.g. homework 30%) with an optional drop-lowest rule
+// @Tags         gradebook
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        category body domain.GradeCategoryCreate true "Category to create"
+// @Success      201  {object}  response.Response{data=domain.GradeCategory}
+// @Failure      400  {object}  response.Response "Validation error or weights exceed 100%"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      409  {object}  response.Response "Category already exists"
+// @Router       /api/v1/teachers/{id}/gradebook/categories [post]
+func (h *GradebookHandler) CreateCategory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		// リクエストボディからカテゴリデータを取得
+		var input domain.GradeCategoryCreate
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		category, err := h.gradebookService.CreateCategory(c.Request.Context(), teacherID, &input)
+		if err != nil {
+			respondError(c, err, "failed to create grade category")
+			return
+		}
+
+		response.Success(c, http.StatusCreated, category)
+	}
+}
+
+// 成績カテゴリ一覧を取得する
+// @Summary      List grade categories
+// @Description  List the teacher's weighted grade categories
+// @Tags         gradebook
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Success      200  {object}  response.Response{data=[]domain.GradeCategory}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Router       /api/v1/teachers/{id}/gradebook/categories [get]
+func (h *GradebookHandler) ListCategories() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		categories, err := h.gradebookService.ListCategories(c.Request.Context(), teacherID)
+		if err != nil {
+			respondError(c, err, "failed to list grade categories")
+			return
+		}
+
+		response.Success(c, http.StatusOK, categories)
+	}
+}
+
+// 学生の得点を記録する
+// @Summary      Record a score
+// @Description  Record a student's score. Scores for the same assignment are overwritten.
+// @Tags         gradebook
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        score body domain.GradeEntryInput true "Score to record"
+// @Success      200  {object}  response.Response{data=domain.GradeEntry}
+// @Failure      400  {object}  response.Response "Validation error"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Category, assignment or student does not belong to the teacher"
+// @Router       /api/v1/teachers/{id}/gradebook/scores [put]
+func (h *GradebookHandler) RecordScore() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		// リクエストボディから成績データを取得
+		var input domain.GradeEntryInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		entry, err := h.gradebookService.RecordScore(c.Request.Context(), teacherID, &input)
+		if err != nil {
+			respondError(c, err, "failed to record score")
+			return
+		}
+
+		response.Success(c, http.StatusOK, entry)
+	}
+}
+
+// 評定基準を設定する
+// @Summary      Set the grade scale
+// @Description  Replace the teacher's letter grade scale. One grade must start at 0%.
+// @Tags         gradebook
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        scale body []domain.GradeThreshold true "Letter grade thresholds"
+// @Success      200  {object}  response.Response{data=[]domain.GradeThreshold}
+// @Failure      400  {object}  response.Response "Invalid scale"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Router       /api/v1/teachers/{id}/gradebook/scale [put]
+func (h *GradebookHandler) SetScale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		// リクエストボディから評定基準を取得
+		var scale []domain.GradeThreshold
+		if err := c.ShouldBindJSON(&scale); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		saved, err := h.gradebookService.SetScale(c.Request.Context(), teacherID, scale)
+		if err != nil {
+			respondError(c, err, "failed to set grade scale")
+			return
+		}
+
+		response.Success(c, http.StatusOK, saved)
+	}
+}
+
+// ログイン中の学生の成績を取得する
+// @Summary      Get my grades
+// @Description  Get the logged-in student's computed grades for every teacher
+// @Tags         gradebook
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response{data=[]domain.StudentGrade}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Forbidden - Student role required"
+// @Router       /api/v1/me/grades [get]
+func (h *GradebookHandler) MyGrades() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// トークンから学生IDを取得
+		studentID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		grades, err := h.gradebookService.GetStudentGrades(c.Request.Context(), studentID)
+		if err != nil {
+			respondError(c, err, "failed to get grades")
+			return
+		}
+
+		response.Success(c, http.StatusOK, grades)
+	}
+}
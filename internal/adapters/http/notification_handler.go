@@ -0,0 +1,74 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/OICjangirrahul/students/internal/core/ports"
+	"github.com/OICjangirrahul/students/internal/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// 通知ハンドラー構造体：ユーザーへのアプリ内の通知とファイルの隔離に関するHTTPリクエストを処理
+type NotificationHandler struct {
+	// 通知サービスインターフェース
+	notificationService ports.NotificationService
+	// コンテンツ検査サービスインターフェース
+	scanService ports.ContentScanService
+}
+
+// 新しい通知ハンドラーインスタンスを作成する
+func NewNotificationHandler(notificationService ports.NotificationService, scanService ports.ContentScanService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		scanService:         scanService,
+	}
+}
+
+// ログイン中のユーザーへの通知を取得する
+// @Summary      List my notifications
+// @Description  List the notifications for the authenticated user, newest first (at most 100), such as uploads quarantined by the malware scan
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response{data=[]domain.Notification}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Router       /api/v1/me/notifications [get]
+func (h *NotificationHandler) MyNotifications() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		notifications, err := h.notificationService.ListNotifications(c.Request.Context(), currentUserRole(c), userID)
+		if err != nil {
+			respondError(c, err, "failed to list notifications")
+			return
+		}
+
+		response.Success(c, http.StatusOK, notifications)
+	}
+}
+
+// 隔離されたファイルの一覧を取得する
+// @Summary      List quarantined files
+// @Description  List files whose contents were found to contain malware by the upload scan. They cannot be downloaded and are hidden from file listings. Administrators only.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response{data=[]domain.File}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Not an administrator"
+// @Router       /api/v1/admin/quarantine [get]
+func (h *NotificationHandler) ListQuarantinedFiles() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		files, err := h.scanService.ListQuarantinedFiles(c.Request.Context())
+		if err != nil {
+			respondError(c, err, "failed to list quarantined files")
+			return
+		}
+
+		response.Success(c, http.StatusOK, files)
+	}
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/OICjangirrahul/students/internal/core/ports"
+	"github.com/OICjangirrahul/students/internal/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// 問題バンクハンドラー構造体：問題バンクとQTIパッケージの取り込み・書き出しに関するHTTPリクエストを処理
+type QuestionBankHandler struct {
+	// 問題バンクサービスインターフェース
+	questionBankService ports.QuestionBankService
+}
+
+// 新しい問題バンクハンドラーインスタンスを作成する
+func NewQuestionBankHandler(questionBankService ports.QuestionBankService) *QuestionBankHandler {
+	return &QuestionBankHandler{
+		questionBankService: questionBankService,
+	}
+}
+
+// 教師の問題バンクの項目一覧を取得する
+// @Summary      List question bank items
+// @Description  List the questions in the teacher's question bank
+// @Tags         question-bank
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Success      200  {object}  response.Response{data=[]domain.QuestionBankItem}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Router       /api/v1/teachers/{id}/question-bank [get]
+func (h *QuestionBankHandler) ListItems() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		items, err := h.questionBankService.ListItems(c.Request.Context(), teacherID)
+		if err != nil {
+			respondError(c, err, "failed to list question bank items")
+			return
+		}
+
+		response.Success(c, http.StatusOK, items)
+	}
+}
+
+// 教師の問題バンクから項目を削除する
+// @Summary      Delete a question bank item
+// @Description  Remove a question from the teacher's question bank
+// @Tags         question-bank
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        itemId path string true "Question bank item ID"
+// @Success      204
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Item belongs to another teacher"
+// @Failure      404  {object}  response.Response "Item not found"
+// @Router       /api/v1/teachers/{id}/question-bank/{itemId} [delete]
+func (h *QuestionBankHandler) DeleteItem() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		if err := h.questionBankService.DeleteItem(c.Request.Context(), teacherID, c.Param("itemId")); err != nil {
+			respondError(c, err, "failed to delete question bank item")
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// QTI 2.1パッケージを問題バンクに取り込む
+// @Summary      Import a QTI package
+// @Description  Import a QTI 2.1 zip package into the teacher's question bank. choiceInteraction and textEntryInteraction items are imported; other interaction types are reported as unsupported.
+// @Tags         question-bank
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        file formData file true "QTI 2.1 zip package"
+// @Success      201  {object}  response.Response{data=domain.QTIImportResult}
+// @Failure      400  {object}  response.Response "Not a QTI package"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Router       /api/v1/teachers/{id}/question-bank/import [post]
+func (h *QuestionBankHandler) ImportQTI() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		// マルチパートフォームからパッケージを取得
+		header, err := c.FormFile("file")
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid file")
+			return
+		}
+		upload, closer, err := openFormFile(header)
+		if err != nil {
+			response.Error(c, http.StatusInternalServerError, "failed to read file")
+			return
+		}
+		defer closer.Close()
+
+		result, err := h.questionBankService.ImportQTI(c.Request.Context(), teacherID, upload)
+		if err != nil {
+			respondError(c, err, "failed to import QTI package")
+			return
+		}
+
+		response.Success(c, http.StatusCreated, result)
+	}
+}
+
+// 問題バンクをQTI 2.1パッケージとして書き出す
+// @Summary      Export a QTI package
+// @Description  Download the teacher's question bank as a QTI 2.1 zip package. Pass comma-separated item IDs to export a subset.
+// @Tags         question-bank
+// @Produce      application/zip
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        ids query string false "Comma-separated question bank item IDs"
+// @Success      200
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Item belongs to another teacher"
+// @Failure      404  {object}  response.Response "Item not found or question bank is empty"
+// @Router       /api/v1/teachers/{id}/question-bank/export [get]
+func (h *QuestionBankHandler) ExportQTI() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		// クエリパラメータから書き出す項目のIDを取得
+		var itemIDs []string
+		for _, id := range strings.Split(c.Query("ids"), ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				itemIDs = append(itemIDs, id)
+			}
+		}
+
+		data, err := h.questionBankService.ExportQTI(c.Request.Context(), teacherID, itemIDs)
+		if err != nil {
+			respondError(c, err, "failed to export QTI package")
+			return
+		}
+
+		c.Header("Content-Disposition", "attachment; filename=question-bank-qti.zip")
+		c.Data(http.StatusOK, "application/zip", data)
+	}
+}
@@ -0,0 +1,294 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+	"github.com/OICjangirrahul/students/internal/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// 小テストハンドラー構造体：小テストの作成、受験、採点結果に関するHTTPリクエストを処理
+type QuizHandler struct {
+	// 小テストサービスインターフェース
+	quizService ports.QuizService
+}
+
+// 新しい小テストハンドラーインスタンスを作成する
+func NewQuizHandler(quizService ports.QuizService) *QuizHandler {
+	return &QuizHandler{
+		quizService: quizService,
+	}
+}
+
+// 教師の小テストを作成する
+// @Summary      Create a quiz
+// @Description  Create a quiz with typed questions (multiple_choice, multi_select, true_false, numeric, short_text). Results are recorded in the given grade category.
+// @Tags         quizzes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        quiz body domain.QuizCreate true "Quiz to create"
+// @Success      201  {object}  response.Response{data=domain.Quiz}
+// @Failure      400  {object}  response.Response "Invalid question or answer key"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Grade category belongs to another teacher"
+// @Router       /api/v1/teachers/{id}/quizzes [post]
+func (h *QuizHandler) CreateQuiz() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		// リクエストボディから小テストデータを取得
+		var input domain.QuizCreate
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		quiz, err := h.quizService.CreateQuiz(c.Request.Context(), teacherID, &input)
+		if err != nil {
+			respondError(c, err, "failed to create quiz")
+			return
+		}
+
+		response.Success(c, http.StatusCreated, quiz)
+	}
+}
+
+// 教師が作成した小テスト一覧を取得する
+// @Summary      List teacher's quizzes
+// @Description  List the quizzes created by the teacher, including answer keys
+// @Tags         quizzes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Success      200  {object}  response.Response{data=[]domain.Quiz}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Router       /api/v1/teachers/{id}/quizzes [get]
+func (h *QuizHandler) ListByTeacher() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		quizzes, err := h.quizService.ListQuizzesByTeacher(c.Request.Context(), teacherID)
+		if err != nil {
+			respondError(c, err, "failed to list quizzes")
+			return
+		}
+
+		response.Success(c, http.StatusOK, quizzes)
+	}
+}
+
+// 教師が作成した小テストを取得する
+// @Summary      Get a quiz
+// @Description  Get one of the teacher's quizzes, including the answer key
+// @Tags         quizzes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        quizId path string true "Quiz ID"
+// @Success      200  {object}  response.Response{data=domain.Quiz}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Quiz belongs to another teacher"
+// @Failure      404  {object}  response.Response "Quiz not found"
+// @Router       /api/v1/teachers/{id}/quizzes/{quizId} [get]
+func (h *QuizHandler) GetQuiz() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		quiz, err := h.quizService.GetQuiz(c.Request.Context(), teacherID, c.Param("quizId"))
+		if err != nil {
+			respondError(c, err, "failed to get quiz")
+			return
+		}
+
+		response.Success(c, http.StatusOK, quiz)
+	}
+}
+
+// 小テストの全学生の受験記録を取得する
+// @Summary      List quiz attempts
+// @Description  List every student's attempts of one of the teacher's quizzes
+// @Tags         quizzes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        quizId path string true "Quiz ID"
+// @Success      200  {object}  response.Response{data=[]domain.QuizAttempt}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Quiz belongs to another teacher"
+// @Router       /api/v1/teachers/{id}/quizzes/{quizId}/attempts [get]
+func (h *QuizHandler) ListAttempts() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		attempts, err := h.quizService.ListAttempts(c.Request.Context(), teacherID, c.Param("quizId"))
+		if err != nil {
+			respondError(c, err, "failed to list quiz attempts")
+			return
+		}
+
+		response.Success(c, http.StatusOK, attempts)
+	}
+}
+
+// 担当教師の小テスト一覧を取得する
+// @Summary      List available quizzes
+// @Description  List quizzes created by the logged-in student's teachers
+// @Tags         quizzes
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response{data=[]domain.QuizSummary}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Forbidden - Student role required"
+// @Router       /api/v1/quizzes [get]
+func (h *QuizHandler) ListForStudent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// トークンから学生IDを取得
+		studentID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		quizzes, err := h.quizService.ListQuizzesForStudent(c.Request.Context(), studentID)
+		if err != nil {
+			respondError(c, err, "failed to list quizzes")
+			return
+		}
+
+		response.Success(c, http.StatusOK, quizzes)
+	}
+}
+
+// 小テストの受験を開始する
+// @Summary      Start a quiz attempt
+// @Description  Start an attempt, or resume the attempt in progress. Questions are returned in the attempt's order without answers.
+// @Tags         quizzes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Quiz ID"
+// @Success      201  {object}  response.Response{data=domain.QuizAttemptSession}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Attempt limit reached or quiz is not available"
+// @Failure      404  {object}  response.Response "Quiz not found"
+// @Router       /api/v1/quizzes/{id}/attempts [post]
+func (h *QuizHandler) StartAttempt() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// トークンから学生IDを取得
+		studentID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		session, err := h.quizService.StartAttempt(c.Request.Context(), studentID, c.Param("id"))
+		if err != nil {
+			respondError(c, err, "failed to start quiz attempt")
+			return
+		}
+
+		response.Success(c, http.StatusCreated, session)
+	}
+}
+
+// 小テストの回答を提出する
+// @Summary      Submit a quiz attempt
+// @Description  Submit answers for an attempt. The attempt is scored immediately and the best score is written to the gradebook.
+// @Tags         quizzes
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Quiz ID"
+// @Param        attemptId path int true "Attempt ID"
+// @Param        submission body domain.QuizSubmission true "Answers"
+// @Success      200  {object}  response.Response{data=domain.QuizAttempt}
+// @Failure      400  {object}  response.Response "Attempt already finished or unknown question"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Attempt belongs to another student"
+// @Router       /api/v1/quizzes/{id}/attempts/{attemptId}/submit [post]
+func (h *QuizHandler) SubmitAttempt() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// トークンから学生IDを取得
+		studentID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		attemptID, err := strconv.ParseInt(c.Param("attemptId"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid attempt id")
+			return
+		}
+
+		// リクエストボディから回答を取得
+		var input domain.QuizSubmission
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if input.Answers == nil {
+			input.Answers = []domain.QuizAnswer{}
+		}
+
+		attempt, err := h.quizService.SubmitAttempt(c.Request.Context(), studentID, c.Param("id"), attemptID, input.Answers)
+		if err != nil {
+			respondError(c, err, "failed to submit quiz attempt")
+			return
+		}
+
+		response.Success(c, http.StatusOK, attempt)
+	}
+}
+
+// 自分の小テストの受験記録を取得する
+// @Summary      List my quiz attempts
+// @Description  List the logged-in student's attempts of a quiz with scores
+// @Tags         quizzes
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Quiz ID"
+// @Success      200  {object}  response.Response{data=[]domain.QuizAttempt}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Router       /api/v1/quizzes/{id}/attempts [get]
+func (h *QuizHandler) ListMyAttempts() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// トークンから学生IDを取得
+		studentID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		attempts, err := h.quizService.ListMyAttempts(c.Request.Context(), studentID, c.Param("id"))
+		if err != nil {
+			respondError(c, err, "failed to list quiz attempts")
+			return
+		}
+
+		response.Success(c, http.StatusOK, attempts)
+	}
+}
@@ -0,0 +1,311 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+	"github.com/OICjangirrahul/students/internal/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// 通知表ハンドラー構造体：通知表の作成とダウンロードに関するHTTPリクエストを処理
+type ReportCardHandler struct {
+	// 通知表サービスインターフェース
+	reportCardService ports.ReportCardService
+}
+
+// 新しい通知表ハンドラーインスタンスを作成する
+func NewReportCardHandler(reportCardService ports.ReportCardService) *ReportCardHandler {
+	return &ReportCardHandler{
+		reportCardService: reportCardService,
+	}
+}
+
+// 学生の所見（コメントと出欠）を記入する
+// @Summary      Save report card remarks
+// @Description  Save the teacher's comment and attendance days for a student in a term. Existing remarks are overwritten.
+// @Tags         report-cards
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        remark body domain.ReportCardRemarkInput true "Comment and attendance"
+// @Success      200  {object}  response.Response{data=domain.ReportCardRemark}
+// @Failure      400  {object}  response.Response "Validation error"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Student is not assigned to the teacher"
+// @Router       /api/v1/teachers/{id}/report-cards/remarks [put]
+func (h *ReportCardHandler) SaveRemark() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		// リクエストボディから所見データを取得
+		var input domain.ReportCardRemarkInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		remark, err := h.reportCardService.SaveRemark(c.Request.Context(), teacherID, &input)
+		if err != nil {
+			respondError(c, err, "failed to save report card remark")
+			return
+		}
+
+		response.Success(c, http.StatusOK, remark)
+	}
+}
+
+// 担当学生全員分の通知表を生成する
+// @Summary      Generate report cards for the class
+// @Description  Generate report card PDFs for every student assigned to the teacher. Students that fail are listed in the result.
+// @Tags         report-cards
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        request body domain.ReportCardGenerate true "Term to generate"
+// @Success      201  {object}  response.Response{data=domain.ReportCardBatch}
+// @Failure      400  {object}  response.Response "Validation error"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Router       /api/v1/teachers/{id}/report-cards [post]
+func (h *ReportCardHandler) GenerateForClass() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		// リクエストボディから学期を取得
+		var input domain.ReportCardGenerate
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		batch, err := h.reportCardService.GenerateForClass(c.Request.Context(), teacherID, input.Term)
+		if err != nil {
+			respondError(c, err, "failed to generate report cards")
+			return
+		}
+
+		for i := range batch.Generated {
+			batch.Generated[i].DownloadURL = teacherReportCardURL(teacherID, batch.Generated[i].ID)
+		}
+		response.Success(c, http.StatusCreated, batch)
+	}
+}
+
+// 学生1人分の通知表を生成する
+// @Summary      Generate a student's report card
+// @Description  Generate the report card PDF of one student assigned to the teacher
+// @Tags         report-cards
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        studentId path int true "Student ID"
+// @Param        request body domain.ReportCardGenerate true "Term to generate"
+// @Success      201  {object}  response.Response{data=domain.ReportCardFile}
+// @Failure      400  {object}  response.Response "Validation error"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Student is not assigned to the teacher"
+// @Router       /api/v1/teachers/{id}/report-cards/students/{studentId} [post]
+func (h *ReportCardHandler) Generate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDと学生IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+		studentID, err := strconv.ParseInt(c.Param("studentId"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid student id")
+			return
+		}
+
+		// リクエストボディから学期を取得
+		var input domain.ReportCardGenerate
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		card, err := h.reportCardService.Generate(c.Request.Context(), teacherID, studentID, input.Term)
+		if err != nil {
+			respondError(c, err, "failed to generate report card")
+			return
+		}
+
+		card.DownloadURL = teacherReportCardURL(teacherID, card.ID)
+		response.Success(c, http.StatusCreated, card)
+	}
+}
+
+// 担当学生の生成済み通知表一覧を取得する
+// @Summary      List report cards
+// @Description  List generated report cards of the teacher's students with download links
+// @Tags         report-cards
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        term query string false "Term"
+// @Success      200  {object}  response.Response{data=[]domain.ReportCardFile}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Router       /api/v1/teachers/{id}/report-cards [get]
+func (h *ReportCardHandler) ListByTeacher() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+
+		cards, err := h.reportCardService.ListByTeacher(c.Request.Context(), teacherID, c.Query("term"))
+		if err != nil {
+			respondError(c, err, "failed to list report cards")
+			return
+		}
+
+		for i := range cards {
+			cards[i].DownloadURL = teacherReportCardURL(teacherID, cards[i].ID)
+		}
+		response.Success(c, http.StatusOK, cards)
+	}
+}
+
+// 担当学生の通知表PDFをダウンロードする
+// @Summary      Download a report card
+// @Description  Download the report card PDF of a student assigned to the teacher
+// @Tags         report-cards
+// @Produce      application/pdf
+// @Security     BearerAuth
+// @Param        id path int true "Teacher ID"
+// @Param        reportCardId path int true "Report card ID"
+// @Success      200
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Student is not assigned to the teacher"
+// @Failure      404  {object}  response.Response "Report card not found"
+// @Router       /api/v1/teachers/{id}/report-cards/{reportCardId}/download [get]
+func (h *ReportCardHandler) DownloadForTeacher() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータから教師IDと通知表IDを取得
+		teacherID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid teacher id")
+			return
+		}
+		reportCardID, err := strconv.ParseInt(c.Param("reportCardId"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid report card id")
+			return
+		}
+
+		file, body, err := h.reportCardService.DownloadForTeacher(c.Request.Context(), teacherID, reportCardID)
+		if err != nil {
+			respondError(c, err, "failed to download report card")
+			return
+		}
+
+		sendReportCard(c, reportCardID, file, body)
+	}
+}
+
+// ログイン中の学生の通知表一覧を取得する
+// @Summary      List my report cards
+// @Description  List the logged-in student's generated report cards with download links
+// @Tags         report-cards
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response{data=[]domain.ReportCardFile}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Forbidden - Student role required"
+// @Router       /api/v1/me/report-cards [get]
+func (h *ReportCardHandler) ListForStudent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// トークンから学生IDを取得
+		studentID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		cards, err := h.reportCardService.ListForStudent(c.Request.Context(), studentID)
+		if err != nil {
+			respondError(c, err, "failed to list report cards")
+			return
+		}
+
+		for i := range cards {
+			cards[i].DownloadURL = studentReportCardURL(cards[i].ID)
+		}
+		response.Success(c, http.StatusOK, cards)
+	}
+}
+
+// ログイン中の学生の通知表PDFをダウンロードする
+// @Summary      Download my report card
+// @Description  Download one of the logged-in student's report card PDFs
+// @Tags         report-cards
+// @Produce      application/pdf
+// @Security     BearerAuth
+// @Param        reportCardId path int true "Report card ID"
+// @Success      200
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Report card belongs to another student"
+// @Failure      404  {object}  response.Response "Report card not found"
+// @Router       /api/v1/me/report-cards/{reportCardId}/download [get]
+func (h *ReportCardHandler) DownloadForStudent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// トークンから学生IDを取得
+		studentID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		reportCardID, err := strconv.ParseInt(c.Param("reportCardId"), 10, 64)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid report card id")
+			return
+		}
+
+		file, body, err := h.reportCardService.DownloadForStudent(c.Request.Context(), studentID, reportCardID)
+		if err != nil {
+			respondError(c, err, "failed to download report card")
+			return
+		}
+
+		sendReportCard(c, reportCardID, file, body)
+	}
+}
+
+// 教師向けの通知表ダウンロードURLを返す
+func teacherReportCardURL(teacherID, reportCardID int64) string {
+	return fmt.Sprintf("/api/v1/teachers/%d/report-cards/%d/download", teacherID, reportCardID)
+}
+
+// 学生向けの通知表ダウンロードURLを返す
+func studentReportCardURL(reportCardID int64) string {
+	return fmt.Sprintf("/api/v1/me/report-cards/%d/download", reportCardID)
+}
+
+// 通知表PDFをクライアントに送信する
+func sendReportCard(c *gin.Context, reportCardID int64, file *domain.File, body io.ReadCloser) {
+	defer body.Close()
+	c.DataFromReader(http.StatusOK, file.Size, "application/pdf", body, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=report-card-%d.pdf", reportCardID),
+	})
+}
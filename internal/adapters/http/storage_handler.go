@@ -1,9 +1,17 @@
 package http
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"strconv"
+	"time"
 
 	"github.com/OICjangirrahul/students/internal/core/domain"
 	"github.com/OICjangirrahul/students/internal/core/ports"
@@ -16,17 +24,37 @@ import (
 type StorageHandler struct {
 	// ファイルストレージインターフェース
 	fileStorage ports.FileStorage
+	// ファイル転送サービスインターフェース（署名付きURLによる直接転送）
+	fileTransfer ports.FileTransferService
+	// バージョン管理ファイルストレージインターフェース（ファイルストレージが対応していない場合はnil）
+	fileVersions ports.VersionedFileStorage
 	// ドキュメントストレージインターフェース
 	documentStorage ports.DocumentStorage
+	// ドキュメントサービスインターフェース（スキーマによる検証を伴う作成と更新）
+	documents ports.DocumentService
+	// ゴミ箱サービスインターフェース
+	trash ports.TrashService
+	// ファイル共有サービスインターフェース（共有されたファイルのダウンロード権限の確認に使用）
+	sharing ports.FileSharingService
+	// サムネイルサービスインターフェース（画像ファイルのサムネイルの取得）
+	thumbnails ports.ThumbnailService
 	// バリデーター
 	validator *validator.Validate
 }
 
 // 新しいストレージハンドラーを作成する関数
-func NewStorageHandler(fileStorage ports.FileStorage, documentStorage ports.DocumentStorage) *StorageHandler {
+// ファイルストレージがports.VersionedFileStorageを実装していない場合、バージョン関連の操作は利用できない
+func NewStorageHandler(fileStorage ports.FileStorage, fileTransfer ports.FileTransferService, documentStorage ports.DocumentStorage, documents ports.DocumentService, trash ports.TrashService, sharing ports.FileSharingService, thumbnails ports.ThumbnailService) *StorageHandler {
+	fileVersions, _ := fileStorage.(ports.VersionedFileStorage)
 	return &StorageHandler{
 		fileStorage:     fileStorage,
+		fileTransfer:    fileTransfer,
+		fileVersions:    fileVersions,
 		documentStorage: documentStorage,
+		documents:       documents,
+		trash:           trash,
+		sharing:         sharing,
+		thumbnails:      thumbnails,
 		validator:       validator.New(),
 	}
 }
@@ -46,114 +74,408 @@ func NewStorageHandler(fileStorage ports.FileStorage, documentStorage ports.Docu
 // @Router       /api/v1/files [post]
 func (h *StorageHandler) UploadFile() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// マルチパートフォームからファイルを取得
-		file, header, err := c.Request.FormFile("file")
+		// アップロードしたユーザーをトークンから取得
+		uploaderID, err := currentUserID(c)
 		if err != nil {
-			response.Error(c, http.StatusBadRequest, "invalid file")
+			response.Error(c, http.StatusUnauthorized, err.Error())
 			return
 		}
-		defer file.Close()
 
-		// ファイルデータを読み込む
-		data, err := io.ReadAll(file)
+		// マルチパートのファイルのパートをそのまま転送する
+		uploadFile, closer, err := multipartUpload(c, uploaderID)
 		if err != nil {
-			response.Error(c, http.StatusInternalServerError, "failed to read file")
+			response.Error(c, http.StatusBadRequest, "invalid file")
 			return
 		}
+		defer closer.Close()
 
-		// アップロード用のファイル構造体を作成
-		uploadFile := &domain.FileUpload{
-			Name:        header.Filename,
-			ContentType: header.Header.Get("Content-Type"),
-			Data:        data,
-		}
-
-		// ファイルをS3にアップロード
+		// ファイルをストレージに保存し、カタログに登録
 		result, err := h.fileStorage.Upload(c.Request.Context(), uploadFile)
 		if err != nil {
-			slog.Error("failed to upload file", slog.String("error", err.Error()))
-			response.Error(c, http.StatusInternalServerError, "failed to upload file")
+			respondError(c, err, "failed to upload file")
 			return
 		}
 
+		result.URL = fileDownloadURL(result.ID)
 		response.Success(c, http.StatusCreated, result)
 	}
 }
 
 // S3からファイルをダウンロードする機能を提供するハンドラー
 // ファイルIDを受け取り、対応するファイルをダウンロードする
+// Rangeヘッダーによる部分取得（複数範囲を含む）と、ETag・Last-Modifiedによる条件付き取得に対応する
 // @Summary      Download a file from S3
-// @Description  Download a file from S3 storage
+// @Description  Download a file from S3 storage with its original file name. Supports single and multi-range requests (206 Partial Content) and conditional requests via If-None-Match / If-Modified-Since (304 Not Modified).
 // @Tags         files
 // @Produce      octet-stream
 // @Security     BearerAuth
 // @Param        id path string true "File ID"
+// @Param        Range header string false "Byte ranges, e.g. bytes=0-1023"
+// @Param        If-None-Match header string false "ETag of the cached copy"
+// @Param        If-Modified-Since header string false "Date of the cached copy"
 // @Success      200
+// @Success      206
+// @Success      304
 // @Failure      401  {object}  response.Response "Unauthorized"
-// @Failure      403  {object}  response.Response "Forbidden - Teacher role required"
+// @Failure      403  {object}  response.Response "Teacher role required, or the file is neither the teacher's upload nor a submission to their assignment"
+// @Failure      404  {object}  response.Response "File not found"
+// @Failure      416  {object}  response.Response "Range not satisfiable"
 // @Router       /api/v1/files/{id} [get]
 func (h *StorageHandler) DownloadFile() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// パスパラメータからファイルIDを取得
+		// 自身がアップロードしたファイルと、自身の課題への提出物のみダウンロードできる
+		id, ok := h.authorizeTeacher(c, "failed to download file")
+		if !ok {
+			return
+		}
+		h.sendFile(c, id)
+	}
+}
+
+// 学生と共有されているファイルをダウンロードする機能を提供するハンドラー
+// 学生個人または担当学生全員への有効な共有がある場合のみダウンロードできる
+// @Summary      Download a file shared with me
+// @Description  Download a file that a teacher shared with the authenticated student, either directly or with all of the teacher's students. Supports the same range and conditional requests as the teacher download.
+// @Tags         files
+// @Produce      octet-stream
+// @Security     BearerAuth
+// @Param        id path string true "File ID"
+// @Param        Range header string false "Byte ranges, e.g. bytes=0-1023"
+// @Success      200
+// @Success      206
+// @Success      304
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "File is not shared with the student"
+// @Failure      404  {object}  response.Response "File not found"
+// @Router       /api/v1/me/files/{id} [get]
+func (h *StorageHandler) DownloadSharedFile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		studentID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
 		id := c.Param("id")
-		// S3からファイルをダウンロード
-		file, data, err := h.fileStorage.Download(c.Request.Context(), id)
+		if err := h.sharing.AuthorizeStudent(c.Request.Context(), studentID, id); err != nil {
+			respondError(c, err, "failed to download file")
+			return
+		}
+
+		h.sendFile(c, id)
+	}
+}
+
+// 課題の添付ファイルをダウンロードする機能を提供するハンドラー
+// 担当教師の課題に添付されたファイルのみダウンロードできる
+// @Summary      Download an assignment attachment
+// @Description  Download a file attached to an assignment of one of the authenticated student's teachers. Supports the same range and conditional requests as the teacher download.
+// @Tags         assignments
+// @Produce      octet-stream
+// @Security     BearerAuth
+// @Param        id     path int    true "Assignment ID"
+// @Param        fileId path string true "File ID"
+// @Param        Range header string false "Byte ranges, e.g. bytes=0-1023"
+// @Success      200
+// @Success      206
+// @Success      304
+// @Failure      400  {object}  response.Response "Invalid assignment ID"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "File is not attached to an assignment of the student's teacher"
+// @Failure      404  {object}  response.Response "File not found"
+// @Router       /api/v1/assignments/{id}/files/{fileId} [get]
+func (h *StorageHandler) DownloadAssignmentFile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		studentID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		assignmentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 		if err != nil {
-			slog.Error("failed to download file", slog.String("error", err.Error()))
-			response.Error(c, http.StatusInternalServerError, "failed to download file")
+			response.Error(c, http.StatusBadRequest, "invalid assignment id")
+			return
+		}
+
+		id := c.Param("fileId")
+		if err := h.sharing.AuthorizeAssignmentFile(c.Request.Context(), studentID, assignmentID, id); err != nil {
+			respondError(c, err, "failed to download file")
 			return
 		}
 
-		// ファイルをクライアントに送信
-		c.Header("Content-Disposition", "attachment; filename="+file.Name)
-		c.Data(http.StatusOK, file.ContentType, data)
+		h.sendFile(c, id)
 	}
 }
 
-// S3に保存されているファイルの一覧を取得する機能を提供するハンドラー
-// @Summary      List files from S3
-// @Description  List all files from S3 storage
+// 共有リンクのファイルをダウンロードする機能を提供するハンドラー
+// 認証は不要で、有効期限内のリンクの場合のみダウンロードできる
+// @Summary      Download a file through a shared link
+// @Description  Download a file through a public shared link. No authentication is required; expired or revoked links return 404.
 // @Tags         files
+// @Produce      octet-stream
+// @Param        token path string true "Shared link token"
+// @Param        Range header string false "Byte ranges, e.g. bytes=0-1023"
+// @Success      200
+// @Success      206
+// @Success      304
+// @Failure      404  {object}  response.Response "Link not found or expired"
+// @Router       /api/v1/shared/{token} [get]
+func (h *StorageHandler) DownloadLinkedFile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := h.sharing.ResolveLink(c.Request.Context(), c.Param("token"))
+		if err != nil {
+			respondError(c, err, "failed to download file")
+			return
+		}
+
+		h.sendFile(c, id)
+	}
+}
+
+// ファイルをアップロード時の名前でクライアントに送信する
+// Rangeヘッダーによる部分取得と、ETag・Last-Modifiedによる条件付き取得に対応する
+func (h *StorageHandler) sendFile(c *gin.Context, id string) {
+	ctx := c.Request.Context()
+
+	// カタログからファイル情報を取得し、検証用ヘッダーを設定
+	file, err := h.fileStorage.Get(ctx, id)
+	if err != nil {
+		respondError(c, err, "failed to download file")
+		return
+	}
+	setFileValidators(c.Writer.Header(), file)
+
+	// クライアントのキャッシュが有効な場合は本文を送信しない
+	if fileNotModified(c.Request, file) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	ranges, err := requestedRanges(c.Request, file)
+	if err != nil {
+		c.Header("Content-Range", fmt.Sprintf("bytes */%d", file.Size))
+		response.Error(c, http.StatusRequestedRangeNotSatisfiable, "requested range not satisfiable")
+		return
+	}
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": file.Name})
+	switch len(ranges) {
+	case 0:
+		// ファイル全体をアップロード時の名前でクライアントにストリーミング送信
+		_, body, err := h.fileStorage.Download(ctx, id)
+		if err != nil {
+			respondError(c, err, "failed to download file")
+			return
+		}
+		defer body.Close()
+
+		c.DataFromReader(http.StatusOK, file.Size, file.ContentType, body, map[string]string{
+			"Content-Disposition": disposition,
+		})
+	case 1:
+		// 単一の範囲のみをバックエンドから取得して送信
+		_, body, err := h.fileStorage.DownloadRange(ctx, id, ranges[0])
+		if err != nil {
+			respondError(c, err, "failed to download file")
+			return
+		}
+		defer body.Close()
+
+		c.DataFromReader(http.StatusPartialContent, ranges[0].Length, file.ContentType, body, map[string]string{
+			"Content-Disposition": disposition,
+			"Content-Range":       contentRange(ranges[0], file.Size),
+		})
+	default:
+		h.sendFileRanges(c, file, ranges, disposition)
+	}
+}
+
+// 複数の範囲をmultipart/byterangesとして送信する
+// 各範囲は順番にバックエンドから取得してそのまま書き込む
+func (h *StorageHandler) sendFileRanges(c *gin.Context, file *domain.File, ranges []domain.ByteRange, disposition string) {
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	writer := multipart.NewWriter(c.Writer)
+	c.Header("Content-Type", "multipart/byteranges; boundary="+writer.Boundary())
+	c.Header("Content-Disposition", disposition)
+	c.Status(http.StatusPartialContent)
+
+	for _, byteRange := range ranges {
+		if err := h.writeFileRange(c.Request.Context(), writer, file, byteRange, contentType); err != nil {
+			// ステータスは送信済みのため、ログに記録して中断する
+			slog.Error("failed to send file range", slog.String("file_id", file.ID), slog.String("error", err.Error()))
+			return
+		}
+	}
+	if err := writer.Close(); err != nil {
+		slog.Error("failed to send file range", slog.String("file_id", file.ID), slog.String("error", err.Error()))
+	}
+}
+
+// 1つの範囲をmultipart/byterangesのパートとして書き込む
+func (h *StorageHandler) writeFileRange(ctx context.Context, writer *multipart.Writer, file *domain.File, byteRange domain.ByteRange, contentType string) error {
+	_, body, err := h.fileStorage.DownloadRange(ctx, file.ID, byteRange)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":  {contentType},
+		"Content-Range": {contentRange(byteRange, file.Size)},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.CopyN(part, body, byteRange.Length)
+	return err
+}
+
+// ストレージへ直接アップロードするための署名付きURLを発行する機能を提供するハンドラー
+// 返されたURLに指定されたメソッドとヘッダーでファイルを送信した後、完了を通知する必要がある
+// @Summary      Create a presigned upload URL
+// @Description  Create a time-limited presigned URL for uploading a file directly to storage. The upload must use the returned method and headers; the content type and size are enforced by the signature. Call the completion endpoint afterwards to register the file.
+// @Tags         files
+// @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Success      200  {array}   domain.File
+// @Param        request body domain.UploadURLRequest true "File to upload"
+// @Success      201  {object}  response.Response{data=domain.PresignedUpload}
+// @Failure      400  {object}  response.Response "Invalid request"
 // @Failure      401  {object}  response.Response "Unauthorized"
 // @Failure      403  {object}  response.Response "Forbidden - Teacher role required"
-// @Router       /api/v1/files [get]
-func (h *StorageHandler) ListFiles() gin.HandlerFunc {
+// @Failure      501  {object}  response.Response "Storage does not support presigned URLs"
+// @Router       /api/v1/files/upload-url [post]
+func (h *StorageHandler) CreateUploadURL() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// S3からファイル一覧を取得
-		files, err := h.fileStorage.List(c.Request.Context())
+		var request domain.UploadURLRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if err := h.validator.Struct(request); err != nil {
+			response.Error(c, http.StatusBadRequest, "validation failed")
+			return
+		}
+
+		// アップロードするユーザーをトークンから取得
+		uploaderID, err := currentUserID(c)
 		if err != nil {
-			slog.Error("failed to list files", slog.String("error", err.Error()))
-			response.Error(c, http.StatusInternalServerError, "failed to list files")
+			response.Error(c, http.StatusUnauthorized, err.Error())
 			return
 		}
+		request.UploaderID = uploaderID
+		request.UploaderRole = currentUserRole(c)
 
-		response.Success(c, http.StatusOK, files)
+		upload, err := h.fileTransfer.CreateUploadURL(c.Request.Context(), &request)
+		if err != nil {
+			respondError(c, err, "failed to create upload URL")
+			return
+		}
+
+		response.Success(c, http.StatusCreated, upload)
+	}
+}
+
+// 直接アップロードの完了を受け取り、ファイルをカタログに登録する機能を提供するハンドラー
+// @Summary      Complete a presigned upload
+// @Description  Register a file uploaded through a presigned URL in the file catalog
+// @Tags         files
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.UploadCompletion true "Upload to complete"
+// @Success      201  {object}  response.Response{data=domain.File}
+// @Failure      400  {object}  response.Response "Invalid request"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Upload was issued to another user"
+// @Failure      404  {object}  response.Response "Object has not been uploaded"
+// @Failure      409  {object}  response.Response "Upload already completed"
+// @Router       /api/v1/files/upload-url/complete [post]
+func (h *StorageHandler) CompleteUpload() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var completion domain.UploadCompletion
+		if err := c.ShouldBindJSON(&completion); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if err := h.validator.Struct(completion); err != nil {
+			response.Error(c, http.StatusBadRequest, "validation failed")
+			return
+		}
+
+		uploaderID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		file, err := h.fileTransfer.CompleteUpload(c.Request.Context(), uploaderID, currentUserRole(c), completion.UploadID)
+		if err != nil {
+			respondError(c, err, "failed to complete upload")
+			return
+		}
+
+		file.URL = fileDownloadURL(file.ID)
+		response.Success(c, http.StatusCreated, file)
 	}
 }
 
-// S3からファイルを削除する機能を提供するハンドラー
-// ファイルIDを受け取り、対応するファイルを削除する
-// @Summary      Delete a file from S3
-// @Description  Delete a file from S3 storage
+// ストレージから直接ダウンロードするための署名付きURLを発行する機能を提供するハンドラー
+// @Summary      Create a presigned download URL
+// @Description  Create a time-limited presigned URL for downloading a file directly from storage with its original file name
+// @Tags         files
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "File ID"
+// @Success      200  {object}  response.Response{data=domain.PresignedURL}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Teacher role required, or the file is neither the teacher's upload nor a submission to their assignment"
+// @Failure      404  {object}  response.Response "File not found"
+// @Failure      501  {object}  response.Response "Storage does not support presigned URLs"
+// @Router       /api/v1/files/{id}/download-url [get]
+func (h *StorageHandler) CreateDownloadURL() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := h.authorizeTeacher(c, "failed to create download URL")
+		if !ok {
+			return
+		}
+
+		download, err := h.fileTransfer.CreateDownloadURL(c.Request.Context(), id)
+		if err != nil {
+			respondError(c, err, "failed to create download URL")
+			return
+		}
+
+		response.Success(c, http.StatusOK, download)
+	}
+}
+
+// ファイルをゴミ箱に移動する機能を提供するハンドラー
+// ゴミ箱に移動したファイルは一覧から除かれ、保持期間を過ぎると完全に削除される
+// @Summary      Move a file to the trash
+// @Description  Move a file to the trash. Trashed files are hidden from the file list and permanently deleted after the trash retention period; restore them with the restore endpoint.
 // @Tags         files
 // @Security     BearerAuth
 // @Param        id path string true "File ID"
 // @Success      204
 // @Failure      401  {object}  response.Response "Unauthorized"
-// @Failure      403  {object}  response.Response "Forbidden - Teacher role required"
+// @Failure      403  {object}  response.Response "Teacher role required, or the file was uploaded by someone else"
+// @Failure      404  {object}  response.Response "File not found"
 // @Router       /api/v1/files/{id} [delete]
 func (h *StorageHandler) DeleteFile() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// パスパラメータからファイルIDを取得
-		id := c.Param("id")
-		// S3からファイルを削除
-		err := h.fileStorage.Delete(c.Request.Context(), id)
+		teacherID, err := currentUserID(c)
 		if err != nil {
-			slog.Error("failed to delete file", slog.String("error", err.Error()))
-			response.Error(c, http.StatusInternalServerError, "failed to delete file")
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		if err := h.trash.TrashFile(c.Request.Context(), teacherID, c.Param("id")); err != nil {
+			respondError(c, err, "failed to delete file")
 			return
 		}
 
@@ -161,16 +483,348 @@ func (h *StorageHandler) DeleteFile() gin.HandlerFunc {
 	}
 }
 
+// ゴミ箱にあるファイルの一覧を取得する機能を提供するハンドラー
+// @Summary      List trashed files
+// @Description  List the files the authenticated teacher uploaded that are in the trash, most recently trashed first
+// @Tags         files
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response{data=[]domain.File}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Forbidden - Teacher role required"
+// @Router       /api/v1/files/trash [get]
+func (h *StorageHandler) ListTrashedFiles() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		teacherID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		files, err := h.trash.ListTrashedFiles(c.Request.Context(), teacherID)
+		if err != nil {
+			respondError(c, err, "failed to list trashed files")
+			return
+		}
+
+		response.Success(c, http.StatusOK, files)
+	}
+}
+
+// ゴミ箱にあるファイルを元に戻す機能を提供するハンドラー
+// @Summary      Restore a trashed file
+// @Description  Move a file out of the trash
+// @Tags         files
+// @Security     BearerAuth
+// @Param        id path string true "File ID"
+// @Success      204
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Teacher role required, or the file was uploaded by someone else"
+// @Failure      404  {object}  response.Response "File not found in the trash"
+// @Router       /api/v1/files/{id}/restore [post]
+func (h *StorageHandler) RestoreFile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		teacherID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		if err := h.trash.RestoreFile(c.Request.Context(), teacherID, c.Param("id")); err != nil {
+			respondError(c, err, "failed to restore file")
+			return
+		}
+
+		response.Success(c, http.StatusNoContent, nil)
+	}
+}
+
+// 既存のファイルに新しいバージョンをアップロードする機能を提供するハンドラー
+// ファイルIDは変わらず、以前の内容はバージョン履歴に残る
+// @Summary      Upload a new file version
+// @Description  Upload a new version of an existing file. The file keeps its ID; previous contents stay in the version history up to the configured retention.
+// @Tags         files
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "File ID"
+// @Param        file formData file true "New contents"
+// @Success      200  {object}  response.Response{data=domain.File}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Teacher role required, or the file was uploaded by someone else"
+// @Failure      404  {object}  response.Response "File not found"
+// @Failure      501  {object}  response.Response "Storage does not support versions"
+// @Router       /api/v1/files/{id} [put]
+func (h *StorageHandler) UploadFileVersion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.fileVersions == nil {
+			response.Error(c, http.StatusNotImplemented, "file storage does not support versions")
+			return
+		}
+
+		// アップロードしたユーザーをトークンから取得
+		uploaderID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		// 新しいバージョンの容量はファイルの所有者の使用量に加算されるため、所有者のみがアップロードできる
+		id := c.Param("id")
+		if err := h.sharing.AuthorizeOwner(c.Request.Context(), uploaderID, id); err != nil {
+			respondError(c, err, "failed to upload file version")
+			return
+		}
+
+		uploadFile, closer, err := multipartUpload(c, uploaderID)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid file")
+			return
+		}
+		defer closer.Close()
+
+		result, err := h.fileVersions.UploadVersion(c.Request.Context(), id, uploadFile)
+		if err != nil {
+			respondError(c, err, "failed to upload file version")
+			return
+		}
+
+		result.URL = fileDownloadURL(result.ID)
+		response.Success(c, http.StatusOK, result)
+	}
+}
+
+// 画像ファイルのサムネイルをダウンロードする機能を提供するハンドラー
+// サムネイルはアップロード後に非同期で作成されるため、作成前は404を返す
+// @Summary      Download a file thumbnail
+// @Description  Download a JPEG thumbnail of an image file. Thumbnails are generated asynchronously after upload, so a 404 is returned until the requested size is ready. Omitting size returns the smallest configured size.
+// @Tags         files
+// @Produce      jpeg
+// @Security     BearerAuth
+// @Param        id path string true "File ID"
+// @Param        size query int false "Longest edge in pixels, one of the configured sizes"
+// @Param        If-None-Match header string false "ETag of the cached copy"
+// @Success      200
+// @Success      304
+// @Failure      400  {object}  response.Response "Invalid size"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Teacher role required, or the file is neither the teacher's upload nor a submission to their assignment"
+// @Failure      404  {object}  response.Response "File has no thumbnail or it is not ready yet"
+// @Failure      501  {object}  response.Response "Thumbnails are disabled"
+// @Router       /api/v1/files/{id}/thumbnail [get]
+func (h *StorageHandler) DownloadThumbnail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		size := 0
+		if raw := c.Query("size"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 1 {
+				response.Error(c, http.StatusBadRequest, "invalid size")
+				return
+			}
+			size = parsed
+		}
+
+		id, ok := h.authorizeTeacher(c, "failed to download thumbnail")
+		if !ok {
+			return
+		}
+
+		thumbnail, body, err := h.thumbnails.Open(c.Request.Context(), id, size)
+		if err != nil {
+			respondError(c, err, "failed to download thumbnail")
+			return
+		}
+		defer body.Close()
+
+		// サムネイルは内容ごとに不変のため、チェックサムとサイズをETagとして使用する
+		etag := fmt.Sprintf("\"%s-%d\"", thumbnail.Checksum, thumbnail.Size)
+		c.Header("ETag", etag)
+		c.Header("Cache-Control", "private, max-age=86400")
+		if c.GetHeader("If-None-Match") == etag {
+			c.Status(http.StatusNotModified)
+			return
+		}
+		c.DataFromReader(http.StatusOK, thumbnail.ByteSize, thumbnail.ContentType, body, nil)
+	}
+}
+
+// ファイルのバージョン一覧を取得する機能を提供するハンドラー
+// @Summary      List file versions
+// @Description  List the retained versions of a file, newest first
+// @Tags         files
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "File ID"
+// @Success      200  {object}  response.Response{data=[]domain.File}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Teacher role required, or the file was uploaded by someone else"
+// @Failure      404  {object}  response.Response "File not found"
+// @Failure      501  {object}  response.Response "Storage does not support versions"
+// @Router       /api/v1/files/{id}/versions [get]
+func (h *StorageHandler) ListFileVersions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.fileVersions == nil {
+			response.Error(c, http.StatusNotImplemented, "file storage does not support versions")
+			return
+		}
+
+		id, ok := h.authorizeOwner(c, "failed to list file versions")
+		if !ok {
+			return
+		}
+
+		versions, err := h.fileVersions.ListVersions(c.Request.Context(), id)
+		if err != nil {
+			respondError(c, err, "failed to list file versions")
+			return
+		}
+		for i := range versions {
+			versions[i].URL = fileVersionDownloadURL(versions[i].ID, versions[i].Version)
+		}
+
+		response.Success(c, http.StatusOK, versions)
+	}
+}
+
+// ファイルの指定されたバージョンをダウンロードする機能を提供するハンドラー
+// @Summary      Download a file version
+// @Description  Download a specific version of a file with the name it was uploaded under
+// @Tags         files
+// @Produce      octet-stream
+// @Security     BearerAuth
+// @Param        id path string true "File ID"
+// @Param        version path int true "Version number"
+// @Success      200
+// @Failure      400  {object}  response.Response "Invalid version"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Teacher role required, or the file was uploaded by someone else"
+// @Failure      404  {object}  response.Response "File or version not found"
+// @Failure      501  {object}  response.Response "Storage does not support versions"
+// @Router       /api/v1/files/{id}/versions/{version} [get]
+func (h *StorageHandler) DownloadFileVersion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.fileVersions == nil {
+			response.Error(c, http.StatusNotImplemented, "file storage does not support versions")
+			return
+		}
+
+		version, err := strconv.Atoi(c.Param("version"))
+		if err != nil || version < 1 {
+			response.Error(c, http.StatusBadRequest, "invalid version")
+			return
+		}
+
+		id, ok := h.authorizeOwner(c, "failed to download file version")
+		if !ok {
+			return
+		}
+
+		file, body, err := h.fileVersions.DownloadVersion(c.Request.Context(), id, version)
+		if err != nil {
+			respondError(c, err, "failed to download file version")
+			return
+		}
+		defer body.Close()
+
+		if etag := fileETag(file); etag != "" {
+			c.Header("ETag", etag)
+		}
+		c.DataFromReader(http.StatusOK, file.Size, file.ContentType, body, map[string]string{
+			"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}),
+		})
+	}
+}
+
+// ファイルの指定されたバージョンを復元する機能を提供するハンドラー
+// 復元した内容は新しいバージョンとして追加する
+// @Summary      Restore a file version
+// @Description  Make the contents of an earlier version current again by adding them as a new version
+// @Tags         files
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "File ID"
+// @Param        version path int true "Version number"
+// @Success      200  {object}  response.Response{data=domain.File}
+// @Failure      400  {object}  response.Response "Invalid version"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Teacher role required, or the file was uploaded by someone else"
+// @Failure      404  {object}  response.Response "File or version not found"
+// @Failure      501  {object}  response.Response "Storage does not support versions"
+// @Router       /api/v1/files/{id}/versions/{version}/restore [post]
+func (h *StorageHandler) RestoreFileVersion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.fileVersions == nil {
+			response.Error(c, http.StatusNotImplemented, "file storage does not support versions")
+			return
+		}
+
+		version, err := strconv.Atoi(c.Param("version"))
+		if err != nil || version < 1 {
+			response.Error(c, http.StatusBadRequest, "invalid version")
+			return
+		}
+
+		id, ok := h.authorizeOwner(c, "failed to restore file version")
+		if !ok {
+			return
+		}
+
+		result, err := h.fileVersions.RestoreVersion(c.Request.Context(), id, version)
+		if err != nil {
+			respondError(c, err, "failed to restore file version")
+			return
+		}
+
+		result.URL = fileDownloadURL(result.ID)
+		response.Success(c, http.StatusOK, result)
+	}
+}
+
+// パスパラメータのファイルをログイン中の教師が閲覧できることを確認し、ファイルIDを返す
+// 確認できない場合はエラーを送信してfalseを返す
+func (h *StorageHandler) authorizeTeacher(c *gin.Context, message string) (string, bool) {
+	teacherID, err := currentUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, err.Error())
+		return "", false
+	}
+
+	id := c.Param("id")
+	if err := h.sharing.AuthorizeTeacher(c.Request.Context(), teacherID, id); err != nil {
+		respondError(c, err, message)
+		return "", false
+	}
+	return id, true
+}
+
+// パスパラメータのファイルをログイン中の教師がアップロードしたことを確認し、ファイルIDを返す
+// 確認できない場合はエラーを送信してfalseを返す
+func (h *StorageHandler) authorizeOwner(c *gin.Context, message string) (string, bool) {
+	teacherID, err := currentUserID(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, err.Error())
+		return "", false
+	}
+
+	id := c.Param("id")
+	if err := h.sharing.AuthorizeOwner(c.Request.Context(), teacherID, id); err != nil {
+		respondError(c, err, message)
+		return "", false
+	}
+	return id, true
+}
+
 // DynamoDBに新しいドキュメントを作成する機能を提供するハンドラー
 // ドキュメントデータを受け取り、新しいドキュメントを作成する
 // @Summary      Create a document in DynamoDB
-// @Description  Create a new document in DynamoDB
+// @Description  Create a new document in DynamoDB. If a JSON Schema is registered for the type, data is validated against its latest version, which is recorded as schema_version; fields lists every field that does not match.
 // @Tags         documents
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        document body domain.DocumentCreate true "Document to create"
 // @Success      201  {object}  domain.Document
+// @Failure      400  {object}  response.Response "Invalid request or data not matching the schema"
 // @Failure      401  {object}  response.Response "Unauthorized"
 // @Failure      403  {object}  response.Response "Forbidden - Teacher role required"
 // @Router       /api/v1/documents [post]
@@ -189,14 +843,14 @@ func (h *StorageHandler) CreateDocument() gin.HandlerFunc {
 			return
 		}
 
-		// DynamoDBにドキュメントを作成
-		result, err := h.documentStorage.Create(c.Request.Context(), &doc)
+		// スキーマで検証してDynamoDBにドキュメントを作成
+		result, err := h.documents.CreateDocument(c.Request.Context(), &doc)
 		if err != nil {
-			slog.Error("failed to create document", slog.String("error", err.Error()))
-			response.Error(c, http.StatusInternalServerError, "failed to create document")
+			respondError(c, err, "failed to create document")
 			return
 		}
 
+		c.Header("ETag", documentETag(result.Version))
 		response.Success(c, http.StatusCreated, result)
 	}
 }
@@ -204,7 +858,7 @@ func (h *StorageHandler) CreateDocument() gin.HandlerFunc {
 // DynamoDBからドキュメントを取得する機能を提供するハンドラー
 // ドキュメントIDを受け取り、対応するドキュメントを返す
 // @Summary      Get a document from DynamoDB
-// @Description  Get a document from DynamoDB by ID
+// @Description  Get a document from DynamoDB by ID. The ETag header holds the version of the document; send it back in If-Match when updating.
 // @Tags         documents
 // @Produce      json
 // @Security     BearerAuth
@@ -220,11 +874,11 @@ func (h *StorageHandler) GetDocument() gin.HandlerFunc {
 		// DynamoDBからドキュメントを取得
 		doc, err := h.documentStorage.Get(c.Request.Context(), id)
 		if err != nil {
-			slog.Error("failed to get document", slog.String("error", err.Error()))
-			response.Error(c, http.StatusInternalServerError, "failed to get document")
+			respondError(c, err, "failed to get document")
 			return
 		}
 
+		c.Header("ETag", documentETag(doc.Version))
 		response.Success(c, http.StatusOK, doc)
 	}
 }
@@ -232,16 +886,19 @@ func (h *StorageHandler) GetDocument() gin.HandlerFunc {
 // DynamoDBのドキュメントを更新する機能を提供するハンドラー
 // ドキュメントIDと更新データを受け取り、ドキュメントを更新する
 // @Summary      Update a document in DynamoDB
-// @Description  Update a document in DynamoDB by ID
+// @Description  Update a document in DynamoDB by ID. If a JSON Schema is registered for the type of the document, data is validated against its latest version. With If-Match set to the ETag of the document, the update is only applied if nobody else has written the document since; otherwise 412 is returned with the current version.
 // @Tags         documents
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id path string true "Document ID"
-// @Param        document body domain.DocumentUpdate true "Document update data"
+// @Param        id       path   string                true  "Document ID"
+// @Param        If-Match header string                false "ETag of the version being updated"
+// @Param        document body   domain.DocumentUpdate true  "Document update data"
 // @Success      200  {object}  domain.Document
+// @Failure      400  {object}  response.Response "Invalid request or data not matching the schema"
 // @Failure      401  {object}  response.Response "Unauthorized"
 // @Failure      403  {object}  response.Response "Forbidden - Teacher role required"
+// @Failure      412  {object}  response.Response "The document was changed by another update"
 // @Router       /api/v1/documents/{id} [put]
 func (h *StorageHandler) UpdateDocument() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -260,38 +917,141 @@ func (h *StorageHandler) UpdateDocument() gin.HandlerFunc {
 			return
 		}
 
-		// DynamoDBのドキュメントを更新
-		result, err := h.documentStorage.Update(c.Request.Context(), id, &update)
+		// If-Matchで指定されたバージョンの場合だけ更新する
+		expected, err := ifMatchVersion(c.GetHeader("If-Match"))
 		if err != nil {
-			slog.Error("failed to update document", slog.String("error", err.Error()))
-			response.Error(c, http.StatusInternalServerError, "failed to update document")
+			response.Error(c, http.StatusBadRequest, err.Error())
 			return
 		}
+		update.ExpectedVersion = expected
 
+		// スキーマで検証してDynamoDBのドキュメントを更新
+		result, err := h.documents.UpdateDocument(c.Request.Context(), id, &update)
+		if err != nil {
+			respondError(c, err, "failed to update document")
+			return
+		}
+
+		c.Header("ETag", documentETag(result.Version))
 		response.Success(c, http.StatusOK, result)
 	}
 }
 
-// DynamoDBからドキュメントを削除する機能を提供するハンドラー
-// ドキュメントIDを受け取り、対応するドキュメントを削除する
-// @Summary      Delete a document from DynamoDB
-// @Description  Delete a document from DynamoDB by ID
+// ドキュメントの一部を変更する機能を提供するハンドラー
+// JSON Merge PatchまたはJSON Patchを受け取り、ドキュメントのdataに適用する
+// @Summary      Patch a document in DynamoDB
+// @Description  Partially update the data of a document. Send a JSON Merge Patch (RFC 7396) with Content-Type application/merge-patch+json, or a JSON Patch (RFC 6902) with Content-Type application/json-patch+json. JSON Patch is applied atomically: if any operation fails, including a test operation, nothing is changed and 409 is returned. The patched data is validated against the latest JSON Schema of the type. With If-Match set to the ETag of the document, the patch is only applied if nobody else has written the document since.
+// @Tags         documents
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path   string                  true  "Document ID"
+// @Param        If-Match header string                  false "ETag of the version being patched"
+// @Param        patch    body   []domain.PatchOperation true  "JSON Patch operations, or a JSON Merge Patch object"
+// @Success      200  {object}  domain.Document
+// @Failure      400  {object}  response.Response "Invalid patch or patched data not matching the schema"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Forbidden - Teacher role required"
+// @Failure      404  {object}  response.Response "Document not found"
+// @Failure      409  {object}  response.Response "A test operation failed or a target does not exist"
+// @Failure      412  {object}  response.Response "The document was changed by another update"
+// @Failure      415  {object}  response.Response "Unsupported patch media type"
+// @Router       /api/v1/documents/{id} [patch]
+func (h *StorageHandler) PatchDocument() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// パスパラメータからドキュメントIDを取得
+		id := c.Param("id")
+		// Content-Typeに応じてリクエストボディからパッチを取得
+		patch, err := decodeDocumentPatch(c.ContentType(), c.Request.Body)
+		if err != nil {
+			if errors.Is(err, errUnsupportedPatchType) {
+				response.Error(c, http.StatusUnsupportedMediaType, err.Error())
+				return
+			}
+			respondError(c, err, "invalid patch")
+			return
+		}
+
+		// If-Matchで指定されたバージョンの場合だけ変更する
+		expected, err := ifMatchVersion(c.GetHeader("If-Match"))
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		patch.ExpectedVersion = expected
+
+		// パッチを適用してスキーマで検証し、DynamoDBのドキュメントを更新
+		result, err := h.documents.PatchDocument(c.Request.Context(), id, patch)
+		if err != nil {
+			respondError(c, err, "failed to patch document")
+			return
+		}
+
+		c.Header("ETag", documentETag(result.Version))
+		response.Success(c, http.StatusOK, result)
+	}
+}
+
+// ドキュメントをゴミ箱に移動する機能を提供するハンドラー
+// ゴミ箱に移動したドキュメントは一覧や取得の対象外になり、保持期間を過ぎると完全に削除される
+// @Summary      Move a document to the trash
+// @Description  Move a document to the trash. Trashed documents are hidden from listing and retrieval and permanently deleted after the trash retention period; restore them with the restore endpoint.
 // @Tags         documents
 // @Security     BearerAuth
 // @Param        id path string true "Document ID"
 // @Success      204
 // @Failure      401  {object}  response.Response "Unauthorized"
 // @Failure      403  {object}  response.Response "Forbidden - Teacher role required"
+// @Failure      404  {object}  response.Response "Document not found"
 // @Router       /api/v1/documents/{id} [delete]
 func (h *StorageHandler) DeleteDocument() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// パスパラメータからドキュメントIDを取得
-		id := c.Param("id")
-		// DynamoDBからドキュメントを削除
-		err := h.documentStorage.Delete(c.Request.Context(), id)
+		if err := h.trash.TrashDocument(c.Request.Context(), c.Param("id")); err != nil {
+			respondError(c, err, "failed to delete document")
+			return
+		}
+
+		response.Success(c, http.StatusNoContent, nil)
+	}
+}
+
+// ゴミ箱にあるドキュメントの一覧を取得する機能を提供するハンドラー
+// @Summary      List trashed documents
+// @Description  List the documents in the trash
+// @Tags         documents
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response{data=[]domain.Document}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Forbidden - Teacher role required"
+// @Router       /api/v1/documents/trash [get]
+func (h *StorageHandler) ListTrashedDocuments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		docs, err := h.trash.ListTrashedDocuments(c.Request.Context())
 		if err != nil {
-			slog.Error("failed to delete document", slog.String("error", err.Error()))
-			response.Error(c, http.StatusInternalServerError, "failed to delete document")
+			respondError(c, err, "failed to list trashed documents")
+			return
+		}
+
+		response.Success(c, http.StatusOK, docs)
+	}
+}
+
+// ゴミ箱にあるドキュメントを元に戻す機能を提供するハンドラー
+// @Summary      Restore a trashed document
+// @Description  Move a document out of the trash
+// @Tags         documents
+// @Security     BearerAuth
+// @Param        id path string true "Document ID"
+// @Success      204
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Forbidden - Teacher role required"
+// @Failure      404  {object}  response.Response "Document not found in the trash"
+// @Router       /api/v1/documents/{id}/restore [post]
+func (h *StorageHandler) RestoreDocument() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := h.trash.RestoreDocument(c.Request.Context(), c.Param("id")); err != nil {
+			respondError(c, err, "failed to restore document")
 			return
 		}
 
@@ -302,32 +1062,118 @@ func (h *StorageHandler) DeleteDocument() gin.HandlerFunc {
 // DynamoDBに保存されているドキュメントの一覧を取得する機能を提供するハンドラー
 // ドキュメントタイプを受け取り、該当するドキュメントの一覧を返す
 // @Summary      List documents from DynamoDB
-// @Description  List documents from DynamoDB by type
+// @Description  List documents of a type ordered by creation time, one page at a time. Pass the returned next_cursor as cursor to get the next page; it is omitted on the last page.
 // @Tags         documents
 // @Produce      json
 // @Security     BearerAuth
-// @Param        type query string true "Document type"
-// @Success      200  {array}   domain.Document
+// @Param        type   query string true  "Document type"
+// @Param        from   query string false "Only documents created at or after this time (RFC 3339)"
+// @Param        to     query string false "Only documents created at or before this time (RFC 3339)"
+// @Param        order  query string false "Sort order by creation time (asc or desc, default asc)"
+// @Param        limit  query int    false "Maximum number of documents (default 50, at most 100)"
+// @Param        cursor query string false "Cursor of the next page"
+// @Success      200  {object}  response.Response{data=domain.DocumentPage}
+// @Failure      400  {object}  response.Response "Invalid query parameters or cursor"
 // @Failure      401  {object}  response.Response "Unauthorized"
 // @Failure      403  {object}  response.Response "Forbidden - Teacher role required"
 // @Router       /api/v1/documents [get]
 func (h *StorageHandler) ListDocuments() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// クエリパラメータからドキュメントタイプを取得
-		docType := c.Query("type")
-		if docType == "" {
+		// クエリパラメータから検索条件を取得
+		query := domain.DocumentQuery{
+			Type:   c.Query("type"),
+			Order:  c.Query("order"),
+			Cursor: c.Query("cursor"),
+		}
+		if query.Type == "" {
 			response.Error(c, http.StatusBadRequest, "document type is required")
 			return
 		}
+		var err error
+		if query.From, err = timeQuery(c, "from"); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid from")
+			return
+		}
+		if query.To, err = timeQuery(c, "to"); err != nil {
+			response.Error(c, http.StatusBadRequest, "invalid to")
+			return
+		}
+		if value := c.Query("limit"); value != "" {
+			limit, err := strconv.Atoi(value)
+			if err != nil {
+				response.Error(c, http.StatusBadRequest, "invalid limit")
+				return
+			}
+			query.Limit = limit
+		}
 
-		// DynamoDBからドキュメント一覧を取得
-		docs, err := h.documentStorage.List(c.Request.Context(), docType)
+		// DynamoDBからドキュメント一覧の1ページを取得
+		page, err := h.documentStorage.Query(c.Request.Context(), &query)
 		if err != nil {
-			slog.Error("failed to list documents", slog.String("error", err.Error()))
-			response.Error(c, http.StatusInternalServerError, "failed to list documents")
+			respondError(c, err, "failed to list documents")
 			return
 		}
 
-		response.Success(c, http.StatusOK, docs)
+		response.Success(c, http.StatusOK, page)
+	}
+}
+
+// RFC 3339形式の日時のクエリパラメータを取得する（指定されていない場合はnil）
+func timeQuery(c *gin.Context, name string) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// ファイルのダウンロードURLを返す
+func fileDownloadURL(id string) string {
+	return fmt.Sprintf("/api/v1/files/%s", id)
+}
+
+// 学生と共有されているファイルのダウンロード用のURLを返す
+func sharedFileDownloadURL(id string) string {
+	return fmt.Sprintf("/api/v1/me/files/%s", id)
+}
+
+// 共有リンクのダウンロード用のURLを返す
+func sharedLinkURL(token string) string {
+	return fmt.Sprintf("/api/v1/shared/%s", token)
+}
+
+// ファイルの指定されたバージョンのダウンロード用のURLを返す
+func fileVersionDownloadURL(id string, version int) string {
+	return fmt.Sprintf("/api/v1/files/%s/versions/%d", id, version)
+}
+
+// マルチパートのパートを順に読み、fileフィールドのパートを本文とするアップロードを返す
+// パートはディスクやメモリに展開せずそのまま転送するため、サイズは不明（-1）とする
+// 返されたCloserは呼び出し側で閉じる必要がある
+func multipartUpload(c *gin.Context, uploaderID int64) (*domain.FileUpload, io.Closer, error) {
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, nil, err
+	}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, nil, err
+		}
+		if part.FormName() == "file" && part.FileName() != "" {
+			return &domain.FileUpload{
+				Name:         part.FileName(),
+				ContentType:  part.Header.Get("Content-Type"),
+				Body:         part,
+				Size:         -1,
+				UploaderID:   uploaderID,
+				UploaderRole: currentUserRole(c),
+			}, part, nil
+		}
+		part.Close()
 	}
 }
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/OICjangirrahul/students/internal/core/ports"
+	"github.com/OICjangirrahul/students/internal/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// 使用量レポートで取得するユーザー数のデフォルト値
+const defaultTopConsumersLimit = 10
+
+// ストレージ使用量ハンドラー構造体：保存容量の使用状況に関するHTTPリクエストを処理
+type StorageUsageHandler struct {
+	// ストレージ使用量サービスインターフェース
+	storageUsageService ports.StorageUsageService
+}
+
+// 新しいストレージ使用量ハンドラーインスタンスを作成する
+func NewStorageUsageHandler(storageUsageService ports.StorageUsageService) *StorageUsageHandler {
+	return &StorageUsageHandler{
+		storageUsageService: storageUsageService,
+	}
+}
+
+// ログイン中のユーザーのストレージ使用量を取得する
+// @Summary      Get my storage usage
+// @Description  Get the bytes and number of files stored by the authenticated user, together with the quota for their role
+// @Tags         storage
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  response.Response{data=domain.StorageUsage}
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Router       /api/v1/me/storage [get]
+func (h *StorageUsageHandler) MyUsage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := currentUserID(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		usage, err := h.storageUsageService.GetUsage(c.Request.Context(), currentUserRole(c), userID)
+		if err != nil {
+			respondError(c, err, "failed to get storage usage")
+			return
+		}
+
+		response.Success(c, http.StatusOK, usage)
+	}
+}
+
+// ストレージ使用量の多いユーザーの一覧を取得する
+// @Summary      List top storage consumers
+// @Description  List the uploaders storing the most bytes, largest first. Administrators only.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit query int false "Number of uploaders to return (1-100)" default(10)
+// @Success      200  {object}  response.Response{data=[]domain.StorageUsage}
+// @Failure      400  {object}  response.Response "Invalid limit"
+// @Failure      401  {object}  response.Response "Unauthorized"
+// @Failure      403  {object}  response.Response "Not an administrator"
+// @Router       /api/v1/admin/storage/top-consumers [get]
+func (h *StorageUsageHandler) TopConsumers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := defaultTopConsumersLimit
+		if value := c.Query("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				response.Error(c, http.StatusBadRequest, "invalid limit")
+				return
+			}
+			limit = parsed
+		}
+
+		usages, err := h.storageUsageService.TopConsumers(c.Request.Context(), limit)
+		if err != nil {
+			respondError(c, err, "failed to list storage usage")
+			return
+		}
+
+		response.Success(c, http.StatusOK, usages)
+	}
+}
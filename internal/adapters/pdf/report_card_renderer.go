@@ -0,0 +1,148 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// 表の列幅（mm）：科目、教師、パーセント、評定、出席率
+var subjectColumnWidths = []float64{50, 50, 25, 20, 35}
+
+// 通知表レンダラー構造体：gofpdfを使用して通知表をPDFに変換する
+type ReportCardRenderer struct{}
+
+// 新しい通知表レンダラーインスタンスを作成する
+func NewReportCardRenderer() *ReportCardRenderer {
+	return &ReportCardRenderer{}
+}
+
+// 通知表をA4縦のPDFに変換する
+// 標準フォントを使用するため、文字はWindows-1252で表現できる範囲に変換される
+func (r *ReportCardRenderer) Render(card *domain.ReportCard) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Report Card - "+card.StudentName, true)
+	pdf.SetCreationDate(card.GeneratedAt)
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	// ヘッダー
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, "Report Card", "", 1, "C", false, 0, "")
+	pdf.Ln(4)
+
+	// 学生情報
+	pdf.SetFont("Helvetica", "", 11)
+	labeled := func(label, value string) {
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(30, 7, label, "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.CellFormat(0, 7, tr(value), "", 1, "L", false, 0, "")
+	}
+	labeled("Student:", card.StudentName)
+	labeled("Email:", card.StudentEmail)
+	labeled("Term:", card.Term)
+	labeled("Issued:", card.GeneratedAt.Format("2006-01-02"))
+	pdf.Ln(6)
+
+	// 科目ごとの成績表
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.SetFillColor(230, 230, 230)
+	for i, header := range []string{"Subject", "Teacher", "Percent", "Grade", "Attendance"} {
+		pdf.CellFormat(subjectColumnWidths[i], 8, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 10)
+	if len(card.Subjects) == 0 {
+		pdf.CellFormat(sum(subjectColumnWidths), 8, "No subjects", "1", 1, "C", false, 0, "")
+	}
+	for _, subject := range card.Subjects {
+		attendance := "-"
+		if subject.Attendance != nil {
+			attendance = formatPercent(subject.Attendance.Rate)
+		}
+		cells := []string{
+			tr(subject.Subject),
+			tr(subject.TeacherName),
+			formatPercent(subject.Percent),
+			orDash(tr(subject.Letter)),
+			attendance,
+		}
+		for i, cell := range cells {
+			align := "C"
+			if i < 2 {
+				align = "L"
+			}
+			pdf.CellFormat(subjectColumnWidths[i], 8, cell, "1", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.Ln(6)
+
+	// 出欠集計
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(0, 8, "Attendance Summary", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(0, 6, fmt.Sprintf("Days present: %d    Days absent: %d    Days tardy: %d    Attendance rate: %s",
+		card.Attendance.DaysPresent, card.Attendance.DaysAbsent, card.Attendance.DaysTardy, formatPercent(card.Attendance.Rate)),
+		"", 1, "L", false, 0, "")
+	pdf.Ln(6)
+
+	// 教師のコメント
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(0, 8, "Teacher Comments", "", 1, "L", false, 0, "")
+	hasComment := false
+	for _, subject := range card.Subjects {
+		if strings.TrimSpace(subject.Comment) == "" {
+			continue
+		}
+		hasComment = true
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(0, 6, tr(fmt.Sprintf("%s (%s)", subject.Subject, subject.TeacherName)), "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.MultiCell(0, 6, tr(subject.Comment), "", "L", false)
+		pdf.Ln(2)
+	}
+	if !hasComment {
+		pdf.SetFont("Helvetica", "I", 11)
+		pdf.CellFormat(0, 6, "No comments", "", 1, "L", false, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render report card: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// パーセントを表示用の文字列に変換する（値がない場合は"-"）
+func formatPercent(percent *float64) string {
+	if percent == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f%%", *percent)
+}
+
+// 空文字列を"-"に置き換える
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// 数値の合計を返す
+func sum(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,81 @@
+package pdf
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestReportCardRenderer_Render(t *testing.T) {
+	generatedAt := time.Date(2024, 6, 28, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		card *domain.ReportCard
+	}{
+		{
+			name: "graded subjects with comments",
+			card: &domain.ReportCard{
+				StudentID:    12,
+				StudentName:  "Zoë Müller",
+				StudentEmail: "zoe@example.com",
+				Term:         "2024-spring",
+				Subjects: []domain.ReportCardSubject{
+					{
+						TeacherID: 7, TeacherName: "Ms. García", Subject: "Mathematics",
+						Percent: floatPtr(91.5), Letter: "A-",
+						Attendance: &domain.AttendanceSummary{DaysPresent: 58, DaysAbsent: 2, DaysTardy: 1, Rate: floatPtr(96.67)},
+						Comment:    "Excellent participation in class.",
+					},
+					{TeacherID: 8, TeacherName: "Mr. Lee", Subject: "History", Percent: floatPtr(78), Letter: "C+"},
+				},
+				Attendance:  domain.AttendanceSummary{DaysPresent: 58, DaysAbsent: 2, DaysTardy: 1, Rate: floatPtr(96.67)},
+				GeneratedAt: generatedAt,
+			},
+		},
+		{
+			name: "student with no grades",
+			card: &domain.ReportCard{
+				StudentID:   13,
+				StudentName: "New Student",
+				Term:        "2024-spring",
+				Subjects: []domain.ReportCardSubject{
+					{TeacherID: 7, TeacherName: "Ms. García", Subject: "Mathematics"},
+				},
+				GeneratedAt: generatedAt,
+			},
+		},
+		{
+			name: "student with no subjects",
+			card: &domain.ReportCard{StudentID: 14, StudentName: "Unassigned Student", Term: "2024-spring", GeneratedAt: generatedAt},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			renderer := NewReportCardRenderer()
+
+			// Test
+			data, err := renderer.Render(tt.card)
+
+			// Assertions
+			require.NoError(t, err)
+			require.NotEmpty(t, data)
+			assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")), "output starts with the PDF header")
+			assert.True(t, bytes.HasSuffix(bytes.TrimSpace(data), []byte("%%EOF")), "output ends with the PDF trailer")
+		})
+	}
+}
+
+func TestFormatPercent(t *testing.T) {
+	assert.Equal(t, "-", formatPercent(nil))
+	assert.Equal(t, "91.50%", formatPercent(floatPtr(91.5)))
+	assert.Equal(t, "0.00%", formatPercent(floatPtr(0)))
+}
@@ -0,0 +1,355 @@
+package qti
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ファイル名と内容の組からzipパッケージを作成する
+func buildPackage(t *testing.T, files map[string]string) []byte {
+	buf := new(bytes.Buffer)
+	archive := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := archive.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	return buf.Bytes()
+}
+
+// マニフェストにアイテムとして登録するリソースを並べたマニフェストを作成する
+func manifestFor(hrefs ...string) string {
+	var resources strings.Builder
+	for _, href := range hrefs {
+		resources.WriteString(`<resource identifier="` + href + `" type="imsqti_item_xmlv2p1" href="` + href + `"/>`)
+	}
+	return `<manifest xmlns="http://www.imsglobal.org/xsd/imscp_v1p1"><resources>` + resources.String() + `</resources></manifest>`
+}
+
+// 選択問題のアイテムを作成する
+func choiceItem(identifier, correct string) string {
+	return `<assessmentItem xmlns="http://www.imsglobal.org/xsd/imsqti_v2p1" identifier="` + identifier + `" title="Capital">
+  <responseDeclaration identifier="RESPONSE" cardinality="single" baseType="identifier">
+    <correctResponse><value>` + correct + `</value></correctResponse>
+  </responseDeclaration>
+  <itemBody>
+    <choiceInteraction responseIdentifier="RESPONSE" maxChoices="1">
+      <prompt>Capital of France?</prompt>
+      <simpleChoice identifier="A">Paris</simpleChoice>
+      <simpleChoice identifier="B">Lyon</simpleChoice>
+    </choiceInteraction>
+  </itemBody>
+</assessmentItem>`
+}
+
+func intPtr(v int) *int           { return &v }
+func boolPtr(v bool) *bool        { return &v }
+func floatPtr(v float64) *float64 { return &v }
+
+func TestPackageCodec_RoundTrip(t *testing.T) {
+	items := []domain.QuestionBankItem{
+		{ID: "mc", Question: domain.QuizQuestion{
+			Type: domain.QuestionMultipleChoice, Prompt: "What is 2 + 2?", Points: 2,
+			Options: []string{"3", "4", "5"}, CorrectOption: intPtr(1),
+		}},
+		{ID: "ms", Question: domain.QuizQuestion{
+			Type: domain.QuestionMultiSelect, Prompt: "Pick the primes", Points: 3,
+			Options: []string{"2", "4", "5", "9"}, CorrectOptions: []int{0, 2},
+		}},
+		{ID: "tf", Question: domain.QuizQuestion{
+			Type: domain.QuestionTrueFalse, Prompt: "The earth is round", Points: 1, CorrectBool: boolPtr(true),
+		}},
+		{ID: "num", Question: domain.QuizQuestion{
+			Type: domain.QuestionNumeric, Prompt: "Approximate pi", Points: 1.5, CorrectNumber: floatPtr(3.14), Tolerance: 0.01,
+		}},
+		{ID: "text", Question: domain.QuizQuestion{
+			Type: domain.QuestionShortText, Prompt: "Name the largest planet", Points: 1,
+			AcceptedAnswers: []string{"Jupiter", "jupiter"}, CaseSensitive: true,
+		}},
+		{ID: "text-ci", Question: domain.QuizQuestion{
+			Type: domain.QuestionShortText, Prompt: "Name the closest star", Points: 1,
+			AcceptedAnswers: []string{"Sun"},
+		}},
+	}
+	codec := NewPackageCodec()
+
+	// Test
+	data, err := codec.Encode(items)
+	require.NoError(t, err)
+	pkg, err := codec.Decode(data)
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Empty(t, pkg.Unsupported)
+	require.Len(t, pkg.Items, len(items))
+	for i, item := range items {
+		want := item.Question
+		want.ID = "item-" + item.ID
+		assert.Equal(t, "items/item-"+item.ID+".xml", pkg.Items[i].File)
+		assert.Equal(t, "item-"+item.ID, pkg.Items[i].Identifier)
+		assert.Equal(t, want, pkg.Items[i].Question, item.ID)
+	}
+}
+
+func TestPackageCodec_EncodeUnsupportedType(t *testing.T) {
+	codec := NewPackageCodec()
+
+	_, err := codec.Encode([]domain.QuestionBankItem{{ID: "essay", Question: domain.QuizQuestion{Type: "essay", Prompt: "Discuss"}}})
+
+	assert.ErrorContains(t, err, `question type "essay" cannot be exported`)
+}
+
+func TestPackageCodec_DecodeMalformedPackage(t *testing.T) {
+	codec := NewPackageCodec()
+
+	t.Run("not a zip", func(t *testing.T) {
+		_, err := codec.Decode([]byte("plain text"))
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("invalid manifest", func(t *testing.T) {
+		data := buildPackage(t, map[string]string{manifestFile: "<manifest><resources>"})
+
+		_, err := codec.Decode(data)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.ErrorContains(t, err, manifestFile)
+	})
+
+	t.Run("empty manifest", func(t *testing.T) {
+		data := buildPackage(t, map[string]string{manifestFile: ""})
+
+		_, err := codec.Decode(data)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+}
+
+func TestPackageCodec_DecodeUnsupportedItems(t *testing.T) {
+	tests := []struct {
+		name        string
+		item        string
+		interaction string
+		reason      string
+	}{
+		{
+			name:   "invalid XML",
+			item:   `<assessmentItem identifier="broken"><itemBody>`,
+			reason: "invalid XML",
+		},
+		{
+			name:   "not an assessment item",
+			item:   `<assessmentTest identifier="test"/>`,
+			reason: "expected an assessmentItem, found assessmentTest",
+		},
+		{
+			name:   "no item body",
+			item:   `<assessmentItem identifier="item"/>`,
+			reason: "item has no itemBody",
+		},
+		{
+			name:   "no interaction",
+			item:   `<assessmentItem identifier="item"><itemBody><p>Read this</p></itemBody></assessmentItem>`,
+			reason: "item has no interaction",
+		},
+		{
+			name: "more than one interaction",
+			item: `<assessmentItem identifier="item"><itemBody>
+				<choiceInteraction responseIdentifier="A"/><textEntryInteraction responseIdentifier="B"/>
+			</itemBody></assessmentItem>`,
+			interaction: "choiceInteraction,textEntryInteraction",
+			reason:      "more than one interaction",
+		},
+		{
+			name:        "missing response declaration",
+			item:        `<assessmentItem identifier="item"><itemBody><choiceInteraction responseIdentifier="RESPONSE"/></itemBody></assessmentItem>`,
+			interaction: "choiceInteraction",
+			reason:      `no responseDeclaration for "RESPONSE"`,
+		},
+		{
+			name: "unsupported interaction",
+			item: `<assessmentItem identifier="item">
+				<responseDeclaration identifier="RESPONSE" cardinality="single" baseType="string"/>
+				<itemBody><extendedTextInteraction responseIdentifier="RESPONSE"/></itemBody>
+			</assessmentItem>`,
+			interaction: "extendedTextInteraction",
+			reason:      "interaction type extendedTextInteraction is not supported",
+		},
+		{
+			name:        "correct response is not a choice",
+			item:        choiceItem("item", "Z"),
+			interaction: "choiceInteraction",
+			reason:      `correct response "Z" is not one of the choices`,
+		},
+		{
+			name: "numeric item without a number",
+			item: `<assessmentItem identifier="item">
+				<responseDeclaration identifier="RESPONSE" cardinality="single" baseType="float">
+					<correctResponse><value>many</value></correctResponse>
+				</responseDeclaration>
+				<itemBody><p>How many?</p><textEntryInteraction responseIdentifier="RESPONSE"/></itemBody>
+			</assessmentItem>`,
+			interaction: "textEntryInteraction",
+			reason:      `correct response "many" is not a number`,
+		},
+		{
+			name: "text item without an answer",
+			item: `<assessmentItem identifier="item">
+				<responseDeclaration identifier="RESPONSE" cardinality="single" baseType="string"/>
+				<itemBody><p>Name it</p><textEntryInteraction responseIdentifier="RESPONSE"/></itemBody>
+			</assessmentItem>`,
+			interaction: "textEntryInteraction",
+			reason:      "item has no correct response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			data := buildPackage(t, map[string]string{
+				manifestFile:  manifestFor("good.xml", "bad.xml"),
+				"good.xml":    choiceItem("good", "A"),
+				"bad.xml":     tt.item,
+				"ignored.xml": choiceItem("ignored", "A"),
+				"media/a.png": "png",
+			})
+			codec := NewPackageCodec()
+
+			// Test
+			pkg, err := codec.Decode(data)
+
+			// Assertions
+			require.NoError(t, err)
+			require.Len(t, pkg.Items, 1)
+			assert.Equal(t, "good", pkg.Items[0].Identifier)
+			require.Len(t, pkg.Unsupported, 1)
+			assert.Equal(t, "bad.xml", pkg.Unsupported[0].File)
+			assert.Equal(t, tt.interaction, pkg.Unsupported[0].Interaction)
+			assert.Contains(t, pkg.Unsupported[0].Reason, tt.reason)
+		})
+	}
+}
+
+func TestPackageCodec_DecodeMissingManifestFile(t *testing.T) {
+	data := buildPackage(t, map[string]string{
+		manifestFile: manifestFor("items/missing.xml", "items/present.xml"),
+		// 相対パスの表記が異なっても同じファイルとして扱う
+		"items/./present.xml": choiceItem("present", "A"),
+	})
+
+	pkg, err := NewPackageCodec().Decode(data)
+
+	require.NoError(t, err)
+	require.Len(t, pkg.Items, 1)
+	assert.Equal(t, "items/present.xml", pkg.Items[0].File)
+	require.Len(t, pkg.Unsupported, 1)
+	assert.Equal(t, "items/missing.xml", pkg.Unsupported[0].File)
+	assert.Equal(t, "file listed in the manifest is missing from the package", pkg.Unsupported[0].Reason)
+}
+
+func TestPackageCodec_DecodeWithoutManifest(t *testing.T) {
+	data := buildPackage(t, map[string]string{
+		"a.xml":     choiceItem("a", "A"),
+		"test.xml":  `<assessmentTest identifier="test"/>`,
+		"style.css": "p { color: red }",
+	})
+
+	pkg, err := NewPackageCodec().Decode(data)
+
+	// マニフェストがない場合、アイテム以外のXMLは無視する
+	require.NoError(t, err)
+	assert.Empty(t, pkg.Unsupported)
+	require.Len(t, pkg.Items, 1)
+	assert.Equal(t, domain.QuizQuestion{
+		ID:            "a",
+		Type:          domain.QuestionMultipleChoice,
+		Prompt:        "Capital of France?",
+		Options:       []string{"Paris", "Lyon"},
+		CorrectOption: intPtr(0),
+	}, pkg.Items[0].Question)
+}
+
+func TestPackageCodec_DecodeScoringVariants(t *testing.T) {
+	t.Run("correct choice from a mapping and points from SCORE", func(t *testing.T) {
+		item := `<assessmentItem identifier="tf">
+			<responseDeclaration identifier="RESPONSE" cardinality="single" baseType="identifier">
+				<mapping defaultValue="0"><mapEntry mapKey="F" mappedValue="0"/><mapEntry mapKey="T" mappedValue="1"/></mapping>
+			</responseDeclaration>
+			<outcomeDeclaration identifier="SCORE" cardinality="single" baseType="float" normalMaximum="4"/>
+			<itemBody><p>Water boils at <b>100</b> °C.</p>
+				<choiceInteraction responseIdentifier="RESPONSE" maxChoices="1">
+					<simpleChoice identifier="F">false</simpleChoice><simpleChoice identifier="T">TRUE</simpleChoice>
+				</choiceInteraction>
+			</itemBody>
+		</assessmentItem>`
+		data := buildPackage(t, map[string]string{"tf.xml": item})
+
+		pkg, err := NewPackageCodec().Decode(data)
+
+		require.NoError(t, err)
+		require.Len(t, pkg.Items, 1)
+		assert.Equal(t, domain.QuizQuestion{
+			ID:          "tf",
+			Type:        domain.QuestionTrueFalse,
+			Prompt:      "Water boils at 100 °C.",
+			Points:      4,
+			CorrectBool: boolPtr(true),
+		}, pkg.Items[0].Question)
+	})
+
+	t.Run("relative tolerance is converted to an absolute one", func(t *testing.T) {
+		item := `<assessmentItem identifier="num">
+			<responseDeclaration identifier="RESPONSE" cardinality="single" baseType="float">
+				<correctResponse><value>200</value></correctResponse>
+			</responseDeclaration>
+			<itemBody><p>Speed?</p><textEntryInteraction responseIdentifier="RESPONSE"/></itemBody>
+			<responseProcessing><responseCondition><responseIf>
+				<equal toleranceMode="relative" tolerance="10 5"><variable identifier="RESPONSE"/><correct identifier="RESPONSE"/></equal>
+			</responseIf></responseCondition></responseProcessing>
+		</assessmentItem>`
+		data := buildPackage(t, map[string]string{"num.xml": item})
+
+		pkg, err := NewPackageCodec().Decode(data)
+
+		require.NoError(t, err)
+		require.Len(t, pkg.Items, 1)
+		assert.Equal(t, domain.QuestionNumeric, pkg.Items[0].Question.Type)
+		assert.Equal(t, 200.0, *pkg.Items[0].Question.CorrectNumber)
+		assert.Equal(t, 10.0, pkg.Items[0].Question.Tolerance)
+	})
+}
+
+func TestParseNode(t *testing.T) {
+	t.Run("namespaces are ignored and text is normalized", func(t *testing.T) {
+		root, err := parseNode(strings.NewReader(`<qti:item xmlns:qti="urn:qti" qti:identifier=" x1 ">
+			<qti:body><p>One</p><p>two<br/>three</p>  <span>four</span></qti:body>
+		</qti:item>`))
+
+		require.NoError(t, err)
+		assert.Equal(t, "item", root.name)
+		assert.Equal(t, "x1", root.attr("identifier"))
+		assert.Equal(t, "One two three four", root.child("body").textContent(nil))
+		assert.Equal(t, "One four", root.child("body").textContent(func(n *node) bool { return n.name == "p" && n.find("br") != nil }))
+	})
+
+	t.Run("document without elements", func(t *testing.T) {
+		_, err := parseNode(strings.NewReader("   "))
+
+		assert.ErrorContains(t, err, "no root element")
+	})
+
+	t.Run("unclosed element", func(t *testing.T) {
+		_, err := parseNode(strings.NewReader("<item><body></item>"))
+
+		assert.Error(t, err)
+	})
+}
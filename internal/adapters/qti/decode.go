@@ -0,0 +1,450 @@
+package qti
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+)
+
+// パッケージ内の1ファイルとして読み込むXMLの最大サイズ
+const maxItemFileSize = 5 << 20
+
+// マニフェストのファイル名
+const manifestFile = "imsmanifest.xml"
+
+// QTIパッケージ（zip）を解析し、対応している問題と未対応のアイテムを返す
+// マニフェストがある場合はアイテムとして登録されたリソースを順に読み込み、
+// ない場合はパッケージ内のassessmentItemのXMLをすべて読み込む
+func (c *PackageCodec) Decode(data []byte) (*domain.QTIPackage, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: not a zip package: %s", domain.ErrInvalidInput, err)
+	}
+
+	files := make(map[string]*zip.File, len(reader.File))
+	var names []string
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := path.Clean(f.Name)
+		files[name] = f
+		names = append(names, name)
+	}
+
+	paths, fromManifest, err := itemPaths(files, names)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &domain.QTIPackage{
+		Items:       []domain.QTIItem{},
+		Unsupported: []domain.QTIUnsupportedItem{},
+	}
+	for _, name := range paths {
+		f, ok := files[name]
+		if !ok {
+			pkg.Unsupported = append(pkg.Unsupported, domain.QTIUnsupportedItem{
+				File:   name,
+				Reason: "file listed in the manifest is missing from the package",
+			})
+			continue
+		}
+
+		root, err := readXMLFile(f)
+		if err != nil {
+			pkg.Unsupported = append(pkg.Unsupported, domain.QTIUnsupportedItem{
+				File:   name,
+				Reason: err.Error(),
+			})
+			continue
+		}
+		if root.name != "assessmentItem" {
+			// マニフェストなしで走査した場合、テストやスタイル定義などのXMLは無視する
+			if fromManifest {
+				pkg.Unsupported = append(pkg.Unsupported, domain.QTIUnsupportedItem{
+					File:   name,
+					Reason: fmt.Sprintf("expected an assessmentItem, found %s", root.name),
+				})
+			}
+			continue
+		}
+
+		identifier := root.attr("identifier")
+		question, interaction, err := decodeItem(root)
+		if err != nil {
+			pkg.Unsupported = append(pkg.Unsupported, domain.QTIUnsupportedItem{
+				File:        name,
+				Identifier:  identifier,
+				Interaction: interaction,
+				Reason:      err.Error(),
+			})
+			continue
+		}
+		pkg.Items = append(pkg.Items, domain.QTIItem{
+			File:       name,
+			Identifier: identifier,
+			Question:   *question,
+		})
+	}
+
+	return pkg, nil
+}
+
+// 読み込むアイテムのファイルパスを返す
+// マニフェストにアイテムのリソースがある場合はその順序を、ない場合はXMLファイルをすべて返す
+func itemPaths(files map[string]*zip.File, names []string) ([]string, bool, error) {
+	if f, ok := files[manifestFile]; ok {
+		manifest, err := readXMLFile(f)
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: %s: %s", domain.ErrInvalidInput, manifestFile, err)
+		}
+
+		var paths []string
+		for _, resource := range manifest.findAll(func(n *node) bool { return n.name == "resource" }) {
+			if !strings.HasPrefix(resource.attr("type"), "imsqti_item_xmlv2p") {
+				continue
+			}
+			href := resource.attr("href")
+			if href == "" {
+				if file := resource.child("file"); file != nil {
+					href = file.attr("href")
+				}
+			}
+			if href != "" {
+				paths = append(paths, path.Clean(href))
+			}
+		}
+		if len(paths) > 0 {
+			return paths, true, nil
+		}
+	}
+
+	var paths []string
+	for _, name := range names {
+		if name != manifestFile && strings.EqualFold(path.Ext(name), ".xml") {
+			paths = append(paths, name)
+		}
+	}
+	return paths, false, nil
+}
+
+// パッケージ内のXMLファイルを読み込む
+func readXMLFile(f *zip.File) (*node, error) {
+	if f.UncompressedSize64 > maxItemFileSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxItemFileSize)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %s", err)
+	}
+	defer rc.Close()
+
+	root, err := parseNode(io.LimitReader(rc, maxItemFileSize))
+	if err != nil {
+		return nil, fmt.Errorf("invalid XML: %s", err)
+	}
+	return root, nil
+}
+
+// assessmentItemを問題に変換する
+// 変換できない場合は、アイテムのインタラクションの種類とともに理由を返す
+func decodeItem(root *node) (*domain.QuizQuestion, string, error) {
+	body := root.child("itemBody")
+	if body == nil {
+		return nil, "", errors.New("item has no itemBody")
+	}
+
+	interactions := body.findAll(isInteraction)
+	if len(interactions) == 0 {
+		return nil, "", errors.New("item has no interaction")
+	}
+	if len(interactions) > 1 {
+		names := make([]string, len(interactions))
+		for i, interaction := range interactions {
+			names[i] = interaction.name
+		}
+		return nil, strings.Join(names, ","), errors.New("items with more than one interaction are not supported")
+	}
+	interaction := interactions[0]
+
+	declaration := responseDeclaration(root, interaction.attr("responseIdentifier"))
+	if declaration == nil {
+		return nil, interaction.name, fmt.Errorf("no responseDeclaration for %q", interaction.attr("responseIdentifier"))
+	}
+
+	question := &domain.QuizQuestion{
+		ID:     root.attr("identifier"),
+		Prompt: itemPrompt(body, interaction),
+		Points: itemPoints(root),
+	}
+	if question.Prompt == "" {
+		question.Prompt = root.attr("title")
+	}
+
+	var err error
+	switch interaction.name {
+	case "choiceInteraction":
+		err = decodeChoice(question, interaction, declaration)
+	case "textEntryInteraction":
+		err = decodeTextEntry(question, root, declaration)
+	default:
+		err = fmt.Errorf("interaction type %s is not supported", interaction.name)
+	}
+	if err != nil {
+		return nil, interaction.name, err
+	}
+	return question, interaction.name, nil
+}
+
+// 要素がインタラクションかどうかを判定する
+func isInteraction(n *node) bool {
+	return strings.HasSuffix(n.name, "Interaction")
+}
+
+// 指定された識別子のresponseDeclarationを返す
+func responseDeclaration(root *node, identifier string) *node {
+	for _, declaration := range root.childrenNamed("responseDeclaration") {
+		if declaration.attr("identifier") == identifier {
+			return declaration
+		}
+	}
+	return nil
+}
+
+// 問題文を組み立てる
+// itemBodyのインタラクション以外のテキストと、インタラクションのpromptを連結する
+func itemPrompt(body, interaction *node) string {
+	parts := make([]string, 0, 2)
+	if text := body.textContent(isInteraction); text != "" {
+		parts = append(parts, text)
+	}
+	if prompt := interaction.child("prompt"); prompt != nil {
+		if text := prompt.textContent(nil); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// 配点を返す
+// MAXSCOREの既定値、SCOREのnormalMaximumの順に参照し、どちらもない場合は0を返す
+func itemPoints(root *node) float64 {
+	outcomes := root.childrenNamed("outcomeDeclaration")
+	for _, outcome := range outcomes {
+		if outcome.attr("identifier") != "MAXSCORE" {
+			continue
+		}
+		if values := outcome.child("defaultValue").values(); len(values) > 0 {
+			if points, err := strconv.ParseFloat(values[0], 64); err == nil && points > 0 {
+				return points
+			}
+		}
+	}
+	for _, outcome := range outcomes {
+		if outcome.attr("identifier") != "SCORE" {
+			continue
+		}
+		if points, err := strconv.ParseFloat(outcome.attr("normalMaximum"), 64); err == nil && points > 0 {
+			return points
+		}
+	}
+	return 0
+}
+
+// choiceInteractionを単一選択、複数選択、正誤問題のいずれかに変換する
+func decodeChoice(question *domain.QuizQuestion, interaction, declaration *node) error {
+	choices := interaction.childrenNamed("simpleChoice")
+	if len(choices) < 2 {
+		return errors.New("choice interaction needs at least two choices")
+	}
+
+	indexes := make(map[string]int, len(choices))
+	options := make([]string, len(choices))
+	for i, choice := range choices {
+		indexes[choice.attr("identifier")] = i
+		options[i] = choice.textContent(nil)
+	}
+
+	// 正答は correctResponse、なければ正の点数が割り当てられた mapping から取得する
+	correct := declaration.child("correctResponse").values()
+	if len(correct) == 0 {
+		correct = positiveMapKeys(declaration)
+	}
+	if len(correct) == 0 {
+		return errors.New("item has no correct response")
+	}
+	correctIndexes := make([]int, 0, len(correct))
+	for _, identifier := range correct {
+		index, ok := indexes[identifier]
+		if !ok {
+			return fmt.Errorf("correct response %q is not one of the choices", identifier)
+		}
+		correctIndexes = append(correctIndexes, index)
+	}
+
+	maxChoices := interaction.attr("maxChoices")
+	single := declaration.attr("cardinality") != "multiple" && (maxChoices == "" || maxChoices == "1")
+	if !single {
+		question.Type = domain.QuestionMultiSelect
+		question.Options = options
+		question.CorrectOptions = correctIndexes
+		return nil
+	}
+
+	if len(correctIndexes) != 1 {
+		return errors.New("single choice item needs exactly one correct response")
+	}
+	if value, ok := trueFalseChoice(options, correctIndexes[0]); ok {
+		question.Type = domain.QuestionTrueFalse
+		question.CorrectBool = &value
+		return nil
+	}
+	question.Type = domain.QuestionMultipleChoice
+	question.Options = options
+	question.CorrectOption = &correctIndexes[0]
+	return nil
+}
+
+// 選択肢が「True」と「False」の2つだけの場合、正誤問題の正答を返す
+func trueFalseChoice(options []string, correct int) (bool, bool) {
+	if len(options) != 2 {
+		return false, false
+	}
+	first, second := strings.ToLower(options[0]), strings.ToLower(options[1])
+	if !(first == "true" && second == "false") && !(first == "false" && second == "true") {
+		return false, false
+	}
+	return strings.ToLower(options[correct]) == "true", true
+}
+
+// textEntryInteractionを数値問題または短文記述問題に変換する
+func decodeTextEntry(question *domain.QuizQuestion, root, declaration *node) error {
+	correct := declaration.child("correctResponse").values()
+
+	switch baseType := declaration.attr("baseType"); baseType {
+	case "float", "integer":
+		if len(correct) == 0 {
+			return errors.New("item has no correct response")
+		}
+		number, err := strconv.ParseFloat(correct[0], 64)
+		if err != nil {
+			return fmt.Errorf("correct response %q is not a number", correct[0])
+		}
+		tolerance, err := numericTolerance(root, number)
+		if err != nil {
+			return err
+		}
+		question.Type = domain.QuestionNumeric
+		question.CorrectNumber = &number
+		question.Tolerance = tolerance
+		return nil
+	case "string":
+		accepted, caseSensitive := acceptedAnswers(declaration, correct)
+		if len(accepted) == 0 {
+			return errors.New("item has no correct response")
+		}
+		question.Type = domain.QuestionShortText
+		question.AcceptedAnswers = accepted
+		question.CaseSensitive = caseSensitive
+		return nil
+	default:
+		return fmt.Errorf("text entry with base type %q is not supported", baseType)
+	}
+}
+
+// 応答処理のequal演算子から数値問題の許容誤差を求める
+// 相対誤差（パーセント）は正答に対する絶対誤差に換算する
+func numericTolerance(root *node, correct float64) (float64, error) {
+	processing := root.child("responseProcessing")
+	if processing == nil {
+		return 0, nil
+	}
+	equal := processing.find("equal")
+	if equal == nil {
+		return 0, nil
+	}
+
+	bounds := strings.Fields(equal.attr("tolerance"))
+	mode := equal.attr("toleranceMode")
+	if mode == "" || mode == "exact" || len(bounds) == 0 {
+		return 0, nil
+	}
+	// 下限と上限が異なる場合は小さい方を採用する
+	tolerance := math.Inf(1)
+	for _, bound := range bounds {
+		value, err := strconv.ParseFloat(bound, 64)
+		if err != nil {
+			return 0, fmt.Errorf("tolerance %q is not a number", bound)
+		}
+		tolerance = math.Min(tolerance, value)
+	}
+
+	switch mode {
+	case "absolute":
+		return tolerance, nil
+	case "relative":
+		return math.Abs(correct) * tolerance / 100, nil
+	default:
+		return 0, fmt.Errorf("tolerance mode %q is not supported", mode)
+	}
+}
+
+// 短文記述問題の正答として認める回答を返す
+// correctResponseの値と、正の点数が割り当てられたmappingのキーを合わせる
+// mappingがない場合、QTIの文字列比較と同じく大文字と小文字を区別する
+func acceptedAnswers(declaration *node, correct []string) ([]string, bool) {
+	seen := make(map[string]bool)
+	var accepted []string
+	add := func(answer string) {
+		if answer != "" && !seen[answer] {
+			seen[answer] = true
+			accepted = append(accepted, answer)
+		}
+	}
+	for _, answer := range correct {
+		add(answer)
+	}
+
+	caseSensitive := true
+	if mapping := declaration.child("mapping"); mapping != nil {
+		entries := mapping.childrenNamed("mapEntry")
+		if len(entries) > 0 {
+			caseSensitive = false
+		}
+		for _, entry := range entries {
+			if value, err := strconv.ParseFloat(entry.attr("mappedValue"), 64); err != nil || value <= 0 {
+				continue
+			}
+			add(entry.attr("mapKey"))
+			if entry.attr("caseSensitive") == "true" {
+				caseSensitive = true
+			}
+		}
+	}
+	return accepted, caseSensitive
+}
+
+// mappingで正の点数が割り当てられたキーを返す
+func positiveMapKeys(declaration *node) []string {
+	mapping := declaration.child("mapping")
+	if mapping == nil {
+		return nil
+	}
+	var keys []string
+	for _, entry := range mapping.childrenNamed("mapEntry") {
+		if value, err := strconv.ParseFloat(entry.attr("mappedValue"), 64); err == nil && value > 0 {
+			keys = append(keys, entry.attr("mapKey"))
+		}
+	}
+	return keys
+}
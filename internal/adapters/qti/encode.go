@@ -0,0 +1,365 @@
+package qti
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+)
+
+const (
+	// QTI 2.1の名前空間
+	qtiNamespace = "http://www.imsglobal.org/xsd/imsqti_v2p1"
+	// QTI 2.1のスキーマの場所
+	qtiSchemaLocation = qtiNamespace + " http://www.imsglobal.org/xsd/qti/qtiv2p1/imsqti_v2p1.xsd"
+	// IMSコンテンツパッケージの名前空間
+	manifestNamespace = "http://www.imsglobal.org/xsd/imscp_v1p1"
+	// XMLスキーマインスタンスの名前空間
+	xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+	// 短文記述問題の応答処理テンプレート
+	mapResponseTemplate = "http://www.imsglobal.org/question/qti_v2p1/rptemplates/map_response"
+	// アイテムの応答変数の識別子
+	responseIdentifier = "RESPONSE"
+	// タイトルとして使用する問題文の最大文字数
+	maxTitleLength = 60
+)
+
+// QTIパッケージコーデック構造体：QTI 2.1パッケージと問題バンクの相互変換を実装
+type PackageCodec struct{}
+
+// 新しいQTIパッケージコーデックインスタンスを作成する
+func NewPackageCodec() *PackageCodec {
+	return &PackageCodec{}
+}
+
+// 問題バンクの項目をQTI 2.1パッケージ（zip）に変換する
+// 各項目をitems/以下のassessmentItemとして書き出し、マニフェストに登録する
+func (c *PackageCodec) Encode(items []domain.QuestionBankItem) ([]byte, error) {
+	manifest := manifestXML{
+		Xmlns:      manifestNamespace,
+		Identifier: "MANIFEST-question-bank",
+		Metadata:   manifestMetadataXML{Schema: "QTIv2.1 Package", SchemaVersion: "1.0.0"},
+	}
+
+	buf := new(bytes.Buffer)
+	archive := zip.NewWriter(buf)
+	for _, item := range items {
+		identifier := "item-" + item.ID
+		href := "items/" + identifier + ".xml"
+
+		data, err := encodeItem(identifier, item.Question)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode question bank item %s: %w", item.ID, err)
+		}
+		if err := writeZipFile(archive, href, data); err != nil {
+			return nil, err
+		}
+
+		manifest.Resources = append(manifest.Resources, resourceXML{
+			Identifier: identifier,
+			Type:       "imsqti_item_xmlv2p1",
+			Href:       href,
+			File:       fileXML{Href: href},
+		})
+	}
+
+	data, err := marshalXML(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := writeZipFile(archive, manifestFile, data); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write package: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// 問題をassessmentItemのXMLに変換する
+func encodeItem(identifier string, question domain.QuizQuestion) ([]byte, error) {
+	points := formatNumber(question.Points)
+	item := itemXML{
+		Xmlns:          qtiNamespace,
+		XmlnsXsi:       xsiNamespace,
+		SchemaLocation: qtiSchemaLocation,
+		Identifier:     identifier,
+		Title:          itemTitle(question.Prompt),
+		Response: responseDeclarationXML{
+			Identifier:  responseIdentifier,
+			Cardinality: "single",
+			BaseType:    "identifier",
+		},
+		Outcomes: []outcomeDeclarationXML{
+			{Identifier: "SCORE", Cardinality: "single", BaseType: "float", NormalMaximum: points, Default: &defaultValueXML{Value: "0"}},
+			{Identifier: "MAXSCORE", Cardinality: "single", BaseType: "float", Default: &defaultValueXML{Value: points}},
+		},
+	}
+
+	// 選択問題は正答との一致、数値問題は許容誤差内での一致でSCOREに配点を設定する
+	matchCorrect := &responseProcessingXML{Condition: &responseConditionXML{If: responseIfXML{
+		Match: &compareXML{Variable: varRefXML{responseIdentifier}, Correct: varRefXML{responseIdentifier}},
+		Set:   setOutcomeValueXML{Identifier: "SCORE", Value: baseValueXML{BaseType: "float", Value: points}},
+	}}}
+
+	switch question.Type {
+	case domain.QuestionMultipleChoice, domain.QuestionMultiSelect, domain.QuestionTrueFalse:
+		interaction := &choiceInteractionXML{
+			ResponseIdentifier: responseIdentifier,
+			MaxChoices:         1,
+			Prompt:             question.Prompt,
+		}
+		var correct []string
+		switch question.Type {
+		case domain.QuestionTrueFalse:
+			interaction.Choices = []simpleChoiceXML{{Identifier: "true", Text: "True"}, {Identifier: "false", Text: "False"}}
+			correct = []string{strconv.FormatBool(question.CorrectBool != nil && *question.CorrectBool)}
+		case domain.QuestionMultipleChoice:
+			interaction.Choices = simpleChoices(question.Options)
+			if question.CorrectOption != nil {
+				correct = []string{choiceIdentifier(*question.CorrectOption)}
+			}
+		case domain.QuestionMultiSelect:
+			item.Response.Cardinality = "multiple"
+			interaction.MaxChoices = 0
+			interaction.Choices = simpleChoices(question.Options)
+			for _, index := range question.CorrectOptions {
+				correct = append(correct, choiceIdentifier(index))
+			}
+		}
+		item.Response.Correct = &correctResponseXML{Values: correct}
+		item.Body.Choice = interaction
+		item.Processing = matchCorrect
+	case domain.QuestionNumeric:
+		item.Response.BaseType = "float"
+		if question.CorrectNumber != nil {
+			item.Response.Correct = &correctResponseXML{Values: []string{formatNumber(*question.CorrectNumber)}}
+		}
+		item.Body.Paragraphs = textEntryBody(question.Prompt)
+		compare := matchCorrect.Condition.If.Match
+		compare.ToleranceMode = "absolute"
+		compare.Tolerance = formatNumber(question.Tolerance) + " " + formatNumber(question.Tolerance)
+		matchCorrect.Condition.If.Match = nil
+		matchCorrect.Condition.If.Equal = compare
+		item.Processing = matchCorrect
+	case domain.QuestionShortText:
+		item.Response.BaseType = "string"
+		mapping := &mappingXML{DefaultValue: "0", UpperBound: points}
+		for _, answer := range question.AcceptedAnswers {
+			mapping.Entries = append(mapping.Entries, mapEntryXML{MapKey: answer, MappedValue: points, CaseSensitive: question.CaseSensitive})
+		}
+		if len(question.AcceptedAnswers) > 0 {
+			item.Response.Correct = &correctResponseXML{Values: question.AcceptedAnswers[:1]}
+		}
+		item.Response.Mapping = mapping
+		item.Body.Paragraphs = textEntryBody(question.Prompt)
+		item.Processing = &responseProcessingXML{Template: mapResponseTemplate}
+	default:
+		return nil, fmt.Errorf("question type %q cannot be exported", question.Type)
+	}
+
+	return marshalXML(item)
+}
+
+// 選択肢をsimpleChoiceに変換する
+func simpleChoices(options []string) []simpleChoiceXML {
+	choices := make([]simpleChoiceXML, len(options))
+	for i, option := range options {
+		choices[i] = simpleChoiceXML{Identifier: choiceIdentifier(i), Text: option}
+	}
+	return choices
+}
+
+// 選択肢番号から選択肢の識別子を作成する
+func choiceIdentifier(index int) string {
+	return fmt.Sprintf("choice-%d", index)
+}
+
+// 記述式の問題本文（問題文と入力欄）を作成する
+func textEntryBody(prompt string) []paragraphXML {
+	return []paragraphXML{
+		{Text: prompt},
+		{TextEntry: &textEntryXML{ResponseIdentifier: responseIdentifier, ExpectedLength: 20}},
+	}
+}
+
+// 問題文からアイテムのタイトルを作成する
+func itemTitle(prompt string) string {
+	if utf8.RuneCountInString(prompt) <= maxTitleLength {
+		return prompt
+	}
+	runes := []rune(prompt)
+	return string(runes[:maxTitleLength-1]) + "…"
+}
+
+// 数値を余分な桁のない文字列に変換する
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// XML宣言付きでXMLに変換する
+func marshalXML(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// zipにファイルを追加する
+func writeZipFile(archive *zip.Writer, name string, data []byte) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// マニフェストのXML表現
+type manifestXML struct {
+	XMLName       xml.Name            `xml:"manifest"`
+	Xmlns         string              `xml:"xmlns,attr"`
+	Identifier    string              `xml:"identifier,attr"`
+	Metadata      manifestMetadataXML `xml:"metadata"`
+	Organizations struct{}            `xml:"organizations"`
+	Resources     []resourceXML       `xml:"resources>resource"`
+}
+
+type manifestMetadataXML struct {
+	Schema        string `xml:"schema"`
+	SchemaVersion string `xml:"schemaversion"`
+}
+
+type resourceXML struct {
+	Identifier string  `xml:"identifier,attr"`
+	Type       string  `xml:"type,attr"`
+	Href       string  `xml:"href,attr"`
+	File       fileXML `xml:"file"`
+}
+
+type fileXML struct {
+	Href string `xml:"href,attr"`
+}
+
+// assessmentItemのXML表現
+type itemXML struct {
+	XMLName        xml.Name                `xml:"assessmentItem"`
+	Xmlns          string                  `xml:"xmlns,attr"`
+	XmlnsXsi       string                  `xml:"xmlns:xsi,attr"`
+	SchemaLocation string                  `xml:"xsi:schemaLocation,attr"`
+	Identifier     string                  `xml:"identifier,attr"`
+	Title          string                  `xml:"title,attr"`
+	Adaptive       bool                    `xml:"adaptive,attr"`
+	TimeDependent  bool                    `xml:"timeDependent,attr"`
+	Response       responseDeclarationXML  `xml:"responseDeclaration"`
+	Outcomes       []outcomeDeclarationXML `xml:"outcomeDeclaration"`
+	Body           itemBodyXML             `xml:"itemBody"`
+	Processing     *responseProcessingXML  `xml:"responseProcessing"`
+}
+
+type responseDeclarationXML struct {
+	Identifier  string              `xml:"identifier,attr"`
+	Cardinality string              `xml:"cardinality,attr"`
+	BaseType    string              `xml:"baseType,attr"`
+	Correct     *correctResponseXML `xml:"correctResponse"`
+	Mapping     *mappingXML         `xml:"mapping"`
+}
+
+type correctResponseXML struct {
+	Values []string `xml:"value"`
+}
+
+type mappingXML struct {
+	DefaultValue string        `xml:"defaultValue,attr"`
+	UpperBound   string        `xml:"upperBound,attr,omitempty"`
+	Entries      []mapEntryXML `xml:"mapEntry"`
+}
+
+type mapEntryXML struct {
+	MapKey        string `xml:"mapKey,attr"`
+	MappedValue   string `xml:"mappedValue,attr"`
+	CaseSensitive bool   `xml:"caseSensitive,attr"`
+}
+
+type outcomeDeclarationXML struct {
+	Identifier    string           `xml:"identifier,attr"`
+	Cardinality   string           `xml:"cardinality,attr"`
+	BaseType      string           `xml:"baseType,attr"`
+	NormalMaximum string           `xml:"normalMaximum,attr,omitempty"`
+	Default       *defaultValueXML `xml:"defaultValue"`
+}
+
+type defaultValueXML struct {
+	Value string `xml:"value"`
+}
+
+type itemBodyXML struct {
+	Paragraphs []paragraphXML        `xml:"p"`
+	Choice     *choiceInteractionXML `xml:"choiceInteraction"`
+}
+
+type paragraphXML struct {
+	Text      string        `xml:",chardata"`
+	TextEntry *textEntryXML `xml:"textEntryInteraction"`
+}
+
+type textEntryXML struct {
+	ResponseIdentifier string `xml:"responseIdentifier,attr"`
+	ExpectedLength     int    `xml:"expectedLength,attr"`
+}
+
+type choiceInteractionXML struct {
+	ResponseIdentifier string            `xml:"responseIdentifier,attr"`
+	Shuffle            bool              `xml:"shuffle,attr"`
+	MaxChoices         int               `xml:"maxChoices,attr"`
+	Prompt             string            `xml:"prompt"`
+	Choices            []simpleChoiceXML `xml:"simpleChoice"`
+}
+
+type simpleChoiceXML struct {
+	Identifier string `xml:"identifier,attr"`
+	Text       string `xml:",chardata"`
+}
+
+type responseProcessingXML struct {
+	Template  string                `xml:"template,attr,omitempty"`
+	Condition *responseConditionXML `xml:"responseCondition"`
+}
+
+type responseConditionXML struct {
+	If responseIfXML `xml:"responseIf"`
+}
+
+type responseIfXML struct {
+	Match *compareXML        `xml:"match"`
+	Equal *compareXML        `xml:"equal"`
+	Set   setOutcomeValueXML `xml:"setOutcomeValue"`
+}
+
+type compareXML struct {
+	ToleranceMode string    `xml:"toleranceMode,attr,omitempty"`
+	Tolerance     string    `xml:"tolerance,attr,omitempty"`
+	Variable      varRefXML `xml:"variable"`
+	Correct       varRefXML `xml:"correct"`
+}
+
+type varRefXML struct {
+	Identifier string `xml:"identifier,attr"`
+}
+
+type setOutcomeValueXML struct {
+	Identifier string       `xml:"identifier,attr"`
+	Value      baseValueXML `xml:"baseValue"`
+}
+
+type baseValueXML struct {
+	BaseType string `xml:"baseType,attr"`
+	Value    string `xml:",chardata"`
+}
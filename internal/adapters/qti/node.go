@@ -0,0 +1,164 @@
+package qti
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// XMLノード構造体：QTIのXMLを名前空間を無視して走査するための簡易的な木構造
+// テキストノードはnameが空で、textに内容を保持する
+type node struct {
+	// 要素のローカル名
+	name string
+	// 属性（ローカル名をキーとする）
+	attrs map[string]string
+	// テキストノードの内容
+	text string
+	// 子ノード（要素とテキストを出現順に保持）
+	children []*node
+}
+
+// テキスト抽出時に前後を空白で区切るブロック要素
+var blockElements = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "ul": true, "ol": true,
+	"table": true, "tr": true, "td": true, "th": true, "blockquote": true, "pre": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"prompt": true, "simpleChoice": true,
+}
+
+// XMLを読み込み、ルート要素のノードを返す
+func parseNode(r io.Reader) (*node, error) {
+	decoder := xml.NewDecoder(r)
+	var stack []*node
+	var root *node
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			n := &node{name: t.Name.Local, attrs: make(map[string]string, len(t.Attr))}
+			for _, attr := range t.Attr {
+				n.attrs[attr.Name.Local] = attr.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			} else if root == nil {
+				root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, &node{text: string(t)})
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("document has no root element")
+	}
+	return root, nil
+}
+
+// 属性の値を前後の空白を除いて返す
+func (n *node) attr(name string) string {
+	return strings.TrimSpace(n.attrs[name])
+}
+
+// 指定された名前の最初の子要素を返す
+func (n *node) child(name string) *node {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// 指定された名前の子要素をすべて返す
+func (n *node) childrenNamed(name string) []*node {
+	var found []*node
+	for _, c := range n.children {
+		if c.name == name {
+			found = append(found, c)
+		}
+	}
+	return found
+}
+
+// 条件に一致する子孫要素を深さ優先ですべて返す
+func (n *node) findAll(match func(*node) bool) []*node {
+	var found []*node
+	for _, c := range n.children {
+		if c.name == "" {
+			continue
+		}
+		if match(c) {
+			found = append(found, c)
+			continue
+		}
+		found = append(found, c.findAll(match)...)
+	}
+	return found
+}
+
+// 指定された名前の最初の子孫要素を返す
+func (n *node) find(name string) *node {
+	found := n.findAll(func(c *node) bool { return c.name == name })
+	if len(found) == 0 {
+		return nil
+	}
+	return found[0]
+}
+
+// 要素内のテキストを空白を正規化して返す
+// skipがtrueを返す子孫要素のテキストは含めない
+func (n *node) textContent(skip func(*node) bool) string {
+	var b strings.Builder
+	n.writeText(&b, skip)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func (n *node) writeText(b *strings.Builder, skip func(*node) bool) {
+	for _, c := range n.children {
+		if c.name == "" {
+			b.WriteString(c.text)
+			continue
+		}
+		if skip != nil && skip(c) {
+			continue
+		}
+		if blockElements[c.name] {
+			b.WriteByte(' ')
+		}
+		c.writeText(b, skip)
+		if blockElements[c.name] {
+			b.WriteByte(' ')
+		}
+	}
+}
+
+// valueの子要素のテキストを一覧で返す（correctResponse、defaultValueなどで使用）
+func (n *node) values() []string {
+	if n == nil {
+		return nil
+	}
+	var values []string
+	for _, v := range n.childrenNamed("value") {
+		values = append(values, v.textContent(nil))
+	}
+	return values
+}
@@ -0,0 +1,337 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 課題リポジトリ構造体：データベースを使用した課題と提出物の永続化を実装
+type AssignmentRepository struct {
+	// データベース接続
+	db *gorm.DB
+}
+
+// 課題データベースモデル：データベースのassignmentsテーブルとマッピング
+type Assignment struct {
+	// 課題の一意識別子
+	ID uint `gorm:"primaryKey"`
+	// 課題を作成した教師のID
+	TeacherID uint `gorm:"not null;index"`
+	// 課題のタイトル
+	Title string `gorm:"not null"`
+	// 課題の説明・指示
+	Instructions string `gorm:"not null"`
+	// 提出期限
+	DueDate time.Time `gorm:"not null"`
+	// 満点
+	MaxPoints float64 `gorm:"not null"`
+	// レコードの作成日時
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	// レコードの更新日時
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	// 添付ファイル
+	Files []AssignmentFile `gorm:"foreignKey:AssignmentID"`
+}
+
+// テーブル名を指定する
+func (Assignment) TableName() string {
+	return "assignments"
+}
+
+// 課題添付ファイルデータベースモデル：assignment_filesテーブルとマッピング
+type AssignmentFile struct {
+	// 課題ID
+	AssignmentID uint `gorm:"primaryKey"`
+	// ファイルストレージのファイルID
+	FileID string `gorm:"primaryKey"`
+	// レコードの作成日時
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// テーブル名を指定する
+func (AssignmentFile) TableName() string {
+	return "assignment_files"
+}
+
+// 提出物データベースモデル：submissionsテーブルとマッピング
+type Submission struct {
+	// 提出物の一意識別子
+	ID uint `gorm:"primaryKey"`
+	// 対象の課題ID
+	AssignmentID uint `gorm:"not null"`
+	// 提出した学生のID
+	StudentID uint `gorm:"not null"`
+	// テキストによる回答
+	Text string `gorm:"not null"`
+	// 期限後の提出かどうか
+	Late bool `gorm:"not null"`
+	// 提出回数
+	Attempt int `gorm:"not null"`
+	// 最新の提出日時
+	SubmittedAt time.Time `gorm:"not null"`
+	// レコードの作成日時
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	// レコードの更新日時
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	// 提出ファイル
+	Files []SubmissionFile `gorm:"foreignKey:SubmissionID"`
+}
+
+// テーブル名を指定する
+func (Submission) TableName() string {
+	return "submissions"
+}
+
+// 提出ファイルデータベースモデル：submission_filesテーブルとマッピング
+type SubmissionFile struct {
+	// 提出物ID
+	SubmissionID uint `gorm:"primaryKey"`
+	// ファイルストレージのファイルID
+	FileID string `gorm:"primaryKey"`
+	// レコードの作成日時
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// テーブル名を指定する
+func (SubmissionFile) TableName() string {
+	return "submission_files"
+}
+
+// 新しい課題リポジトリインスタンスを作成する
+func NewAssignmentRepository(db *gorm.DB) *AssignmentRepository {
+	return &AssignmentRepository{
+		db: db,
+	}
+}
+
+// 新しい課題を作成し、作成された課題のIDを返す
+func (r *AssignmentRepository) CreateAssignment(assignment *domain.Assignment) (int64, error) {
+	model := Assignment{
+		TeacherID:    uint(assignment.TeacherID),
+		Title:        assignment.Title,
+		Instructions: assignment.Instructions,
+		DueDate:      assignment.DueDate,
+		MaxPoints:    assignment.MaxPoints,
+	}
+
+	result := r.db.Create(&model)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to create assignment: %w", result.Error)
+	}
+
+	return int64(model.ID), nil
+}
+
+// 指定されたIDの課題を添付ファイルと共に取得する
+func (r *AssignmentRepository) GetAssignmentByID(id int64) (*domain.Assignment, error) {
+	var model Assignment
+	result := r.db.Preload("Files").First(&model, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no assignment found with id: %d", domain.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("query error: %w", result.Error)
+	}
+
+	assignment := toDomainAssignment(model)
+	return &assignment, nil
+}
+
+// 教師が作成した課題一覧を取得する
+func (r *AssignmentRepository) GetAssignmentsByTeacherID(teacherID int64) ([]domain.Assignment, error) {
+	var models []Assignment
+	result := r.db.Preload("Files").
+		Where("teacher_id = ?", teacherID).
+		Order("due_date").
+		Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get assignments: %w", result.Error)
+	}
+
+	return toDomainAssignments(models), nil
+}
+
+// 学生の担当教師が作成した課題一覧を取得する
+func (r *AssignmentRepository) GetAssignmentsByStudentID(studentID int64) ([]domain.Assignment, error) {
+	var models []Assignment
+	result := r.db.Preload("Files").
+		Joins("JOIN teacher_students ts ON ts.teacher_id = assignments.teacher_id").
+		Where("ts.student_id = ?", studentID).
+		Order("assignments.due_date").
+		Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get assignments: %w", result.Error)
+	}
+
+	return toDomainAssignments(models), nil
+}
+
+// 課題にファイルを添付する
+func (r *AssignmentRepository) AddAssignmentFile(assignmentID int64, fileID string) error {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&AssignmentFile{
+		AssignmentID: uint(assignmentID),
+		FileID:       fileID,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to attach file to assignment: %w", result.Error)
+	}
+
+	return nil
+}
+
+// 学生の課題提出物を取得する
+func (r *AssignmentRepository) GetSubmission(assignmentID, studentID int64) (*domain.Submission, error) {
+	var model Submission
+	result := r.db.Preload("Files").
+		Where("assignment_id = ? AND student_id = ?", assignmentID, studentID).
+		First(&model)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no submission for assignment %d by student %d", domain.ErrNotFound, assignmentID, studentID)
+		}
+		return nil, fmt.Errorf("query error: %w", result.Error)
+	}
+
+	submission := toDomainSubmission(model)
+	return &submission, nil
+}
+
+// 提出物を保存し、置き換えた以前の提出ファイルのIDを返す
+// 既存の提出物がある場合は行をロックして提出回数を1増やし、内容と提出ファイルを置き換える
+// 同時に提出された場合も後の提出が先の提出を置き換えるため、提出回数や置き換えたファイルが失われない
+func (r *AssignmentRepository) SaveSubmission(submission *domain.Submission) ([]string, error) {
+	model := Submission{
+		AssignmentID: uint(submission.AssignmentID),
+		StudentID:    uint(submission.StudentID),
+		Text:         submission.Text,
+		Late:         submission.Late,
+		Attempt:      1,
+		SubmittedAt:  submission.SubmittedAt,
+	}
+
+	var replaced []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// 初回の提出として作成する（課題と学生の組は一意のため、既に提出物がある場合は何もしない）
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit("Files").Create(&model)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			// 既存の提出物をロックして再提出として更新する
+			var current Submission
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("assignment_id = ? AND student_id = ?", model.AssignmentID, model.StudentID).
+				First(&current).Error; err != nil {
+				return err
+			}
+			var files []SubmissionFile
+			if err := tx.Where("submission_id = ?", current.ID).Find(&files).Error; err != nil {
+				return err
+			}
+			for _, file := range files {
+				replaced = append(replaced, file.FileID)
+			}
+
+			model.ID = current.ID
+			model.Attempt = current.Attempt + 1
+			model.CreatedAt = current.CreatedAt
+			if err := tx.Model(&current).Select("text", "late", "attempt", "submitted_at").Updates(&model).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("submission_id = ?", current.ID).Delete(&SubmissionFile{}).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, fileID := range submission.FileIDs {
+			if err := tx.Create(&SubmissionFile{SubmissionID: model.ID, FileID: fileID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save submission: %w", err)
+	}
+
+	submission.ID = int64(model.ID)
+	submission.Attempt = model.Attempt
+	submission.Resubmitted = model.Attempt > 1
+	submission.CreatedAt = model.CreatedAt
+	return replaced, nil
+}
+
+// 課題の提出物一覧を取得する
+func (r *AssignmentRepository) GetSubmissionsByAssignmentID(assignmentID int64) ([]domain.Submission, error) {
+	var models []Submission
+	result := r.db.Preload("Files").
+		Where("assignment_id = ?", assignmentID).
+		Order("submitted_at").
+		Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get submissions: %w", result.Error)
+	}
+
+	submissions := make([]domain.Submission, len(models))
+	for i, m := range models {
+		submissions[i] = toDomainSubmission(m)
+	}
+
+	return submissions, nil
+}
+
+// 課題データベースモデルをドメインモデルに変換する
+func toDomainAssignment(m Assignment) domain.Assignment {
+	fileIDs := make([]string, len(m.Files))
+	for i, f := range m.Files {
+		fileIDs[i] = f.FileID
+	}
+
+	return domain.Assignment{
+		ID:           int64(m.ID),
+		TeacherID:    int64(m.TeacherID),
+		Title:        m.Title,
+		Instructions: m.Instructions,
+		DueDate:      m.DueDate,
+		MaxPoints:    m.MaxPoints,
+		FileIDs:      fileIDs,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+// 課題データベースモデルのリストをドメインモデルに変換する
+func toDomainAssignments(models []Assignment) []domain.Assignment {
+	assignments := make([]domain.Assignment, len(models))
+	for i, m := range models {
+		assignments[i] = toDomainAssignment(m)
+	}
+	return assignments
+}
+
+// 提出物データベースモデルをドメインモデルに変換する
+func toDomainSubmission(m Submission) domain.Submission {
+	fileIDs := make([]string, len(m.Files))
+	for i, f := range m.Files {
+		fileIDs[i] = f.FileID
+	}
+
+	return domain.Submission{
+		ID:           int64(m.ID),
+		AssignmentID: int64(m.AssignmentID),
+		StudentID:    int64(m.StudentID),
+		Text:         m.Text,
+		FileIDs:      fileIDs,
+		Late:         m.Late,
+		Resubmitted:  m.Attempt > 1,
+		Attempt:      m.Attempt,
+		SubmittedAt:  m.SubmittedAt,
+		CreatedAt:    m.CreatedAt,
+	}
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+// ドキュメントスキーマリポジトリ構造体：データベースを使用したドキュメントのJSON Schemaの永続化を実装
+type DocumentSchemaRepository struct {
+	// データベース接続
+	db *gorm.DB
+}
+
+// ドキュメントスキーマデータベースモデル：document_schemasテーブルとマッピング
+type DocumentSchema struct {
+	// スキーマを適用するドキュメントの種類
+	Type string `gorm:"primaryKey"`
+	// スキーマのバージョン
+	Version int `gorm:"primaryKey;autoIncrement:false"`
+	// JSON Schema本体（JSON）
+	Schema string `gorm:"type:jsonb;not null"`
+	// スキーマを登録した管理者のID
+	CreatedBy uint `gorm:"not null"`
+	// 登録日時
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// テーブル名を指定する
+func (DocumentSchema) TableName() string {
+	return "document_schemas"
+}
+
+// 新しいドキュメントスキーマリポジトリインスタンスを作成する
+func NewDocumentSchemaRepository(db *gorm.DB) *DocumentSchemaRepository {
+	return &DocumentSchemaRepository{
+		db: db,
+	}
+}
+
+// スキーマを種類の次のバージョンとして登録し、採番したバージョンを設定する
+// 同じ種類のスキーマの登録はアドバイザリロックで直列化し、バージョンが重複しないようにする
+func (r *DocumentSchemaRepository) CreateDocumentSchema(schema *domain.DocumentSchema) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "document_schemas:"+schema.Type).Error; err != nil {
+			return err
+		}
+
+		var latest int
+		if err := tx.Model(&DocumentSchema{}).Where("type = ?", schema.Type).
+			Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		model := DocumentSchema{
+			Type:      schema.Type,
+			Version:   latest + 1,
+			Schema:    string(schema.Schema),
+			CreatedBy: uint(schema.CreatedBy),
+			CreatedAt: schema.CreatedAt,
+		}
+		if err := tx.Create(&model).Error; err != nil {
+			return err
+		}
+		schema.Version = model.Version
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create document schema: %w", err)
+	}
+	return nil
+}
+
+// 種類の最新のバージョンのスキーマを取得する
+func (r *DocumentSchemaRepository) GetLatestDocumentSchema(docType string) (*domain.DocumentSchema, error) {
+	var model DocumentSchema
+	result := r.db.Where("type = ?", docType).Order("version DESC").First(&model)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no schema registered for document type: %s", domain.ErrNotFound, docType)
+		}
+		return nil, fmt.Errorf("failed to get document schema: %w", result.Error)
+	}
+	schema := toDomainDocumentSchema(model)
+	return &schema, nil
+}
+
+// 種類の指定されたバージョンのスキーマを取得する
+func (r *DocumentSchemaRepository) GetDocumentSchema(docType string, version int) (*domain.DocumentSchema, error) {
+	var model DocumentSchema
+	result := r.db.Where("type = ? AND version = ?", docType, version).First(&model)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no schema version %d for document type: %s", domain.ErrNotFound, version, docType)
+		}
+		return nil, fmt.Errorf("failed to get document schema: %w", result.Error)
+	}
+	schema := toDomainDocumentSchema(model)
+	return &schema, nil
+}
+
+// 種類のスキーマのすべてのバージョンを新しい順に取得する
+func (r *DocumentSchemaRepository) ListDocumentSchemaVersions(docType string) ([]domain.DocumentSchema, error) {
+	var models []DocumentSchema
+	if err := r.db.Where("type = ?", docType).Order("version DESC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list document schemas: %w", err)
+	}
+	return toDomainDocumentSchemas(models), nil
+}
+
+// 種類ごとの最新のバージョンのスキーマを種類の順に取得する
+func (r *DocumentSchemaRepository) ListLatestDocumentSchemas() ([]domain.DocumentSchema, error) {
+	var models []DocumentSchema
+	result := r.db.Raw(`SELECT DISTINCT ON (type) * FROM document_schemas ORDER BY type, version DESC`).Scan(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list document schemas: %w", result.Error)
+	}
+	return toDomainDocumentSchemas(models), nil
+}
+
+// データベースモデルをドメインモデルに変換する
+func toDomainDocumentSchema(m DocumentSchema) domain.DocumentSchema {
+	return domain.DocumentSchema{
+		Type:      m.Type,
+		Version:   m.Version,
+		Schema:    []byte(m.Schema),
+		CreatedBy: int64(m.CreatedBy),
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// データベースモデルの一覧をドメインモデルの一覧に変換する
+func toDomainDocumentSchemas(models []DocumentSchema) []domain.DocumentSchema {
+	schemas := make([]domain.DocumentSchema, len(models))
+	for i, m := range models {
+		schemas[i] = toDomainDocumentSchema(m)
+	}
+	return schemas
+}
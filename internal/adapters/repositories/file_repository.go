@@ -0,0 +1,923 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ファイルリポジトリ構造体：データベースを使用したファイルカタログの永続化を実装
+type FileRepository struct {
+	// データベース接続
+	db *gorm.DB
+}
+
+// ファイルデータベースモデル：filesテーブルとマッピング
+type File struct {
+	// ファイルの一意識別子（UUID）
+	ID string `gorm:"primaryKey"`
+	// ストレージ上のオブジェクトキー
+	ObjectKey string `gorm:"not null"`
+	// アップロード時の元のファイル名
+	OriginalName string `gorm:"not null"`
+	// ファイルのサイズ（バイト）
+	Size int64 `gorm:"not null"`
+	// ファイルのMIMEタイプ
+	ContentType string `gorm:"not null"`
+	// ファイル内容のSHA-256チェックサム
+	Checksum string `gorm:"not null"`
+	// アップロードしたユーザーのID
+	UploaderID *uint
+	// アップロードしたユーザーの役割
+	UploaderRole string `gorm:"not null"`
+	// 現在のバージョン番号
+	Version int `gorm:"not null"`
+	// 現在のバージョンの作成日時
+	CreatedAt time.Time `gorm:"not null"`
+	// ゴミ箱に移動した日時（ゴミ箱にない場合はnil）
+	DeletedAt *time.Time
+	// ファイルが属するフォルダのID（どのフォルダにも属さない場合はnil）
+	FolderID *string
+}
+
+// テーブル名を指定する
+func (File) TableName() string {
+	return "files"
+}
+
+// ファイルバージョンデータベースモデル：file_versionsテーブルとマッピング
+type FileVersion struct {
+	// ファイルの一意識別子
+	FileID string `gorm:"primaryKey"`
+	// バージョン番号（1から始まる連番）
+	Version int `gorm:"primaryKey"`
+	// ストレージ上のオブジェクトキー
+	ObjectKey string `gorm:"not null"`
+	// アップロード時の元のファイル名
+	OriginalName string `gorm:"not null"`
+	// ファイルのサイズ（バイト）
+	Size int64 `gorm:"not null"`
+	// ファイルのMIMEタイプ
+	ContentType string `gorm:"not null"`
+	// ファイル内容のSHA-256チェックサム
+	Checksum string `gorm:"not null"`
+	// このバージョンをアップロードしたユーザーのID
+	UploaderID *uint
+	// このバージョンをアップロードしたユーザーの役割
+	UploaderRole string `gorm:"not null"`
+	// 作成日時
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// テーブル名を指定する
+func (FileVersion) TableName() string {
+	return "file_versions"
+}
+
+// ファイルタグデータベースモデル：file_tagsテーブルとマッピング
+type FileTag struct {
+	// ファイルの一意識別子
+	FileID string `gorm:"primaryKey"`
+	// タグ
+	Tag string `gorm:"primaryKey"`
+}
+
+// テーブル名を指定する
+func (FileTag) TableName() string {
+	return "file_tags"
+}
+
+// ストレージ使用量データベースモデル：storage_usageテーブルとマッピング
+type StorageUsage struct {
+	// アップロードしたユーザーの役割
+	UploaderRole string `gorm:"primaryKey"`
+	// アップロードしたユーザーのID
+	UploaderID uint `gorm:"primaryKey"`
+	// 保存しているファイルの合計サイズ
+	Bytes int64 `gorm:"not null"`
+	// 保存しているファイル数
+	Files int64 `gorm:"not null"`
+	// 更新日時
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+// テーブル名を指定する
+func (StorageUsage) TableName() string {
+	return "storage_usage"
+}
+
+// ブロブデータベースモデル：blobsテーブルとマッピング
+// 同じ内容のファイルは1つのオブジェクトを共有し、参照しているファイル数を記録する
+type Blob struct {
+	// 内容のSHA-256チェックサム
+	Checksum string `gorm:"primaryKey"`
+	// ストレージ上のオブジェクトキー
+	ObjectKey string `gorm:"not null"`
+	// 内容のサイズ（バイト）
+	Size int64 `gorm:"not null"`
+	// 参照しているファイル数
+	RefCount int `gorm:"not null"`
+	// マルウェア検査の状態（pending_scan, clean, infected）
+	ScanStatus string `gorm:"not null"`
+	// 検出したマルウェアの名前（検出していない場合はnil）
+	ScanSignature *string
+	// 検査した日時（検査前の場合はnil）
+	ScannedAt *time.Time
+	// 作成日時
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// テーブル名を指定する
+func (Blob) TableName() string {
+	return "blobs"
+}
+
+// 完了したアップロードのデータベースモデル：completed_uploadsテーブルとマッピング
+// 直接アップロードのオブジェクトキーごとに1行だけ登録できるため、同じアップロードを二重に登録できない
+type CompletedUpload struct {
+	// アップロードされたオブジェクトのキー
+	UploadKey string `gorm:"primaryKey"`
+	// 登録したファイルのID
+	FileID string `gorm:"not null"`
+	// 登録日時
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// テーブル名を指定する
+func (CompletedUpload) TableName() string {
+	return "completed_uploads"
+}
+
+// 新しいファイルリポジトリインスタンスを作成する
+func NewFileRepository(db *gorm.DB) *FileRepository {
+	return &FileRepository{
+		db: db,
+	}
+}
+
+// ファイルのメタデータを最初のバージョンとして登録し、アップロードしたユーザーの使用量に加算する
+// 使用量の行を条件付きで更新するため、同時に登録しても使用量がクォータを超えることはない
+// 同じチェックサムのブロブが既にある場合は参照数を加算し、ファイルは既存のオブジェクトを参照する
+func (r *FileRepository) CreateFile(file *domain.File, quota domain.StorageQuota) error {
+	return r.createFile(file, quota, nil)
+}
+
+// 直接アップロードされたオブジェクトをファイルとして登録する
+// file.ObjectKeyをアップロードのキーとして同じトランザクションで記録し、既に記録されている場合はErrAlreadyExistsを返す
+// キーは主キーのため、同じアップロードを同時に完了しても登録されるのは1回だけになる
+func (r *FileRepository) CreateUploadedFile(file *domain.File, quota domain.StorageQuota) error {
+	uploadKey := file.ObjectKey
+	return r.createFile(file, quota, func(tx *gorm.DB) error {
+		upload := CompletedUpload{UploadKey: uploadKey, FileID: file.ID, CreatedAt: time.Now()}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&upload)
+		if result.Error != nil {
+			return fmt.Errorf("failed to record completed upload: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: upload %s has already been completed", domain.ErrAlreadyExists, uploadKey)
+		}
+		return nil
+	})
+}
+
+// ファイルのメタデータを最初のバージョンとして登録する
+// beforeが指定されている場合は、同じトランザクションで使用量の加算より前に実行する
+func (r *FileRepository) createFile(file *domain.File, quota domain.StorageQuota, before func(tx *gorm.DB) error) error {
+	model := toFileModel(file)
+	model.Version = 1
+	var scanStatus string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if before != nil {
+			if err := before(tx); err != nil {
+				return err
+			}
+		}
+		if err := chargeStorageUsage(tx, model.UploaderRole, model.UploaderID, model.Size, 1, quota); err != nil {
+			return err
+		}
+
+		blob, err := acquireBlob(tx, model.Checksum, model.ObjectKey, model.Size)
+		if err != nil {
+			return err
+		}
+		model.ObjectKey = blob.ObjectKey
+		scanStatus = blob.ScanStatus
+
+		if err := tx.Create(&model).Error; err != nil {
+			return fmt.Errorf("failed to create file record: %w", err)
+		}
+		version := toFileVersionModel(model)
+		if err := tx.Create(&version).Error; err != nil {
+			return fmt.Errorf("failed to create file version: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	file.ObjectKey = model.ObjectKey
+	file.Version = model.Version
+	file.ScanStatus = scanStatus
+	return nil
+}
+
+// 既存のファイルに新しいバージョンを追加し、ファイルの所有者の使用量に加算する
+// ファイルのメタデータは新しいバージョンの内容に更新し、retainを超える古いバージョンは削除する
+// 参照がなくなったオブジェクトのキーを返す
+func (r *FileRepository) AddFileVersion(file *domain.File, quota domain.StorageQuota, retain int) ([]string, error) {
+	var released []string
+	var next File
+	var scanStatus string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// 同じファイルへの同時の追加を直列化するため、ファイルの行をロックする
+		var current File
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND deleted_at IS NULL", file.ID).First(&current)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: no file found with id: %s", domain.ErrNotFound, file.ID)
+			}
+			return fmt.Errorf("query error: %w", result.Error)
+		}
+
+		// バージョンの容量はファイルの所有者の使用量として扱う（ファイル数は増えない）
+		if err := chargeStorageUsage(tx, current.UploaderRole, current.UploaderID, file.Size, 0, quota); err != nil {
+			return err
+		}
+
+		blob, err := acquireBlob(tx, file.Checksum, file.ObjectKey, file.Size)
+		if err != nil {
+			return err
+		}
+		scanStatus = blob.ScanStatus
+
+		next = toFileModel(file)
+		next.ObjectKey = blob.ObjectKey
+		next.Version = current.Version + 1
+		version := toFileVersionModel(next)
+		if err := tx.Create(&version).Error; err != nil {
+			return fmt.Errorf("failed to create file version: %w", err)
+		}
+
+		result = tx.Model(&current).Updates(map[string]interface{}{
+			"object_key":    next.ObjectKey,
+			"original_name": next.OriginalName,
+			"size":          next.Size,
+			"content_type":  next.ContentType,
+			"checksum":      next.Checksum,
+			"version":       next.Version,
+			"created_at":    next.CreatedAt,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update file record: %w", result.Error)
+		}
+
+		if retain <= 0 {
+			return nil
+		}
+		var expired []FileVersion
+		result = tx.Where("file_id = ?", file.ID).Order("version DESC").Offset(retain).Find(&expired)
+		if result.Error != nil {
+			return fmt.Errorf("failed to list file versions: %w", result.Error)
+		}
+		released, err = deleteFileVersions(tx, current, expired)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	file.ObjectKey = next.ObjectKey
+	file.Version = next.Version
+	file.ScanStatus = scanStatus
+	return released, nil
+}
+
+// 指定されたIDのファイルのバージョン一覧を新しい順に取得する
+func (r *FileRepository) ListFileVersions(id string) ([]domain.File, error) {
+	var models []FileVersion
+	result := r.db.Where("file_id = ?", id).Order("version DESC").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list file versions: %w", result.Error)
+	}
+	if len(models) == 0 {
+		return nil, fmt.Errorf("%w: no file found with id: %s", domain.ErrNotFound, id)
+	}
+
+	versions := make([]domain.File, len(models))
+	for i, m := range models {
+		versions[i] = toDomainFileVersion(m)
+	}
+	if err := loadScanStatus(r.db, versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// 指定されたIDのファイルの指定されたバージョンを取得する
+func (r *FileRepository) GetFileVersion(id string, version int) (*domain.File, error) {
+	var model FileVersion
+	result := r.db.Where("file_id = ? AND version = ?", id, version).First(&model)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no version %d found for file: %s", domain.ErrNotFound, version, id)
+		}
+		return nil, fmt.Errorf("query error: %w", result.Error)
+	}
+
+	files := []domain.File{toDomainFileVersion(model)}
+	if err := loadScanStatus(r.db, files); err != nil {
+		return nil, err
+	}
+	return &files[0], nil
+}
+
+// 指定されたIDのファイルのメタデータを取得する（ゴミ箱にあるファイルは除く）
+func (r *FileRepository) GetFileByID(id string) (*domain.File, error) {
+	var model File
+	result := r.db.Where("id = ? AND deleted_at IS NULL", id).First(&model)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no file found with id: %s", domain.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("query error: %w", result.Error)
+	}
+
+	files := []domain.File{toDomainFile(model)}
+	if err := loadFileTags(r.db, files); err != nil {
+		return nil, err
+	}
+	if err := loadScanStatus(r.db, files); err != nil {
+		return nil, err
+	}
+	return &files[0], nil
+}
+
+// 指定されたオブジェクトキーを参照しているファイルのメタデータを取得する
+func (r *FileRepository) GetFileByObjectKey(objectKey string) (*domain.File, error) {
+	var model File
+	result := r.db.Where("object_key = ?", objectKey).First(&model)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no file found with object key: %s", domain.ErrNotFound, objectKey)
+		}
+		return nil, fmt.Errorf("query error: %w", result.Error)
+	}
+
+	files := []domain.File{toDomainFile(model)}
+	if err := loadScanStatus(r.db, files); err != nil {
+		return nil, err
+	}
+	return &files[0], nil
+}
+
+// 条件に一致するファイルのメタデータ一覧をアップロード日時の新しい順に取得する（ゴミ箱にあるファイルと隔離したファイルは除く）
+// タグを指定した場合は、指定されたすべてのタグが付いているファイルのみを返す
+func (r *FileRepository) ListFiles(filter domain.FileFilter) ([]domain.File, error) {
+	query := r.db.Where("deleted_at IS NULL").Where("checksum NOT IN (?)", r.infectedChecksums())
+	if filter.UploaderRole != "" {
+		query = query.Where("uploader_role = ? AND uploader_id = ?", filter.UploaderRole, filter.UploaderID)
+	}
+	if filter.FolderID != "" {
+		query = query.Where("folder_id = ?", filter.FolderID)
+	} else if filter.Unfiled {
+		query = query.Where("folder_id IS NULL")
+	}
+	if len(filter.Tags) > 0 {
+		tagged := r.db.Model(&FileTag{}).Select("file_id").Where("tag IN ?", filter.Tags).
+			Group("file_id").Having("COUNT(*) = ?", len(filter.Tags))
+		query = query.Where("id IN (?)", tagged)
+	}
+
+	var models []File
+	result := query.Order("created_at DESC, id").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list files: %w", result.Error)
+	}
+
+	files := make([]domain.File, len(models))
+	for i, m := range models {
+		files[i] = toDomainFile(m)
+	}
+	if err := loadFileTags(r.db, files); err != nil {
+		return nil, err
+	}
+	if err := loadScanStatus(r.db, files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// 指定されたIDのファイルを指定されたフォルダへ移動する
+// カタログのフォルダのみを更新し、オブジェクトキーやバージョンは変わらない
+func (r *FileRepository) MoveFile(id string, folderID *string) error {
+	result := r.db.Model(&File{}).Where("id = ? AND deleted_at IS NULL", id).Update("folder_id", folderID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to move file: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: no file found with id: %s", domain.ErrNotFound, id)
+	}
+	return nil
+}
+
+// 指定されたIDのファイルのタグを置き換える
+func (r *FileRepository) SetFileTags(id string, tags []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 同じファイルへの同時の設定を直列化するため、ファイルの行をロックする
+		var model File
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND deleted_at IS NULL", id).First(&model)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: no file found with id: %s", domain.ErrNotFound, id)
+			}
+			return fmt.Errorf("query error: %w", result.Error)
+		}
+
+		if err := tx.Where("file_id = ?", id).Delete(&FileTag{}).Error; err != nil {
+			return fmt.Errorf("failed to delete file tags: %w", err)
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+		models := make([]FileTag, len(tags))
+		for i, tag := range tags {
+			models[i] = FileTag{FileID: id, Tag: tag}
+		}
+		if err := tx.Create(&models).Error; err != nil {
+			return fmt.Errorf("failed to create file tags: %w", err)
+		}
+		return nil
+	})
+}
+
+// 指定されたIDのファイルをゴミ箱に移動する
+func (r *FileRepository) TrashFile(id string, at time.Time) error {
+	result := r.db.Model(&File{}).Where("id = ? AND deleted_at IS NULL", id).Update("deleted_at", at)
+	if result.Error != nil {
+		return fmt.Errorf("failed to trash file: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: no file found with id: %s", domain.ErrNotFound, id)
+	}
+	return nil
+}
+
+// ゴミ箱にある指定されたIDのファイルを元に戻す
+func (r *FileRepository) RestoreFile(id string) error {
+	result := r.db.Model(&File{}).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	if result.Error != nil {
+		return fmt.Errorf("failed to restore file: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: no trashed file found with id: %s", domain.ErrNotFound, id)
+	}
+	return nil
+}
+
+// ゴミ箱にある指定されたIDのファイルのメタデータを取得する
+func (r *FileRepository) GetTrashedFileByID(id string) (*domain.File, error) {
+	var model File
+	result := r.db.Where("id = ? AND deleted_at IS NOT NULL", id).First(&model)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no trashed file found with id: %s", domain.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("query error: %w", result.Error)
+	}
+	file := toDomainFile(model)
+	return &file, nil
+}
+
+// ゴミ箱にあるファイルの一覧を、ゴミ箱に移動した日時の新しい順に取得する
+func (r *FileRepository) ListTrashedFiles() ([]domain.File, error) {
+	return r.listTrashedFiles(r.db)
+}
+
+// アップロードしたユーザーのゴミ箱にあるファイルの一覧を、ゴミ箱に移動した日時の新しい順に取得する
+func (r *FileRepository) ListTrashedFilesByUploader(uploaderRole string, uploaderID int64) ([]domain.File, error) {
+	return r.listTrashedFiles(r.db.Where("uploader_role = ? AND uploader_id = ?", uploaderRole, uploaderID))
+}
+
+// 条件に一致するゴミ箱にあるファイルの一覧を、ゴミ箱に移動した日時の新しい順に取得する
+func (r *FileRepository) listTrashedFiles(query *gorm.DB) ([]domain.File, error) {
+	var models []File
+	result := query.Where("deleted_at IS NOT NULL").Order("deleted_at DESC, id").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list trashed files: %w", result.Error)
+	}
+
+	files := make([]domain.File, len(models))
+	for i, m := range models {
+		files[i] = toDomainFile(m)
+	}
+	if err := loadFileTags(r.db, files); err != nil {
+		return nil, err
+	}
+	if err := loadScanStatus(r.db, files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// 指定されたIDのファイルのメタデータをすべてのバージョンとともに削除し、所有者の使用量から減算する
+// ゴミ箱にあるファイルも削除でき、各バージョンのブロブの参照数を減算して参照がなくなったオブジェクトのキーを返す
+func (r *FileRepository) DeleteFile(id string) ([]string, error) {
+	return r.deleteFile(id, "no file found with id", "id = ?", id)
+}
+
+// ゴミ箱に移動した日時が指定された日時以前のファイルを、DeleteFileと同様にすべてのバージョンとともに削除する
+// 削除までに元に戻された、または削除されたファイルは削除せずErrNotFoundを返す
+func (r *FileRepository) PurgeTrashedFile(id string, trashedBefore time.Time) ([]string, error) {
+	return r.deleteFile(id, "no file in the trash with id", "id = ? AND deleted_at IS NOT NULL AND deleted_at <= ?", id, trashedBefore)
+}
+
+// 条件に一致するファイルの行をロックし、すべてのバージョンとともに削除する
+// 一致する行がない場合はErrNotFoundを返し、参照がなくなったオブジェクトのキーを返す
+func (r *FileRepository) deleteFile(id string, notFound string, query string, args ...interface{}) ([]string, error) {
+	var released []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var model File
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(query, args...).First(&model)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %s: %s", domain.ErrNotFound, notFound, id)
+			}
+			return fmt.Errorf("query error: %w", result.Error)
+		}
+
+		var versions []FileVersion
+		if err := tx.Where("file_id = ?", id).Find(&versions).Error; err != nil {
+			return fmt.Errorf("failed to list file versions: %w", err)
+		}
+		var err error
+		released, err = deleteFileVersions(tx, model, versions)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&model).Error; err != nil {
+			return fmt.Errorf("failed to delete file record: %w", err)
+		}
+		return chargeStorageUsage(tx, model.UploaderRole, model.UploaderID, 0, -1, domain.StorageQuota{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return released, nil
+}
+
+// マルウェア検査を待っている内容を作成日時の古い順に最大limit件取得する
+func (r *FileRepository) ListPendingScans(limit int) ([]domain.ScanTarget, error) {
+	var models []Blob
+	result := r.db.Where("scan_status = ? AND ref_count > 0", domain.ScanPending).Order("created_at, checksum").Limit(limit).Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list pending scans: %w", result.Error)
+	}
+
+	targets := make([]domain.ScanTarget, len(models))
+	for i, m := range models {
+		targets[i] = domain.ScanTarget{Checksum: m.Checksum, ObjectKey: m.ObjectKey, Size: m.Size}
+	}
+	return targets, nil
+}
+
+// 内容の検査結果を記録し、その内容を現在のバージョンとして参照しているファイルの一覧を返す（ゴミ箱にあるファイルも含む）
+// 検査待ちの場合のみ記録するため、同じ内容を同時に検査しても結果の処理は一度だけ行われる
+func (r *FileRepository) SetScanResult(checksum string, result domain.ScanResult, at time.Time) ([]domain.File, error) {
+	status := domain.ScanClean
+	var signature *string
+	if result.Infected {
+		status = domain.ScanInfected
+		signature = &result.Signature
+	}
+
+	updated := r.db.Model(&Blob{}).Where("checksum = ? AND scan_status = ?", checksum, domain.ScanPending).Updates(map[string]interface{}{
+		"scan_status":    status,
+		"scan_signature": signature,
+		"scanned_at":     at,
+	})
+	if updated.Error != nil {
+		return nil, fmt.Errorf("failed to record scan result: %w", updated.Error)
+	}
+	if updated.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	var models []File
+	if err := r.db.Where("checksum = ?", checksum).Order("created_at, id").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	files := make([]domain.File, len(models))
+	for i, m := range models {
+		files[i] = toDomainFile(m)
+		files[i].ScanStatus = status
+	}
+	return files, nil
+}
+
+// マルウェアを検出して隔離したファイルの一覧をアップロード日時の新しい順に取得する（ゴミ箱にあるファイルは除く）
+func (r *FileRepository) ListQuarantinedFiles() ([]domain.File, error) {
+	var models []File
+	result := r.db.Where("deleted_at IS NULL AND checksum IN (?)", r.infectedChecksums()).Order("created_at DESC, id").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list quarantined files: %w", result.Error)
+	}
+
+	files := make([]domain.File, len(models))
+	for i, m := range models {
+		files[i] = toDomainFile(m)
+		files[i].ScanStatus = domain.ScanInfected
+	}
+	if err := loadFileTags(r.db, files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// マルウェアを検出した内容のチェックサムを返すサブクエリ
+func (r *FileRepository) infectedChecksums() *gorm.DB {
+	return r.db.Model(&Blob{}).Select("checksum").Where("scan_status = ?", domain.ScanInfected)
+}
+
+// アップロードしたユーザーのストレージ使用量を取得する
+func (r *FileRepository) GetStorageUsage(uploaderRole string, uploaderID int64) (*domain.StorageUsage, error) {
+	usage := &domain.StorageUsage{UploaderID: uploaderID, UploaderRole: uploaderRole}
+
+	var model StorageUsage
+	result := r.db.Where("uploader_role = ? AND uploader_id = ?", uploaderRole, uploaderID).First(&model)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return usage, nil
+		}
+		return nil, fmt.Errorf("query error: %w", result.Error)
+	}
+
+	usage.Bytes = model.Bytes
+	usage.Files = model.Files
+	return usage, nil
+}
+
+// ストレージ使用量の多いユーザーを使用量の多い順に取得する
+func (r *FileRepository) ListTopStorageUsage(limit int) ([]domain.StorageUsage, error) {
+	var models []StorageUsage
+	result := r.db.Where("files > 0").Order("bytes DESC, files DESC").Limit(limit).Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list storage usage: %w", result.Error)
+	}
+
+	usages := make([]domain.StorageUsage, len(models))
+	for i, m := range models {
+		usages[i] = domain.StorageUsage{
+			UploaderID:   int64(m.UploaderID),
+			UploaderRole: m.UploaderRole,
+			Bytes:        m.Bytes,
+			Files:        m.Files,
+		}
+	}
+	return usages, nil
+}
+
+// ファイルのタグを読み込み、各ファイルに名前順で設定する
+func loadFileTags(db *gorm.DB, files []domain.File) error {
+	if len(files) == 0 {
+		return nil
+	}
+	ids := make([]string, len(files))
+	for i, file := range files {
+		ids[i] = file.ID
+	}
+
+	var tags []FileTag
+	if err := db.Where("file_id IN ?", ids).Order("tag").Find(&tags).Error; err != nil {
+		return fmt.Errorf("failed to load file tags: %w", err)
+	}
+	byFile := make(map[string][]string, len(files))
+	for _, tag := range tags {
+		byFile[tag.FileID] = append(byFile[tag.FileID], tag.Tag)
+	}
+	for i := range files {
+		files[i].Tags = byFile[files[i].ID]
+	}
+	return nil
+}
+
+// ファイルの内容のマルウェア検査の状態を読み込み、各ファイルに設定する
+// ブロブとして登録されていない内容は検査の導入前のものとして検査済みとして扱う
+func loadScanStatus(db *gorm.DB, files []domain.File) error {
+	if len(files) == 0 {
+		return nil
+	}
+	checksums := make([]string, len(files))
+	for i, file := range files {
+		checksums[i] = file.Checksum
+	}
+
+	var blobs []Blob
+	if err := db.Select("checksum", "scan_status").Where("checksum IN ?", checksums).Find(&blobs).Error; err != nil {
+		return fmt.Errorf("failed to load scan status: %w", err)
+	}
+	byChecksum := make(map[string]string, len(blobs))
+	for _, blob := range blobs {
+		byChecksum[blob.Checksum] = blob.ScanStatus
+	}
+	for i := range files {
+		files[i].ScanStatus = domain.ScanClean
+		if status, ok := byChecksum[files[i].Checksum]; ok {
+			files[i].ScanStatus = status
+		}
+	}
+	return nil
+}
+
+// ユーザーのストレージ使用量にサイズとファイル数を加算する（減算する場合は負の値を指定する）
+// 加算する場合はクォータの範囲内の場合のみ更新し、超える場合はErrQuotaExceededを返す
+// 行ロックにより同時の更新は直列化されるため、同時にアップロードしても使用量がクォータを超えることはない
+func chargeStorageUsage(tx *gorm.DB, role string, uploaderID *uint, bytes int64, files int64, quota domain.StorageQuota) error {
+	if uploaderID == nil {
+		return nil
+	}
+
+	// 使用量の行がない場合は作成
+	usage := StorageUsage{UploaderRole: role, UploaderID: *uploaderID, UpdatedAt: time.Now()}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&usage).Error; err != nil {
+		return fmt.Errorf("failed to create storage usage: %w", err)
+	}
+
+	query := tx.Model(&StorageUsage{}).Where("uploader_role = ? AND uploader_id = ?", role, *uploaderID)
+	if quota.MaxBytes > 0 && bytes > 0 {
+		query = query.Where("bytes + ? <= ?", bytes, quota.MaxBytes)
+	}
+	if quota.MaxFiles > 0 && files > 0 {
+		query = query.Where("files + ? <= ?", files, quota.MaxFiles)
+	}
+	result := query.Updates(map[string]interface{}{
+		"bytes":      gorm.Expr("GREATEST(bytes + ?, 0)", bytes),
+		"files":      gorm.Expr("GREATEST(files + ?, 0)", files),
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update storage usage: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: storing %d more bytes would exceed the quota", domain.ErrQuotaExceeded, bytes)
+	}
+	return nil
+}
+
+// チェックサムのブロブを登録し、参照するブロブを返す
+// 新しいブロブは検査待ちとして登録し、同じチェックサムのブロブが既にある場合は参照数を加算して既存のブロブ（検査の状態を含む）を返す
+func acquireBlob(tx *gorm.DB, checksum string, objectKey string, size int64) (Blob, error) {
+	blob := Blob{Checksum: checksum, ObjectKey: objectKey, Size: size, RefCount: 1, ScanStatus: domain.ScanPending, CreatedAt: time.Now()}
+	result := tx.Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "checksum"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"ref_count": gorm.Expr("blobs.ref_count + 1")}),
+		},
+		clause.Returning{},
+	).Create(&blob)
+	if result.Error != nil {
+		return Blob{}, fmt.Errorf("failed to register blob: %w", result.Error)
+	}
+	return blob, nil
+}
+
+// ブロブの参照数を減算し、参照がなくなった場合はブロブを削除して、不要になったオブジェクトのキーを返す
+// ブロブのサムネイルもブロブとともに削除し、そのオブジェクトのキーも返す
+// ブロブとして登録されていないオブジェクトは参照しているファイル専用のものとして扱う
+func releaseBlob(tx *gorm.DB, checksum string, objectKey string) ([]string, error) {
+	var blob Blob
+	result := tx.Model(&blob).Clauses(clause.Returning{}).
+		Where("checksum = ? AND object_key = ?", checksum, objectKey).
+		Update("ref_count", gorm.Expr("ref_count - 1"))
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to release blob: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return []string{objectKey}, nil
+	}
+	if blob.RefCount > 0 {
+		return nil, nil
+	}
+
+	var thumbnails []Thumbnail
+	if err := tx.Where("checksum = ?", checksum).Find(&thumbnails).Error; err != nil {
+		return nil, fmt.Errorf("failed to list thumbnails: %w", err)
+	}
+	// サムネイルは外部キーにより連鎖して削除される
+	if err := tx.Where("checksum = ?", checksum).Delete(&Blob{}).Error; err != nil {
+		return nil, fmt.Errorf("failed to delete blob: %w", err)
+	}
+
+	released := []string{objectKey}
+	for _, thumbnail := range thumbnails {
+		released = append(released, thumbnail.ObjectKey)
+	}
+	return released, nil
+}
+
+// ファイルのバージョンを削除し、ブロブの参照を解放して所有者の使用量から減算する
+// 参照がなくなったオブジェクトのキーを返す
+func deleteFileVersions(tx *gorm.DB, file File, versions []FileVersion) ([]string, error) {
+	var released []string
+	var bytes int64
+	for _, version := range versions {
+		if err := tx.Delete(&version).Error; err != nil {
+			return nil, fmt.Errorf("failed to delete file version: %w", err)
+		}
+		keys, err := releaseBlob(tx, version.Checksum, version.ObjectKey)
+		if err != nil {
+			return nil, err
+		}
+		released = append(released, keys...)
+		bytes += version.Size
+	}
+
+	if bytes > 0 {
+		if err := chargeStorageUsage(tx, file.UploaderRole, file.UploaderID, -bytes, 0, domain.StorageQuota{}); err != nil {
+			return nil, err
+		}
+	}
+	return released, nil
+}
+
+// ファイルのドメインモデルをデータベースモデルに変換する
+func toFileModel(file *domain.File) File {
+	model := File{
+		ID:           file.ID,
+		ObjectKey:    file.ObjectKey,
+		OriginalName: file.Name,
+		Size:         file.Size,
+		ContentType:  file.ContentType,
+		Checksum:     file.Checksum,
+		UploaderRole: file.UploaderRole,
+		Version:      file.Version,
+		CreatedAt:    file.UploadedAt,
+		FolderID:     file.FolderID,
+	}
+	if file.UploaderID != 0 {
+		uploaderID := uint(file.UploaderID)
+		model.UploaderID = &uploaderID
+	}
+	return model
+}
+
+// ファイルのデータベースモデルをドメインモデルに変換する
+func toDomainFile(m File) domain.File {
+	file := domain.File{
+		ID:           m.ID,
+		Name:         m.OriginalName,
+		Size:         m.Size,
+		ContentType:  m.ContentType,
+		UploadedAt:   m.CreatedAt,
+		ObjectKey:    m.ObjectKey,
+		Checksum:     m.Checksum,
+		UploaderRole: m.UploaderRole,
+		Version:      m.Version,
+		DeletedAt:    m.DeletedAt,
+		FolderID:     m.FolderID,
+	}
+	if m.UploaderID != nil {
+		file.UploaderID = int64(*m.UploaderID)
+	}
+	return file
+}
+
+// ファイルのデータベースモデルから現在のバージョンのデータベースモデルを作成する
+func toFileVersionModel(m File) FileVersion {
+	return FileVersion{
+		FileID:       m.ID,
+		Version:      m.Version,
+		ObjectKey:    m.ObjectKey,
+		OriginalName: m.OriginalName,
+		Size:         m.Size,
+		ContentType:  m.ContentType,
+		Checksum:     m.Checksum,
+		UploaderID:   m.UploaderID,
+		UploaderRole: m.UploaderRole,
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+// ファイルバージョンのデータベースモデルをドメインモデルに変換する
+func toDomainFileVersion(m FileVersion) domain.File {
+	file := domain.File{
+		ID:           m.FileID,
+		Name:         m.OriginalName,
+		Size:         m.Size,
+		ContentType:  m.ContentType,
+		UploadedAt:   m.CreatedAt,
+		ObjectKey:    m.ObjectKey,
+		Checksum:     m.Checksum,
+		UploaderRole: m.UploaderRole,
+		Version:      m.Version,
+	}
+	if m.UploaderID != nil {
+		file.UploaderID = int64(*m.UploaderID)
+	}
+	return file
+}
@@ -0,0 +1,210 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+// ファイル共有リポジトリ構造体：データベースを使用したファイルの共有の永続化を実装
+type FileShareRepository struct {
+	// データベース接続
+	db *gorm.DB
+}
+
+// ファイル共有データベースモデル：file_sharesテーブルとマッピング
+type FileShare struct {
+	// 共有の一意識別子
+	ID uint `gorm:"primaryKey"`
+	// 共有するファイルのID
+	FileID string `gorm:"not null"`
+	// 共有の種類
+	Kind string `gorm:"not null"`
+	// 共有した教師のID
+	TeacherID uint `gorm:"not null"`
+	// 共有する学生のID（種類がstudentの場合のみ）
+	StudentID *uint
+	// 共有リンクのトークンのSHA-256ハッシュ（種類がlinkの場合のみ）
+	TokenHash *string
+	// 共有の有効期限
+	ExpiresAt *time.Time
+	// 作成日時
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// テーブル名を指定する
+func (FileShare) TableName() string {
+	return "file_shares"
+}
+
+// 新しいファイル共有リポジトリインスタンスを作成する
+func NewFileShareRepository(db *gorm.DB) *FileShareRepository {
+	return &FileShareRepository{
+		db: db,
+	}
+}
+
+// 新しい共有を作成し、作成された共有のIDを返す
+func (r *FileShareRepository) CreateFileShare(share *domain.FileShare) (int64, error) {
+	model := toFileShareModel(share)
+	if err := r.db.Create(&model).Error; err != nil {
+		return 0, fmt.Errorf("failed to create file share: %w", err)
+	}
+	return int64(model.ID), nil
+}
+
+// ファイルの共有一覧を作成日時順に取得する
+func (r *FileShareRepository) GetFileSharesByFileID(fileID string) ([]domain.FileShare, error) {
+	var models []FileShare
+	result := r.db.Where("file_id = ?", fileID).Order("created_at, id").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list file shares: %w", result.Error)
+	}
+
+	shares := make([]domain.FileShare, len(models))
+	for i, m := range models {
+		shares[i] = toDomainFileShare(m)
+	}
+	return shares, nil
+}
+
+// トークンのハッシュに一致するリンクの共有を取得する
+func (r *FileShareRepository) GetFileShareByTokenHash(tokenHash string) (*domain.FileShare, error) {
+	var model FileShare
+	result := r.db.Where("kind = ? AND token_hash = ?", domain.FileShareLink, tokenHash).First(&model)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no shared link found for the token", domain.ErrNotFound)
+		}
+		return nil, fmt.Errorf("query error: %w", result.Error)
+	}
+
+	share := toDomainFileShare(model)
+	return &share, nil
+}
+
+// ファイルの指定されたIDの共有を削除する
+func (r *FileShareRepository) DeleteFileShare(fileID string, id int64) error {
+	result := r.db.Where("id = ? AND file_id = ?", id, fileID).Delete(&FileShare{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete file share: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: no share %d found for file: %s", domain.ErrNotFound, id, fileID)
+	}
+	return nil
+}
+
+// 指定された日時に有効な共有により学生が閲覧できるファイルの一覧を、アップロード日時の新しい順に取得する
+// 担当学生全員への共有は、共有した教師の現在の担当学生に対して有効になる
+func (r *FileShareRepository) GetFilesSharedWithStudent(studentID int64, at time.Time) ([]domain.File, error) {
+	var models []File
+	result := r.db.Where("deleted_at IS NULL AND id IN (?)", r.sharedWithStudent(studentID, at)).
+		Order("created_at DESC, id").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list shared files: %w", result.Error)
+	}
+
+	files := make([]domain.File, len(models))
+	for i, m := range models {
+		files[i] = toDomainFile(m)
+	}
+	if err := loadFileTags(r.db, files); err != nil {
+		return nil, err
+	}
+	if err := loadScanStatus(r.db, files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// 指定された日時に有効な共有によりファイルを学生が閲覧できるかどうかを判定する
+func (r *FileShareRepository) IsFileSharedWithStudent(fileID string, studentID int64, at time.Time) (bool, error) {
+	var count int64
+	result := r.sharedWithStudent(studentID, at).Where("file_id = ?", fileID).Count(&count)
+	if result.Error != nil {
+		return false, fmt.Errorf("query error: %w", result.Error)
+	}
+	return count > 0, nil
+}
+
+// ファイルが学生の担当教師の課題に添付されているかどうかを判定する
+func (r *FileShareRepository) IsAssignmentFileForStudent(assignmentID int64, fileID string, studentID int64) (bool, error) {
+	var count int64
+	result := r.db.Table("assignment_files").
+		Joins("JOIN assignments ON assignments.id = assignment_files.assignment_id").
+		Joins("JOIN teacher_students ON teacher_students.teacher_id = assignments.teacher_id").
+		Where("assignment_files.assignment_id = ? AND assignment_files.file_id = ? AND teacher_students.student_id = ?", assignmentID, fileID, studentID).
+		Count(&count)
+	if result.Error != nil {
+		return false, fmt.Errorf("query error: %w", result.Error)
+	}
+	return count > 0, nil
+}
+
+// ファイルが教師の課題への提出物に含まれているかどうかを判定する
+func (r *FileShareRepository) IsFileSubmittedToTeacher(fileID string, teacherID int64) (bool, error) {
+	var count int64
+	result := r.db.Table("submission_files").
+		Joins("JOIN submissions ON submissions.id = submission_files.submission_id").
+		Joins("JOIN assignments ON assignments.id = submissions.assignment_id").
+		Where("submission_files.file_id = ? AND assignments.teacher_id = ?", fileID, teacherID).
+		Count(&count)
+	if result.Error != nil {
+		return false, fmt.Errorf("query error: %w", result.Error)
+	}
+	return count > 0, nil
+}
+
+// 学生に対して指定された日時に有効な共有のファイルIDを選択するサブクエリを返す
+func (r *FileShareRepository) sharedWithStudent(studentID int64, at time.Time) *gorm.DB {
+	roster := r.db.Table("teacher_students").Select("teacher_id").Where("student_id = ?", studentID)
+	return r.db.Model(&FileShare{}).Select("file_id").
+		Where("expires_at IS NULL OR expires_at > ?", at).
+		Where(r.db.Where("kind = ? AND student_id = ?", domain.FileShareStudent, studentID).
+			Or("kind = ? AND teacher_id IN (?)", domain.FileShareRoster, roster))
+}
+
+// ファイル共有のドメインモデルをデータベースモデルに変換する
+func toFileShareModel(share *domain.FileShare) FileShare {
+	model := FileShare{
+		ID:        uint(share.ID),
+		FileID:    share.FileID,
+		Kind:      share.Kind,
+		TeacherID: uint(share.TeacherID),
+		ExpiresAt: share.ExpiresAt,
+		CreatedAt: share.CreatedAt,
+	}
+	if share.StudentID != nil {
+		studentID := uint(*share.StudentID)
+		model.StudentID = &studentID
+	}
+	if share.TokenHash != "" {
+		tokenHash := share.TokenHash
+		model.TokenHash = &tokenHash
+	}
+	return model
+}
+
+// ファイル共有のデータベースモデルをドメインモデルに変換する
+func toDomainFileShare(m FileShare) domain.FileShare {
+	share := domain.FileShare{
+		ID:        int64(m.ID),
+		FileID:    m.FileID,
+		Kind:      m.Kind,
+		TeacherID: int64(m.TeacherID),
+		ExpiresAt: m.ExpiresAt,
+		CreatedAt: m.CreatedAt,
+	}
+	if m.StudentID != nil {
+		studentID := int64(*m.StudentID)
+		share.StudentID = &studentID
+	}
+	if m.TokenHash != nil {
+		share.TokenHash = *m.TokenHash
+	}
+	return share
+}
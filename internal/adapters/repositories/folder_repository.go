@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+// フォルダリポジトリ構造体：データベースを使用したフォルダの永続化を実装
+type FolderRepository struct {
+	// データベース接続
+	db *gorm.DB
+}
+
+// フォルダデータベースモデル：foldersテーブルとマッピング
+type Folder struct {
+	// フォルダの一意識別子（UUID）
+	ID string `gorm:"primaryKey"`
+	// フォルダを所有する教師のID
+	OwnerID uint `gorm:"not null"`
+	// 親フォルダのID（最上位のフォルダの場合はnil）
+	ParentID *string
+	// フォルダ名
+	Name string `gorm:"not null"`
+	// 作成日時
+	CreatedAt time.Time `gorm:"not null"`
+	// 最終更新日時
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+// テーブル名を指定する
+func (Folder) TableName() string {
+	return "folders"
+}
+
+// 新しいフォルダリポジトリインスタンスを作成する
+func NewFolderRepository(db *gorm.DB) *FolderRepository {
+	return &FolderRepository{
+		db: db,
+	}
+}
+
+// 新しいフォルダを作成する
+func (r *FolderRepository) CreateFolder(folder *domain.Folder) error {
+	model := toFolderModel(folder)
+	if err := r.db.Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to create folder: %w", err)
+	}
+	return nil
+}
+
+// 指定されたIDのフォルダを取得する
+func (r *FolderRepository) GetFolderByID(id string) (*domain.Folder, error) {
+	var model Folder
+	result := r.db.Where("id = ?", id).First(&model)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no folder found with id: %s", domain.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("query error: %w", result.Error)
+	}
+
+	folder := toDomainFolder(model)
+	return &folder, nil
+}
+
+// 教師が所有するフォルダの一覧を名前順に取得する
+func (r *FolderRepository) GetFoldersByOwnerID(ownerID int64) ([]domain.Folder, error) {
+	var models []Folder
+	result := r.db.Where("owner_id = ?", ownerID).Order("LOWER(name), id").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list folders: %w", result.Error)
+	}
+
+	folders := make([]domain.Folder, len(models))
+	for i, m := range models {
+		folders[i] = toDomainFolder(m)
+	}
+	return folders, nil
+}
+
+// フォルダの名前と親フォルダを更新する
+func (r *FolderRepository) UpdateFolder(folder *domain.Folder) error {
+	result := r.db.Model(&Folder{}).Where("id = ?", folder.ID).Updates(map[string]interface{}{
+		"name":       folder.Name,
+		"parent_id":  folder.ParentID,
+		"updated_at": folder.UpdatedAt,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update folder: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: no folder found with id: %s", domain.ErrNotFound, folder.ID)
+	}
+	return nil
+}
+
+// 指定されたIDのフォルダを中のフォルダとともに削除する
+// 削除するフォルダに属するファイルはゴミ箱に移動し（フォルダの削除によりどのフォルダにも属さなくなる）、ゴミ箱に移動したファイル数を返す
+func (r *FolderRepository) DeleteFolder(id string, trashedAt time.Time) (int64, error) {
+	var trashed int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		result := tx.Raw(`WITH RECURSIVE subtree AS (
+			SELECT id FROM folders WHERE id = ?
+			UNION ALL
+			SELECT folders.id FROM folders JOIN subtree ON folders.parent_id = subtree.id
+		) SELECT id FROM subtree`, id).Scan(&ids)
+		if result.Error != nil {
+			return fmt.Errorf("failed to list folder contents: %w", result.Error)
+		}
+		if len(ids) == 0 {
+			return fmt.Errorf("%w: no folder found with id: %s", domain.ErrNotFound, id)
+		}
+
+		result = tx.Model(&File{}).Where("folder_id IN ? AND deleted_at IS NULL", ids).Update("deleted_at", trashedAt)
+		if result.Error != nil {
+			return fmt.Errorf("failed to trash folder files: %w", result.Error)
+		}
+		trashed = result.RowsAffected
+
+		// 中のフォルダは外部キーにより連鎖して削除される
+		if err := tx.Where("id = ?", id).Delete(&Folder{}).Error; err != nil {
+			return fmt.Errorf("failed to delete folder: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return trashed, nil
+}
+
+// フォルダのドメインモデルをデータベースモデルに変換する
+func toFolderModel(folder *domain.Folder) Folder {
+	return Folder{
+		ID:        folder.ID,
+		OwnerID:   uint(folder.OwnerID),
+		ParentID:  folder.ParentID,
+		Name:      folder.Name,
+		CreatedAt: folder.CreatedAt,
+		UpdatedAt: folder.UpdatedAt,
+	}
+}
+
+// フォルダのデータベースモデルをドメインモデルに変換する
+func toDomainFolder(m Folder) domain.Folder {
+	return domain.Folder{
+		ID:        m.ID,
+		Name:      m.Name,
+		ParentID:  m.ParentID,
+		OwnerID:   int64(m.OwnerID),
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
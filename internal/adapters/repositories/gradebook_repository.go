@@ -0,0 +1,280 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 成績簿リポジトリ構造体：データベースを使用した成績データの永続化を実装
+type GradebookRepository struct {
+	// データベース接続
+	db *gorm.DB
+}
+
+// 成績カテゴリデータベースモデル：grade_categoriesテーブルとマッピング
+type GradeCategory struct {
+	// カテゴリの一意識別子
+	ID uint `gorm:"primaryKey"`
+	// カテゴリを定義した教師のID
+	TeacherID uint `gorm:"not null;index"`
+	// カテゴリ名
+	Name string `gorm:"not null"`
+	// 重み（パーセント）
+	Weight float64 `gorm:"not null"`
+	// 除外する最低点の件数
+	DropLowest int `gorm:"not null"`
+	// レコードの作成日時
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// テーブル名を指定する
+func (GradeCategory) TableName() string {
+	return "grade_categories"
+}
+
+// 成績記録データベースモデル：grade_entriesテーブルとマッピング
+type GradeEntry struct {
+	// 成績記録の一意識別子
+	ID uint `gorm:"primaryKey"`
+	// 採点した教師のID
+	TeacherID uint `gorm:"not null"`
+	// 学生のID
+	StudentID uint `gorm:"not null"`
+	// 成績カテゴリのID
+	CategoryID uint `gorm:"not null"`
+	// 対象の課題ID
+	AssignmentID *uint
+	// 対象の小テストID
+	QuizID *string
+	// 成績の名称
+	Title string `gorm:"not null"`
+	// 得点
+	Score float64 `gorm:"not null"`
+	// 満点
+	MaxPoints float64 `gorm:"not null"`
+	// 採点日時
+	GradedAt time.Time `gorm:"not null"`
+}
+
+// テーブル名を指定する
+func (GradeEntry) TableName() string {
+	return "grade_entries"
+}
+
+// 評定基準データベースモデル：grade_scalesテーブルとマッピング
+type GradeScale struct {
+	// 教師のID
+	TeacherID uint `gorm:"primaryKey"`
+	// 評定
+	Letter string `gorm:"primaryKey"`
+	// 評定の下限パーセント
+	MinPercent float64 `gorm:"not null"`
+}
+
+// テーブル名を指定する
+func (GradeScale) TableName() string {
+	return "grade_scales"
+}
+
+// 新しい成績簿リポジトリインスタンスを作成する
+func NewGradebookRepository(db *gorm.DB) *GradebookRepository {
+	return &GradebookRepository{
+		db: db,
+	}
+}
+
+// 新しい成績カテゴリを作成し、作成されたカテゴリのIDを返す
+func (r *GradebookRepository) CreateCategory(category *domain.GradeCategory) (int64, error) {
+	model := GradeCategory{
+		TeacherID:  uint(category.TeacherID),
+		Name:       category.Name,
+		Weight:     category.Weight,
+		DropLowest: category.DropLowest,
+	}
+
+	result := r.db.Create(&model)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to create grade category: %w", result.Error)
+	}
+
+	return int64(model.ID), nil
+}
+
+// 指定されたIDの成績カテゴリを取得する
+func (r *GradebookRepository) GetCategoryByID(id int64) (*domain.GradeCategory, error) {
+	var model GradeCategory
+	result := r.db.First(&model, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no grade category found with id: %d", domain.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("query error: %w", result.Error)
+	}
+
+	category := toDomainGradeCategory(model)
+	return &category, nil
+}
+
+// 教師の成績カテゴリ一覧を取得する
+func (r *GradebookRepository) GetCategoriesByTeacherID(teacherID int64) ([]domain.GradeCategory, error) {
+	var models []GradeCategory
+	result := r.db.Where("teacher_id = ?", teacherID).Order("id").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get grade categories: %w", result.Error)
+	}
+
+	categories := make([]domain.GradeCategory, len(models))
+	for i, m := range models {
+		categories[i] = toDomainGradeCategory(m)
+	}
+
+	return categories, nil
+}
+
+// 成績記録を保存する
+// 課題または小テストに紐付く成績は、同じ教師・学生・課題（小テスト）の既存の成績を上書きする
+func (r *GradebookRepository) SaveGradeEntry(entry *domain.GradeEntry) (int64, error) {
+	model := GradeEntry{
+		TeacherID:  uint(entry.TeacherID),
+		StudentID:  uint(entry.StudentID),
+		CategoryID: uint(entry.CategoryID),
+		Title:      entry.Title,
+		Score:      entry.Score,
+		MaxPoints:  entry.MaxPoints,
+		GradedAt:   entry.GradedAt,
+		QuizID:     entry.QuizID,
+	}
+	if entry.AssignmentID != nil {
+		assignmentID := uint(*entry.AssignmentID)
+		model.AssignmentID = &assignmentID
+	}
+
+	query := r.db
+	switch {
+	case model.AssignmentID != nil:
+		// 部分一意インデックス（assignment_idがNULLでない行）を使った上書き
+		query = query.Clauses(upsertGradeEntryOn("assignment_id"))
+	case model.QuizID != nil:
+		// 部分一意インデックス（quiz_idがNULLでない行）を使った上書き
+		query = query.Clauses(upsertGradeEntryOn("quiz_id"))
+	}
+
+	result := query.Create(&model)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to save grade entry: %w", result.Error)
+	}
+
+	return int64(model.ID), nil
+}
+
+// 教師・学生・指定列が一致する成績を上書きするON CONFLICT句を作成する
+func upsertGradeEntryOn(column string) clause.OnConflict {
+	return clause.OnConflict{
+		Columns:     []clause.Column{{Name: "teacher_id"}, {Name: "student_id"}, {Name: column}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: column + " IS NOT NULL"}}},
+		DoUpdates:   clause.AssignmentColumns([]string{"category_id", "title", "score", "max_points", "graded_at"}),
+	}
+}
+
+// 教師が記録した成績一覧を取得する
+func (r *GradebookRepository) GetGradeEntriesByTeacherID(teacherID int64) ([]domain.GradeEntry, error) {
+	var models []GradeEntry
+	result := r.db.Where("teacher_id = ?", teacherID).Order("graded_at").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get grade entries: %w", result.Error)
+	}
+
+	return toDomainGradeEntries(models), nil
+}
+
+// 学生の成績一覧を取得する
+func (r *GradebookRepository) GetGradeEntriesByStudentID(studentID int64) ([]domain.GradeEntry, error) {
+	var models []GradeEntry
+	result := r.db.Where("student_id = ?", studentID).Order("graded_at").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get grade entries: %w", result.Error)
+	}
+
+	return toDomainGradeEntries(models), nil
+}
+
+// 教師の評定基準を取得する
+func (r *GradebookRepository) GetGradeScale(teacherID int64) ([]domain.GradeThreshold, error) {
+	var models []GradeScale
+	result := r.db.Where("teacher_id = ?", teacherID).Order("min_percent DESC").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get grade scale: %w", result.Error)
+	}
+
+	scale := make([]domain.GradeThreshold, len(models))
+	for i, m := range models {
+		scale[i] = domain.GradeThreshold{Letter: m.Letter, MinPercent: m.MinPercent}
+	}
+
+	return scale, nil
+}
+
+// 教師の評定基準を置き換える
+func (r *GradebookRepository) SaveGradeScale(teacherID int64, scale []domain.GradeThreshold) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("teacher_id = ?", teacherID).Delete(&GradeScale{}).Error; err != nil {
+			return err
+		}
+		for _, threshold := range scale {
+			model := GradeScale{
+				TeacherID:  uint(teacherID),
+				Letter:     threshold.Letter,
+				MinPercent: threshold.MinPercent,
+			}
+			if err := tx.Create(&model).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save grade scale: %w", err)
+	}
+
+	return nil
+}
+
+// 成績カテゴリデータベースモデルをドメインモデルに変換する
+func toDomainGradeCategory(m GradeCategory) domain.GradeCategory {
+	return domain.GradeCategory{
+		ID:         int64(m.ID),
+		TeacherID:  int64(m.TeacherID),
+		Name:       m.Name,
+		Weight:     m.Weight,
+		DropLowest: m.DropLowest,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+// 成績記録データベースモデルのリストをドメインモデルに変換する
+func toDomainGradeEntries(models []GradeEntry) []domain.GradeEntry {
+	entries := make([]domain.GradeEntry, len(models))
+	for i, m := range models {
+		entries[i] = domain.GradeEntry{
+			ID:         int64(m.ID),
+			TeacherID:  int64(m.TeacherID),
+			StudentID:  int64(m.StudentID),
+			CategoryID: int64(m.CategoryID),
+			Title:      m.Title,
+			Score:      m.Score,
+			MaxPoints:  m.MaxPoints,
+			GradedAt:   m.GradedAt,
+			QuizID:     m.QuizID,
+		}
+		if m.AssignmentID != nil {
+			assignmentID := int64(*m.AssignmentID)
+			entries[i].AssignmentID = &assignmentID
+		}
+	}
+	return entries
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+// 通知リポジトリ構造体：データベースを使用したアプリ内の通知の永続化を実装
+type NotificationRepository struct {
+	// データベース接続
+	db *gorm.DB
+}
+
+// 通知データベースモデル：notificationsテーブルとマッピング
+type Notification struct {
+	// 通知の一意識別子
+	ID uint `gorm:"primaryKey"`
+	// 通知を受け取るユーザーの役割
+	RecipientRole string `gorm:"not null"`
+	// 通知を受け取るユーザーのID
+	RecipientID uint `gorm:"not null"`
+	// 通知の種類
+	Kind string `gorm:"not null"`
+	// 通知の本文
+	Message string `gorm:"not null"`
+	// 関連するファイルのID
+	FileID *string
+	// 作成日時
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// テーブル名を指定する
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// 新しい通知リポジトリインスタンスを作成する
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{
+		db: db,
+	}
+}
+
+// 新しい通知を作成する
+func (r *NotificationRepository) CreateNotification(notification *domain.Notification) error {
+	model := Notification{
+		RecipientRole: notification.RecipientRole,
+		RecipientID:   uint(notification.RecipientID),
+		Kind:          notification.Kind,
+		Message:       notification.Message,
+		CreatedAt:     notification.CreatedAt,
+	}
+	if notification.FileID != "" {
+		model.FileID = &notification.FileID
+	}
+	if err := r.db.Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	notification.ID = int64(model.ID)
+	return nil
+}
+
+// ユーザーへの通知を新しい順に最大limit件取得する
+func (r *NotificationRepository) GetNotificationsByRecipient(role string, recipientID int64, limit int) ([]domain.Notification, error) {
+	var models []Notification
+	result := r.db.Where("recipient_role = ? AND recipient_id = ?", role, recipientID).
+		Order("created_at DESC, id DESC").Limit(limit).Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", result.Error)
+	}
+
+	notifications := make([]domain.Notification, len(models))
+	for i, m := range models {
+		notifications[i] = domain.Notification{
+			ID:            int64(m.ID),
+			RecipientRole: m.RecipientRole,
+			RecipientID:   int64(m.RecipientID),
+			Kind:          m.Kind,
+			Message:       m.Message,
+			CreatedAt:     m.CreatedAt,
+		}
+		if m.FileID != nil {
+			notifications[i].FileID = *m.FileID
+		}
+	}
+	return notifications, nil
+}
@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+// 小テスト受験リポジトリ構造体：データベースを使用した受験記録の永続化を実装
+type QuizRepository struct {
+	// データベース接続
+	db *gorm.DB
+}
+
+// 受験記録データベースモデル：quiz_attemptsテーブルとマッピング
+// 出題順、回答、採点結果はJSONとして保存する
+type QuizAttempt struct {
+	// 受験の一意識別子
+	ID uint `gorm:"primaryKey"`
+	// 小テストのID（ドキュメントID）
+	QuizID string `gorm:"not null"`
+	// 学生のID
+	StudentID uint `gorm:"not null"`
+	// 小テストを作成した教師のID
+	TeacherID uint `gorm:"not null"`
+	// 何回目の受験か
+	Number int `gorm:"not null"`
+	// 受験の状態
+	Status string `gorm:"not null"`
+	// 開始日時
+	StartedAt time.Time `gorm:"not null"`
+	// 提出期限
+	Deadline *time.Time
+	// 提出日時
+	SubmittedAt *time.Time
+	// 得点
+	Score *float64
+	// 満点
+	MaxScore float64 `gorm:"not null"`
+	// 出題順の問題ID（JSON）
+	QuestionOrder string `gorm:"type:jsonb;not null"`
+	// 問題ごとの選択肢の提示順（JSON）
+	OptionOrder string `gorm:"type:jsonb;not null"`
+	// 回答（JSON）
+	Answers string `gorm:"type:jsonb;not null"`
+	// 採点結果（JSON）
+	Results string `gorm:"type:jsonb;not null"`
+}
+
+// テーブル名を指定する
+func (QuizAttempt) TableName() string {
+	return "quiz_attempts"
+}
+
+// 新しい小テスト受験リポジトリインスタンスを作成する
+func NewQuizRepository(db *gorm.DB) *QuizRepository {
+	return &QuizRepository{
+		db: db,
+	}
+}
+
+// 新しい受験記録を作成し、作成された受験のIDを返す
+func (r *QuizRepository) CreateAttempt(attempt *domain.QuizAttempt) (int64, error) {
+	model, err := toQuizAttemptModel(attempt)
+	if err != nil {
+		return 0, err
+	}
+
+	result := r.db.Create(&model)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to create quiz attempt: %w", result.Error)
+	}
+
+	return int64(model.ID), nil
+}
+
+// 指定されたIDの受験記録を取得する
+func (r *QuizRepository) GetAttemptByID(id int64) (*domain.QuizAttempt, error) {
+	var model QuizAttempt
+	result := r.db.First(&model, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no quiz attempt found with id: %d", domain.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("query error: %w", result.Error)
+	}
+
+	return toDomainQuizAttempt(model)
+}
+
+// 学生の小テストの受験記録一覧を回数順に取得する
+func (r *QuizRepository) GetAttemptsByQuizAndStudent(quizID string, studentID int64) ([]domain.QuizAttempt, error) {
+	var models []QuizAttempt
+	result := r.db.Where("quiz_id = ? AND student_id = ?", quizID, studentID).Order("number").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get quiz attempts: %w", result.Error)
+	}
+
+	return toDomainQuizAttempts(models)
+}
+
+// 小テストの全学生の受験記録一覧を取得する
+func (r *QuizRepository) GetAttemptsByQuizID(quizID string) ([]domain.QuizAttempt, error) {
+	var models []QuizAttempt
+	result := r.db.Where("quiz_id = ?", quizID).Order("student_id, number").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get quiz attempts: %w", result.Error)
+	}
+
+	return toDomainQuizAttempts(models)
+}
+
+// 受験中の受験記録の状態、回答、採点結果を更新する
+// 同時に提出された場合などで受験記録が既に受験中でない場合はErrConflictを返す
+func (r *QuizRepository) UpdateAttempt(attempt *domain.QuizAttempt) error {
+	model, err := toQuizAttemptModel(attempt)
+	if err != nil {
+		return err
+	}
+
+	result := r.db.Model(&QuizAttempt{ID: model.ID}).Where("status = ?", domain.AttemptInProgress).
+		Select("status", "submitted_at", "score", "max_score", "answers", "results").Updates(&model)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update quiz attempt: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: quiz attempt %d is no longer in progress", domain.ErrConflict, attempt.ID)
+	}
+
+	return nil
+}
+
+// 受験記録のドメインモデルをデータベースモデルに変換する
+func toQuizAttemptModel(attempt *domain.QuizAttempt) (QuizAttempt, error) {
+	model := QuizAttempt{
+		ID:          uint(attempt.ID),
+		QuizID:      attempt.QuizID,
+		StudentID:   uint(attempt.StudentID),
+		TeacherID:   uint(attempt.TeacherID),
+		Number:      attempt.Number,
+		Status:      attempt.Status,
+		StartedAt:   attempt.StartedAt,
+		Deadline:    attempt.Deadline,
+		SubmittedAt: attempt.SubmittedAt,
+		Score:       attempt.Score,
+		MaxScore:    attempt.MaxScore,
+	}
+
+	fields := []struct {
+		target *string
+		value  interface{}
+		empty  string
+	}{
+		{&model.QuestionOrder, attempt.QuestionOrder, "[]"},
+		{&model.OptionOrder, attempt.OptionOrder, "{}"},
+		{&model.Answers, attempt.Answers, "[]"},
+		{&model.Results, attempt.Results, "[]"},
+	}
+	for _, field := range fields {
+		encoded, err := json.Marshal(field.value)
+		if err != nil {
+			return QuizAttempt{}, fmt.Errorf("failed to encode quiz attempt: %w", err)
+		}
+		if string(encoded) == "null" {
+			encoded = []byte(field.empty)
+		}
+		*field.target = string(encoded)
+	}
+
+	return model, nil
+}
+
+// 受験記録のデータベースモデルをドメインモデルに変換する
+func toDomainQuizAttempt(m QuizAttempt) (*domain.QuizAttempt, error) {
+	attempt := &domain.QuizAttempt{
+		ID:          int64(m.ID),
+		QuizID:      m.QuizID,
+		StudentID:   int64(m.StudentID),
+		TeacherID:   int64(m.TeacherID),
+		Number:      m.Number,
+		Status:      m.Status,
+		StartedAt:   m.StartedAt,
+		Deadline:    m.Deadline,
+		SubmittedAt: m.SubmittedAt,
+		Score:       m.Score,
+		MaxScore:    m.MaxScore,
+	}
+
+	fields := []struct {
+		value  string
+		target interface{}
+	}{
+		{m.QuestionOrder, &attempt.QuestionOrder},
+		{m.OptionOrder, &attempt.OptionOrder},
+		{m.Answers, &attempt.Answers},
+		{m.Results, &attempt.Results},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(field.value), field.target); err != nil {
+			return nil, fmt.Errorf("failed to decode quiz attempt %d: %w", m.ID, err)
+		}
+	}
+
+	return attempt, nil
+}
+
+// 受験記録のデータベースモデルのリストをドメインモデルに変換する
+func toDomainQuizAttempts(models []QuizAttempt) ([]domain.QuizAttempt, error) {
+	attempts := make([]domain.QuizAttempt, len(models))
+	for i, m := range models {
+		attempt, err := toDomainQuizAttempt(m)
+		if err != nil {
+			return nil, err
+		}
+		attempts[i] = *attempt
+	}
+	return attempts, nil
+}
@@ -0,0 +1,226 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 通知表リポジトリ構造体：データベースを使用した通知表データの永続化を実装
+type ReportCardRepository struct {
+	// データベース接続
+	db *gorm.DB
+}
+
+// 通知表所見データベースモデル：report_card_remarksテーブルとマッピング
+type ReportCardRemark struct {
+	// 所見の一意識別子
+	ID uint `gorm:"primaryKey"`
+	// 記入した教師のID
+	TeacherID uint `gorm:"not null"`
+	// 学生のID
+	StudentID uint `gorm:"not null"`
+	// 学期
+	Term string `gorm:"not null"`
+	// 教師のコメント
+	Comment string
+	// 出席日数
+	DaysPresent int `gorm:"not null"`
+	// 欠席日数
+	DaysAbsent int `gorm:"not null"`
+	// 遅刻日数
+	DaysTardy int `gorm:"not null"`
+	// 最終更新日時
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+// テーブル名を指定する
+func (ReportCardRemark) TableName() string {
+	return "report_card_remarks"
+}
+
+// 通知表データベースモデル：report_cardsテーブルとマッピング
+type ReportCard struct {
+	// 通知表の一意識別子
+	ID uint `gorm:"primaryKey"`
+	// 学生のID
+	StudentID uint `gorm:"not null"`
+	// 学期
+	Term string `gorm:"not null"`
+	// ファイルストレージ上のPDFのID
+	FileID string `gorm:"not null"`
+	// 通知表を生成した教師のID
+	GeneratedBy uint `gorm:"not null"`
+	// 生成日時
+	GeneratedAt time.Time `gorm:"not null"`
+}
+
+// テーブル名を指定する
+func (ReportCard) TableName() string {
+	return "report_cards"
+}
+
+// 新しい通知表リポジトリインスタンスを作成する
+func NewReportCardRepository(db *gorm.DB) *ReportCardRepository {
+	return &ReportCardRepository{
+		db: db,
+	}
+}
+
+// 所見を保存する
+// 同じ教師・学生・学期の所見がある場合は上書きする
+func (r *ReportCardRepository) SaveRemark(remark *domain.ReportCardRemark) (int64, error) {
+	model := ReportCardRemark{
+		TeacherID:   uint(remark.TeacherID),
+		StudentID:   uint(remark.StudentID),
+		Term:        remark.Term,
+		Comment:     remark.Comment,
+		DaysPresent: remark.DaysPresent,
+		DaysAbsent:  remark.DaysAbsent,
+		DaysTardy:   remark.DaysTardy,
+		UpdatedAt:   remark.UpdatedAt,
+	}
+
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "teacher_id"}, {Name: "student_id"}, {Name: "term"}},
+		DoUpdates: clause.AssignmentColumns([]string{"comment", "days_present", "days_absent", "days_tardy", "updated_at"}),
+	}).Create(&model)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to save report card remark: %w", result.Error)
+	}
+
+	return int64(model.ID), nil
+}
+
+// 学生の指定学期の所見一覧を取得する
+func (r *ReportCardRepository) GetRemarksByStudentID(studentID int64, term string) ([]domain.ReportCardRemark, error) {
+	var models []ReportCardRemark
+	result := r.db.Where("student_id = ? AND term = ?", studentID, term).Order("teacher_id").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get report card remarks: %w", result.Error)
+	}
+
+	remarks := make([]domain.ReportCardRemark, len(models))
+	for i, m := range models {
+		remarks[i] = domain.ReportCardRemark{
+			ID:          int64(m.ID),
+			TeacherID:   int64(m.TeacherID),
+			StudentID:   int64(m.StudentID),
+			Term:        m.Term,
+			Comment:     m.Comment,
+			DaysPresent: m.DaysPresent,
+			DaysAbsent:  m.DaysAbsent,
+			DaysTardy:   m.DaysTardy,
+			UpdatedAt:   m.UpdatedAt,
+		}
+	}
+
+	return remarks, nil
+}
+
+// 生成済み通知表を保存する
+// 同じ学生・学期の通知表がある場合は置き換える
+func (r *ReportCardRepository) SaveReportCardFile(card *domain.ReportCardFile) (int64, error) {
+	model := ReportCard{
+		StudentID:   uint(card.StudentID),
+		Term:        card.Term,
+		FileID:      card.FileID,
+		GeneratedBy: uint(card.GeneratedBy),
+		GeneratedAt: card.GeneratedAt,
+	}
+
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "student_id"}, {Name: "term"}},
+		DoUpdates: clause.AssignmentColumns([]string{"file_id", "generated_by", "generated_at"}),
+	}).Create(&model)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to save report card: %w", result.Error)
+	}
+
+	return int64(model.ID), nil
+}
+
+// 学生の指定学期の生成済み通知表を取得する
+func (r *ReportCardRepository) GetReportCardFile(studentID int64, term string) (*domain.ReportCardFile, error) {
+	var model ReportCard
+	result := r.db.Where("student_id = ? AND term = ?", studentID, term).First(&model)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no report card found for student %d in term %s", domain.ErrNotFound, studentID, term)
+		}
+		return nil, fmt.Errorf("query error: %w", result.Error)
+	}
+
+	card := toDomainReportCardFile(model)
+	return &card, nil
+}
+
+// 指定されたIDの生成済み通知表を取得する
+func (r *ReportCardRepository) GetReportCardFileByID(id int64) (*domain.ReportCardFile, error) {
+	var model ReportCard
+	result := r.db.First(&model, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no report card found with id: %d", domain.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("query error: %w", result.Error)
+	}
+
+	card := toDomainReportCardFile(model)
+	return &card, nil
+}
+
+// 教師の担当学生の生成済み通知表一覧を取得する
+// 学期が空の場合は全学期の通知表を返す
+func (r *ReportCardRepository) GetReportCardFilesByTeacherID(teacherID int64, term string) ([]domain.ReportCardFile, error) {
+	var models []ReportCard
+	query := r.db.
+		Joins("JOIN teacher_students ts ON ts.student_id = report_cards.student_id").
+		Where("ts.teacher_id = ?", teacherID)
+	if term != "" {
+		query = query.Where("report_cards.term = ?", term)
+	}
+
+	result := query.Order("report_cards.term, report_cards.student_id").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get report cards: %w", result.Error)
+	}
+
+	return toDomainReportCardFiles(models), nil
+}
+
+// 学生の生成済み通知表一覧を取得する
+func (r *ReportCardRepository) GetReportCardFilesByStudentID(studentID int64) ([]domain.ReportCardFile, error) {
+	var models []ReportCard
+	result := r.db.Where("student_id = ?", studentID).Order("generated_at DESC").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get report cards: %w", result.Error)
+	}
+
+	return toDomainReportCardFiles(models), nil
+}
+
+// 通知表データベースモデルをドメインモデルに変換する
+func toDomainReportCardFile(m ReportCard) domain.ReportCardFile {
+	return domain.ReportCardFile{
+		ID:          int64(m.ID),
+		StudentID:   int64(m.StudentID),
+		Term:        m.Term,
+		FileID:      m.FileID,
+		GeneratedBy: int64(m.GeneratedBy),
+		GeneratedAt: m.GeneratedAt,
+	}
+}
+
+// 通知表データベースモデルのリストをドメインモデルに変換する
+func toDomainReportCardFiles(models []ReportCard) []domain.ReportCardFile {
+	cards := make([]domain.ReportCardFile, len(models))
+	for i, m := range models {
+		cards[i] = toDomainReportCardFile(m)
+	}
+	return cards
+}
@@ -238,3 +238,46 @@ func (r *TeacherRepository) GetStudentsByTeacherID(teacherID int64) ([]domain.St
 
 	return domainStudents, nil
 }
+
+// 学生が教師に割り当てられているかを確認する
+func (r *TeacherRepository) IsStudentAssigned(teacherID, studentID int64) (bool, error) {
+	var count int64
+	result := r.db.Table("teacher_students").
+		Where("teacher_id = ? AND student_id = ?", teacherID, studentID).
+		Count(&count)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to check student assignment: %w", result.Error)
+	}
+
+	return count > 0, nil
+}
+
+// 学生を担当している教師一覧を取得する
+func (r *TeacherRepository) GetTeachersByStudentID(studentID int64) ([]domain.Teacher, error) {
+	var teachers []Teacher
+	// 学生に割り当てられた教師を取得
+	result := r.db.Raw(`
+		SELECT t.* FROM teachers t
+		JOIN teacher_students ts ON t.id = ts.teacher_id
+		WHERE ts.student_id = ?
+		ORDER BY t.id
+	`, studentID).Scan(&teachers)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get teachers: %w", result.Error)
+	}
+
+	// データベースモデルをドメインモデルに変換
+	domainTeachers := make([]domain.Teacher, len(teachers))
+	for i, t := range teachers {
+		domainTeachers[i] = domain.Teacher{
+			ID:        int64(t.ID),
+			Name:      t.Name,
+			Email:     t.Email,
+			Subject:   t.Subject,
+			CreatedAt: t.CreatedAt,
+			UpdatedAt: t.UpdatedAt,
+		}
+	}
+
+	return domainTeachers, nil
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// サムネイルリポジトリ構造体：データベースを使用したサムネイルの永続化を実装
+type ThumbnailRepository struct {
+	// データベース接続
+	db *gorm.DB
+}
+
+// サムネイルデータベースモデル：thumbnailsテーブルとマッピング
+// サムネイルはブロブ（内容のチェックサム）ごとに作成し、ブロブの削除とともに削除される
+type Thumbnail struct {
+	// 元の内容のSHA-256チェックサム
+	Checksum string `gorm:"primaryKey"`
+	// サムネイルのサイズ（長辺のピクセル数）
+	Size int `gorm:"primaryKey"`
+	// ストレージ上のオブジェクトキー
+	ObjectKey string `gorm:"not null"`
+	// サムネイルのMIMEタイプ
+	ContentType string `gorm:"not null"`
+	// サムネイルのデータサイズ（バイト）
+	ByteSize int64 `gorm:"not null"`
+	// 作成日時
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// テーブル名を指定する
+func (Thumbnail) TableName() string {
+	return "thumbnails"
+}
+
+// 新しいサムネイルリポジトリインスタンスを作成する
+func NewThumbnailRepository(db *gorm.DB) *ThumbnailRepository {
+	return &ThumbnailRepository{
+		db: db,
+	}
+}
+
+// 指定されたチェックサムの内容から作成したサムネイルの一覧をサイズ順に取得する
+func (r *ThumbnailRepository) GetThumbnailsByChecksum(checksum string) ([]domain.Thumbnail, error) {
+	var models []Thumbnail
+	result := r.db.Where("checksum = ?", checksum).Order("size").Find(&models)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list thumbnails: %w", result.Error)
+	}
+
+	thumbnails := make([]domain.Thumbnail, len(models))
+	for i, m := range models {
+		thumbnails[i] = toDomainThumbnail(m)
+	}
+	return thumbnails, nil
+}
+
+// サムネイルを登録する
+// ブロブの行を共有ロックしてから登録するため、ブロブの削除と同時に登録されて参照のないサムネイルが残ることはない
+func (r *ThumbnailRepository) SaveThumbnail(thumbnail *domain.Thumbnail) (bool, error) {
+	saved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var blob Blob
+		result := tx.Clauses(clause.Locking{Strength: "SHARE"}).Where("checksum = ?", thumbnail.Checksum).First(&blob)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return fmt.Errorf("query error: %w", result.Error)
+		}
+
+		model := Thumbnail{
+			Checksum:    thumbnail.Checksum,
+			Size:        thumbnail.Size,
+			ObjectKey:   thumbnail.ObjectKey,
+			ContentType: thumbnail.ContentType,
+			ByteSize:    thumbnail.ByteSize,
+			CreatedAt:   thumbnail.CreatedAt,
+		}
+		result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model)
+		if result.Error != nil {
+			return fmt.Errorf("failed to create thumbnail: %w", result.Error)
+		}
+		saved = result.RowsAffected > 0
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return saved, nil
+}
+
+// サムネイルのデータベースモデルをドメインモデルに変換する
+func toDomainThumbnail(m Thumbnail) domain.Thumbnail {
+	return domain.Thumbnail{
+		Checksum:    m.Checksum,
+		Size:        m.Size,
+		ObjectKey:   m.ObjectKey,
+		ContentType: m.ContentType,
+		ByteSize:    m.ByteSize,
+		CreatedAt:   m.CreatedAt,
+	}
+}
@@ -0,0 +1,139 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+)
+
+const (
+	// clamdへ送信する1チャンクの最大サイズ
+	clamdChunkSize = 64 << 10
+	// clamdの応答の最大長
+	clamdMaxReplyLength = 4 << 10
+)
+
+// ClamAVスキャナー構造体：clamdのINSTREAMコマンドで内容を送信して検査する
+// 内容はチャンクに分けて送信するため、ファイル全体をメモリに保持しない
+type ClamAVScanner struct {
+	// clamdのアドレス（host:port、または"/"から始まるUNIXソケットのパス）
+	address string
+	// 1回の検査の最大時間（接続から応答の受信まで）
+	timeout time.Duration
+}
+
+// 新しいClamAVスキャナーインスタンスを作成する
+func NewClamAVScanner(address string, timeout time.Duration) *ClamAVScanner {
+	return &ClamAVScanner{
+		address: address,
+		timeout: timeout,
+	}
+}
+
+// 内容をclamdへ送信して検査し、結果を返す
+// clamdがエラーを返した場合（サイズの上限を超えた場合など）はエラーを返す
+func (s *ClamAVScanner) Scan(ctx context.Context, content io.Reader) (*domain.ScanResult, error) {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	network := "tcp"
+	if strings.HasPrefix(s.address, "/") {
+		network = "unix"
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, s.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, fmt.Errorf("failed to set clamd deadline: %w", err)
+		}
+	}
+
+	if err := sendStream(conn, content); err != nil {
+		// clamdはサイズの上限を超えると応答を返して接続を閉じるため、応答があればそちらを優先する
+		if reply, replyErr := readReply(conn); replyErr == nil && reply != "" {
+			return parseReply(reply)
+		}
+		return nil, fmt.Errorf("failed to send content to clamd: %w", err)
+	}
+
+	reply, err := readReply(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	return parseReply(reply)
+}
+
+// INSTREAMコマンドと内容のチャンク（4バイトのビッグエンディアンの長さと本体）、終端の長さ0のチャンクを送信する
+func sendStream(conn net.Conn, content io.Reader) error {
+	writer := bufio.NewWriterSize(conn, clamdChunkSize+4)
+	if _, err := writer.WriteString("zINSTREAM\x00"); err != nil {
+		return err
+	}
+
+	chunk := make([]byte, clamdChunkSize)
+	var length [4]byte
+	for {
+		n, err := io.ReadFull(content, chunk)
+		if n > 0 {
+			binary.BigEndian.PutUint32(length[:], uint32(n))
+			if _, err := writer.Write(length[:]); err != nil {
+				return err
+			}
+			if _, err := writer.Write(chunk[:n]); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read content: %w", err)
+		}
+	}
+
+	binary.BigEndian.PutUint32(length[:], 0)
+	if _, err := writer.Write(length[:]); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// clamdの応答をNUL文字または接続の終了まで読み込む
+func readReply(conn net.Conn) (string, error) {
+	reply, err := bufio.NewReader(io.LimitReader(conn, clamdMaxReplyLength)).ReadBytes(0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return string(bytes.TrimSpace(bytes.TrimRight(reply, "\x00"))), nil
+}
+
+// clamdの応答（"stream: OK"、"stream: <名前> FOUND"、"<理由> ERROR"）を検査結果に変換する
+func parseReply(reply string) (*domain.ScanResult, error) {
+	status := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case status == "OK":
+		return &domain.ScanResult{}, nil
+	case strings.HasSuffix(status, " FOUND"):
+		return &domain.ScanResult{Infected: true, Signature: strings.TrimSuffix(status, " FOUND")}, nil
+	case reply == "":
+		return nil, errors.New("clamd closed the connection without a reply")
+	default:
+		return nil, fmt.Errorf("clamd: %s", reply)
+	}
+}
@@ -0,0 +1,117 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clamdのINSTREAMコマンドを受け付ける偽のサーバーを起動し、受信した内容に対する応答を返す
+func startFakeClamd(t *testing.T, reply func(content []byte) string) (string, <-chan []byte) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan []byte, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				command, err := reader.ReadString(0)
+				if err != nil || command != "zINSTREAM\x00" {
+					conn.Write([]byte("UNKNOWN COMMAND\x00"))
+					return
+				}
+
+				var content bytes.Buffer
+				for {
+					var length uint32
+					if err := binary.Read(reader, binary.BigEndian, &length); err != nil {
+						return
+					}
+					if length == 0 {
+						break
+					}
+					if _, err := io.CopyN(&content, reader, int64(length)); err != nil {
+						return
+					}
+				}
+				received <- content.Bytes()
+				conn.Write([]byte(reply(content.Bytes()) + "\x00"))
+			}(conn)
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+func eicarReply(content []byte) string {
+	if bytes.Contains(content, []byte("EICAR-STANDARD-ANTIVIRUS-TEST-FILE")) {
+		return "stream: Eicar-Test-Signature FOUND"
+	}
+	return "stream: OK"
+}
+
+func TestClamAVScanner_Scan(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("clean content is streamed in chunks", func(t *testing.T) {
+		// Setup
+		address, received := startFakeClamd(t, eicarReply)
+		scanner := NewClamAVScanner(address, 5*time.Second)
+		content := strings.Repeat("lesson notes\n", 20000)
+
+		// Test
+		result, err := scanner.Scan(ctx, strings.NewReader(content))
+
+		// Assertions
+		require.NoError(t, err)
+		assert.False(t, result.Infected)
+		assert.Equal(t, content, string(<-received))
+	})
+
+	t.Run("infected content reports the signature", func(t *testing.T) {
+		address, _ := startFakeClamd(t, eicarReply)
+		scanner := NewClamAVScanner(address, 5*time.Second)
+
+		result, err := scanner.Scan(ctx, strings.NewReader(`X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`))
+
+		require.NoError(t, err)
+		assert.True(t, result.Infected)
+		assert.Equal(t, "Eicar-Test-Signature", result.Signature)
+	})
+
+	t.Run("error reply is returned as an error", func(t *testing.T) {
+		address, _ := startFakeClamd(t, func([]byte) string { return "INSTREAM size limit exceeded. ERROR" })
+		scanner := NewClamAVScanner(address, 5*time.Second)
+
+		_, err := scanner.Scan(ctx, strings.NewReader("large"))
+
+		assert.ErrorContains(t, err, "size limit exceeded")
+	})
+
+	t.Run("unreachable clamd", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		address := listener.Addr().String()
+		listener.Close()
+		scanner := NewClamAVScanner(address, time.Second)
+
+		_, err = scanner.Scan(ctx, strings.NewReader("content"))
+
+		assert.ErrorContains(t, err, "failed to connect to clamd")
+	})
+}
@@ -0,0 +1,200 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// 受け付けるJSON Schemaのドラフト（$schemaを省略した場合もこのドラフトとして扱う）
+const draft2020 = "https://json-schema.org/draft/2020-12/schema"
+
+// エラーメッセージに使用する言語
+var printer = message.NewPrinter(language.English)
+
+// JSON Schemaバリデーター構造体：draft 2020-12のJSON Schemaによるドキュメントの検証を実装
+// スキーマのバージョンは変更されないため、コンパイルしたスキーマを種類とバージョンごとに再利用する
+type JSONSchemaValidator struct {
+	// コンパイル済みのスキーマを保護するミューテックス
+	mu sync.Mutex
+	// 種類とバージョンごとのコンパイル済みのスキーマ
+	compiled map[string]*jsonschema.Schema
+}
+
+// 新しいJSON Schemaバリデーターインスタンスを作成する関数
+func NewJSONSchemaValidator() *JSONSchemaValidator {
+	return &JSONSchemaValidator{
+		compiled: make(map[string]*jsonschema.Schema),
+	}
+}
+
+// JSON Schema（draft 2020-12）として使用できるか確認する
+func (v *JSONSchemaValidator) Check(schema json.RawMessage) error {
+	_, err := compile("check", schema)
+	return err
+}
+
+// データをスキーマで検証し、適合しなかった項目を返す
+func (v *JSONSchemaValidator) Validate(schema *domain.DocumentSchema, data map[string]interface{}) ([]domain.FieldError, error) {
+	compiled, err := v.schema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	// スキーマのライブラリはencoding/jsonで読み込んだ値を前提とするため、JSONを経由して変換する
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document data: %w", err)
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode document data: %w", err)
+	}
+
+	err = compiled.Validate(instance)
+	if err == nil {
+		return nil, nil
+	}
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, fmt.Errorf("failed to validate document: %w", err)
+	}
+	return fieldErrors(validationErr), nil
+}
+
+// 種類とバージョンのコンパイル済みのスキーマを取得する（初回はコンパイルする）
+func (v *JSONSchemaValidator) schema(schema *domain.DocumentSchema) (*jsonschema.Schema, error) {
+	key := fmt.Sprintf("%s/v%d", schema.Type, schema.Version)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if compiled, ok := v.compiled[key]; ok {
+		return compiled, nil
+	}
+	compiled, err := compile(key, schema.Schema)
+	if err != nil {
+		return nil, err
+	}
+	v.compiled[key] = compiled
+	return compiled, nil
+}
+
+// スキーマをコンパイルする
+// 外部のスキーマを参照するとサーバーのファイルやネットワークを読みにいくことになるため、参照はスキーマ内に限る
+func compile(name string, schema json.RawMessage) (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return nil, fmt.Errorf("%w: schema is not valid JSON: %s", domain.ErrInvalidInput, err)
+	}
+	object, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: schema must be a JSON object", domain.ErrInvalidInput)
+	}
+	if draft, ok := object["$schema"]; ok && strings.TrimSuffix(fmt.Sprint(draft), "#") != draft2020 {
+		return nil, fmt.Errorf("%w: only JSON Schema draft 2020-12 is supported", domain.ErrInvalidInput)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	compiler.UseLoader(noLoader{})
+
+	url := "urn:document-schema:" + name
+	if err := compiler.AddResource(url, doc); err != nil {
+		return nil, fmt.Errorf("%w: invalid schema: %s", domain.ErrInvalidInput, err)
+	}
+	compiled, err := compiler.Compile(url)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid schema: %s", domain.ErrInvalidInput, err)
+	}
+	return compiled, nil
+}
+
+// 外部のスキーマの読み込みを拒否するローダー
+type noLoader struct{}
+
+// 外部のスキーマを読み込まずにエラーを返す
+func (noLoader) Load(url string) (any, error) {
+	return nil, fmt.Errorf("external schema references are not allowed: %s", url)
+}
+
+// 検証エラーの木から、原因となった末端のエラーを項目ごとのエラーとして取り出す
+// 項目はドキュメント内のJSON Pointer（dataからの位置）で表す
+func fieldErrors(err *jsonschema.ValidationError) []domain.FieldError {
+	var fields []domain.FieldError
+	seen := make(map[domain.FieldError]bool)
+
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) > 0 {
+			for _, cause := range e.Causes {
+				walk(cause)
+			}
+			return
+		}
+		for _, field := range leafErrors(e) {
+			if !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	walk(err)
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].Field < fields[j].Field
+	})
+	return fields
+}
+
+// 末端の検証エラーを項目のエラーに変換する
+// 必須の項目の不足と許可されていない項目は、オブジェクトではなくそれぞれの項目のエラーとする
+func leafErrors(e *jsonschema.ValidationError) []domain.FieldError {
+	child := func(name string, message string) domain.FieldError {
+		location := append(append([]string{}, e.InstanceLocation...), name)
+		return domain.FieldError{Field: fieldPointer(location), Message: message}
+	}
+
+	var fields []domain.FieldError
+	switch errorKind := e.ErrorKind.(type) {
+	case *kind.Required:
+		for _, name := range errorKind.Missing {
+			fields = append(fields, child(name, "is required"))
+		}
+	case *kind.DependentRequired:
+		for _, name := range errorKind.Missing {
+			fields = append(fields, child(name, fmt.Sprintf("is required when %s is present", errorKind.Prop)))
+		}
+	case *kind.AdditionalProperties:
+		for _, name := range errorKind.Properties {
+			fields = append(fields, child(name, "is not allowed"))
+		}
+	default:
+		fields = append(fields, domain.FieldError{
+			Field:   fieldPointer(e.InstanceLocation),
+			Message: e.ErrorKind.LocalizedString(printer),
+		})
+	}
+	return fields
+}
+
+// データ内の位置をドキュメントのJSON Pointerに変換する（例：["title"] → "/data/title"）
+func fieldPointer(location []string) string {
+	var b strings.Builder
+	b.WriteString("/data")
+	for _, token := range location {
+		b.WriteByte('/')
+		token = strings.ReplaceAll(token, "~", "~0")
+		b.WriteString(strings.ReplaceAll(token, "/", "~1"))
+	}
+	return b.String()
+}
@@ -0,0 +1,287 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// カタログ付きファイルストレージ構造体：ファイルカタログを介してファイル操作を行う
+// 実際のデータはバックエンドのストレージにオブジェクトとして保存し、
+// ファイルID、元のファイル名、アップロードしたユーザー、バージョン履歴などのメタデータはカタログで管理する
+type CatalogFileStorage struct {
+	// オブジェクトを保存するバックエンドのストレージ（IDはオブジェクトキー）
+	backend ports.FileStorage
+	// ファイルカタログ
+	catalog ports.FileRepository
+	// アップロードポリシー（nilの場合は制限しない）
+	policy ports.UploadPolicyService
+	// コンテンツ検査サービス（保存した内容のマルウェア検査を予約する、nilの場合は定期的な確認による検査のみ）
+	scans ports.ContentScanService
+	// ファイルごとに保持するバージョン数（現在のバージョンを含む、0の場合は無制限）
+	retention int
+	// 現在時刻を返す関数
+	now func() time.Time
+}
+
+// 新しいカタログ付きファイルストレージインスタンスを作成する関数
+func NewCatalogFileStorage(backend ports.FileStorage, catalog ports.FileRepository, policy ports.UploadPolicyService, scans ports.ContentScanService, retention int) *CatalogFileStorage {
+	return &CatalogFileStorage{
+		backend:   backend,
+		catalog:   catalog,
+		policy:    policy,
+		scans:     scans,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// ファイルをバックエンドに保存し、カタログに登録する
+// アップロードポリシーを適用し、MIMEタイプは内容から判定したものを使用する
+// アップロードしたユーザーの使用量はカタログへの登録時にクォータと照合する
+// 同じ内容のファイルが既に保存されている場合は既存のオブジェクトを共有し、今回保存したオブジェクトは削除する
+// カタログへの登録に失敗した場合も、保存したオブジェクトは削除する
+// 新しい内容はマルウェア検査が終わるまでダウンロードできない
+func (s *CatalogFileStorage) Upload(ctx context.Context, file *domain.FileUpload) (*domain.File, error) {
+	record, err := s.store(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	storedKey := record.ObjectKey
+
+	if err := s.catalog.CreateFile(record, s.quota(file.UploaderRole)); err != nil {
+		s.removeObject(ctx, storedKey)
+		return nil, err
+	}
+	if record.ObjectKey != storedKey {
+		s.removeObject(ctx, storedKey)
+	}
+
+	s.enqueueScan(record)
+	return record, nil
+}
+
+// ファイルの新しいバージョンをバックエンドに保存し、カタログに追加する
+// 保持するバージョン数を超えた古いバージョンは削除する
+func (s *CatalogFileStorage) UploadVersion(ctx context.Context, id string, file *domain.FileUpload) (*domain.File, error) {
+	current, err := s.catalog.GetFileByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.store(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	record.ID = id
+	storedKey := record.ObjectKey
+
+	if err := s.addVersion(ctx, record, current.UploaderRole); err != nil {
+		s.removeObject(ctx, storedKey)
+		return nil, err
+	}
+	if record.ObjectKey != storedKey {
+		s.removeObject(ctx, storedKey)
+	}
+
+	return s.currentVersion(id)
+}
+
+// 指定されたIDのファイルのバージョン一覧を新しい順に取得する
+func (s *CatalogFileStorage) ListVersions(ctx context.Context, id string) ([]domain.File, error) {
+	return s.catalog.ListFileVersions(id)
+}
+
+// カタログから指定されたバージョンのオブジェクトキーを解決し、内容を読み込むストリームを返す
+// 内容のマルウェア検査が終わっていない場合はErrScanPending、隔離されている場合はErrQuarantinedを返す
+func (s *CatalogFileStorage) DownloadVersion(ctx context.Context, id string, version int) (*domain.File, io.ReadCloser, error) {
+	record, err := s.catalog.GetFileVersion(id, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkServable(record); err != nil {
+		return nil, nil, err
+	}
+
+	_, body, err := s.backend.Download(ctx, record.ObjectKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return record, body, nil
+}
+
+// 指定されたバージョンの内容を新しいバージョンとして追加する
+// 内容は既存のオブジェクトを共有するため、バックエンドへの転送は発生しない
+func (s *CatalogFileStorage) RestoreVersion(ctx context.Context, id string, version int) (*domain.File, error) {
+	current, err := s.catalog.GetFileByID(id)
+	if err != nil {
+		return nil, err
+	}
+	record, err := s.catalog.GetFileVersion(id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	record.UploadedAt = s.now().UTC()
+	if err := s.addVersion(ctx, record, current.UploaderRole); err != nil {
+		return nil, err
+	}
+	return s.currentVersion(id)
+}
+
+// カタログからオブジェクトキーを解決し、ファイルの内容を読み込むストリームを返す
+// 内容のマルウェア検査が終わっていない場合はErrScanPending、隔離されている場合はErrQuarantinedを返す
+func (s *CatalogFileStorage) Download(ctx context.Context, id string) (*domain.File, io.ReadCloser, error) {
+	record, err := s.catalog.GetFileByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkServable(record); err != nil {
+		return nil, nil, err
+	}
+
+	_, body, err := s.backend.Download(ctx, record.ObjectKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return record, body, nil
+}
+
+// カタログからオブジェクトキーを解決し、ファイルの指定された範囲を読み込むストリームを返す
+// 返すファイル情報はカタログのもの（Sizeはファイル全体のサイズ）
+func (s *CatalogFileStorage) DownloadRange(ctx context.Context, id string, byteRange domain.ByteRange) (*domain.File, io.ReadCloser, error) {
+	record, err := s.catalog.GetFileByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkServable(record); err != nil {
+		return nil, nil, err
+	}
+
+	_, body, err := s.backend.DownloadRange(ctx, record.ObjectKey, byteRange)
+	if err != nil {
+		return nil, nil, err
+	}
+	return record, body, nil
+}
+
+// ファイルをすべてのバージョンとともにカタログから削除し、他に参照するファイルがなくなったオブジェクトを削除する
+// カタログを先に削除するため、オブジェクトの削除に失敗しても存在しないファイルが一覧に残ることはない
+func (s *CatalogFileStorage) Delete(ctx context.Context, id string) error {
+	released, err := s.catalog.DeleteFile(id)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range released {
+		if err := s.backend.Delete(ctx, key); err != nil {
+			return fmt.Errorf("file %s was removed from the catalog but its object was not deleted: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// カタログに登録されているファイルの一覧を取得する
+func (s *CatalogFileStorage) List(ctx context.Context) ([]domain.File, error) {
+	return s.catalog.ListFiles(domain.FileFilter{})
+}
+
+// カタログから指定されたIDのファイル情報を取得する
+func (s *CatalogFileStorage) Get(ctx context.Context, id string) (*domain.File, error) {
+	return s.catalog.GetFileByID(id)
+}
+
+// アップロードポリシーを適用してファイルをバックエンドに保存し、カタログに登録するファイル情報を返す
+// チェックサムとサイズはバックエンドへ送信しながら計算する
+func (s *CatalogFileStorage) store(ctx context.Context, file *domain.FileUpload) (*domain.File, error) {
+	if s.policy != nil {
+		applied, err := s.policy.Apply(file)
+		if err != nil {
+			return nil, err
+		}
+		file = applied
+	}
+
+	hash := sha256.New()
+	body := &countingReader{reader: io.TeeReader(file.Body, hash)}
+	upload := *file
+	upload.Body = body
+
+	stored, err := s.backend.Upload(ctx, &upload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.File{
+		ID:           uuid.New().String(),
+		Name:         file.Name,
+		Size:         body.count,
+		ContentType:  file.ContentType,
+		UploadedAt:   s.now().UTC(),
+		ObjectKey:    stored.ID,
+		Checksum:     hex.EncodeToString(hash.Sum(nil)),
+		UploaderID:   file.UploaderID,
+		UploaderRole: file.UploaderRole,
+	}, nil
+}
+
+// カタログにバージョンを追加し、保持するバージョン数を超えて参照がなくなったオブジェクトを削除する
+// バージョンの容量はファイルの所有者のクォータと照合する
+func (s *CatalogFileStorage) addVersion(ctx context.Context, record *domain.File, ownerRole string) error {
+	released, err := s.catalog.AddFileVersion(record, s.quota(ownerRole), s.retention)
+	if err != nil {
+		return err
+	}
+	for _, key := range released {
+		s.removeObject(ctx, key)
+	}
+	return nil
+}
+
+// ファイルの現在のバージョンをカタログから取得し、内容のマルウェア検査を予約する
+func (s *CatalogFileStorage) currentVersion(id string) (*domain.File, error) {
+	record, err := s.catalog.GetFileByID(id)
+	if err != nil {
+		return nil, err
+	}
+	s.enqueueScan(record)
+	return record, nil
+}
+
+// ファイルの内容のマルウェア検査を予約する（検査サービスがない場合は何もしない）
+func (s *CatalogFileStorage) enqueueScan(record *domain.File) {
+	if s.scans != nil {
+		s.scans.Enqueue(record)
+	}
+}
+
+// ファイルの内容をダウンロードできる状態か確認する
+func checkServable(record *domain.File) error {
+	if err := record.CheckServable(); err != nil {
+		return fmt.Errorf("%w: file %s", err, record.ID)
+	}
+	return nil
+}
+
+// 役割のクォータを返す（ポリシーがない場合は無制限）
+func (s *CatalogFileStorage) quota(role string) domain.StorageQuota {
+	if s.policy == nil {
+		return domain.StorageQuota{}
+	}
+	return s.policy.Quota(role)
+}
+
+// カタログに登録されていないオブジェクトを削除する（失敗した場合はログに記録する）
+func (s *CatalogFileStorage) removeObject(ctx context.Context, key string) {
+	if err := s.backend.Delete(ctx, key); err != nil {
+		slog.Error("failed to remove uncataloged object", slog.String("key", key), slog.String("error", err.Error()))
+	}
+}
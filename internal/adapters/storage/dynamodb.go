@@ -2,7 +2,11 @@ package storage
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/OICjangirrahul/students/internal/core/domain"
@@ -13,6 +17,12 @@ import (
 	"github.com/google/uuid"
 )
 
+// 種類と作成日時によるグローバルセカンダリインデックスの名前
+const TypeCreatedAtIndex = "TypeCreatedAtIndex"
+
+// 作成日時を文字列の順序で並べ替えられるように保存する形式（UTCでナノ秒まで固定長）
+const sortableTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
 // DynamoDBストレージ構造体：DynamoDBを使用したドキュメント操作を実装
 type DynamoDBStorage struct {
 	// DynamoDBクライアント
@@ -33,14 +43,16 @@ func NewDynamoDBStorage(client *dynamodb.Client, tableName string) *DynamoDBStor
 // ドキュメントデータを受け取り、一意のIDを生成して保存し、作成されたドキュメントを返す
 func (d *DynamoDBStorage) Create(ctx context.Context, doc *domain.DocumentCreate) (*domain.Document, error) {
 	// 現在時刻を取得
-	now := time.Now()
+	now := time.Now().UTC()
 	// ドキュメントを作成
 	document := &domain.Document{
-		ID:        uuid.New().String(),
-		Type:      doc.Type,
-		Data:      doc.Data,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:            uuid.New().String(),
+		Type:          doc.Type,
+		Data:          doc.Data,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		SchemaVersion: doc.SchemaVersion,
+		Version:       1,
 	}
 
 	// ドキュメントをDynamoDB形式に変換
@@ -48,6 +60,8 @@ func (d *DynamoDBStorage) Create(ctx context.Context, doc *domain.DocumentCreate
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal document: %w", err)
 	}
+	// 作成日時はインデックスのソートキーとして文字列の順序で並ぶ形式で保存する
+	item["CreatedAt"] = sortableTime(now)
 
 	// DynamoDBにドキュメントを保存
 	_, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{
@@ -64,11 +78,13 @@ func (d *DynamoDBStorage) Create(ctx context.Context, doc *domain.DocumentCreate
 // 指定されたIDのドキュメントを取得する
 func (d *DynamoDBStorage) Get(ctx context.Context, id string) (*domain.Document, error) {
 	// DynamoDBからドキュメントを取得
+	// 更新の前提となるバージョンを正しく判断できるよう、強い整合性で読み込む
 	result, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(d.tableName),
 		Key: map[string]types.AttributeValue{
 			"ID": &types.AttributeValueMemberS{Value: id},
 		},
+		ConsistentRead: aws.Bool(true),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get document: %w", err)
@@ -76,7 +92,7 @@ func (d *DynamoDBStorage) Get(ctx context.Context, id string) (*domain.Document,
 
 	// ドキュメントが存在しない場合はエラー
 	if result.Item == nil {
-		return nil, fmt.Errorf("document not found")
+		return nil, fmt.Errorf("%w: document not found: %s", domain.ErrNotFound, id)
 	}
 
 	// DynamoDB形式からドキュメントに変換
@@ -86,91 +102,350 @@ func (d *DynamoDBStorage) Get(ctx context.Context, id string) (*domain.Document,
 		return nil, fmt.Errorf("failed to unmarshal document: %w", err)
 	}
 
+	// ゴミ箱にあるドキュメントは存在しないものとして扱う
+	if document.DeletedAt != nil {
+		return nil, fmt.Errorf("%w: document not found: %s", domain.ErrNotFound, id)
+	}
+
 	return &document, nil
 }
 
 // 指定されたIDのドキュメントを更新する
 // ドキュメントIDと更新データを受け取り、ドキュメントを更新して更新後のドキュメントを返す
 func (d *DynamoDBStorage) Update(ctx context.Context, id string, update *domain.DocumentUpdate) (*domain.Document, error) {
-	// 更新式とパラメータを設定
-	updateExpr := "SET #data = :data, #updatedAt = :updatedAt"
-	attrNames := map[string]string{
-		"#data":      "Data",
-		"#updatedAt": "UpdatedAt",
+	// dataの全体を置き換える
+	expr := newUpdateExpression(update.SchemaVersion, update.ExpectedVersion)
+	expr.set("#data", update.Data)
+
+	document, err := d.updateItem(ctx, id, expr)
+	if err != nil {
+		return nil, updateError(id, update.ExpectedVersion, nil, err)
 	}
-	attrValues, err := attributevalue.MarshalMap(map[string]interface{}{
-		":data":      update.Data,
-		":updatedAt": time.Now(),
+	return document, nil
+}
+
+// 指定されたIDのドキュメントを完全に削除する
+func (d *DynamoDBStorage) Delete(ctx context.Context, id string) error {
+	// DynamoDBからドキュメントを削除
+	_, err := d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal update values: %w", err)
+		return fmt.Errorf("failed to delete document: %w", err)
 	}
 
-	// DynamoDBのドキュメントを更新
-	result, err := d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+	return nil
+}
+
+// 指定されたタイプのすべてのドキュメントを作成日時の古い順に取得する
+func (d *DynamoDBStorage) List(ctx context.Context, docType string) ([]domain.Document, error) {
+	var documents []domain.Document
+	input, err := d.queryInput(&domain.DocumentQuery{Type: docType, Order: domain.SortAscending})
+	if err != nil {
+		return nil, err
+	}
+
+	// 1回のクエリで返されるのは最大1MBのため、すべてのページを読み込む
+	paginator := dynamodb.NewQueryPaginator(d.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list documents: %w", err)
+		}
+
+		var items []domain.Document
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal documents: %w", err)
+		}
+		documents = append(documents, items...)
+	}
+
+	return documents, nil
+}
+
+// 検索条件に一致するドキュメントを作成日時順に1ページ分取得する
+// 次のページがある場合は、最後に評価したキーをカーソルとして返す
+func (d *DynamoDBStorage) Query(ctx context.Context, query *domain.DocumentQuery) (*domain.DocumentPage, error) {
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	input, err := d.queryInput(query)
+	if err != nil {
+		return nil, err
+	}
+	if query.Cursor != "" {
+		startKey, err := decodeDocumentCursor(query)
+		if err != nil {
+			return nil, err
+		}
+		input.ExclusiveStartKey = startKey
+	}
+
+	page := &domain.DocumentPage{Items: []domain.Document{}}
+	for {
+		// ゴミ箱にあるドキュメントは読み込んだ後に除かれるため、件数に達するか最後まで読み込むまで続ける
+		input.Limit = aws.Int32(int32(query.Limit - len(page.Items)))
+		result, err := d.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list documents: %w", err)
+		}
+
+		var items []domain.Document
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal documents: %w", err)
+		}
+		page.Items = append(page.Items, items...)
+
+		if result.LastEvaluatedKey == nil {
+			return page, nil
+		}
+		if len(page.Items) >= query.Limit {
+			page.NextCursor, err = encodeDocumentCursor(result.LastEvaluatedKey)
+			if err != nil {
+				return nil, err
+			}
+			return page, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// 種類と作成日時のインデックスに対するクエリの条件を作成する（ゴミ箱にあるドキュメントは除く）
+// dataの値による絞り込みは、インデックスから読み込んだ項目に対するフィルターとして指定する
+func (d *DynamoDBStorage) queryInput(query *domain.DocumentQuery) (*dynamodb.QueryInput, error) {
+	keyCondition := "#type = :type"
+	attrNames := map[string]string{
+		"#type":      "Type",
+		"#deletedAt": "DeletedAt",
+	}
+	attrValues := map[string]types.AttributeValue{
+		":type": &types.AttributeValueMemberS{Value: query.Type},
+	}
+
+	// 作成日時の範囲はインデックスのソートキーに対する条件として指定する
+	switch {
+	case query.From != nil && query.To != nil:
+		keyCondition += " AND #createdAt BETWEEN :from AND :to"
+	case query.From != nil:
+		keyCondition += " AND #createdAt >= :from"
+	case query.To != nil:
+		keyCondition += " AND #createdAt <= :to"
+	}
+	if query.From != nil {
+		attrNames["#createdAt"] = "CreatedAt"
+		attrValues[":from"] = sortableTime(*query.From)
+	}
+	if query.To != nil {
+		attrNames["#createdAt"] = "CreatedAt"
+		attrValues[":to"] = sortableTime(*query.To)
+	}
+
+	filter := "attribute_not_exists(#deletedAt)"
+	keys := make([]string, 0, len(query.DataEquals))
+	for key := range query.DataEquals {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		value, err := attributevalue.Marshal(query.DataEquals[key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal data filter %s: %w", key, err)
+		}
+		attrNames["#data"] = "Data"
+		attrNames[fmt.Sprintf("#d%d", i)] = key
+		attrValues[fmt.Sprintf(":d%d", i)] = value
+		filter += fmt.Sprintf(" AND #data.#d%d = :d%d", i, i)
+	}
+
+	return &dynamodb.QueryInput{
 		TableName:                 aws.String(d.tableName),
-		Key:                       map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: id}},
-		UpdateExpression:          aws.String(updateExpr),
+		IndexName:                 aws.String(TypeCreatedAtIndex),
+		KeyConditionExpression:    aws.String(keyCondition),
+		FilterExpression:          aws.String(filter),
 		ExpressionAttributeNames:  attrNames,
 		ExpressionAttributeValues: attrValues,
-		ReturnValues:              types.ReturnValueAllNew,
-	})
+		ScanIndexForward:          aws.Bool(query.Order != domain.SortDescending),
+	}, nil
+}
+
+// 指定されたIDのドキュメントをゴミ箱に移動する
+// ドキュメントが存在しない、または既にゴミ箱にある場合はErrNotFoundを返す
+func (d *DynamoDBStorage) Trash(ctx context.Context, id string, at time.Time) error {
+	deletedAt, err := attributevalue.Marshal(at.UTC())
 	if err != nil {
-		return nil, fmt.Errorf("failed to update document: %w", err)
+		return fmt.Errorf("failed to marshal deletion time: %w", err)
 	}
 
-	// 更新後のドキュメントをDynamoDB形式から変換
-	var document domain.Document
-	err = attributevalue.UnmarshalMap(result.Attributes, &document)
+	_, err = d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(d.tableName),
+		Key:                       map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: id}},
+		UpdateExpression:          aws.String("SET #deletedAt = :deletedAt ADD #version :one"),
+		ConditionExpression:       aws.String("attribute_exists(ID) AND attribute_not_exists(#deletedAt)"),
+		ExpressionAttributeNames:  map[string]string{"#deletedAt": "DeletedAt", "#version": "Version"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":deletedAt": deletedAt, ":one": &types.AttributeValueMemberN{Value: "1"}},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal updated document: %w", err)
+		return documentError(id, "failed to trash document", err)
 	}
-
-	return &document, nil
+	return nil
 }
 
-// 指定されたIDのドキュメントを削除する
-func (d *DynamoDBStorage) Delete(ctx context.Context, id string) error {
-	// DynamoDBからドキュメントを削除
-	_, err := d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
-		TableName: aws.String(d.tableName),
-		Key: map[string]types.AttributeValue{
-			"ID": &types.AttributeValueMemberS{Value: id},
-		},
+// ゴミ箱にある指定されたIDのドキュメントを元に戻す
+// ドキュメントがゴミ箱にない場合はErrNotFoundを返す
+func (d *DynamoDBStorage) Restore(ctx context.Context, id string) error {
+	_, err := d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(d.tableName),
+		Key:                       map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: id}},
+		UpdateExpression:          aws.String("REMOVE #deletedAt ADD #version :one"),
+		ConditionExpression:       aws.String("attribute_exists(#deletedAt)"),
+		ExpressionAttributeNames:  map[string]string{"#deletedAt": "DeletedAt", "#version": "Version"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":one": &types.AttributeValueMemberN{Value: "1"}},
 	})
 	if err != nil {
-		return fmt.Errorf("failed to delete document: %w", err)
+		return documentError(id, "failed to restore document", err)
+	}
+	return nil
+}
+
+// ゴミ箱に移動した日時がdeletedAtのままのドキュメントを完全に削除する
+// 一覧を取得した後に元に戻された、またはゴミ箱に移動し直されたドキュメントは条件式で除外し、ErrNotFoundを返す
+func (d *DynamoDBStorage) PurgeTrashed(ctx context.Context, id string, deletedAt time.Time) error {
+	expected, err := attributevalue.Marshal(deletedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to marshal deletion time: %w", err)
 	}
 
+	_, err = d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:                 aws.String(d.tableName),
+		Key:                       map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: id}},
+		ConditionExpression:       aws.String("#deletedAt = :deletedAt"),
+		ExpressionAttributeNames:  map[string]string{"#deletedAt": "DeletedAt"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":deletedAt": expected},
+	})
+	if err != nil {
+		return documentError(id, "failed to purge document", err)
+	}
 	return nil
 }
 
-// 指定されたタイプのドキュメント一覧を取得する
-func (d *DynamoDBStorage) List(ctx context.Context, docType string) ([]domain.Document, error) {
-	// スキャン条件を設定
+// ゴミ箱にあるドキュメントの一覧を取得する
+func (d *DynamoDBStorage) ListTrashed(ctx context.Context) ([]domain.Document, error) {
+	var documents []domain.Document
 	input := &dynamodb.ScanInput{
-		TableName:        aws.String(d.tableName),
-		FilterExpression: aws.String("#type = :type"),
-		ExpressionAttributeNames: map[string]string{
-			"#type": "Type",
-		},
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":type": &types.AttributeValueMemberS{Value: docType},
-		},
+		TableName:                aws.String(d.tableName),
+		FilterExpression:         aws.String("attribute_exists(#deletedAt)"),
+		ExpressionAttributeNames: map[string]string{"#deletedAt": "DeletedAt"},
+	}
+
+	// ゴミ箱のドキュメントはテーブル全体に散らばっているため、すべてのページを読み込む
+	paginator := dynamodb.NewScanPaginator(d.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list trashed documents: %w", err)
+		}
+
+		var items []domain.Document
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal documents: %w", err)
+		}
+		documents = append(documents, items...)
+	}
+
+	return documents, nil
+}
+
+// 更新のエラーを変換する
+// 条件を満たさなかった場合、更新前の項目からドキュメントがない（ErrNotFound）のか、
+// バージョンが一致しない（DocumentVersionConflictError）のか、パッチを適用できない（ErrConflict）のかを判断する
+func updateError(id string, expected *int, patch *domain.DocumentPatch, err error) error {
+	var conditionFailed *types.ConditionalCheckFailedException
+	if !errors.As(err, &conditionFailed) {
+		return fmt.Errorf("failed to update document: %w", err)
+	}
+	if conditionFailed.Item == nil {
+		return fmt.Errorf("%w: document not found: %s", domain.ErrNotFound, id)
+	}
+
+	var current domain.Document
+	if err := attributevalue.UnmarshalMap(conditionFailed.Item, &current); err != nil {
+		return fmt.Errorf("failed to unmarshal document: %w", err)
+	}
+	if current.DeletedAt != nil {
+		return fmt.Errorf("%w: document not found: %s", domain.ErrNotFound, id)
+	}
+	if expected != nil && current.Version != *expected {
+		return &domain.DocumentVersionConflictError{ID: id, ExpectedVersion: *expected, CurrentVersion: current.Version}
+	}
+	if patch != nil {
+		if _, err := patch.Apply(current.Data); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("%w: %s", errConcurrentUpdate, id)
+}
+
+// DynamoDBのエラーを変換する（条件を満たさない場合はErrNotFound）
+func documentError(id string, message string, err error) error {
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return fmt.Errorf("%w: document not found: %s", domain.ErrNotFound, id)
 	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// 日時をインデックスのソートキーとして並べ替え可能な文字列の属性値に変換する
+func sortableTime(t time.Time) types.AttributeValue {
+	return &types.AttributeValueMemberS{Value: t.UTC().Format(sortableTimeLayout)}
+}
 
-	// DynamoDBからドキュメント一覧を取得
-	result, err := d.client.Scan(ctx, input)
+// インデックスのクエリで最後に評価したキーをカーソル文字列に変換する
+func encodeDocumentCursor(key map[string]types.AttributeValue) (string, error) {
+	values := make(map[string]string, len(key))
+	if err := attributevalue.UnmarshalMap(key, &values); err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	encoded, err := json.Marshal(values)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list documents: %w", err)
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
 	}
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
 
-	// DynamoDB形式からドキュメントリストに変換
-	var documents []domain.Document
-	err = attributevalue.UnmarshalListOfMaps(result.Items, &documents)
+// カーソル文字列をクエリの開始キーに変換する
+// 別の種類や日付範囲外のカーソルはErrInvalidInputとする
+func decodeDocumentCursor(query *domain.DocumentQuery) (map[string]types.AttributeValue, error) {
+	invalid := fmt.Errorf("%w: invalid cursor", domain.ErrInvalidInput)
+
+	decoded, err := base64.RawURLEncoding.DecodeString(query.Cursor)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal documents: %w", err)
+		return nil, invalid
+	}
+	var values map[string]string
+	if err := json.Unmarshal(decoded, &values); err != nil {
+		return nil, invalid
+	}
+	if len(values) != 3 || values["ID"] == "" || values["Type"] != query.Type {
+		return nil, invalid
+	}
+	createdAt, ok := values["CreatedAt"]
+	if !ok {
+		return nil, invalid
+	}
+	if query.From != nil && createdAt < query.From.UTC().Format(sortableTimeLayout) {
+		return nil, invalid
+	}
+	if query.To != nil && createdAt > query.To.UTC().Format(sortableTimeLayout) {
+		return nil, invalid
 	}
 
-	return documents, nil
+	key, err := attributevalue.MarshalMap(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cursor: %w", err)
+	}
+	return key, nil
 }
@@ -0,0 +1,283 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// 更新式に変換するパッチが変更できるdataの最上位のキーの最大数（これを超える場合は読み込んで書き戻す）
+	maxTargetedPatchKeys = 25
+	// 読み込んで書き戻す際に、他の更新と競合した場合に試行する最大回数
+	maxPatchAttempts = 3
+)
+
+// 他の更新と競合したため、パッチを適用できなかったことを示すエラー
+var errConcurrentUpdate = fmt.Errorf("%w: document was changed by another update", domain.ErrConflict)
+
+// 指定されたIDのドキュメントのdataにパッチを適用する
+// 単純なパッチ（dataの最上位のキーだけを変更するもの）はDynamoDBの更新式に変換して1回の書き込みで適用し、
+// それ以外はドキュメントを読み込んでパッチを適用し、読み込んだバージョンを条件に書き戻す
+func (d *DynamoDBStorage) Patch(ctx context.Context, id string, patch *domain.DocumentPatch) (*domain.Document, error) {
+	if expr, ok := targetedPatch(patch); ok {
+		document, err := d.updateItem(ctx, id, expr)
+		if err == nil {
+			return document, nil
+		}
+		// 条件式で表現できない差異（dataの値の型など）で失敗した場合は、読み込んで書き戻す
+		if err = updateError(id, patch.ExpectedVersion, patch, err); !errors.Is(err, errConcurrentUpdate) {
+			return nil, err
+		}
+	}
+	return d.patchByRewrite(ctx, id, patch)
+}
+
+// ドキュメントを読み込んでパッチを適用し、読み込んだバージョンを条件に書き戻す
+// 前提となるバージョンが指定されていない場合は、他の更新と競合しても最新のドキュメントに適用し直す
+func (d *DynamoDBStorage) patchByRewrite(ctx context.Context, id string, patch *domain.DocumentPatch) (*domain.Document, error) {
+	for attempt := 1; ; attempt++ {
+		current, err := d.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if patch.ExpectedVersion != nil && current.Version != *patch.ExpectedVersion {
+			return nil, &domain.DocumentVersionConflictError{ID: id, ExpectedVersion: *patch.ExpectedVersion, CurrentVersion: current.Version}
+		}
+
+		data, err := patch.Apply(current.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		expr := newUpdateExpression(patch.SchemaVersion, &current.Version)
+		expr.set("#data", data)
+		document, err := d.updateItem(ctx, id, expr)
+		if err == nil {
+			return document, nil
+		}
+
+		err = updateError(id, &current.Version, nil, err)
+		var conflict *domain.DocumentVersionConflictError
+		if !errors.As(err, &conflict) || patch.ExpectedVersion != nil {
+			return nil, err
+		}
+		if attempt == maxPatchAttempts {
+			return nil, fmt.Errorf("%w: %s", errConcurrentUpdate, id)
+		}
+	}
+}
+
+// パッチをDynamoDBの更新式に変換する（変換できない場合はfalse）
+// dataの最上位のキーの設定と削除、およびスカラー値のtestだけを変換する
+func targetedPatch(patch *domain.DocumentPatch) (*updateExpression, bool) {
+	expr := newUpdateExpression(patch.SchemaVersion, patch.ExpectedVersion)
+	// dataがオブジェクトでない場合は更新式のパスが無効になるため、条件で確認する
+	expr.condition("attribute_type(#data, " + expr.value("M") + ")")
+
+	switch patch.Format {
+	case domain.PatchFormatMerge:
+		if len(patch.Merge) > maxTargetedPatchKeys {
+			return nil, false
+		}
+		for key, value := range patch.Merge {
+			if key == "" {
+				return nil, false
+			}
+			switch value.(type) {
+			case nil:
+				expr.remove(expr.dataPath(key))
+			case map[string]interface{}:
+				// オブジェクトは再帰的にマージする必要があるため変換しない
+				return nil, false
+			default:
+				expr.set(expr.dataPath(key), value)
+			}
+		}
+		return expr, true
+
+	case domain.PatchFormatJSON:
+		if len(patch.Operations) > maxTargetedPatchKeys {
+			return nil, false
+		}
+		// 1つの更新式では同じパスを複数回変更できず、条件は更新前の値で評価されるため、
+		// 同じキーを複数回変更する操作や、変更後のキーに対するtestは変換しない
+		modified := make(map[string]bool)
+		for _, op := range patch.Operations {
+			path, err := domain.ParseJSONPointer(op.Path)
+			if err != nil || len(path) != 1 || path[0] == "" || modified[path[0]] {
+				return nil, false
+			}
+			key := path[0]
+
+			switch op.Op {
+			case "add", "replace":
+				value, err := op.DecodeValue()
+				if err != nil {
+					return nil, false
+				}
+				if op.Op == "replace" {
+					expr.condition("attribute_exists(" + expr.dataPath(key) + ")")
+				}
+				expr.set(expr.dataPath(key), value)
+				modified[key] = true
+			case "remove":
+				expr.condition("attribute_exists(" + expr.dataPath(key) + ")")
+				expr.remove(expr.dataPath(key))
+				modified[key] = true
+			case "test":
+				value, err := op.DecodeValue()
+				if err != nil {
+					return nil, false
+				}
+				switch value.(type) {
+				case string, float64, bool:
+					expr.condition(expr.dataPath(key) + " = " + expr.value(value))
+				default:
+					return nil, false
+				}
+			default:
+				return nil, false
+			}
+		}
+		return expr, true
+
+	default:
+		return nil, false
+	}
+}
+
+// ドキュメントの更新式：書き込みのたびにバージョンを1増やし、存在してゴミ箱にないドキュメントだけを更新する
+type updateExpression struct {
+	sets       []string
+	removes    []string
+	conditions []string
+	names      map[string]string
+	values     map[string]interface{}
+	fields     map[string]string
+}
+
+// ドキュメントの更新式を作成する
+// スキーマで検証していない場合は以前のスキーマのバージョンを削除する
+// 前提となるバージョンが指定されている場合は、現在のバージョンが一致する場合だけ更新する
+func newUpdateExpression(schemaVersion int, expectedVersion *int) *updateExpression {
+	expr := &updateExpression{
+		sets:       []string{"#updatedAt = :updatedAt"},
+		conditions: []string{"attribute_exists(ID)", "attribute_not_exists(#deletedAt)"},
+		names: map[string]string{
+			"#data":          "Data",
+			"#updatedAt":     "UpdatedAt",
+			"#schemaVersion": "SchemaVersion",
+			"#version":       "Version",
+			"#deletedAt":     "DeletedAt",
+		},
+		values: map[string]interface{}{
+			":updatedAt": time.Now(),
+			":one":       1,
+		},
+		fields: make(map[string]string),
+	}
+
+	if schemaVersion > 0 {
+		expr.sets = append(expr.sets, "#schemaVersion = :schemaVersion")
+		expr.values[":schemaVersion"] = schemaVersion
+	} else {
+		expr.removes = append(expr.removes, "#schemaVersion")
+	}
+
+	if expectedVersion != nil {
+		expr.conditions = append(expr.conditions, versionCondition(*expectedVersion))
+		expr.values[":expectedVersion"] = *expectedVersion
+	}
+	return expr
+}
+
+// パスに値を設定する
+func (e *updateExpression) set(path string, value interface{}) {
+	e.sets = append(e.sets, path+" = "+e.value(value))
+}
+
+// パスの値を削除する
+func (e *updateExpression) remove(path string) {
+	e.removes = append(e.removes, path)
+}
+
+// 条件を追加する
+func (e *updateExpression) condition(condition string) {
+	e.conditions = append(e.conditions, condition)
+}
+
+// 値のプレースホルダーを割り当てる
+func (e *updateExpression) value(value interface{}) string {
+	placeholder := ":v" + strconv.Itoa(len(e.values))
+	e.values[placeholder] = value
+	return placeholder
+}
+
+// dataの最上位のキーのパスを返す（キーは任意の文字列のため、属性名のプレースホルダーを使用する）
+func (e *updateExpression) dataPath(key string) string {
+	name, ok := e.fields[key]
+	if !ok {
+		name = "#f" + strconv.Itoa(len(e.fields))
+		e.fields[key] = name
+		e.names[name] = key
+	}
+	return "#data." + name
+}
+
+// UpdateExpressionの文字列
+func (e *updateExpression) update() string {
+	expr := "SET " + strings.Join(e.sets, ", ")
+	if len(e.removes) > 0 {
+		expr += " REMOVE " + strings.Join(e.removes, ", ")
+	}
+	return expr + " ADD #version :one"
+}
+
+// バージョンが指定された値であることを確認する条件式
+// バージョン導入前に作成されたドキュメントはバージョン属性を持たないため、バージョン0として扱う
+func versionCondition(expected int) string {
+	if expected == 0 {
+		return "(attribute_not_exists(#version) OR #version = :expectedVersion)"
+	}
+	return "#version = :expectedVersion"
+}
+
+// 更新式でドキュメントを更新し、更新後のドキュメントを返す
+// 条件を満たさなかった場合に理由を判断できるよう、更新前の項目を返させる（エラーはupdateErrorで変換する）
+func (d *DynamoDBStorage) updateItem(ctx context.Context, id string, expr *updateExpression) (*domain.Document, error) {
+	attrValues, err := attributevalue.MarshalMap(expr.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal update values: %w", err)
+	}
+
+	result, err := d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                           aws.String(d.tableName),
+		Key:                                 map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: id}},
+		UpdateExpression:                    aws.String(expr.update()),
+		ConditionExpression:                 aws.String(strings.Join(expr.conditions, " AND ")),
+		ExpressionAttributeNames:            expr.names,
+		ExpressionAttributeValues:           attrValues,
+		ReturnValues:                        types.ReturnValueAllNew,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 更新後のドキュメントをDynamoDB形式から変換
+	var document domain.Document
+	if err := attributevalue.UnmarshalMap(result.Attributes, &document); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal updated document: %w", err)
+	}
+	return &document, nil
+}
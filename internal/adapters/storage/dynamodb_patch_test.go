@@ -0,0 +1,241 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 更新式に共通する属性名のプレースホルダー
+var baseUpdateNames = map[string]string{
+	"#data":          "Data",
+	"#updatedAt":     "UpdatedAt",
+	"#schemaVersion": "SchemaVersion",
+	"#version":       "Version",
+	"#deletedAt":     "DeletedAt",
+}
+
+// 共通の属性名にdataのキーのプレースホルダーを加える
+func updateNames(fields map[string]string) map[string]string {
+	names := make(map[string]string, len(baseUpdateNames)+len(fields))
+	for placeholder, name := range baseUpdateNames {
+		names[placeholder] = name
+	}
+	for placeholder, key := range fields {
+		names[placeholder] = key
+	}
+	return names
+}
+
+// 更新式の文字列、条件、属性名、値を確認する（更新日時は毎回変わるため存在だけを確認する）
+func assertUpdateExpression(t *testing.T, expr *updateExpression, update string, conditions []string, names map[string]string, values map[string]interface{}) {
+	t.Helper()
+	assert.Equal(t, update, expr.update())
+	assert.Equal(t, conditions, expr.conditions)
+	assert.Equal(t, names, expr.names)
+
+	actual := make(map[string]interface{}, len(expr.values))
+	for placeholder, value := range expr.values {
+		actual[placeholder] = value
+	}
+	assert.Contains(t, actual, ":updatedAt")
+	delete(actual, ":updatedAt")
+	assert.Equal(t, values, actual)
+}
+
+// JSON Patchの操作を作成する
+func patchOp(op, path, value string) domain.PatchOperation {
+	operation := domain.PatchOperation{Op: op, Path: path}
+	if value != "" {
+		operation.Value = json.RawMessage(value)
+	}
+	return operation
+}
+
+func TestTargetedPatch_Merge(t *testing.T) {
+	t.Run("null removes the key", func(t *testing.T) {
+		expr, ok := targetedPatch(&domain.DocumentPatch{
+			Format: domain.PatchFormatMerge,
+			Merge:  map[string]interface{}{"draft": nil},
+		})
+
+		require.True(t, ok)
+		assertUpdateExpression(t, expr,
+			"SET #updatedAt = :updatedAt REMOVE #schemaVersion, #data.#f0 ADD #version :one",
+			[]string{"attribute_exists(ID)", "attribute_not_exists(#deletedAt)", "attribute_type(#data, :v2)"},
+			updateNames(map[string]string{"#f0": "draft"}),
+			map[string]interface{}{":one": 1, ":v2": "M"},
+		)
+	})
+
+	t.Run("values are set with the schema and expected version", func(t *testing.T) {
+		expectedVersion := 5
+		expr, ok := targetedPatch(&domain.DocumentPatch{
+			Format:          domain.PatchFormatMerge,
+			Merge:           map[string]interface{}{"title": "Fractions", "tags": []interface{}{"math"}},
+			ExpectedVersion: &expectedVersion,
+			SchemaVersion:   2,
+		})
+
+		require.True(t, ok)
+		// マージパッチのキーの順序は決まらないため、キーごとのプレースホルダーから期待値を組み立てる
+		title, tags := expr.dataPath("title"), expr.dataPath("tags")
+		titleValue, tagsValue := ":v5", ":v6"
+		if expr.fields["title"] != "#f0" {
+			titleValue, tagsValue = tagsValue, titleValue
+		}
+		assert.ElementsMatch(t, []string{
+			"#updatedAt = :updatedAt",
+			"#schemaVersion = :schemaVersion",
+			title + " = " + titleValue,
+			tags + " = " + tagsValue,
+		}, expr.sets)
+		assert.Empty(t, expr.removes)
+		assert.Equal(t, []string{
+			"attribute_exists(ID)", "attribute_not_exists(#deletedAt)", "#version = :expectedVersion", "attribute_type(#data, :v4)",
+		}, expr.conditions)
+		assert.Equal(t, updateNames(map[string]string{expr.fields["title"]: "title", expr.fields["tags"]: "tags"}), expr.names)
+		assert.Equal(t, "Fractions", expr.values[titleValue])
+		assert.Equal(t, []interface{}{"math"}, expr.values[tagsValue])
+		assert.Equal(t, 2, expr.values[":schemaVersion"])
+		assert.Equal(t, 5, expr.values[":expectedVersion"])
+	})
+
+	t.Run("expected version 0 accepts documents without a version", func(t *testing.T) {
+		expectedVersion := 0
+		expr, ok := targetedPatch(&domain.DocumentPatch{
+			Format:          domain.PatchFormatMerge,
+			Merge:           map[string]interface{}{"title": "Fractions"},
+			ExpectedVersion: &expectedVersion,
+		})
+
+		require.True(t, ok)
+		assert.Contains(t, expr.conditions, "(attribute_not_exists(#version) OR #version = :expectedVersion)")
+	})
+
+	t.Run("key limit", func(t *testing.T) {
+		merge := make(map[string]interface{}, maxTargetedPatchKeys+1)
+		for i := 0; i < maxTargetedPatchKeys; i++ {
+			merge[fmt.Sprintf("key%d", i)] = i
+		}
+		_, ok := targetedPatch(&domain.DocumentPatch{Format: domain.PatchFormatMerge, Merge: merge})
+		assert.True(t, ok, "a patch at the limit is converted")
+
+		merge["one-more"] = true
+		_, ok = targetedPatch(&domain.DocumentPatch{Format: domain.PatchFormatMerge, Merge: merge})
+		assert.False(t, ok, "a patch over the limit falls back to a rewrite")
+	})
+
+	t.Run("fallbacks", func(t *testing.T) {
+		for name, merge := range map[string]map[string]interface{}{
+			"nested object": {"settings": map[string]interface{}{"color": "red"}},
+			"empty key":     {"": "value"},
+		} {
+			_, ok := targetedPatch(&domain.DocumentPatch{Format: domain.PatchFormatMerge, Merge: merge})
+			assert.False(t, ok, name)
+		}
+	})
+}
+
+func TestTargetedPatch_JSON(t *testing.T) {
+	t.Run("replace and remove require the key to exist", func(t *testing.T) {
+		expr, ok := targetedPatch(&domain.DocumentPatch{
+			Format: domain.PatchFormatJSON,
+			Operations: []domain.PatchOperation{
+				patchOp("test", "/status", `"draft"`),
+				patchOp("replace", "/status", `"published"`),
+				patchOp("remove", "/notes", ""),
+				patchOp("add", "/score", `42`),
+			},
+			SchemaVersion: 1,
+		})
+
+		require.True(t, ok)
+		assertUpdateExpression(t, expr,
+			"SET #updatedAt = :updatedAt, #schemaVersion = :schemaVersion, #data.#f0 = :v5, #data.#f2 = :v6 REMOVE #data.#f1 ADD #version :one",
+			[]string{
+				"attribute_exists(ID)",
+				"attribute_not_exists(#deletedAt)",
+				"attribute_type(#data, :v3)",
+				"#data.#f0 = :v4",
+				"attribute_exists(#data.#f0)",
+				"attribute_exists(#data.#f1)",
+			},
+			updateNames(map[string]string{"#f0": "status", "#f1": "notes", "#f2": "score"}),
+			map[string]interface{}{
+				":one":           1,
+				":schemaVersion": 1,
+				":v3":            "M",
+				":v4":            "draft",
+				":v5":            "published",
+				":v6":            float64(42),
+			},
+		)
+	})
+
+	t.Run("keys are escaped with name placeholders", func(t *testing.T) {
+		expr, ok := targetedPatch(&domain.DocumentPatch{
+			Format:     domain.PatchFormatJSON,
+			Operations: []domain.PatchOperation{patchOp("add", "/a~1b c", `null`)},
+		})
+
+		require.True(t, ok)
+		assert.Equal(t, "a/b c", expr.names["#f0"])
+		assert.Contains(t, expr.sets, "#data.#f0 = :v3")
+		assert.Nil(t, expr.values[":v3"])
+	})
+
+	t.Run("operation limit", func(t *testing.T) {
+		operations := make([]domain.PatchOperation, 0, maxTargetedPatchKeys+1)
+		for i := 0; i < maxTargetedPatchKeys; i++ {
+			operations = append(operations, patchOp("add", fmt.Sprintf("/key%d", i), `1`))
+		}
+		_, ok := targetedPatch(&domain.DocumentPatch{Format: domain.PatchFormatJSON, Operations: operations})
+		assert.True(t, ok, "a patch at the limit is converted")
+
+		operations = append(operations, patchOp("add", "/one-more", `1`))
+		_, ok = targetedPatch(&domain.DocumentPatch{Format: domain.PatchFormatJSON, Operations: operations})
+		assert.False(t, ok, "a patch over the limit falls back to a rewrite")
+	})
+
+	tests := []struct {
+		name       string
+		operations []domain.PatchOperation
+	}{
+		{name: "same key modified twice", operations: []domain.PatchOperation{patchOp("add", "/title", `"a"`), patchOp("replace", "/title", `"b"`)}},
+		{name: "remove after add", operations: []domain.PatchOperation{patchOp("add", "/title", `"a"`), patchOp("remove", "/title", "")}},
+		{name: "test after modification", operations: []domain.PatchOperation{patchOp("replace", "/title", `"a"`), patchOp("test", "/title", `"a"`)}},
+		{name: "nested path", operations: []domain.PatchOperation{patchOp("replace", "/settings/color", `"red"`)}},
+		{name: "whole document", operations: []domain.PatchOperation{patchOp("replace", "", `{}`)}},
+		{name: "empty key", operations: []domain.PatchOperation{patchOp("add", "/", `1`)}},
+		{name: "invalid pointer", operations: []domain.PatchOperation{patchOp("add", "title", `1`)}},
+		{name: "move", operations: []domain.PatchOperation{{Op: "move", From: "/a", Path: "/b"}}},
+		{name: "copy", operations: []domain.PatchOperation{{Op: "copy", From: "/a", Path: "/b"}}},
+		{name: "test of an object", operations: []domain.PatchOperation{patchOp("test", "/settings", `{"color":"red"}`)}},
+		{name: "test of null", operations: []domain.PatchOperation{patchOp("test", "/settings", `null`)}},
+		{name: "missing value", operations: []domain.PatchOperation{patchOp("add", "/title", "")}},
+		{name: "malformed value", operations: []domain.PatchOperation{patchOp("add", "/title", `{`)}},
+	}
+
+	for _, tt := range tests {
+		t.Run("fallback: "+tt.name, func(t *testing.T) {
+			// Test
+			expr, ok := targetedPatch(&domain.DocumentPatch{Format: domain.PatchFormatJSON, Operations: tt.operations})
+
+			// Assertions
+			assert.False(t, ok)
+			assert.Nil(t, expr)
+		})
+	}
+}
+
+func TestTargetedPatch_UnknownFormat(t *testing.T) {
+	expr, ok := targetedPatch(&domain.DocumentPatch{Format: "xml"})
+
+	assert.False(t, ok)
+	assert.Nil(t, expr)
+}
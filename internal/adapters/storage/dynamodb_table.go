@@ -0,0 +1,225 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// テーブルとインデックスが利用可能になるまで待つ最大時間
+const tableReadyTimeout = 5 * time.Minute
+
+// ドキュメントのテーブルとインデックスを作成する
+// テーブルが既に存在する場合は、不足しているインデックスだけを追加する
+// 作成したテーブルとインデックスが利用可能になるまで待つ
+func (d *DynamoDBStorage) EnsureTable(ctx context.Context) error {
+	description, err := d.describeTable(ctx)
+	if err != nil {
+		return err
+	}
+
+	if description == nil {
+		_, err = d.client.CreateTable(ctx, &dynamodb.CreateTableInput{
+			TableName:            aws.String(d.tableName),
+			AttributeDefinitions: documentAttributeDefinitions(),
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: aws.String("ID"), KeyType: types.KeyTypeHash},
+			},
+			GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{typeCreatedAtIndex()},
+			BillingMode:            types.BillingModePayPerRequest,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create table %s: %w", d.tableName, err)
+		}
+	} else if !hasIndex(description, TypeCreatedAtIndex) {
+		index := typeCreatedAtIndex()
+		_, err = d.client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+			TableName:            aws.String(d.tableName),
+			AttributeDefinitions: documentAttributeDefinitions(),
+			GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{{
+				Create: &types.CreateGlobalSecondaryIndexAction{
+					IndexName:             index.IndexName,
+					KeySchema:             index.KeySchema,
+					Projection:            index.Projection,
+					ProvisionedThroughput: indexThroughput(description),
+				},
+			}},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create index %s: %w", TypeCreatedAtIndex, err)
+		}
+	}
+
+	return d.waitUntilReady(ctx)
+}
+
+// 並べ替えられない形式で保存された作成日時を、インデックスのソートキーの形式に書き換える
+// 以前のバージョンはRFC 3339形式（小数部の桁数が可変）で保存していたため、作成日時の順に並ばない
+// 既に書き換えたドキュメントは変更しないため、何度実行してもよく、書き換えたドキュメントの件数を返す
+func (d *DynamoDBStorage) BackfillCreatedAt(ctx context.Context) (int, error) {
+	input := &dynamodb.ScanInput{
+		TableName:                aws.String(d.tableName),
+		ProjectionExpression:     aws.String("ID, #createdAt"),
+		ExpressionAttributeNames: map[string]string{"#createdAt": "CreatedAt"},
+	}
+
+	rewritten := 0
+	paginator := dynamodb.NewScanPaginator(d.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return rewritten, fmt.Errorf("failed to scan documents: %w", err)
+		}
+
+		for _, item := range page.Items {
+			var key struct {
+				ID        string
+				CreatedAt string
+			}
+			if err := attributevalue.UnmarshalMap(item, &key); err != nil {
+				return rewritten, fmt.Errorf("failed to unmarshal document key: %w", err)
+			}
+			sortable, changed, err := sortableCreatedAt(key.CreatedAt)
+			if err != nil {
+				return rewritten, fmt.Errorf("document %s: %w", key.ID, err)
+			}
+			if !changed {
+				continue
+			}
+
+			// 読み込んだ後に削除されたドキュメントは条件式で除外する
+			_, err = d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+				TableName:                aws.String(d.tableName),
+				Key:                      map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: key.ID}},
+				UpdateExpression:         aws.String("SET #createdAt = :sortable"),
+				ConditionExpression:      aws.String("#createdAt = :original"),
+				ExpressionAttributeNames: map[string]string{"#createdAt": "CreatedAt"},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":sortable": &types.AttributeValueMemberS{Value: sortable},
+					":original": &types.AttributeValueMemberS{Value: key.CreatedAt},
+				},
+			})
+			if err != nil {
+				var conditionFailed *types.ConditionalCheckFailedException
+				if errors.As(err, &conditionFailed) {
+					continue
+				}
+				return rewritten, fmt.Errorf("failed to rewrite created time of document %s: %w", key.ID, err)
+			}
+			rewritten++
+		}
+	}
+	return rewritten, nil
+}
+
+// 保存されている作成日時をインデックスのソートキーの形式に変換する
+// 既にその形式の場合はfalseを返す
+func sortableCreatedAt(value string) (string, bool, error) {
+	createdAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid created time %q: %w", value, err)
+	}
+	sortable := createdAt.UTC().Format(sortableTimeLayout)
+	return sortable, sortable != value, nil
+}
+
+// テーブルの情報を取得する（テーブルが存在しない場合はnil）
+func (d *DynamoDBStorage) describeTable(ctx context.Context) (*types.TableDescription, error) {
+	result, err := d.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(d.tableName)})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to describe table %s: %w", d.tableName, err)
+	}
+	return result.Table, nil
+}
+
+// テーブルとすべてのインデックスがACTIVEになるまで待つ
+func (d *DynamoDBStorage) waitUntilReady(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, tableReadyTimeout)
+	defer cancel()
+
+	for {
+		description, err := d.describeTable(ctx)
+		if err != nil {
+			return err
+		}
+		if description != nil && isActive(description) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("table %s did not become active: %w", d.tableName, ctx.Err())
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// キーとインデックスに使用する属性の定義
+func documentAttributeDefinitions() []types.AttributeDefinition {
+	return []types.AttributeDefinition{
+		{AttributeName: aws.String("ID"), AttributeType: types.ScalarAttributeTypeS},
+		{AttributeName: aws.String("Type"), AttributeType: types.ScalarAttributeTypeS},
+		{AttributeName: aws.String("CreatedAt"), AttributeType: types.ScalarAttributeTypeS},
+	}
+}
+
+// 種類をパーティションキー、作成日時をソートキーとするインデックスの定義
+func typeCreatedAtIndex() types.GlobalSecondaryIndex {
+	return types.GlobalSecondaryIndex{
+		IndexName: aws.String(TypeCreatedAtIndex),
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("Type"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("CreatedAt"), KeyType: types.KeyTypeRange},
+		},
+		Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+	}
+}
+
+// 既存のテーブルにインデックスを追加する際のスループット
+// プロビジョニングモードのテーブルではテーブルと同じ値を使用し、オンデマンドの場合はnilを返す
+func indexThroughput(table *types.TableDescription) *types.ProvisionedThroughput {
+	if table.BillingModeSummary != nil && table.BillingModeSummary.BillingMode == types.BillingModePayPerRequest {
+		return nil
+	}
+	if table.ProvisionedThroughput == nil ||
+		aws.ToInt64(table.ProvisionedThroughput.ReadCapacityUnits) == 0 {
+		return nil
+	}
+	return &types.ProvisionedThroughput{
+		ReadCapacityUnits:  table.ProvisionedThroughput.ReadCapacityUnits,
+		WriteCapacityUnits: table.ProvisionedThroughput.WriteCapacityUnits,
+	}
+}
+
+// テーブルに指定された名前のインデックスがあるかどうか
+func hasIndex(table *types.TableDescription, name string) bool {
+	for _, index := range table.GlobalSecondaryIndexes {
+		if aws.ToString(index.IndexName) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// テーブルとすべてのインデックスがACTIVEかどうか
+func isActive(table *types.TableDescription) bool {
+	if table.TableStatus != types.TableStatusActive {
+		return false
+	}
+	for _, index := range table.GlobalSecondaryIndexes {
+		if index.IndexStatus != types.IndexStatusActive {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// インデックスのクエリが返す最後に評価したキーを作成する
+func lastEvaluatedKey(id, documentType string, createdAt time.Time) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"ID":        &types.AttributeValueMemberS{Value: id},
+		"Type":      &types.AttributeValueMemberS{Value: documentType},
+		"CreatedAt": sortableTime(createdAt),
+	}
+}
+
+// JSONをそのままカーソル文字列に変換する
+func rawCursor(json string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(json))
+}
+
+func TestDocumentCursor_RoundTrip(t *testing.T) {
+	// Setup
+	createdAt := time.Date(2024, 4, 1, 9, 30, 15, 123456789, time.UTC)
+	key := lastEvaluatedKey("doc-1", "lesson", createdAt)
+	from, to := createdAt.Add(-time.Hour), createdAt.Add(time.Hour)
+
+	// Test
+	cursor, err := encodeDocumentCursor(key)
+	require.NoError(t, err)
+	decoded, err := decodeDocumentCursor(&domain.DocumentQuery{Type: "lesson", From: &from, To: &to, Cursor: cursor})
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, key, decoded)
+	assert.NotContains(t, cursor, "=", "cursor must be safe in a query string")
+}
+
+func TestDecodeDocumentCursor_Invalid(t *testing.T) {
+	createdAt := time.Date(2024, 4, 1, 9, 30, 15, 0, time.UTC)
+	cursor, err := encodeDocumentCursor(lastEvaluatedKey("doc-1", "lesson", createdAt))
+	require.NoError(t, err)
+	before, after := createdAt.Add(-time.Second), createdAt.Add(time.Second)
+
+	tests := []struct {
+		name  string
+		query domain.DocumentQuery
+	}{
+		{name: "type mismatch", query: domain.DocumentQuery{Type: "quiz", Cursor: cursor}},
+		{name: "created before the range", query: domain.DocumentQuery{Type: "lesson", From: &after, Cursor: cursor}},
+		{name: "created after the range", query: domain.DocumentQuery{Type: "lesson", To: &before, Cursor: cursor}},
+		{name: "malformed base64", query: domain.DocumentQuery{Type: "lesson", Cursor: "not base64!"}},
+		{name: "padded base64", query: domain.DocumentQuery{Type: "lesson", Cursor: base64.URLEncoding.EncodeToString([]byte(`{"ID":"doc-1"}`))}},
+		{name: "malformed JSON", query: domain.DocumentQuery{Type: "lesson", Cursor: rawCursor(`{"ID":`)}},
+		{name: "JSON that is not an object of strings", query: domain.DocumentQuery{Type: "lesson", Cursor: rawCursor(`{"ID":1,"Type":"lesson","CreatedAt":"x"}`)}},
+		{name: "missing ID", query: domain.DocumentQuery{Type: "lesson", Cursor: rawCursor(`{"ID":"","Type":"lesson","CreatedAt":"2024-04-01T09:30:15.000000000Z"}`)}},
+		{name: "missing CreatedAt", query: domain.DocumentQuery{Type: "lesson", Cursor: rawCursor(`{"ID":"doc-1","Type":"lesson","Other":"x"}`)}},
+		{name: "extra attributes", query: domain.DocumentQuery{Type: "lesson", Cursor: rawCursor(`{"ID":"doc-1","Type":"lesson","CreatedAt":"2024-04-01T09:30:15.000000000Z","Data":"x"}`)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			key, err := decodeDocumentCursor(&tt.query)
+
+			// Assertions
+			assert.ErrorIs(t, err, domain.ErrInvalidInput)
+			assert.Nil(t, key)
+		})
+	}
+}
+
+func TestDecodeDocumentCursor_RangeBoundaries(t *testing.T) {
+	// 範囲の境界と同じ作成日時のカーソルは有効とする
+	createdAt := time.Date(2024, 4, 1, 9, 30, 15, 0, time.UTC)
+	cursor, err := encodeDocumentCursor(lastEvaluatedKey("doc-1", "lesson", createdAt))
+	require.NoError(t, err)
+
+	key, err := decodeDocumentCursor(&domain.DocumentQuery{Type: "lesson", From: &createdAt, To: &createdAt, Cursor: cursor})
+
+	require.NoError(t, err)
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "doc-1"}, key["ID"])
+}
+
+func TestQueryInput_DataFilter(t *testing.T) {
+	// Setup
+	storage := &DynamoDBStorage{tableName: "documents"}
+
+	// Test
+	input, err := storage.queryInput(&domain.DocumentQuery{
+		Type:       "question_bank",
+		DataEquals: map[string]interface{}{"teacher_id": int64(7), "subject": "math"},
+	})
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, "attribute_not_exists(#deletedAt) AND #data.#d0 = :d0 AND #data.#d1 = :d1", *input.FilterExpression)
+	assert.Equal(t, "subject", input.ExpressionAttributeNames["#d0"])
+	assert.Equal(t, "teacher_id", input.ExpressionAttributeNames["#d1"])
+	assert.Equal(t, "Data", input.ExpressionAttributeNames["#data"])
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "math"}, input.ExpressionAttributeValues[":d0"])
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "7"}, input.ExpressionAttributeValues[":d1"])
+}
+
+func TestSortableCreatedAt(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		want        string
+		wantChanged bool
+	}{
+		{name: "old format without fraction", value: "2024-04-01T09:30:15Z", want: "2024-04-01T09:30:15.000000000Z", wantChanged: true},
+		{name: "old format with trimmed fraction", value: "2024-04-01T09:30:15.5Z", want: "2024-04-01T09:30:15.500000000Z", wantChanged: true},
+		{name: "old format with offset", value: "2024-04-01T18:30:15.123+09:00", want: "2024-04-01T09:30:15.123000000Z", wantChanged: true},
+		{name: "already sortable", value: "2024-04-01T09:30:15.000000000Z", want: "2024-04-01T09:30:15.000000000Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			sortable, changed, err := sortableCreatedAt(tt.value)
+
+			// Assertions
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, sortable)
+			assert.Equal(t, tt.wantChanged, changed)
+		})
+	}
+
+	t.Run("malformed value", func(t *testing.T) {
+		_, _, err := sortableCreatedAt("yesterday")
+
+		assert.Error(t, err)
+	})
+}
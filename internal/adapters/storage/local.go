@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// メタデータファイルの拡張子
+const localMetadataSuffix = ".meta.json"
+
+// 書き込み中の一時ファイルの接頭辞（一覧からは除外する）
+const localTempPrefix = ".tmp-"
+
+// ローカルストレージのIDとして使用できる文字列（パスの走査を防ぐため英数字とハイフンのみ）
+var localFileIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
+// ローカルファイルストレージ構造体：ローカルのディレクトリツリーを使用したファイル操作を実装
+// ファイルはIDの先頭の文字でシャーディングしたディレクトリに保存し（例：ab/cd/abcd1234-...）、
+// 元のファイル名などのメタデータは同じディレクトリのサイドカーファイル（.meta.json）に保存する
+type LocalFileStorage struct {
+	// ファイルを保存するルートディレクトリ
+	root string
+}
+
+// ローカルファイルのメタデータ構造体：サイドカーファイルの内容を表現
+type localFileMetadata struct {
+	// ファイルの名前
+	Name string `json:"name"`
+	// ファイルのMIMEタイプ
+	ContentType string `json:"content_type"`
+	// ファイルのサイズ（バイト）
+	Size int64 `json:"size"`
+	// アップロード日時
+	UploadedAt time.Time `json:"uploaded_at"`
+	// アップロードしたユーザーのID
+	UploaderID int64 `json:"uploader_id,omitempty"`
+	// アップロードしたユーザーの役割
+	UploaderRole string `json:"uploader_role,omitempty"`
+}
+
+// 新しいローカルファイルストレージインスタンスを作成する関数
+// ルートディレクトリが存在しない場合は作成する
+func NewLocalFileStorage(root string) (*LocalFileStorage, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &LocalFileStorage{root: root}, nil
+}
+
+// ファイルをローカルディレクトリに保存する
+// 一時ファイルに書き込んでから名前を変更するため、書き込み途中のファイルが読み込まれることはない
+func (s *LocalFileStorage) Upload(ctx context.Context, file *domain.FileUpload) (*domain.File, error) {
+	id := uuid.New().String()
+	path := s.path(id)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	size, err := writeFileAtomic(path, file.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	metadata := localFileMetadata{
+		Name:         file.Name,
+		ContentType:  file.ContentType,
+		Size:         size,
+		UploadedAt:   time.Now().UTC(),
+		UploaderID:   file.UploaderID,
+		UploaderRole: file.UploaderRole,
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode file metadata: %w", err)
+	}
+	if _, err := writeFileAtomic(path+localMetadataSuffix, bytes.NewReader(encoded)); err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	return metadata.file(id), nil
+}
+
+// ローカルディレクトリからファイルを読み込むストリームを返す
+func (s *LocalFileStorage) Download(ctx context.Context, id string) (*domain.File, io.ReadCloser, error) {
+	file, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(s.path(id))
+	if err != nil {
+		return nil, nil, localFileError(id, err)
+	}
+	return file, f, nil
+}
+
+// ローカルディレクトリからファイルの指定された範囲を読み込むストリームを返す
+func (s *LocalFileStorage) DownloadRange(ctx context.Context, id string, byteRange domain.ByteRange) (*domain.File, io.ReadCloser, error) {
+	file, body, err := s.Download(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f := body.(*os.File)
+	if _, err := f.Seek(byteRange.Offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("failed to download file range: %w", err)
+	}
+	return file, &limitedReadCloser{Reader: io.LimitReader(f, byteRange.Length), Closer: f}, nil
+}
+
+// ファイルとメタデータファイルを削除する
+func (s *LocalFileStorage) Delete(ctx context.Context, id string) error {
+	if !localFileIDPattern.MatchString(id) {
+		return fmt.Errorf("%w: no file found with id: %s", domain.ErrNotFound, id)
+	}
+
+	path := s.path(id)
+	if err := os.Remove(path); err != nil {
+		return localFileError(id, err)
+	}
+	if err := os.Remove(path + localMetadataSuffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete file metadata: %w", err)
+	}
+	return nil
+}
+
+// ローカルディレクトリに保存されているファイルの一覧を取得する
+func (s *LocalFileStorage) List(ctx context.Context) ([]domain.File, error) {
+	files := []domain.File{}
+	err := filepath.WalkDir(s.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, localTempPrefix) || strings.HasSuffix(name, localMetadataSuffix) || !localFileIDPattern.MatchString(name) {
+			return nil
+		}
+
+		file, err := s.Get(ctx, name)
+		if err != nil {
+			return err
+		}
+		files = append(files, *file)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	return files, nil
+}
+
+// 指定されたIDのファイル情報をメタデータファイルから取得する
+// メタデータファイルがない場合はファイル自体の情報のみを返す
+func (s *LocalFileStorage) Get(ctx context.Context, id string) (*domain.File, error) {
+	if !localFileIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("%w: no file found with id: %s", domain.ErrNotFound, id)
+	}
+
+	path := s.path(id)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, localFileError(id, err)
+	}
+
+	metadata := localFileMetadata{Size: info.Size(), UploadedAt: info.ModTime().UTC()}
+	encoded, err := os.ReadFile(path + localMetadataSuffix)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(encoded, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to decode file metadata: %w", err)
+		}
+		metadata.Size = info.Size()
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("failed to read file metadata: %w", err)
+	}
+	return metadata.file(id), nil
+}
+
+// IDからファイルのパスを作成する（先頭2文字ずつの2階層でシャーディングする）
+func (s *LocalFileStorage) path(id string) string {
+	if len(id) < 4 {
+		return filepath.Join(s.root, id)
+	}
+	return filepath.Join(s.root, id[:2], id[2:4], id)
+}
+
+// メタデータをファイル情報に変換する
+func (m localFileMetadata) file(id string) *domain.File {
+	return &domain.File{
+		ID:           id,
+		Name:         m.Name,
+		Size:         m.Size,
+		ContentType:  m.ContentType,
+		UploadedAt:   m.UploadedAt,
+		UploaderID:   m.UploaderID,
+		UploaderRole: m.UploaderRole,
+	}
+}
+
+// 同じディレクトリの一時ファイルに内容を書き込み、書き込みが完了してから目的のパスに名前を変更する
+func writeFileAtomic(path string, body io.Reader) (int64, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), localTempPrefix+"*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, body)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// ファイル操作のエラーを変換する（ファイルが存在しない場合はErrNotFound）
+func localFileError(id string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: no file found with id: %s", domain.ErrNotFound, id)
+	}
+	return fmt.Errorf("file %s: %w", id, err)
+}
@@ -0,0 +1,270 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 指定されたバイト数を読み込んだ後にエラーを返すリーダー
+type failingReader struct {
+	data []byte
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// ディレクトリ以下のファイルのパスをルートからの相対パスで返す
+func storedPaths(t *testing.T, root string) []string {
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		paths = append(paths, filepath.ToSlash(rel))
+		return err
+	})
+	require.NoError(t, err)
+	return paths
+}
+
+func TestLocalFileStorage_UploadRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	// Setup
+	root := t.TempDir()
+	storage, err := NewLocalFileStorage(root)
+	require.NoError(t, err)
+
+	// Test
+	uploaded, err := storage.Upload(ctx, &domain.FileUpload{
+		Name:         "lesson plan.pdf",
+		Body:         strings.NewReader("lesson plan"),
+		ContentType:  "application/pdf",
+		UploaderID:   7,
+		UploaderRole: domain.RoleTeacher,
+	})
+	require.NoError(t, err)
+
+	// Assertions
+	id := uploaded.ID
+	assert.ElementsMatch(t, []string{
+		id[:2] + "/" + id[2:4] + "/" + id,
+		id[:2] + "/" + id[2:4] + "/" + id + localMetadataSuffix,
+	}, storedPaths(t, root), "content and sidecar are sharded by the ID prefix")
+
+	// 別のインスタンスからでもサイドカーのメタデータを読み込める
+	reopened, err := NewLocalFileStorage(root)
+	require.NoError(t, err)
+	file, body, err := reopened.Download(ctx, id)
+	require.NoError(t, err)
+	defer body.Close()
+	content, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "lesson plan", string(content))
+	assert.Equal(t, uploaded, file)
+	assert.Equal(t, "lesson plan.pdf", file.Name)
+	assert.Equal(t, "application/pdf", file.ContentType)
+	assert.Equal(t, int64(11), file.Size)
+	assert.Equal(t, int64(7), file.UploaderID)
+	assert.Equal(t, domain.RoleTeacher, file.UploaderRole)
+
+	_, ranged, err := reopened.DownloadRange(ctx, id, domain.ByteRange{Offset: 7, Length: 4})
+	require.NoError(t, err)
+	defer ranged.Close()
+	content, err = io.ReadAll(ranged)
+	require.NoError(t, err)
+	assert.Equal(t, "plan", string(content))
+
+	files, err := reopened.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.File{*uploaded}, files)
+
+	require.NoError(t, reopened.Delete(ctx, id))
+	assert.Empty(t, storedPaths(t, root))
+	_, err = reopened.Get(ctx, id)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestLocalFileStorage_Metadata(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("missing sidecar falls back to the file info", func(t *testing.T) {
+		root := t.TempDir()
+		storage, err := NewLocalFileStorage(root)
+		require.NoError(t, err)
+		uploaded, err := storage.Upload(ctx, &domain.FileUpload{Name: "notes.txt", Body: strings.NewReader("notes")})
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(storage.path(uploaded.ID)+localMetadataSuffix))
+
+		file, err := storage.Get(ctx, uploaded.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, uploaded.ID, file.ID)
+		assert.Empty(t, file.Name)
+		assert.Equal(t, int64(5), file.Size)
+		assert.False(t, file.UploadedAt.IsZero())
+	})
+
+	t.Run("size comes from the content, not the sidecar", func(t *testing.T) {
+		storage, err := NewLocalFileStorage(t.TempDir())
+		require.NoError(t, err)
+		uploaded, err := storage.Upload(ctx, &domain.FileUpload{Name: "notes.txt", Body: strings.NewReader("notes")})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(storage.path(uploaded.ID)+localMetadataSuffix, []byte(`{"name":"notes.txt","size":999}`), 0o644))
+
+		file, err := storage.Get(ctx, uploaded.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "notes.txt", file.Name)
+		assert.Equal(t, int64(5), file.Size)
+	})
+
+	t.Run("corrupt sidecar", func(t *testing.T) {
+		storage, err := NewLocalFileStorage(t.TempDir())
+		require.NoError(t, err)
+		uploaded, err := storage.Upload(ctx, &domain.FileUpload{Name: "notes.txt", Body: strings.NewReader("notes")})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(storage.path(uploaded.ID)+localMetadataSuffix, []byte("{"), 0o644))
+
+		_, err = storage.Get(ctx, uploaded.ID)
+
+		assert.ErrorContains(t, err, "failed to decode file metadata")
+	})
+}
+
+func TestLocalFileStorage_AtomicWrite(t *testing.T) {
+	ctx := context.Background()
+	readErr := errors.New("connection reset")
+
+	t.Run("failed upload leaves nothing behind", func(t *testing.T) {
+		// Setup
+		root := t.TempDir()
+		storage, err := NewLocalFileStorage(root)
+		require.NoError(t, err)
+
+		// Test
+		_, err = storage.Upload(ctx, &domain.FileUpload{
+			Name: "partial.bin",
+			Body: &failingReader{data: []byte("partial content"), err: readErr},
+		})
+
+		// Assertions
+		assert.ErrorIs(t, err, readErr)
+		assert.Empty(t, storedPaths(t, root))
+		files, err := storage.List(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("failed write keeps the previous content", func(t *testing.T) {
+		// Setup
+		dir := t.TempDir()
+		path := filepath.Join(dir, "target")
+		_, err := writeFileAtomic(path, strings.NewReader("original"))
+		require.NoError(t, err)
+
+		// Test
+		_, err = writeFileAtomic(path, &failingReader{data: []byte("replacement"), err: readErr})
+
+		// Assertions
+		assert.ErrorIs(t, err, readErr)
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "original", string(content))
+		assert.Equal(t, []string{"target"}, storedPaths(t, dir), "temporary file is removed")
+	})
+
+	t.Run("successful write replaces the content", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "target")
+		_, err := writeFileAtomic(path, strings.NewReader("original"))
+		require.NoError(t, err)
+
+		size, err := writeFileAtomic(path, strings.NewReader("replaced"))
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(8), size)
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "replaced", string(content))
+		assert.Equal(t, []string{"target"}, storedPaths(t, dir))
+	})
+
+	t.Run("temporary files are not listed", func(t *testing.T) {
+		root := t.TempDir()
+		storage, err := NewLocalFileStorage(root)
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "ab", "cd"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "ab", "cd", localTempPrefix+"abcd1234"), []byte("partial"), 0o644))
+
+		files, err := storage.List(ctx)
+
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+}
+
+func TestLocalFileStorage_RejectsPathTraversal(t *testing.T) {
+	ctx := context.Background()
+
+	// Setup
+	parent := t.TempDir()
+	root := filepath.Join(parent, "storage")
+	storage, err := NewLocalFileStorage(root)
+	require.NoError(t, err)
+	// ルートの外にあるファイル（IDに含まれるパスで到達できてはならない）
+	outside := filepath.Join(parent, "secret")
+	require.NoError(t, os.WriteFile(outside, []byte("secret"), 0o644))
+	require.NoError(t, os.WriteFile(outside+localMetadataSuffix, []byte(`{"name":"secret"}`), 0o644))
+
+	ids := []string{
+		"",
+		"../secret",
+		"..",
+		"./secret",
+		"ab/../../secret",
+		"/etc/passwd",
+		`..\secret`,
+		".meta",
+		"-secret",
+		"secret\x00",
+		"abcd%2F..%2Fsecret",
+	}
+
+	for _, id := range ids {
+		t.Run(id, func(t *testing.T) {
+			// Test
+			_, getErr := storage.Get(ctx, id)
+			_, _, downloadErr := storage.Download(ctx, id)
+			_, _, rangeErr := storage.DownloadRange(ctx, id, domain.ByteRange{Offset: 0, Length: 1})
+			deleteErr := storage.Delete(ctx, id)
+
+			// Assertions
+			assert.ErrorIs(t, getErr, domain.ErrNotFound)
+			assert.ErrorIs(t, downloadErr, domain.ErrNotFound)
+			assert.ErrorIs(t, rangeErr, domain.ErrNotFound)
+			assert.ErrorIs(t, deleteErr, domain.ErrNotFound)
+		})
+	}
+
+	assert.FileExists(t, outside)
+	assert.FileExists(t, outside+localMetadataSuffix)
+}
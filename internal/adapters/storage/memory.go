@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// メモリファイルストレージ構造体：メモリ上にファイルを保持する開発・テスト用のファイルストレージ
+// プロセスを終了すると保存したファイルは失われる
+type MemoryFileStorage struct {
+	// ファイルへのアクセスを保護するロック
+	mu sync.RWMutex
+	// IDごとのファイル
+	objects map[string]memoryObject
+}
+
+// メモリ上のファイル：ファイル情報と内容を保持する
+type memoryObject struct {
+	// ファイル情報
+	file domain.File
+	// ファイルの内容
+	data []byte
+}
+
+// 新しいメモリファイルストレージインスタンスを作成する関数
+func NewMemoryFileStorage() *MemoryFileStorage {
+	return &MemoryFileStorage{
+		objects: make(map[string]memoryObject),
+	}
+}
+
+// ファイルの内容を読み込み、メモリ上に保存する
+func (s *MemoryFileStorage) Upload(ctx context.Context, file *domain.FileUpload) (*domain.File, error) {
+	data, err := io.ReadAll(file.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	stored := domain.File{
+		ID:           uuid.New().String(),
+		Name:         file.Name,
+		Size:         int64(len(data)),
+		ContentType:  file.ContentType,
+		UploadedAt:   time.Now().UTC(),
+		UploaderID:   file.UploaderID,
+		UploaderRole: file.UploaderRole,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[stored.ID] = memoryObject{file: stored, data: data}
+	return &stored, nil
+}
+
+// メモリ上のファイルを読み込むストリームを返す
+func (s *MemoryFileStorage) Download(ctx context.Context, id string) (*domain.File, io.ReadCloser, error) {
+	object, err := s.get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &object.file, io.NopCloser(bytes.NewReader(object.data)), nil
+}
+
+// メモリ上のファイルの指定された範囲を読み込むストリームを返す
+func (s *MemoryFileStorage) DownloadRange(ctx context.Context, id string, byteRange domain.ByteRange) (*domain.File, io.ReadCloser, error) {
+	object, err := s.get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	size := int64(len(object.data))
+	start := min(byteRange.Offset, size)
+	end := min(byteRange.Offset+byteRange.Length, size)
+	return &object.file, io.NopCloser(bytes.NewReader(object.data[start:end])), nil
+}
+
+// メモリ上のファイルを削除する
+func (s *MemoryFileStorage) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.objects[id]; !ok {
+		return fmt.Errorf("%w: no file found with id: %s", domain.ErrNotFound, id)
+	}
+	delete(s.objects, id)
+	return nil
+}
+
+// メモリ上のファイルの一覧をアップロード日時の新しい順に取得する
+func (s *MemoryFileStorage) List(ctx context.Context) ([]domain.File, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	files := make([]domain.File, 0, len(s.objects))
+	for _, object := range s.objects {
+		files = append(files, object.file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].UploadedAt.After(files[j].UploadedAt)
+	})
+	return files, nil
+}
+
+// 指定されたIDのファイル情報を取得する
+func (s *MemoryFileStorage) Get(ctx context.Context, id string) (*domain.File, error) {
+	object, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	return &object.file, nil
+}
+
+// 指定されたIDのファイルを取得する
+func (s *MemoryFileStorage) get(id string) (memoryObject, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	object, ok := s.objects[id]
+	if !ok {
+		return memoryObject{}, fmt.Errorf("%w: no file found with id: %s", domain.ErrNotFound, id)
+	}
+	return object, nil
+}
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ストリームの内容をすべて読み込んで閉じる
+func readAllAndClose(t *testing.T, body io.ReadCloser) string {
+	defer body.Close()
+	content, err := io.ReadAll(body)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestMemoryFileStorage_UploadRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	// Setup
+	storage := NewMemoryFileStorage()
+
+	// Test
+	uploaded, err := storage.Upload(ctx, &domain.FileUpload{
+		Name:         "worksheet.txt",
+		Body:         strings.NewReader("worksheet"),
+		ContentType:  "text/plain",
+		UploaderID:   12,
+		UploaderRole: domain.RoleStudent,
+	})
+	require.NoError(t, err)
+
+	// Assertions
+	assert.NotEmpty(t, uploaded.ID)
+	assert.Equal(t, int64(9), uploaded.Size)
+	assert.Equal(t, int64(12), uploaded.UploaderID)
+	assert.Equal(t, domain.RoleStudent, uploaded.UploaderRole)
+
+	file, body, err := storage.Download(ctx, uploaded.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uploaded, file)
+	assert.Equal(t, "worksheet", readAllAndClose(t, body))
+
+	file, err = storage.Get(ctx, uploaded.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uploaded, file)
+
+	require.NoError(t, storage.Delete(ctx, uploaded.ID))
+	_, _, err = storage.Download(ctx, uploaded.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, storage.Delete(ctx, uploaded.ID), domain.ErrNotFound)
+}
+
+func TestMemoryFileStorage_DownloadRange(t *testing.T) {
+	ctx := context.Background()
+	storage := NewMemoryFileStorage()
+	uploaded, err := storage.Upload(ctx, &domain.FileUpload{Name: "digits.txt", Body: strings.NewReader("0123456789")})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		byteRange domain.ByteRange
+		want      string
+	}{
+		{name: "middle", byteRange: domain.ByteRange{Offset: 2, Length: 3}, want: "234"},
+		{name: "past the end is truncated", byteRange: domain.ByteRange{Offset: 8, Length: 10}, want: "89"},
+		{name: "start past the end", byteRange: domain.ByteRange{Offset: 20, Length: 5}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Test
+			_, body, err := storage.DownloadRange(ctx, uploaded.ID, tt.byteRange)
+
+			// Assertions
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, readAllAndClose(t, body))
+		})
+	}
+}
+
+func TestMemoryFileStorage_FailedUpload(t *testing.T) {
+	ctx := context.Background()
+	readErr := errors.New("connection reset")
+	storage := NewMemoryFileStorage()
+
+	_, err := storage.Upload(ctx, &domain.FileUpload{
+		Name: "partial.bin",
+		Body: &failingReader{data: []byte("partial"), err: readErr},
+	})
+
+	assert.ErrorIs(t, err, readErr)
+	files, err := storage.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestMemoryFileStorage_ListNewestFirst(t *testing.T) {
+	ctx := context.Background()
+
+	// Setup
+	storage := NewMemoryFileStorage()
+	now := time.Now().UTC()
+	storage.objects["old"] = memoryObject{file: domain.File{ID: "old", UploadedAt: now.Add(-2 * time.Hour)}}
+	storage.objects["new"] = memoryObject{file: domain.File{ID: "new", UploadedAt: now}}
+	storage.objects["middle"] = memoryObject{file: domain.File{ID: "middle", UploadedAt: now.Add(-time.Hour)}}
+
+	// Test
+	files, err := storage.List(ctx)
+
+	// Assertions
+	require.NoError(t, err)
+	ids := make([]string, len(files))
+	for i, file := range files {
+		ids[i] = file.ID
+	}
+	assert.Equal(t, []string{"new", "middle", "old"}, ids)
+}
+
+func TestMemoryFileStorage_UnknownIDs(t *testing.T) {
+	ctx := context.Background()
+	storage := NewMemoryFileStorage()
+
+	for _, id := range []string{"", "../secret", "missing"} {
+		_, err := storage.Get(ctx, id)
+		assert.ErrorIs(t, err, domain.ErrNotFound, id)
+		_, _, err = storage.DownloadRange(ctx, id, domain.ByteRange{Offset: 0, Length: 1})
+		assert.ErrorIs(t, err, domain.ErrNotFound, id)
+	}
+}
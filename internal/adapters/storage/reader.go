@@ -0,0 +1,23 @@
+package storage
+
+import "io"
+
+// 読み込んだバイト数を数えるリーダー
+type countingReader struct {
+	// 元のリーダー
+	reader io.Reader
+	// 読み込んだバイト数
+	count int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+// 読み込む範囲を制限し、元のストリームを閉じるリーダー
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
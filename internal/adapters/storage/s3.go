@@ -1,46 +1,100 @@
 package storage
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/OICjangirrahul/students/internal/core/domain"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/google/uuid"
 )
 
+// マルチパートアップロードの1パートのサイズ
+// これより大きいファイルはパートに分割して送信され、メモリ上には最大でパートサイズ×並列数のみ保持される
+const s3UploadPartSize = 8 << 20
+
+// マルチパートアップロードで並列に送信するパート数
+const s3UploadConcurrency = 3
+
+// 署名付きURLで1回のPUTによりアップロードできる最大サイズ（S3の制限）
+const s3MaxPresignedUploadSize = 5 << 30
+
+// アップロードしたユーザーと元のファイル名を記録するオブジェクトのメタデータのキー
+const (
+	s3MetaUploaderID   = "uploader-id"
+	s3MetaUploaderRole = "uploader-role"
+	s3MetaOriginalName = "original-name"
+)
+
 // S3ストレージ構造体：S3を使用したファイル操作を実装
 type S3Storage struct {
 	// S3クライアント
 	client *s3.Client
+	// マルチパートアップロードを行うアップローダー
+	uploader *manager.Uploader
+	// 署名付きURLを作成するクライアント
+	presigner *s3.PresignClient
 	// S3バケット名
 	bucketName string
+	// ファイルのURLを作成する際の公開ベースURL
+	publicBaseURL string
 }
 
 // 新しいS3ストレージインスタンスを作成する関数
-func NewS3Storage(client *s3.Client, bucketName string) *S3Storage {
+// 公開ベースURLが空の場合、エンドポイントが指定されていればパス形式（<endpoint>/<bucket>）、
+// 指定されていなければAWSの仮想ホスト形式（https://<bucket>.s3.amazonaws.com）でファイルのURLを作成する
+func NewS3Storage(client *s3.Client, bucketName string, endpoint string, publicBaseURL string) *S3Storage {
+	if publicBaseURL == "" {
+		publicBaseURL = defaultPublicBaseURL(bucketName, endpoint)
+	}
+
 	return &S3Storage{
-		client:     client,
-		bucketName: bucketName,
+		client: client,
+		uploader: manager.NewUploader(client, func(u *manager.Uploader) {
+			u.PartSize = s3UploadPartSize
+			u.Concurrency = s3UploadConcurrency
+		}),
+		presigner:     s3.NewPresignClient(client),
+		bucketName:    bucketName,
+		publicBaseURL: strings.TrimRight(publicBaseURL, "/"),
 	}
 }
 
+// 公開ベースURLが設定されていない場合のファイルのURLの接頭辞
+// S3互換ストレージはバケットのサブドメインを解決できないことが多いため、エンドポイントではパス形式を使用する
+func defaultPublicBaseURL(bucketName string, endpoint string) string {
+	if endpoint != "" {
+		return strings.TrimRight(endpoint, "/") + "/" + bucketName
+	}
+	return fmt.Sprintf("https://%s.s3.amazonaws.com", bucketName)
+}
+
 // ファイルをS3にアップロードする
-// 一意のIDを生成し、ファイルを保存して、保存されたファイルの情報を返す
+// 一意のオブジェクトキーを生成し、内容を読み込みながら保存して、キーをIDとしたファイルの情報を返す
+// パートサイズを超えるファイルはマルチパートアップロードで送信する
 func (s *S3Storage) Upload(ctx context.Context, file *domain.FileUpload) (*domain.File, error) {
-	// 一意のIDを生成
-	id := uuid.New().String()
-	key := fmt.Sprintf("%s/%s", file.ContentType, id)
+	// 一意のオブジェクトキーを生成
+	key := newObjectKey()
 
 	// S3にファイルをアップロード
-	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+	body := &countingReader{reader: file.Body}
+	_, err := s.uploader.Upload(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(s.bucketName),
 		Key:         aws.String(key),
-		Body:        bytes.NewReader(file.Data),
+		Body:        body,
 		ContentType: aws.String(file.ContentType),
+		Metadata:    objectMetadata(file.Name, file.UploaderID, file.UploaderRole),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload file: %w", err)
@@ -48,58 +102,65 @@ func (s *S3Storage) Upload(ctx context.Context, file *domain.FileUpload) (*domai
 
 	// アップロードされたファイルの情報を返す
 	return &domain.File{
-		ID:          id,
+		ID:          key,
 		Name:        file.Name,
-		Size:        int64(len(file.Data)),
+		Size:        body.count,
 		ContentType: file.ContentType,
-		URL:         fmt.Sprintf("https://%s.s3.amazonaws.com/%s", s.bucketName, key),
+		URL:         s.objectURL(key),
 		BucketName:  s.bucketName,
 		UploadedAt:  time.Now(),
 	}, nil
 }
 
 // S3からファイルをダウンロードする
-// ファイルIDを受け取り、ファイル情報とデータを返す
-func (s *S3Storage) Download(ctx context.Context, id string) (*domain.File, []byte, error) {
-	// ファイル情報を取得
-	file, err := s.Get(ctx, id)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// S3からファイルをダウンロード
+// オブジェクトキーを受け取り、ファイル情報とオブジェクトの内容を読み込むストリームを返す
+func (s *S3Storage) Download(ctx context.Context, id string) (*domain.File, io.ReadCloser, error) {
 	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(s.bucketName),
-		Key:    aws.String(fmt.Sprintf("%s/%s", file.ContentType, id)),
+		Key:    aws.String(id),
 	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to download file: %w", err)
 	}
-	defer result.Body.Close()
 
-	// ファイルデータを読み込む
-	buf := new(bytes.Buffer)
-	_, err = buf.ReadFrom(result.Body)
+	return &domain.File{
+		ID:          id,
+		Size:        aws.ToInt64(result.ContentLength),
+		ContentType: aws.ToString(result.ContentType),
+		URL:         s.objectURL(id),
+		BucketName:  s.bucketName,
+		UploadedAt:  aws.ToTime(result.LastModified),
+	}, result.Body, nil
+}
+
+// S3からファイルの指定された範囲をダウンロードする
+// 範囲はRangeヘッダーとしてS3に渡し、該当部分のみを読み込むストリームを返す
+func (s *S3Storage) DownloadRange(ctx context.Context, id string, byteRange domain.ByteRange) (*domain.File, io.ReadCloser, error) {
+	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(id),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", byteRange.Offset, byteRange.Offset+byteRange.Length-1)),
+	})
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, nil, fmt.Errorf("failed to download file range: %w", err)
 	}
 
-	return file, buf.Bytes(), nil
+	return &domain.File{
+		ID:          id,
+		Size:        aws.ToInt64(result.ContentLength),
+		ContentType: aws.ToString(result.ContentType),
+		URL:         s.objectURL(id),
+		BucketName:  s.bucketName,
+		UploadedAt:  aws.ToTime(result.LastModified),
+	}, result.Body, nil
 }
 
 // S3からファイルを削除する
-// ファイルIDを受け取り、対応するファイルを削除する
+// オブジェクトキーを受け取り、対応するファイルを削除する
 func (s *S3Storage) Delete(ctx context.Context, id string) error {
-	// ファイル情報を取得
-	file, err := s.Get(ctx, id)
-	if err != nil {
-		return err
-	}
-
-	// S3からファイルを削除
-	_, err = s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(s.bucketName),
-		Key:    aws.String(fmt.Sprintf("%s/%s", file.ContentType, id)),
+		Key:    aws.String(id),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to delete file: %w", err)
@@ -124,7 +185,7 @@ func (s *S3Storage) List(ctx context.Context) ([]domain.File, error) {
 		file := domain.File{
 			ID:         aws.ToString(obj.Key),
 			Size:       *obj.Size,
-			URL:        fmt.Sprintf("https://%s.s3.amazonaws.com/%s", s.bucketName, aws.ToString(obj.Key)),
+			URL:        s.objectURL(aws.ToString(obj.Key)),
 			BucketName: s.bucketName,
 			UploadedAt: *obj.LastModified,
 		}
@@ -134,7 +195,7 @@ func (s *S3Storage) List(ctx context.Context) ([]domain.File, error) {
 	return files, nil
 }
 
-// 指定されたIDのファイル情報を取得する
+// 指定されたオブジェクトキーのファイル情報を取得する
 func (s *S3Storage) Get(ctx context.Context, id string) (*domain.File, error) {
 	// S3からファイルのメタデータを取得
 	result, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
@@ -142,16 +203,123 @@ func (s *S3Storage) Get(ctx context.Context, id string) (*domain.File, error) {
 		Key:    aws.String(id),
 	})
 	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return nil, fmt.Errorf("%w: no object found with key: %s", domain.ErrNotFound, id)
+		}
 		return nil, fmt.Errorf("failed to get file metadata: %w", err)
 	}
 
-	// ファイル情報を返す
-	return &domain.File{
+	// ファイル情報を返す（アップロードしたユーザーと元のファイル名はメタデータから復元する）
+	file := &domain.File{
 		ID:          id,
-		Size:        *result.ContentLength,
+		Size:        aws.ToInt64(result.ContentLength),
 		ContentType: aws.ToString(result.ContentType),
-		URL:         fmt.Sprintf("https://%s.s3.amazonaws.com/%s", s.bucketName, id),
+		URL:         s.objectURL(id),
 		BucketName:  s.bucketName,
-		UploadedAt:  *result.LastModified,
+		UploadedAt:  aws.ToTime(result.LastModified),
+	}
+	applyObjectMetadata(file, result.Metadata)
+	return file, nil
+}
+
+// 新しいオブジェクトキーへ直接アップロードするための署名付きPUT URLを作成する
+// Content-Type、Content-Length、メタデータを署名に含めるため、宣言と異なる内容のアップロードはS3に拒否される
+func (s *S3Storage) PresignUpload(ctx context.Context, request *domain.UploadURLRequest, expires time.Duration) (*domain.PresignedUpload, error) {
+	if request.Size > s3MaxPresignedUploadSize {
+		return nil, fmt.Errorf("%w: presigned uploads are limited to %d bytes", domain.ErrInvalidInput, int64(s3MaxPresignedUploadSize))
+	}
+
+	key := newObjectKey()
+	presigned, err := s.presigner.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucketName),
+		Key:           aws.String(key),
+		ContentType:   aws.String(request.ContentType),
+		ContentLength: aws.Int64(request.Size),
+		Metadata:      objectMetadata(request.Name, request.UploaderID, request.UploaderRole),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign upload: %w", err)
+	}
+
+	return &domain.PresignedUpload{
+		UploadID:     key,
+		PresignedURL: presignedURL(presigned, expires),
 	}, nil
 }
+
+// 指定されたオブジェクトキーを直接ダウンロードするための署名付きGET URLを作成する
+// ダウンロード時のファイル名はレスポンスのContent-Dispositionとして署名に含める
+func (s *S3Storage) PresignDownload(ctx context.Context, id string, fileName string, expires time.Duration) (*domain.PresignedURL, error) {
+	presigned, err := s.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket:                     aws.String(s.bucketName),
+		Key:                        aws.String(id),
+		ResponseContentDisposition: aws.String(mime.FormatMediaType("attachment", map[string]string{"filename": fileName})),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign download: %w", err)
+	}
+
+	download := presignedURL(presigned, expires)
+	return &download, nil
+}
+
+// 公開ベースURLからオブジェクトのURLを作成する
+func (s *S3Storage) objectURL(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return s.publicBaseURL + "/" + strings.Join(segments, "/")
+}
+
+// 一意のオブジェクトキーを生成する
+// クライアントが送信したMIMEタイプなどの値はキーに含めない
+func newObjectKey() string {
+	return uuid.New().String()
+}
+
+// アップロードしたユーザーと元のファイル名をオブジェクトのメタデータに変換する
+// メタデータはASCIIのみ使用できるため、ファイル名はURLエンコードする
+func objectMetadata(name string, uploaderID int64, uploaderRole string) map[string]string {
+	metadata := map[string]string{
+		s3MetaOriginalName: url.PathEscape(name),
+	}
+	if uploaderID != 0 {
+		metadata[s3MetaUploaderID] = strconv.FormatInt(uploaderID, 10)
+	}
+	if uploaderRole != "" {
+		metadata[s3MetaUploaderRole] = uploaderRole
+	}
+	return metadata
+}
+
+// オブジェクトのメタデータからアップロードしたユーザーと元のファイル名を復元する
+func applyObjectMetadata(file *domain.File, metadata map[string]string) {
+	if name, err := url.PathUnescape(metadata[s3MetaOriginalName]); err == nil {
+		file.Name = name
+	}
+	if uploaderID, err := strconv.ParseInt(metadata[s3MetaUploaderID], 10, 64); err == nil {
+		file.UploaderID = uploaderID
+	}
+	file.UploaderRole = metadata[s3MetaUploaderRole]
+}
+
+// SDKの署名済みリクエストを署名付きURLに変換する
+// HostヘッダーはURLに含まれるため、クライアントが送信する必要があるヘッダーから除外する
+func presignedURL(presigned *v4.PresignedHTTPRequest, expires time.Duration) domain.PresignedURL {
+	headers := make(map[string]string, len(presigned.SignedHeader))
+	for name, values := range presigned.SignedHeader {
+		if strings.EqualFold(name, "Host") || len(values) == 0 {
+			continue
+		}
+		headers[name] = values[0]
+	}
+
+	return domain.PresignedURL{
+		URL:       presigned.URL,
+		Method:    presigned.Method,
+		Headers:   headers,
+		ExpiresAt: time.Now().Add(expires).UTC(),
+	}
+}
@@ -0,0 +1,84 @@
+package thumbnail
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// 読み込む画像の最大ピクセル数（圧縮率の高い巨大な画像によるメモリの枯渇を防ぐ）
+	maxSourcePixels = 50_000_000
+	// サムネイルのJPEGの品質
+	jpegQuality = 80
+)
+
+// サムネイルレンダラー構造体：標準ライブラリとx/imageを使用して画像を縮小し、JPEGのサムネイルを作成する
+// JPEG、PNG、GIF（最初のフレーム）、WebPを読み込むことができる
+type Renderer struct{}
+
+// 新しいサムネイルレンダラーインスタンスを作成する
+func NewRenderer() *Renderer {
+	return &Renderer{}
+}
+
+// 画像を読み込み、指定されたサイズ（長辺のピクセル数）ごとのサムネイルを返す
+// 元の画像より大きいサイズには拡大せず、透過部分は白で塗りつぶす
+func (r *Renderer) Render(source io.Reader, sizes []int) ([]domain.RenderedThumbnail, error) {
+	data, err := io.ReadAll(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	// 画像全体を読み込む前に大きさを確認
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrUnsupportedMedia, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxSourcePixels {
+		return nil, fmt.Errorf("%w: image of %dx%d pixels cannot be thumbnailed", domain.ErrUnsupportedMedia, config.Width, config.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrUnsupportedMedia, err)
+	}
+
+	thumbnails := make([]domain.RenderedThumbnail, 0, len(sizes))
+	for _, size := range sizes {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, scale(src, size), &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+		}
+		thumbnails = append(thumbnails, domain.RenderedThumbnail{
+			Size:        size,
+			ContentType: "image/jpeg",
+			Data:        buf.Bytes(),
+		})
+	}
+	return thumbnails, nil
+}
+
+// 画像の長辺が指定されたピクセル数になるよう縦横比を保って縮小し、白い背景に描画する
+func scale(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if longest := max(width, height); longest > size {
+		width = max(1, width*size/longest)
+		height = max(1, height*size/longest)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+	return dst
+}
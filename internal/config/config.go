@@ -4,10 +4,15 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
 )
@@ -49,6 +54,95 @@ type AWSConfig struct {
 	S3Bucket string `yaml:"s3_bucket" env:"AWS_S3_BUCKET"`
 	// DynamoDBテーブル名
 	DynamoTable string `yaml:"dynamo_table" env:"AWS_DYNAMO_TABLE"`
+	// S3の署名付きURLの有効期間
+	PresignExpiry time.Duration `yaml:"presign_expiry" env:"AWS_S3_PRESIGN_EXPIRY"`
+	// S3互換ストレージのエンドポイントURL（MinIO、LocalStackなど。空の場合はAWSのエンドポイント）
+	S3Endpoint string `yaml:"s3_endpoint" env:"AWS_S3_ENDPOINT"`
+	// パス形式のアドレス指定を使用するかどうか（http://endpoint/bucket/key）
+	S3UsePathStyle bool `yaml:"s3_use_path_style" env:"AWS_S3_USE_PATH_STYLE"`
+	// ファイルのURLを作成する際の公開ベースURL（空の場合は<endpoint>/<bucket>、エンドポイントも空の場合はhttps://<bucket>.s3.amazonaws.com）
+	S3PublicBaseURL string `yaml:"s3_public_base_url" env:"AWS_S3_PUBLIC_BASE_URL"`
+	// DynamoDBのエンドポイントURL（DynamoDB Localなど。空の場合はAWSのエンドポイント）
+	DynamoEndpoint string `yaml:"dynamo_endpoint" env:"AWS_DYNAMO_ENDPOINT"`
+	// 静的なアクセスキーID（空の場合はデフォルトの認証情報チェーンを使用）
+	AccessKeyID string `yaml:"access_key_id" env:"AWS_ACCESS_KEY_ID"`
+	// 静的なシークレットアクセスキー
+	SecretAccessKey string `yaml:"secret_access_key" env:"AWS_SECRET_ACCESS_KEY"`
+}
+
+// ファイルストレージのドライバー
+const (
+	// S3にファイルを保存する
+	StorageDriverS3 = "s3"
+	// ローカルのディレクトリにファイルを保存する
+	StorageDriverLocal = "local"
+	// メモリ上にファイルを保持する（開発・テスト用）
+	StorageDriverMemory = "memory"
+)
+
+// アップロードされた内容のマルウェア検査のドライバー
+const (
+	// 検査しない（すべての内容を問題なしとして扱う、開発・テスト用）
+	ScanDriverNone = "none"
+	// ClamAV（clamd）で検査する
+	ScanDriverClamAV = "clamav"
+)
+
+// ストレージ設定：ファイルの実データを保存するストレージを管理
+type StorageConfig struct {
+	// ストレージのドライバー（s3, local, memory）
+	Driver string `yaml:"driver" env:"STORAGE_DRIVER"`
+	// localドライバーでファイルを保存するディレクトリ
+	LocalPath string `yaml:"local_path" env:"STORAGE_LOCAL_PATH"`
+	// 役割（teacher, student）ごとのアップロードポリシー
+	UploadPolicies map[string]UploadPolicyConfig `yaml:"upload_policies"`
+	// ファイルごとに保持するバージョン数（現在のバージョンを含む、0の場合は無制限）
+	VersionRetention int `yaml:"version_retention" env:"STORAGE_VERSION_RETENTION"`
+	// ゴミ箱に移動したファイルとドキュメントを保持する期間
+	TrashRetention time.Duration `yaml:"trash_retention" env:"STORAGE_TRASH_RETENTION"`
+	// 保持期間を過ぎた項目を完全に削除する間隔
+	TrashPurgeInterval time.Duration `yaml:"trash_purge_interval" env:"STORAGE_TRASH_PURGE_INTERVAL"`
+	// 画像ファイルから作成するサムネイルのサイズ（長辺のピクセル数）
+	ThumbnailSizes []int `yaml:"thumbnail_sizes" env:"STORAGE_THUMBNAIL_SIZES"`
+	// サムネイルを作成するワーカーの数
+	ThumbnailWorkers int `yaml:"thumbnail_workers" env:"STORAGE_THUMBNAIL_WORKERS"`
+	// サムネイルを作成する画像の最大サイズ（バイト、これより大きい画像のサムネイルは作成しない）
+	ThumbnailMaxSourceSize int64 `yaml:"thumbnail_max_source_size" env:"STORAGE_THUMBNAIL_MAX_SOURCE_SIZE"`
+	// マルウェア検査のドライバー（none, clamav）
+	ScanDriver string `yaml:"scan_driver" env:"STORAGE_SCAN_DRIVER"`
+	// clamdのアドレス（host:port、または"/"から始まるUNIXソケットのパス）
+	ClamAVAddress string `yaml:"clamav_address" env:"STORAGE_CLAMAV_ADDRESS"`
+	// 1つの内容の検査の最大時間
+	ScanTimeout time.Duration `yaml:"scan_timeout" env:"STORAGE_SCAN_TIMEOUT"`
+	// 検査するワーカーの数
+	ScanWorkers int `yaml:"scan_workers" env:"STORAGE_SCAN_WORKERS"`
+	// 検査待ちの内容を確認する間隔（検査に失敗した内容もこの間隔で再検査する）
+	ScanInterval time.Duration `yaml:"scan_interval" env:"STORAGE_SCAN_INTERVAL"`
+}
+
+// アップロードポリシー設定：役割ごとに許可するアップロードの条件を管理
+type UploadPolicyConfig struct {
+	// 最大サイズ（バイト、0の場合は無制限）
+	MaxSize int64 `yaml:"max_size"`
+	// 許可するMIMEタイプ（内容から判定する。"image/*"のようなワイルドカードも使用可能、空の場合はすべて許可）
+	AllowedTypes []string `yaml:"allowed_types"`
+	// 拒否するファイルの拡張子
+	BlockedExtensions []string `yaml:"blocked_extensions"`
+	// ユーザーごとの保存容量の上限（バイト、0の場合は無制限）
+	QuotaBytes int64 `yaml:"quota_bytes"`
+	// ユーザーごとのファイル数の上限（0の場合は無制限）
+	QuotaFiles int64 `yaml:"quota_files"`
+}
+
+// デフォルトで拒否する実行可能ファイル・スクリプトの拡張子
+var defaultBlockedExtensions = []string{
+	".exe", ".dll", ".msi", ".bat", ".cmd", ".com", ".scr", ".ps1", ".vbs", ".js", ".jar", ".sh", ".app",
+}
+
+// 管理者設定：管理者向けのエンドポイントにアクセスできるユーザーを管理
+type AdminConfig struct {
+	// 管理者として扱う教師のメールアドレス
+	Emails []string `yaml:"emails" env:"ADMIN_EMAILS"`
 }
 
 // ログ設定：アプリケーションのログ出力設定を管理
@@ -71,6 +165,10 @@ type Config struct {
 	JWT JWTConfig `yaml:"jwt"`
 	// AWS設定
 	AWS AWSConfig `yaml:"aws"`
+	// ストレージ設定
+	Storage StorageConfig `yaml:"storage"`
+	// 管理者設定
+	Admin AdminConfig `yaml:"admin"`
 	// ログ設定
 	Log LogConfig `yaml:"log"`
 }
@@ -106,9 +204,47 @@ func LoadConfig(path string) (*Config, error) {
 			Expiration: getEnv("JWT_EXPIRATION", "24h"),
 		},
 		AWS: AWSConfig{
-			Region:      getEnv("AWS_REGION", "us-west-2"),
-			S3Bucket:    getEnv("AWS_S3_BUCKET", ""),
-			DynamoTable: getEnv("AWS_DYNAMO_TABLE", ""),
+			Region:          getEnv("AWS_REGION", "us-west-2"),
+			S3Bucket:        getEnv("AWS_S3_BUCKET", ""),
+			DynamoTable:     getEnv("AWS_DYNAMO_TABLE", ""),
+			PresignExpiry:   time.Duration(getEnvAsInt("AWS_S3_PRESIGN_EXPIRY", 900)) * time.Second,
+			S3Endpoint:      getEnv("AWS_S3_ENDPOINT", ""),
+			S3UsePathStyle:  getEnvAsBool("AWS_S3_USE_PATH_STYLE", false),
+			S3PublicBaseURL: getEnv("AWS_S3_PUBLIC_BASE_URL", ""),
+			DynamoEndpoint:  getEnv("AWS_DYNAMO_ENDPOINT", ""),
+			AccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
+		},
+		Storage: StorageConfig{
+			Driver:                 getEnv("STORAGE_DRIVER", StorageDriverS3),
+			LocalPath:              getEnv("STORAGE_LOCAL_PATH", "./data/files"),
+			VersionRetention:       getEnvAsCount("STORAGE_VERSION_RETENTION", 10),
+			TrashRetention:         time.Duration(getEnvAsInt("STORAGE_TRASH_RETENTION", 30*24*60*60)) * time.Second,
+			TrashPurgeInterval:     time.Duration(getEnvAsInt("STORAGE_TRASH_PURGE_INTERVAL", 60*60)) * time.Second,
+			ThumbnailSizes:         getEnvAsCountList("STORAGE_THUMBNAIL_SIZES", []int{128, 256, 512}),
+			ThumbnailWorkers:       getEnvAsCount("STORAGE_THUMBNAIL_WORKERS", 2),
+			ThumbnailMaxSourceSize: int64(getEnvAsCount("STORAGE_THUMBNAIL_MAX_SOURCE_SIZE", 32<<20)),
+			ScanDriver:             getEnv("STORAGE_SCAN_DRIVER", ScanDriverNone),
+			ClamAVAddress:          getEnv("STORAGE_CLAMAV_ADDRESS", "localhost:3310"),
+			ScanTimeout:            time.Duration(getEnvAsInt("STORAGE_SCAN_TIMEOUT", 2*60)) * time.Second,
+			ScanWorkers:            getEnvAsCount("STORAGE_SCAN_WORKERS", 2),
+			ScanInterval:           time.Duration(getEnvAsInt("STORAGE_SCAN_INTERVAL", 30)) * time.Second,
+			UploadPolicies: map[string]UploadPolicyConfig{
+				"teacher": {
+					MaxSize:           500 << 20,
+					BlockedExtensions: defaultBlockedExtensions,
+					QuotaBytes:        10 << 30,
+				},
+				"student": {
+					MaxSize:           50 << 20,
+					AllowedTypes:      []string{"application/pdf", "application/zip", "image/*", "text/plain", "audio/*", "video/*"},
+					BlockedExtensions: defaultBlockedExtensions,
+					QuotaBytes:        1 << 30,
+				},
+			},
+		},
+		Admin: AdminConfig{
+			Emails: getEnvAsList("ADMIN_EMAILS"),
 		},
 		Log: LogConfig{
 			Level:  getEnv("LOG_LEVEL", "debug"),
@@ -123,9 +259,23 @@ func LoadConfig(path string) (*Config, error) {
 		}
 	}
 
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
+// 読み込んだ設定の値を検証する
+func (c *Config) validate() error {
+	if c.Storage.TrashPurgeInterval <= 0 {
+		return fmt.Errorf("invalid config: storage.trash_purge_interval must be positive, got %s", c.Storage.TrashPurgeInterval)
+	}
+	if c.Storage.ScanInterval <= 0 {
+		return fmt.Errorf("invalid config: storage.scan_interval must be positive, got %s", c.Storage.ScanInterval)
+	}
+	return nil
+}
+
 // YAMLファイルから設定を読み込み、既存の設定とマージする
 func loadYAMLConfig(path string, cfg *Config) error {
 	data, err := os.ReadFile(path)
@@ -141,10 +291,18 @@ func loadYAMLConfig(path string, cfg *Config) error {
 }
 
 // AWS SDKの設定をロードする
+// 静的な認証情報が設定されている場合は、デフォルトの認証情報チェーンの代わりに使用する
 func LoadAWSConfig(cfg *Config) (aws.Config, error) {
-	awsCfg, err := config.LoadDefaultConfig(context.Background(),
+	options := []func(*config.LoadOptions) error{
 		config.WithRegion(cfg.AWS.Region),
-	)
+	}
+	if cfg.AWS.AccessKeyID != "" && cfg.AWS.SecretAccessKey != "" {
+		options = append(options, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AWS.AccessKeyID, cfg.AWS.SecretAccessKey, ""),
+		))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(context.Background(), options...)
 	if err != nil {
 		return aws.Config{}, fmt.Errorf("unable to load AWS SDK config: %w", err)
 	}
@@ -152,6 +310,27 @@ func LoadAWSConfig(cfg *Config) (aws.Config, error) {
 	return awsCfg, nil
 }
 
+// S3クライアントを作成する
+// エンドポイントが設定されている場合はS3互換ストレージ（MinIO、LocalStackなど）に接続する
+func NewS3Client(cfg *Config, awsCfg aws.Config) *s3.Client {
+	return s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.AWS.S3Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.AWS.S3Endpoint)
+		}
+		o.UsePathStyle = cfg.AWS.S3UsePathStyle
+	})
+}
+
+// DynamoDBクライアントを作成する
+// エンドポイントが設定されている場合はDynamoDB Localなどに接続する
+func NewDynamoDBClient(cfg *Config, awsCfg aws.Config) *dynamodb.Client {
+	return dynamodb.NewFromConfig(awsCfg, func(o *dynamodb.Options) {
+		if cfg.AWS.DynamoEndpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.AWS.DynamoEndpoint)
+		}
+	})
+}
+
 // 環境変数から値を取得し、存在しない場合はデフォルト値を返す
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
@@ -169,3 +348,57 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// 環境変数から個数を取得し、存在しない場合や数値でない場合はデフォルト値を返す
+func getEnvAsCount(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if count, err := strconv.Atoi(value); err == nil {
+			return count
+		}
+	}
+	return defaultValue
+}
+
+// 環境変数から真偽値を取得し、存在しない場合はデフォルト値を返す
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// 環境変数からカンマ区切りの文字列のリストを取得し、存在しない場合はnilを返す
+func getEnvAsList(key string) []string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return nil
+	}
+
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// 環境変数からカンマ区切りの個数のリストを取得し、存在しない場合や数値でない項目がある場合はデフォルト値を返す
+func getEnvAsCountList(key string, defaultValue []int) []int {
+	items := getEnvAsList(key)
+	if items == nil {
+		return defaultValue
+	}
+
+	counts := make([]int, 0, len(items))
+	for _, item := range items {
+		count, err := strconv.Atoi(item)
+		if err != nil {
+			return defaultValue
+		}
+		counts = append(counts, count)
+	}
+	return counts
+}
@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+// 課題構造体：教師が作成する課題を表現
+type Assignment struct {
+	// 課題の一意識別子
+	ID int64 `json:"id"`
+	// 課題を作成した教師のID
+	TeacherID int64 `json:"teacher_id"`
+	// 課題のタイトル
+	Title string `json:"title" example:"Chapter 3 exercises"`
+	// 課題の説明・指示
+	Instructions string `json:"instructions" example:"Solve problems 1-10 and upload your work."`
+	// 提出期限
+	DueDate time.Time `json:"due_date" example:"2024-04-01T23:59:59Z"`
+	// 満点
+	MaxPoints float64 `json:"max_points" example:"100"`
+	// 添付ファイルのID一覧（ファイルストレージのID）
+	FileIDs []string `json:"file_ids"`
+	// 課題の作成日時
+	CreatedAt time.Time `json:"created_at"`
+	// 課題の更新日時
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 課題作成リクエスト構造体：新規課題作成時に使用
+type AssignmentCreate struct {
+	// 課題のタイトル（必須）
+	Title string `json:"title" binding:"required" example:"Chapter 3 exercises"`
+	// 課題の説明・指示
+	Instructions string `json:"instructions" example:"Solve problems 1-10 and upload your work."`
+	// 提出期限（必須）
+	DueDate time.Time `json:"due_date" binding:"required" example:"2024-04-01T23:59:59Z"`
+	// 満点（必須、0より大きい値）
+	MaxPoints float64 `json:"max_points" binding:"required,gt=0" example:"100"`
+}
+
+// 提出物構造体：学生による課題の提出を表現
+// 学生ごと・課題ごとに1件で、再提出すると内容が置き換わり提出回数が増える
+type Submission struct {
+	// 提出物の一意識別子
+	ID int64 `json:"id"`
+	// 対象の課題ID
+	AssignmentID int64 `json:"assignment_id"`
+	// 提出した学生のID
+	StudentID int64 `json:"student_id"`
+	// テキストによる回答
+	Text string `json:"text"`
+	// 提出ファイルのID一覧（ファイルストレージのID）
+	FileIDs []string `json:"file_ids"`
+	// 提出期限を過ぎて提出されたかどうか
+	Late bool `json:"late"`
+	// 再提出かどうか
+	Resubmitted bool `json:"resubmitted"`
+	// 提出回数
+	Attempt int `json:"attempt"`
+	// 最新の提出日時
+	SubmittedAt time.Time `json:"submitted_at"`
+	// 初回提出日時
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 提出リクエスト構造体：学生が課題を提出する際に使用
+type SubmissionCreate struct {
+	// テキストによる回答
+	Text string
+	// 提出するファイル
+	Files []*FileUpload
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // ドキュメント構造体：DynamoDBに保存されるドキュメントを表現
 type Document struct {
@@ -14,6 +17,12 @@ type Document struct {
 	CreatedAt time.Time `json:"created_at"`
 	// ドキュメントの最終更新日時
 	UpdatedAt time.Time `json:"updated_at"`
+	// ゴミ箱に移動した日時（ゴミ箱にない場合はnil）
+	DeletedAt *time.Time `json:"deleted_at,omitempty" dynamodbav:",omitempty"`
+	// 作成・更新時に検証したスキーマのバージョン（スキーマが登録されていなかった場合は0）
+	SchemaVersion int `json:"schema_version,omitempty" dynamodbav:",omitempty"`
+	// 書き込みのたびに1ずつ増えるバージョン（作成時は1。楽観的排他制御とETagに使用）
+	Version int `json:"version"`
 }
 
 // ドキュメント作成リクエスト構造体：新規ドキュメント作成時に使用
@@ -22,10 +31,107 @@ type DocumentCreate struct {
 	Type string `json:"type" validate:"required"`
 	// ドキュメントのデータ（必須）
 	Data map[string]interface{} `json:"data" validate:"required"`
+	// 検証したスキーマのバージョン（サービスが設定する）
+	SchemaVersion int `json:"-"`
 }
 
 // ドキュメント更新リクエスト構造体：既存ドキュメント更新時に使用
 type DocumentUpdate struct {
 	// 更新するドキュメントのデータ（必須）
 	Data map[string]interface{} `json:"data" validate:"required"`
+	// 検証したスキーマのバージョン（サービスが設定する）
+	SchemaVersion int `json:"-"`
+	// 更新の前提となるドキュメントのバージョン（If-Matchで指定。nilの場合はバージョンを確認しない）
+	ExpectedVersion *int `json:"-"`
+}
+
+// ドキュメントのバージョン競合エラー：更新の前提としたバージョンが現在のバージョンと異なることを表す
+// 他のユーザーが先に更新した場合に返され、errors.Is(err, ErrPreconditionFailed)が成り立つ
+type DocumentVersionConflictError struct {
+	// ドキュメントのID
+	ID string
+	// 更新の前提としたバージョン
+	ExpectedVersion int
+	// ドキュメントの現在のバージョン
+	CurrentVersion int
+}
+
+// エラーメッセージを返す
+func (e *DocumentVersionConflictError) Error() string {
+	return fmt.Sprintf("%s: document %s is at version %d, not %d",
+		ErrPreconditionFailed, e.ID, e.CurrentVersion, e.ExpectedVersion)
+}
+
+// ErrPreconditionFailedとして扱えるようにする
+func (e *DocumentVersionConflictError) Unwrap() error {
+	return ErrPreconditionFailed
+}
+
+// ドキュメント一覧の並び順
+const (
+	// 作成日時の古い順
+	SortAscending = "asc"
+	// 作成日時の新しい順
+	SortDescending = "desc"
+)
+
+// ドキュメント一覧の1ページあたりの件数
+const (
+	// 件数が指定されなかった場合の件数
+	DefaultDocumentPageSize = 50
+	// 指定できる最大の件数
+	MaxDocumentPageSize = 100
+)
+
+// ドキュメント一覧の検索条件構造体：種類ごとのドキュメントを作成日時順にページ単位で取得する際に使用
+type DocumentQuery struct {
+	// ドキュメントの種類（必須）
+	Type string
+	// この日時以降に作成されたドキュメントに絞り込む（nilの場合は制限なし）
+	From *time.Time
+	// この日時以前に作成されたドキュメントに絞り込む（nilの場合は制限なし）
+	To *time.Time
+	// 並び順（SortAscendingまたはSortDescending。空の場合はSortAscending）
+	Order string
+	// 1ページの最大件数（0の場合はDefaultDocumentPageSize）
+	Limit int
+	// 前のページのNextCursor（空の場合は最初のページ）
+	Cursor string
+	// dataの最上位のキーの値が一致するドキュメントに絞り込む（nilの場合は制限なし）
+	DataEquals map[string]interface{}
+}
+
+// 検索条件を検証し、省略された項目に既定値を設定する
+func (q *DocumentQuery) Normalize() error {
+	if q.Type == "" {
+		return fmt.Errorf("%w: document type is required", ErrInvalidInput)
+	}
+	switch q.Order {
+	case "":
+		q.Order = SortAscending
+	case SortAscending, SortDescending:
+	default:
+		return fmt.Errorf("%w: order must be %q or %q", ErrInvalidInput, SortAscending, SortDescending)
+	}
+	if q.From != nil && q.To != nil && q.From.After(*q.To) {
+		return fmt.Errorf("%w: from must not be after to", ErrInvalidInput)
+	}
+	if q.Limit < 0 || q.Limit > MaxDocumentPageSize {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, MaxDocumentPageSize)
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultDocumentPageSize
+	}
+	if _, ok := q.DataEquals[""]; ok {
+		return fmt.Errorf("%w: data filter key must not be empty", ErrInvalidInput)
+	}
+	return nil
+}
+
+// ドキュメント一覧のページ構造体：検索条件に一致するドキュメントの1ページ分を表現
+type DocumentPage struct {
+	// ページに含まれるドキュメント
+	Items []Document `json:"items"`
+	// 次のページを取得するためのカーソル（最後のページの場合は空）
+	NextCursor string `json:"next_cursor,omitempty"`
 }
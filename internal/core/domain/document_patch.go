@@ -0,0 +1,353 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ドキュメントのパッチの形式
+const (
+	// JSON Merge Patch（RFC 7396、application/merge-patch+json）
+	PatchFormatMerge = "merge"
+	// JSON Patch（RFC 6902、application/json-patch+json）
+	PatchFormatJSON = "json"
+)
+
+// JSON Patchの1回のリクエストで受け付ける操作の最大数
+const MaxPatchOperations = 100
+
+// パッチの対象の値が存在しないことを示すエラー
+var errMissingTarget = fmt.Errorf("%w: target does not exist", ErrConflict)
+
+// JSON Patchの操作構造体：RFC 6902の1つの操作を表現
+type PatchOperation struct {
+	// 操作の種類（add、remove、replace、move、copy、test）
+	Op string `json:"op"`
+	// 操作の対象のJSON Pointer（dataからの位置）
+	Path string `json:"path"`
+	// moveとcopyの元のJSON Pointer
+	From string `json:"from,omitempty"`
+	// add、replace、testの値（nullと省略を区別するためJSONのまま保持する）
+	Value json.RawMessage `json:"value,omitempty" swaggertype:"object"`
+}
+
+// ドキュメントのパッチ構造体：ドキュメントのdataの一部を変更する際に使用
+type DocumentPatch struct {
+	// パッチの形式（PatchFormatMergeまたはPatchFormatJSON）
+	Format string
+	// JSON Merge Patchの本文（PatchFormatMergeの場合）
+	Merge map[string]interface{}
+	// JSON Patchの操作（PatchFormatJSONの場合）
+	Operations []PatchOperation
+	// 更新の前提となるドキュメントのバージョン（If-Matchで指定。nilの場合はバージョンを確認しない）
+	ExpectedVersion *int
+	// 検証したスキーマのバージョン（サービスが設定する）
+	SchemaVersion int
+}
+
+// ドキュメントのdataにパッチを適用した結果を返す（引数のdataは変更しない）
+// パッチの形式が不正な場合はErrInvalidInput、testが一致しない場合や対象が存在しない場合はErrConflictを返す
+func (p *DocumentPatch) Apply(data map[string]interface{}) (map[string]interface{}, error) {
+	var result interface{}
+	switch p.Format {
+	case PatchFormatMerge:
+		if p.Merge == nil {
+			return nil, fmt.Errorf("%w: merge patch must be a JSON object", ErrInvalidInput)
+		}
+		result = mergePatch(deepCopy(data), p.Merge)
+	case PatchFormatJSON:
+		if len(p.Operations) > MaxPatchOperations {
+			return nil, fmt.Errorf("%w: at most %d patch operations are allowed", ErrInvalidInput, MaxPatchOperations)
+		}
+		var err error
+		result = deepCopy(data)
+		for i, op := range p.Operations {
+			if result, err = applyOperation(result, op); err != nil {
+				return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown patch format: %s", ErrInvalidInput, p.Format)
+	}
+
+	object, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: document data must remain a JSON object", ErrInvalidInput)
+	}
+	return object, nil
+}
+
+// JSON Pointer（RFC 6901）を参照トークンに分解する（空文字列は全体を表す）
+func ParseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: invalid JSON pointer: %q", ErrInvalidInput, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// JSON Merge Patchを適用する（RFC 7396のMergePatch関数）
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return deepCopy(patch)
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = mergePatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
+
+// JSON Patchの1つの操作を適用する
+func applyOperation(doc interface{}, op PatchOperation) (interface{}, error) {
+	path, err := ParseJSONPointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		value, err := op.DecodeValue()
+		if err != nil {
+			return nil, err
+		}
+		switch op.Op {
+		case "add":
+			return addValue(doc, path, value)
+		case "replace":
+			if len(path) == 0 {
+				return value, nil
+			}
+			if doc, _, err = removeValue(doc, path); err != nil {
+				return nil, err
+			}
+			return addValue(doc, path, value)
+		default:
+			current, err := getValue(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, fmt.Errorf("%w: test failed", ErrConflict)
+			}
+			return doc, nil
+		}
+	case "remove":
+		doc, _, err = removeValue(doc, path)
+		return doc, err
+	case "move", "copy":
+		from, err := ParseJSONPointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if isProperPrefix(from, path) {
+				return nil, fmt.Errorf("%w: cannot move a value into itself", ErrInvalidInput)
+			}
+			var value interface{}
+			if doc, value, err = removeValue(doc, from); err != nil {
+				return nil, err
+			}
+			return addValue(doc, path, value)
+		}
+		value, err := getValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, deepCopy(value))
+	default:
+		return nil, fmt.Errorf("%w: unknown patch operation: %q", ErrInvalidInput, op.Op)
+	}
+}
+
+// 操作の値をデコードする（値が省略されている場合はErrInvalidInput）
+func (op PatchOperation) DecodeValue() (interface{}, error) {
+	if op.Value == nil {
+		return nil, fmt.Errorf("%w: value is required", ErrInvalidInput)
+	}
+	var value interface{}
+	if err := json.Unmarshal(op.Value, &value); err != nil {
+		return nil, fmt.Errorf("%w: invalid value: %s", ErrInvalidInput, err)
+	}
+	return value, nil
+}
+
+// 参照トークンの位置の値を取得する
+func getValue(doc interface{}, path []string) (interface{}, error) {
+	current := doc
+	for _, token := range path {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, errMissingTarget
+			}
+			current = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			current = node[index]
+		default:
+			return nil, errMissingTarget
+		}
+	}
+	return current, nil
+}
+
+// 参照トークンの位置に値を追加し、変更後の値を返す（配列の場合は挿入、"-"は末尾）
+func addValue(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return updateParent(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[token] = value
+			return node, nil
+		case []interface{}:
+			index := len(node)
+			if token != "-" {
+				var err error
+				if index, err = arrayIndex(token, len(node)); err != nil {
+					return nil, err
+				}
+			}
+			node = append(node, nil)
+			copy(node[index+1:], node[index:])
+			node[index] = value
+			return node, nil
+		default:
+			return nil, errMissingTarget
+		}
+	})
+}
+
+// 参照トークンの位置の値を削除し、変更後の値と削除した値を返す
+func removeValue(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: cannot remove the whole document data", ErrInvalidInput)
+	}
+	var removed interface{}
+	updated, err := updateParent(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, errMissingTarget
+			}
+			removed = value
+			delete(node, token)
+			return node, nil
+		case []interface{}:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = node[index]
+			return append(node[:index], node[index+1:]...), nil
+		default:
+			return nil, errMissingTarget
+		}
+	})
+	return updated, removed, err
+}
+
+// 参照トークンの最後の1つを除いた位置にある値（親）を変更し、変更後の全体を返す
+// 配列は要素の追加・削除で別のスライスになるため、親の親に変更後の値を設定し直す
+func updateParent(doc interface{}, path []string, change func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return change(doc, path[0])
+	}
+
+	token := path[0]
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		child, ok := node[token]
+		if !ok {
+			return nil, errMissingTarget
+		}
+		updated, err := updateParent(child, path[1:], change)
+		if err != nil {
+			return nil, err
+		}
+		node[token] = updated
+		return node, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := updateParent(node[index], path[1:], change)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = updated
+		return node, nil
+	default:
+		return nil, errMissingTarget
+	}
+}
+
+// 配列のインデックスを解釈する（0からmaxまで。先頭の0は許可しない）
+func arrayIndex(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: invalid array index: %q", ErrInvalidInput, token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index > max {
+		return 0, fmt.Errorf("%w: array index out of range: %s", ErrConflict, token)
+	}
+	return index, nil
+}
+
+// prefixがpathの真の先頭部分かどうか（moveで自身の子孫に移動しようとしていないかの確認）
+func isProperPrefix(prefix []string, path []string) bool {
+	if len(prefix) >= len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// JSONの値（オブジェクト、配列、スカラー）を複製する
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			copied[key] = deepCopy(child)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, child := range v {
+			copied[i] = deepCopy(child)
+		}
+		return copied
+	default:
+		return v
+	}
+}
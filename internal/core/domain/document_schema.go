@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ドキュメントスキーマ構造体：ドキュメントの種類ごとに管理者が登録したJSON Schema（draft 2020-12）を表現
+// 登録のたびに新しいバージョンが作成され、ドキュメントは作成・更新時の最新のバージョンで検証される
+type DocumentSchema struct {
+	// スキーマを適用するドキュメントの種類
+	Type string `json:"type"`
+	// スキーマのバージョン（種類ごとに1から順に採番）
+	Version int `json:"version"`
+	// JSON Schema本体（ドキュメントのdataに適用する）
+	Schema json.RawMessage `json:"schema" swaggertype:"object"`
+	// スキーマを登録した管理者のID
+	CreatedBy int64 `json:"created_by"`
+	// 登録日時
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ドキュメントスキーマ登録リクエスト構造体：新しいバージョンのスキーマを登録する際に使用
+type DocumentSchemaCreate struct {
+	// JSON Schema本体（必須）
+	Schema json.RawMessage `json:"schema" validate:"required" swaggertype:"object"`
+}
+
+// 項目エラー構造体：スキーマに適合しなかったドキュメントの項目を表現
+type FieldError struct {
+	// 適合しなかった項目のJSON Pointer（例："/data/title"）
+	Field string `json:"field"`
+	// 適合しなかった理由
+	Message string `json:"message"`
+}
+
+// ドキュメント検証エラー：ドキュメントのデータがスキーマに適合しなかったことを表す
+// errors.Is(err, ErrInvalidInput)が成り立つ
+type DocumentValidationError struct {
+	// ドキュメントの種類
+	Type string
+	// 検証に使用したスキーマのバージョン
+	Version int
+	// 適合しなかった項目
+	Fields []FieldError
+}
+
+// エラーメッセージを返す
+func (e *DocumentValidationError) Error() string {
+	return fmt.Sprintf("%s: document does not match schema version %d of type %s (%d errors)",
+		ErrInvalidInput, e.Version, e.Type, len(e.Fields))
+}
+
+// ErrInvalidInputとして扱えるようにする
+func (e *DocumentValidationError) Unwrap() error {
+	return ErrInvalidInput
+}
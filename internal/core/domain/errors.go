@@ -6,4 +6,14 @@ var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrNotFound           = errors.New("not found")
 	ErrAlreadyExists      = errors.New("already exists")
+	ErrConflict           = errors.New("conflict")
+	ErrForbidden          = errors.New("forbidden")
+	ErrInvalidInput       = errors.New("invalid input")
+	ErrNotSupported       = errors.New("not supported")
+	ErrFileTooLarge       = errors.New("file too large")
+	ErrUnsupportedMedia   = errors.New("unsupported media type")
+	ErrQuotaExceeded      = errors.New("storage quota exceeded")
+	ErrScanPending        = errors.New("malware scan pending")
+	ErrQuarantined        = errors.New("quarantined")
+	ErrPreconditionFailed = errors.New("precondition failed")
 )
@@ -0,0 +1,28 @@
+package domain
+
+// ファイルアーカイブリクエスト構造体：複数のファイルをまとめてZIPでダウンロードする際に使用
+// ファイルIDの一覧とフォルダのどちらか一方を指定する
+type FileArchiveRequest struct {
+	// まとめるファイルのIDの一覧
+	FileIDs []string `json:"file_ids" example:"123e4567-e89b-12d3-a456-426614174000,0d7e2c4b-8a6f-4e1d-b3c9-7f6e5d4c3b2a"`
+	// まとめるフォルダのID（中のフォルダのファイルも含める）
+	FolderID string `json:"folder_id" example:"5f0c6a8e-3b1d-4c2a-9e7f-1a2b3c4d5e6f"`
+}
+
+// ファイルアーカイブ構造体：ZIPに含めるファイルとその配置を表現
+type FileArchive struct {
+	// アーカイブのファイル名
+	Name string
+	// アーカイブに含めるファイル（アーカイブ内の順）
+	Entries []FileArchiveEntry
+	// 見つからなかったファイルのID
+	Missing []string
+}
+
+// ファイルアーカイブ項目構造体：アーカイブ内の1つのファイルを表現
+type FileArchiveEntry struct {
+	// アーカイブ内のパス（同じフォルダ内で重複しない）
+	Path string
+	// カタログのファイル情報
+	File File
+}
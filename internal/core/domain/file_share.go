@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// 共有の種類
+const (
+	// 指定された学生と共有する
+	FileShareStudent = "student"
+	// 共有した教師の担当学生全員と共有する（担当学生の増減は共有の範囲に反映される）
+	FileShareRoster = "roster"
+	// リンクを知っている誰とでも期限付きで共有する
+	FileShareLink = "link"
+)
+
+// ファイル共有構造体：教師がファイルの閲覧を許可した相手（共有）を表現
+type FileShare struct {
+	// 共有の一意識別子
+	ID int64 `json:"id" example:"1"`
+	// 共有するファイルのID
+	FileID string `json:"file_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// 共有の種類（student, roster, link）
+	Kind string `json:"kind" example:"student"`
+	// 共有した教師のID
+	TeacherID int64 `json:"teacher_id" example:"1"`
+	// 共有する学生のID（種類がstudentの場合のみ）
+	StudentID *int64 `json:"student_id,omitempty" example:"12"`
+	// 共有リンクのトークン（種類がlinkの共有を作成した時のみ返す）
+	Token string `json:"token,omitempty" example:"Zk3pW1b9vQ0sYxR2mT7uLc4eHf8aNd6j"`
+	// 共有リンクのURL（種類がlinkの共有を作成した時のみ返す）
+	URL string `json:"url,omitempty" example:"/api/v1/shared/Zk3pW1b9vQ0sYxR2mT7uLc4eHf8aNd6j"`
+	// 共有リンクのトークンのSHA-256ハッシュ（トークン自体は保存しない）
+	TokenHash string `json:"-"`
+	// 共有の有効期限（nilの場合は無期限）
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2024-04-21T15:30:45Z"`
+	// 作成日時
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ファイル共有作成リクエスト構造体：ファイルを共有する際に使用
+type FileShareCreate struct {
+	// 共有の種類（必須、student, roster, link）
+	Kind string `json:"kind" binding:"required,oneof=student roster link" example:"student"`
+	// 共有する学生のID（種類がstudentの場合は必須）
+	StudentID *int64 `json:"student_id" example:"12"`
+	// 共有の有効期限（種類がlinkの場合は必須）
+	ExpiresAt *time.Time `json:"expires_at" example:"2024-04-21T15:30:45Z"`
+}
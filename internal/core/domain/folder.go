@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// フォルダ構造体：教師がファイルを整理するための階層構造のフォルダを表現
+// フォルダはカタログ上の分類であり、移動してもファイルの実データは変わらない
+type Folder struct {
+	// フォルダの一意識別子
+	ID string `json:"id" example:"5f0c6a8e-3b1d-4c2a-9e7f-1a2b3c4d5e6f"`
+	// フォルダ名（同じ親フォルダ内で大文字小文字を区別せず一意）
+	Name string `json:"name" example:"Unit 3"`
+	// 親フォルダのID（最上位のフォルダの場合はnil）
+	ParentID *string `json:"parent_id" example:"0d7e2c4b-8a6f-4e1d-b3c9-7f6e5d4c3b2a"`
+	// フォルダを所有する教師のID
+	OwnerID int64 `json:"owner_id" example:"1"`
+	// 作成日時
+	CreatedAt time.Time `json:"created_at"`
+	// 最終更新日時
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// フォルダ作成リクエスト構造体：新しいフォルダを作成する際に使用
+type FolderCreate struct {
+	// フォルダ名（必須）
+	Name string `json:"name" binding:"required" example:"Unit 3"`
+	// 親フォルダのID（省略した場合は最上位に作成）
+	ParentID *string `json:"parent_id" example:"0d7e2c4b-8a6f-4e1d-b3c9-7f6e5d4c3b2a"`
+}
+
+// フォルダ名変更リクエスト構造体：フォルダの名前を変更する際に使用
+type FolderRename struct {
+	// 新しいフォルダ名（必須）
+	Name string `json:"name" binding:"required" example:"Unit 3 (archived)"`
+}
+
+// フォルダ移動リクエスト構造体：フォルダを別の親フォルダへ移動する際に使用
+type FolderMove struct {
+	// 移動先の親フォルダのID（nullの場合は最上位へ移動）
+	ParentID *string `json:"parent_id" example:"0d7e2c4b-8a6f-4e1d-b3c9-7f6e5d4c3b2a"`
+}
+
+// フォルダ削除リクエスト構造体：フォルダを削除する際の条件を表現
+type FolderDelete struct {
+	// 中のフォルダとファイルも削除するかどうか（ファイルはゴミ箱に移動する）
+	Recursive bool
+	// 削除の確認のためのフォルダ名（中身ごと削除する場合は削除するフォルダの名前と一致する必要がある）
+	Confirm string
+}
+
+// ファイル移動リクエスト構造体：ファイルを別のフォルダへ移動する際に使用
+type FileMove struct {
+	// 移動先のフォルダのID（nullの場合はどのフォルダにも属さない最上位へ移動）
+	FolderID *string `json:"folder_id" example:"5f0c6a8e-3b1d-4c2a-9e7f-1a2b3c4d5e6f"`
+}
+
+// ファイルタグ設定リクエスト構造体：ファイルのタグを置き換える際に使用
+type FileTagsUpdate struct {
+	// 新しいタグの一覧（空の場合はすべてのタグを外す）
+	Tags []string `json:"tags" example:"homework,unit-3"`
+}
+
+// ファイル検索条件構造体：ファイル一覧をフォルダとタグで絞り込む条件を表現
+type FileFilter struct {
+	// 指定されたフォルダ直下のファイルのみを対象にする（空の場合はフォルダで絞り込まない）
+	FolderID string
+	// どのフォルダにも属さないファイルのみを対象にする
+	Unfiled bool
+	// すべてのタグが付いているファイルのみを対象にする
+	Tags []string
+	// 指定されたユーザーがアップロードしたファイルのみを対象にする（UploaderRoleが空の場合はアップロードしたユーザーで絞り込まない）
+	UploaderID int64
+	// アップロードしたユーザーの役割
+	UploaderRole string
+}
@@ -0,0 +1,135 @@
+package domain
+
+import "time"
+
+// 成績カテゴリ構造体：教師が定義する重み付きの評価カテゴリ（宿題、試験など）を表現
+type GradeCategory struct {
+	// カテゴリの一意識別子
+	ID int64 `json:"id"`
+	// カテゴリを定義した教師のID
+	TeacherID int64 `json:"teacher_id"`
+	// カテゴリ名
+	Name string `json:"name" example:"Homework"`
+	// 最終成績に占める重み（パーセント）
+	Weight float64 `json:"weight" example:"30"`
+	// 平均計算時に除外する最低点の件数
+	DropLowest int `json:"drop_lowest" example:"1"`
+	// カテゴリの作成日時
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 成績カテゴリ作成リクエスト構造体：新規カテゴリ作成時に使用
+type GradeCategoryCreate struct {
+	// カテゴリ名（必須）
+	Name string `json:"name" binding:"required" example:"Homework"`
+	// 最終成績に占める重み（必須、0より大きく100以下）
+	Weight float64 `json:"weight" binding:"required,gt=0,lte=100" example:"30"`
+	// 平均計算時に除外する最低点の件数
+	DropLowest int `json:"drop_lowest" binding:"min=0" example:"1"`
+}
+
+// 成績記録構造体：学生の課題ごとの得点を表現
+type GradeEntry struct {
+	// 成績記録の一意識別子
+	ID int64 `json:"id"`
+	// 採点した教師のID
+	TeacherID int64 `json:"teacher_id"`
+	// 学生のID
+	StudentID int64 `json:"student_id"`
+	// 成績カテゴリのID
+	CategoryID int64 `json:"category_id"`
+	// 対象の課題ID（課題に紐付かない成績の場合は空）
+	AssignmentID *int64 `json:"assignment_id,omitempty"`
+	// 対象の小テストID（小テストの結果の場合のみ）
+	QuizID *string `json:"quiz_id,omitempty"`
+	// 成績の名称
+	Title string `json:"title" example:"Chapter 3 exercises"`
+	// 得点
+	Score float64 `json:"score" example:"85"`
+	// 満点
+	MaxPoints float64 `json:"max_points" example:"100"`
+	// 採点日時
+	GradedAt time.Time `json:"graded_at"`
+}
+
+// 成績入力リクエスト構造体：教師が得点を記録する際に使用
+// 課題IDを指定した場合、同じ学生・課題の成績は上書きされ、満点とタイトルは課題から補完される
+type GradeEntryInput struct {
+	// 学生のID（必須）
+	StudentID int64 `json:"student_id" binding:"required" example:"12"`
+	// 成績カテゴリのID（必須）
+	CategoryID int64 `json:"category_id" binding:"required" example:"3"`
+	// 対象の課題ID
+	AssignmentID *int64 `json:"assignment_id,omitempty" example:"5"`
+	// 成績の名称（課題IDがない場合は必須）
+	Title string `json:"title" example:"Pop quiz"`
+	// 得点（0以上）
+	Score float64 `json:"score" binding:"min=0" example:"85"`
+	// 満点（課題IDがない場合は必須）
+	MaxPoints float64 `json:"max_points" binding:"min=0" example:"100"`
+}
+
+// 成績段階構造体：評定（A、Bなど）とその下限パーセントを表現
+type GradeThreshold struct {
+	// 評定
+	Letter string `json:"letter" binding:"required" example:"A"`
+	// この評定となる最低パーセント
+	MinPercent float64 `json:"min_percent" binding:"min=0,max=100" example:"90"`
+}
+
+// 教師が評定基準を設定していない場合に使用される標準の評定基準
+var DefaultGradeScale = []GradeThreshold{
+	{Letter: "A", MinPercent: 90},
+	{Letter: "B", MinPercent: 80},
+	{Letter: "C", MinPercent: 70},
+	{Letter: "D", MinPercent: 60},
+	{Letter: "F", MinPercent: 0},
+}
+
+// カテゴリ別成績構造体：カテゴリごとの計算結果を表現
+type CategoryGrade struct {
+	// 成績カテゴリのID
+	CategoryID int64 `json:"category_id"`
+	// カテゴリ名
+	Name string `json:"name"`
+	// 最終成績に占める重み（パーセント）
+	Weight float64 `json:"weight"`
+	// カテゴリの平均パーセント（成績が未入力の場合は空）
+	Percent *float64 `json:"percent"`
+	// 計算に含めた成績の件数
+	Counted int `json:"counted"`
+	// 最低点として除外した成績の件数
+	Dropped int `json:"dropped"`
+}
+
+// 学生成績構造体：ある教師の授業における学生の現在の成績を表現
+type StudentGrade struct {
+	// 学生のID
+	StudentID int64 `json:"student_id"`
+	// 学生の氏名
+	StudentName string `json:"student_name,omitempty"`
+	// 教師のID
+	TeacherID int64 `json:"teacher_id"`
+	// 科目
+	Subject string `json:"subject,omitempty"`
+	// カテゴリ別の成績
+	Categories []CategoryGrade `json:"categories"`
+	// 重み付き平均による最終パーセント（成績が未入力の場合は空）
+	Percent *float64 `json:"percent"`
+	// 評定（成績が未入力の場合は空）
+	Letter string `json:"letter"`
+	// 個別の成績記録
+	Entries []GradeEntry `json:"entries"`
+}
+
+// 成績簿構造体：教師の成績カテゴリ、評定基準、担当学生の成績をまとめて表現
+type Gradebook struct {
+	// 教師のID
+	TeacherID int64 `json:"teacher_id"`
+	// 成績カテゴリ一覧
+	Categories []GradeCategory `json:"categories"`
+	// 評定基準
+	Scale []GradeThreshold `json:"scale"`
+	// 担当学生の成績一覧
+	Students []StudentGrade `json:"students"`
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"io"
+	"time"
+)
 
 // 学生構造体：システムに登録されている学生を表現
 type Student struct {
@@ -54,14 +57,28 @@ type TeacherLogin struct {
 	Password string `json:"password" binding:"required,min=6" example:"SecurePass123"`
 }
 
+// ユーザーの役割（JWTのroleクレームの値）
+const (
+	// 教師
+	RoleTeacher = "teacher"
+	// 学生
+	RoleStudent = "student"
+)
+
 // ファイルアップロードリクエスト構造体：S3へのファイルアップロード時に使用
 type FileUpload struct {
 	// ファイルの名前（必須）
 	Name string `json:"name" binding:"required" example:"document.pdf"`
-	// ファイルのバイナリデータ
-	Data []byte `json:"data" swaggertype:"string" format:"binary"`
+	// ファイルの内容（アップロード中に先頭から順に読み込まれる）
+	Body io.Reader `json:"-"`
+	// ファイルのサイズ（バイト、不明な場合は-1）
+	Size int64 `json:"size" example:"1048576"`
 	// ファイルのMIMEタイプ
 	ContentType string `json:"content_type" example:"application/pdf"`
+	// アップロードしたユーザーのID
+	UploaderID int64 `json:"-"`
+	// アップロードしたユーザーの役割（teacher, student）
+	UploaderRole string `json:"-"`
 }
 
 // ファイル構造体：S3に保存されているファイルを表現
@@ -75,9 +92,116 @@ type File struct {
 	// ファイルのMIMEタイプ
 	ContentType string `json:"content_type" example:"application/pdf"`
 	// ファイルのURL
-	URL string `json:"url" example:"https://storage.example.com/files/document.pdf"`
+	URL string `json:"url" example:"/api/v1/files/123e4567-e89b-12d3-a456-426614174000"`
 	// ファイルが保存されているS3バケット名
-	BucketName string `json:"bucket_name" example:"my-bucket"`
+	BucketName string `json:"bucket_name,omitempty" example:"my-bucket"`
 	// ファイルのアップロード日時
 	UploadedAt time.Time `json:"uploaded_at" example:"2024-03-21T15:30:45Z"`
+	// ストレージ上のオブジェクトキー
+	ObjectKey string `json:"-"`
+	// ファイル内容のSHA-256チェックサム（16進数）
+	Checksum string `json:"checksum,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	// アップロードしたユーザーのID
+	UploaderID int64 `json:"uploader_id,omitempty" example:"1"`
+	// アップロードしたユーザーの役割（teacher, student）
+	UploaderRole string `json:"uploader_role,omitempty" example:"teacher"`
+	// ファイルのバージョン番号（1から始まる）
+	Version int `json:"version,omitempty" example:"1"`
+	// ゴミ箱に移動した日時（ゴミ箱にない場合はnil）
+	DeletedAt *time.Time `json:"deleted_at,omitempty" example:"2024-03-22T09:00:00Z"`
+	// ファイルが属するフォルダのID（どのフォルダにも属さない場合はnil）
+	FolderID *string `json:"folder_id,omitempty" example:"5f0c6a8e-3b1d-4c2a-9e7f-1a2b3c4d5e6f"`
+	// ファイルのタグ（名前順）
+	Tags []string `json:"tags,omitempty" example:"homework,unit-3"`
+	// 内容のマルウェア検査の状態（pending_scan, clean, infected）
+	ScanStatus string `json:"scan_status,omitempty" example:"clean"`
+}
+
+// バイト範囲構造体：ファイルの一部分を表現
+type ByteRange struct {
+	// 開始位置（バイト）
+	Offset int64
+	// 長さ（バイト）
+	Length int64
+}
+
+// アップロードポリシー構造体：役割ごとに許可するアップロードの条件を表現
+type UploadPolicy struct {
+	// 最大サイズ（バイト、0の場合は無制限）
+	MaxSize int64
+	// 許可するMIMEタイプ（内容から判定したタイプと照合する。"image/*"のようなワイルドカードも使用可能、空の場合はすべて許可）
+	AllowedTypes []string
+	// 拒否するファイルの拡張子（例：".exe"）
+	BlockedExtensions []string
+	// ユーザーごとの保存容量の上限
+	Quota StorageQuota
+}
+
+// ストレージクォータ構造体：ユーザーごとの保存容量の上限を表現
+type StorageQuota struct {
+	// 合計サイズの上限（バイト、0の場合は無制限）
+	MaxBytes int64 `json:"max_bytes" example:"10737418240"`
+	// ファイル数の上限（0の場合は無制限）
+	MaxFiles int64 `json:"max_files" example:"0"`
+}
+
+// ストレージ使用量構造体：アップロードしたユーザーごとの保存容量の使用状況を表現
+type StorageUsage struct {
+	// アップロードしたユーザーのID
+	UploaderID int64 `json:"uploader_id" example:"1"`
+	// アップロードしたユーザーの役割（teacher, student）
+	UploaderRole string `json:"uploader_role" example:"teacher"`
+	// 保存しているファイルの合計サイズ（バイト）
+	Bytes int64 `json:"bytes" example:"52428800"`
+	// 保存しているファイル数
+	Files int64 `json:"files" example:"42"`
+	// 保存容量の上限
+	Quota StorageQuota `json:"quota"`
+}
+
+// 署名付きアップロードURLの作成リクエスト構造体：ストレージへ直接アップロードするファイルの情報を表現
+type UploadURLRequest struct {
+	// ファイルの名前
+	Name string `json:"name" validate:"required" example:"lecture.mp4"`
+	// ファイルのMIMEタイプ（アップロード時に同じ値を送信する必要がある）
+	ContentType string `json:"content_type" validate:"required" example:"video/mp4"`
+	// ファイルのサイズ（バイト、アップロード時に同じサイズである必要がある）
+	Size int64 `json:"size" validate:"required,gt=0" example:"104857600"`
+	// アップロードするユーザーのID
+	UploaderID int64 `json:"-"`
+	// アップロードするユーザーの役割
+	UploaderRole string `json:"-"`
+}
+
+// 署名付きURL構造体：有効期限付きでストレージに直接アクセスするためのURLを表現
+type PresignedURL struct {
+	// 署名付きURL
+	URL string `json:"url" example:"https://my-bucket.s3.amazonaws.com/123e4567-e89b-12d3-a456-426614174000?X-Amz-Signature=..."`
+	// リクエストに使用するHTTPメソッド
+	Method string `json:"method" example:"PUT"`
+	// リクエストに含める必要があるヘッダー
+	Headers map[string]string `json:"headers,omitempty"`
+	// URLの有効期限
+	ExpiresAt time.Time `json:"expires_at" example:"2024-03-21T15:45:45Z"`
+}
+
+// 署名付きアップロード構造体：直接アップロード用のURLと完了通知に使用するIDを表現
+type PresignedUpload struct {
+	// アップロードID（アップロード完了の通知に使用する）
+	UploadID string `json:"upload_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	PresignedURL
+}
+
+// アップロード完了通知構造体：直接アップロードが完了したことを表現
+type UploadCompletion struct {
+	// 署名付きURLの作成時に返されたアップロードID
+	UploadID string `json:"upload_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ゴミ箱の完全削除結果構造体：保持期間を過ぎて完全に削除した項目数を表現
+type TrashPurgeResult struct {
+	// 完全に削除したファイル数
+	Files int `json:"files" example:"3"`
+	// 完全に削除したドキュメント数
+	Documents int `json:"documents" example:"1"`
 }
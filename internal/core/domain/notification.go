@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// 通知の種類
+const (
+	// アップロードしたファイルからマルウェアを検出し隔離した
+	NotificationFileQuarantined = "file_quarantined"
+)
+
+// 通知構造体：ユーザーへのアプリ内の通知を表現
+type Notification struct {
+	// 通知の一意識別子
+	ID int64 `json:"id" example:"1"`
+	// 通知を受け取るユーザーの役割（teacher, student）
+	RecipientRole string `json:"-"`
+	// 通知を受け取るユーザーのID
+	RecipientID int64 `json:"-"`
+	// 通知の種類
+	Kind string `json:"kind" example:"file_quarantined"`
+	// 通知の本文
+	Message string `json:"message" example:"essay.docx was quarantined because malware was detected (Eicar-Test-Signature)"`
+	// 関連するファイルのID（ファイルに関する通知の場合）
+	FileID string `json:"file_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// 作成日時
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// 問題バンクの問題を保存するドキュメントの種類
+const QuestionBankDocumentType = "question_bank_item"
+
+// 問題バンク項目構造体：教師の問題バンクに登録された1問を表現
+// ドキュメントストレージに教師IDとともに保存される
+type QuestionBankItem struct {
+	// 項目の一意識別子（ドキュメントID）
+	ID string `json:"id"`
+	// 問題を所有する教師のID
+	TeacherID int64 `json:"teacher_id"`
+	// 問題の定義（小テストの問題と同じ形式）
+	Question QuizQuestion `json:"question"`
+	// 取り込み元のQTIアイテムの識別子
+	SourceIdentifier string `json:"source_identifier,omitempty" example:"item-042"`
+	// 取り込み元のQTIパッケージのファイルID
+	PackageFileID string `json:"package_file_id,omitempty"`
+	// 作成日時
+	CreatedAt time.Time `json:"created_at"`
+	// 最終更新日時
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// QTIアイテム構造体：QTIパッケージから読み取った1問を表現
+type QTIItem struct {
+	// パッケージ内のファイルパス
+	File string `json:"file"`
+	// assessmentItemの識別子
+	Identifier string `json:"identifier"`
+	// 変換後の問題
+	Question QuizQuestion `json:"question"`
+}
+
+// 未対応QTIアイテム構造体：取り込めなかったアイテムとその理由を表現
+type QTIUnsupportedItem struct {
+	// パッケージ内のファイルパス
+	File string `json:"file" example:"items/q7.xml"`
+	// assessmentItemの識別子
+	Identifier string `json:"identifier,omitempty" example:"q7"`
+	// アイテムが使用しているインタラクションの種類
+	Interaction string `json:"interaction,omitempty" example:"orderInteraction"`
+	// 取り込めなかった理由
+	Reason string `json:"reason" example:"interaction type orderInteraction is not supported"`
+}
+
+// QTIパッケージ構造体：QTIパッケージの解析結果を表現
+type QTIPackage struct {
+	// 問題として読み取れたアイテム
+	Items []QTIItem `json:"items"`
+	// 取り込めなかったアイテム
+	Unsupported []QTIUnsupportedItem `json:"unsupported"`
+}
+
+// QTI取り込み結果構造体：QTIパッケージの取り込み結果を表現
+type QTIImportResult struct {
+	// 保存されたパッケージのファイルID
+	PackageFileID string `json:"package_file_id"`
+	// 問題バンクに追加された項目
+	Imported []QuestionBankItem `json:"imported"`
+	// 取り込めなかったアイテム
+	Unsupported []QTIUnsupportedItem `json:"unsupported"`
+}
@@ -0,0 +1,221 @@
+package domain
+
+import "time"
+
+// 小テストを保存するドキュメントの種類
+const QuizDocumentType = "test"
+
+// 問題の種類
+const (
+	// 単一選択問題
+	QuestionMultipleChoice = "multiple_choice"
+	// 複数選択問題
+	QuestionMultiSelect = "multi_select"
+	// 正誤問題
+	QuestionTrueFalse = "true_false"
+	// 数値問題（許容誤差付き）
+	QuestionNumeric = "numeric"
+	// 短文記述問題
+	QuestionShortText = "short_text"
+)
+
+// 受験の状態
+const (
+	// 受験中
+	AttemptInProgress = "in_progress"
+	// 提出済み
+	AttemptSubmitted = "submitted"
+	// 制限時間切れ
+	AttemptExpired = "expired"
+)
+
+// 小テスト問題構造体：問題文、選択肢、正答、配点を表現
+// 正答は問題の種類に対応するフィールドのみを使用する
+type QuizQuestion struct {
+	// 問題の識別子（小テスト内で一意）
+	ID string `json:"id" example:"q1"`
+	// 問題の種類（multiple_choice, multi_select, true_false, numeric, short_text）
+	Type string `json:"type" example:"multiple_choice"`
+	// 問題文
+	Prompt string `json:"prompt" example:"What is 2 + 2?"`
+	// 選択肢（選択問題のみ）
+	Options []string `json:"options,omitempty"`
+	// 配点（省略時は1点）
+	Points float64 `json:"points,omitempty" example:"2"`
+	// 単一選択問題の正答の選択肢番号（0始まり）
+	CorrectOption *int `json:"correct_option,omitempty" example:"1"`
+	// 複数選択問題の正答の選択肢番号（0始まり）
+	CorrectOptions []int `json:"correct_options,omitempty"`
+	// 正誤問題の正答
+	CorrectBool *bool `json:"correct_bool,omitempty"`
+	// 数値問題の正答
+	CorrectNumber *float64 `json:"correct_number,omitempty" example:"3.14"`
+	// 数値問題の許容誤差
+	Tolerance float64 `json:"tolerance,omitempty" example:"0.01"`
+	// 短文記述問題の正答として認める回答
+	AcceptedAnswers []string `json:"accepted_answers,omitempty"`
+	// 短文記述問題で大文字と小文字を区別するかどうか
+	CaseSensitive bool `json:"case_sensitive,omitempty"`
+}
+
+// 小テスト構造体：教師が作成した小テストの定義を表現
+// ドキュメントストレージに種類"test"のドキュメントとして保存される
+type Quiz struct {
+	// 小テストの一意識別子（ドキュメントID）
+	ID string `json:"id"`
+	// 小テストを作成した教師のID
+	TeacherID int64 `json:"teacher_id"`
+	// タイトル
+	Title string `json:"title" example:"Chapter 3 quiz"`
+	// 説明
+	Description string `json:"description,omitempty"`
+	// 結果を記録する成績カテゴリのID
+	CategoryID int64 `json:"category_id" example:"3"`
+	// 制限時間（分、0の場合は無制限）
+	TimeLimitMinutes int `json:"time_limit_minutes" example:"20"`
+	// 受験回数の上限（0の場合は無制限）
+	MaxAttempts int `json:"max_attempts" example:"2"`
+	// 問題の順序を受験ごとにランダムにするかどうか
+	ShuffleQuestions bool `json:"shuffle_questions"`
+	// 選択肢の順序を受験ごとにランダムにするかどうか
+	ShuffleOptions bool `json:"shuffle_options"`
+	// 問題一覧
+	Questions []QuizQuestion `json:"questions"`
+	// 作成日時
+	CreatedAt time.Time `json:"created_at"`
+	// 最終更新日時
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 小テスト作成リクエスト構造体：新規小テスト作成時に使用
+type QuizCreate struct {
+	// タイトル（必須）
+	Title string `json:"title" binding:"required" example:"Chapter 3 quiz"`
+	// 説明
+	Description string `json:"description,omitempty"`
+	// 結果を記録する成績カテゴリのID（必須）
+	CategoryID int64 `json:"category_id" binding:"required" example:"3"`
+	// 制限時間（分、0の場合は無制限）
+	TimeLimitMinutes int `json:"time_limit_minutes" binding:"min=0" example:"20"`
+	// 受験回数の上限（0の場合は無制限）
+	MaxAttempts int `json:"max_attempts" binding:"min=0" example:"2"`
+	// 問題の順序を受験ごとにランダムにするかどうか
+	ShuffleQuestions bool `json:"shuffle_questions"`
+	// 選択肢の順序を受験ごとにランダムにするかどうか
+	ShuffleOptions bool `json:"shuffle_options"`
+	// 問題一覧（必須）
+	Questions []QuizQuestion `json:"questions" binding:"required,min=1"`
+}
+
+// 小テスト概要構造体：学生向けの小テスト一覧に表示される情報を表現
+type QuizSummary struct {
+	// 小テストの一意識別子
+	ID string `json:"id"`
+	// 小テストを作成した教師のID
+	TeacherID int64 `json:"teacher_id"`
+	// タイトル
+	Title string `json:"title"`
+	// 説明
+	Description string `json:"description,omitempty"`
+	// 制限時間（分、0の場合は無制限）
+	TimeLimitMinutes int `json:"time_limit_minutes"`
+	// 受験回数の上限（0の場合は無制限）
+	MaxAttempts int `json:"max_attempts"`
+	// 問題数
+	QuestionCount int `json:"question_count"`
+	// 満点
+	TotalPoints float64 `json:"total_points"`
+}
+
+// 選択肢構造体：学生に提示される選択肢を表現
+type QuizOption struct {
+	// 選択肢番号（回答時に使用する元の順序での番号）
+	ID int `json:"id"`
+	// 選択肢の文言
+	Text string `json:"text"`
+}
+
+// 出題構造体：正答を含まない、学生に提示される問題を表現
+type QuizQuestionView struct {
+	// 問題の識別子
+	ID string `json:"id"`
+	// 問題の種類
+	Type string `json:"type"`
+	// 問題文
+	Prompt string `json:"prompt"`
+	// 選択肢（提示順）
+	Options []QuizOption `json:"options,omitempty"`
+	// 配点
+	Points float64 `json:"points"`
+}
+
+// 回答構造体：学生の1問分の回答を表現
+// 値は問題の種類に応じて、選択肢番号、選択肢番号の配列、真偽値、数値、文字列のいずれか
+type QuizAnswer struct {
+	// 問題の識別子
+	QuestionID string `json:"question_id" binding:"required" example:"q1"`
+	// 回答の値
+	Value interface{} `json:"value" swaggertype:"object"`
+}
+
+// 採点結果構造体：1問分の採点結果を表現
+type QuizQuestionResult struct {
+	// 問題の識別子
+	QuestionID string `json:"question_id"`
+	// 正解かどうか
+	Correct bool `json:"correct"`
+	// 獲得点
+	Points float64 `json:"points"`
+	// 配点
+	MaxPoints float64 `json:"max_points"`
+}
+
+// 受験構造体：学生の小テストの受験1回分を表現
+type QuizAttempt struct {
+	// 受験の一意識別子
+	ID int64 `json:"id"`
+	// 小テストのID
+	QuizID string `json:"quiz_id"`
+	// 学生のID
+	StudentID int64 `json:"student_id"`
+	// 小テストを作成した教師のID
+	TeacherID int64 `json:"teacher_id"`
+	// 何回目の受験か
+	Number int `json:"number"`
+	// 受験の状態（in_progress, submitted, expired）
+	Status string `json:"status"`
+	// 開始日時
+	StartedAt time.Time `json:"started_at"`
+	// 提出期限（制限時間がない場合は空）
+	Deadline *time.Time `json:"deadline,omitempty"`
+	// 提出日時
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+	// 得点（採点前は空）
+	Score *float64 `json:"score"`
+	// 満点
+	MaxScore float64 `json:"max_score"`
+	// 出題順の問題ID
+	QuestionOrder []string `json:"question_order"`
+	// 問題ごとの選択肢の提示順
+	OptionOrder map[string][]int `json:"option_order,omitempty"`
+	// 回答
+	Answers []QuizAnswer `json:"answers,omitempty"`
+	// 問題ごとの採点結果
+	Results []QuizQuestionResult `json:"results,omitempty"`
+}
+
+// 受験セッション構造体：受験開始時に学生に返される問題一式を表現
+type QuizAttemptSession struct {
+	// 受験情報
+	Attempt QuizAttempt `json:"attempt"`
+	// 小テストのタイトル
+	Title string `json:"title"`
+	// 出題順の問題
+	Questions []QuizQuestionView `json:"questions"`
+}
+
+// 受験提出リクエスト構造体：学生が回答を提出する際に使用
+type QuizSubmission struct {
+	// 回答一覧
+	Answers []QuizAnswer `json:"answers" binding:"dive"`
+}
@@ -0,0 +1,133 @@
+package domain
+
+import "time"
+
+// 通知表所見構造体：教師が学期ごとに記入する学生へのコメントと出欠を表現
+// 出欠管理機能は存在しないため、出席・欠席・遅刻の日数は教師が学期末に入力する
+type ReportCardRemark struct {
+	// 所見の一意識別子
+	ID int64 `json:"id"`
+	// 記入した教師のID
+	TeacherID int64 `json:"teacher_id"`
+	// 学生のID
+	StudentID int64 `json:"student_id"`
+	// 学期
+	Term string `json:"term" example:"2024-spring"`
+	// 教師のコメント
+	Comment string `json:"comment" example:"Excellent participation in class."`
+	// 出席日数
+	DaysPresent int `json:"days_present" example:"58"`
+	// 欠席日数
+	DaysAbsent int `json:"days_absent" example:"2"`
+	// 遅刻日数
+	DaysTardy int `json:"days_tardy" example:"1"`
+	// 最終更新日時
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 通知表所見入力リクエスト構造体：教師がコメントと出欠を記入する際に使用
+// 同じ学生・学期の所見は上書きされる
+type ReportCardRemarkInput struct {
+	// 学生のID（必須）
+	StudentID int64 `json:"student_id" binding:"required" example:"12"`
+	// 学期（必須）
+	Term string `json:"term" binding:"required" example:"2024-spring"`
+	// 教師のコメント
+	Comment string `json:"comment" example:"Excellent participation in class."`
+	// 出席日数（0以上）
+	DaysPresent int `json:"days_present" binding:"min=0" example:"58"`
+	// 欠席日数（0以上）
+	DaysAbsent int `json:"days_absent" binding:"min=0" example:"2"`
+	// 遅刻日数（0以上）
+	DaysTardy int `json:"days_tardy" binding:"min=0" example:"1"`
+}
+
+// 出欠集計構造体：出席・欠席・遅刻の日数と出席率を表現
+type AttendanceSummary struct {
+	// 出席日数
+	DaysPresent int `json:"days_present"`
+	// 欠席日数
+	DaysAbsent int `json:"days_absent"`
+	// 遅刻日数（出席日数に含まれる）
+	DaysTardy int `json:"days_tardy"`
+	// 出席率（パーセント、出欠が未入力の場合は空）
+	Rate *float64 `json:"rate"`
+}
+
+// 通知表科目構造体：通知表に記載される科目ごとの成績、出欠、コメントを表現
+type ReportCardSubject struct {
+	// 教師のID
+	TeacherID int64 `json:"teacher_id"`
+	// 教師の氏名
+	TeacherName string `json:"teacher_name"`
+	// 科目
+	Subject string `json:"subject"`
+	// 最終パーセント（成績が未入力の場合は空）
+	Percent *float64 `json:"percent"`
+	// 評定（成績が未入力の場合は空）
+	Letter string `json:"letter"`
+	// 科目の出欠（所見が未入力の場合は空）
+	Attendance *AttendanceSummary `json:"attendance,omitempty"`
+	// 教師のコメント
+	Comment string `json:"comment,omitempty"`
+}
+
+// 通知表構造体：PDFとして出力される学生1人分の通知表の内容を表現
+type ReportCard struct {
+	// 学生のID
+	StudentID int64 `json:"student_id"`
+	// 学生の氏名
+	StudentName string `json:"student_name"`
+	// 学生のメールアドレス
+	StudentEmail string `json:"student_email"`
+	// 学期
+	Term string `json:"term"`
+	// 科目ごとの成績
+	Subjects []ReportCardSubject `json:"subjects"`
+	// 全科目の出欠集計
+	Attendance AttendanceSummary `json:"attendance"`
+	// 作成日時
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// 通知表ファイル構造体：生成され、ファイルストレージに保存された通知表PDFを表現
+type ReportCardFile struct {
+	// 通知表の一意識別子
+	ID int64 `json:"id"`
+	// 学生のID
+	StudentID int64 `json:"student_id"`
+	// 学期
+	Term string `json:"term" example:"2024-spring"`
+	// ファイルストレージ上のPDFのID
+	FileID string `json:"file_id"`
+	// 通知表を生成した教師のID
+	GeneratedBy int64 `json:"generated_by"`
+	// 生成日時
+	GeneratedAt time.Time `json:"generated_at"`
+	// PDFのダウンロードURL
+	DownloadURL string `json:"download_url,omitempty" example:"/api/v1/me/report-cards/3/download"`
+}
+
+// 通知表生成リクエスト構造体：通知表を生成する学期を指定する際に使用
+type ReportCardGenerate struct {
+	// 学期（必須）
+	Term string `json:"term" binding:"required" example:"2024-spring"`
+}
+
+// 通知表生成失敗構造体：一括生成で生成できなかった学生と理由を表現
+type ReportCardFailure struct {
+	// 学生のID
+	StudentID int64 `json:"student_id"`
+	// 失敗の理由
+	Error string `json:"error"`
+}
+
+// 通知表一括生成結果構造体：生成された通知表と失敗した学生を表現
+type ReportCardBatch struct {
+	// 学期
+	Term string `json:"term"`
+	// 生成された通知表
+	Generated []ReportCardFile `json:"generated"`
+	// 生成に失敗した学生
+	Failed []ReportCardFailure `json:"failed"`
+}
@@ -0,0 +1,42 @@
+package domain
+
+// ファイル内容のマルウェア検査の状態
+const (
+	// 検査待ち（ダウンロードできない）
+	ScanPending = "pending_scan"
+	// 検査済みで問題なし
+	ScanClean = "clean"
+	// マルウェアを検出し隔離済み（ダウンロードできない）
+	ScanInfected = "infected"
+)
+
+// 検査結果構造体：コンテンツスキャナーによる1つの内容の検査結果を表現
+type ScanResult struct {
+	// マルウェアを検出したかどうか
+	Infected bool
+	// 検出したマルウェアの名前（検出しなかった場合は空）
+	Signature string
+}
+
+// 検査対象構造体：検査を待っている内容（重複排除されたオブジェクト）を表現
+type ScanTarget struct {
+	// 内容のSHA-256チェックサム
+	Checksum string
+	// ストレージ上のオブジェクトキー
+	ObjectKey string
+	// 内容のサイズ（バイト）
+	Size int64
+}
+
+// ファイルをダウンロードできる状態かどうかを確認し、できない場合は理由に応じたエラーを返す
+// 検査の導入前に保存された内容は検査済みとして扱う
+func (f *File) CheckServable() error {
+	switch f.ScanStatus {
+	case ScanPending:
+		return ErrScanPending
+	case ScanInfected:
+		return ErrQuarantined
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// サムネイル構造体：画像ファイルの内容から作成した縮小画像（派生オブジェクト）を表現
+// サムネイルは内容のチェックサムごとに作成するため、同じ内容のファイルやバージョンは同じサムネイルを共有する
+type Thumbnail struct {
+	// 元の内容のSHA-256チェックサム
+	Checksum string `json:"-"`
+	// サムネイルのサイズ（長辺のピクセル数）
+	Size int `json:"size" example:"256"`
+	// ストレージ上のオブジェクトキー
+	ObjectKey string `json:"-"`
+	// サムネイルのMIMEタイプ
+	ContentType string `json:"content_type" example:"image/jpeg"`
+	// サムネイルのデータサイズ（バイト）
+	ByteSize int64 `json:"byte_size" example:"18234"`
+	// 作成日時
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 描画したサムネイル構造体：保存前のサムネイルのデータを表現
+type RenderedThumbnail struct {
+	// サムネイルのサイズ（長辺のピクセル数）
+	Size int
+	// サムネイルのMIMEタイプ
+	ContentType string
+	// サムネイルのデータ
+	Data []byte
+}
+
+// サムネイルを作成できる画像のMIMEタイプ
+var thumbnailContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// 指定されたMIMEタイプのファイルからサムネイルを作成できるかどうかを判定する
+func HasThumbnail(contentType string) bool {
+	return thumbnailContentTypes[contentType]
+}
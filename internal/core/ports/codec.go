@@ -0,0 +1,13 @@
+package ports
+
+import "github.com/OICjangirrahul/students/internal/core/domain"
+
+// 問題パッケージコーデックインターフェース：QTIパッケージと問題の相互変換を定義
+//
+//go:generate mockery --name=QuestionPackageCodec --output=mocks --outpkg=mocks --case=snake
+type QuestionPackageCodec interface {
+	// QTIパッケージ（zip）を解析し、問題と未対応のアイテムを返す
+	Decode(data []byte) (*domain.QTIPackage, error)
+	// 問題バンクの項目をQTIパッケージ（zip）に変換する
+	Encode(items []domain.QuestionBankItem) ([]byte, error)
+}
@@ -166,6 +166,64 @@ func (_m *TeacherRepository) GetTeacherByID(id int64) (*domain.Teacher, error) {
 	return r0, r1
 }
 
+// GetTeachersByStudentID provides a mock function with given fields: studentID
+func (_m *TeacherRepository) GetTeachersByStudentID(studentID int64) ([]domain.Teacher, error) {
+	ret := _m.Called(studentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTeachersByStudentID")
+	}
+
+	var r0 []domain.Teacher
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]domain.Teacher, error)); ok {
+		return rf(studentID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []domain.Teacher); ok {
+		r0 = rf(studentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Teacher)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(studentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsStudentAssigned provides a mock function with given fields: teacherID, studentID
+func (_m *TeacherRepository) IsStudentAssigned(teacherID int64, studentID int64) (bool, error) {
+	ret := _m.Called(teacherID, studentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsStudentAssigned")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64) (bool, error)); ok {
+		return rf(teacherID, studentID)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64) bool); ok {
+		r0 = rf(teacherID, studentID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(teacherID, studentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // LoginTeacher provides a mock function with given fields: email, password
 func (_m *TeacherRepository) LoginTeacher(email string, password string) (string, error) {
 	ret := _m.Called(email, password)
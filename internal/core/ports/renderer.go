@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"io"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+)
+
+// 通知表レンダラーインターフェース：通知表を印刷可能な文書に変換する操作を定義
+//
+//go:generate mockery --name=ReportCardRenderer --output=mocks --outpkg=mocks --case=snake
+type ReportCardRenderer interface {
+	// 通知表をPDFに変換し、そのバイナリデータを返す
+	Render(card *domain.ReportCard) ([]byte, error)
+}
+
+// サムネイルレンダラーインターフェース：画像を縮小したサムネイルの作成を定義
+//
+//go:generate mockery --name=ThumbnailRenderer --output=mocks --outpkg=mocks --case=snake
+type ThumbnailRenderer interface {
+	// 画像を読み込み、指定されたサイズ（長辺のピクセル数）ごとのサムネイルを返す
+	// 画像として読み込めない場合はErrUnsupportedMediaを返す
+	Render(source io.Reader, sizes []int) ([]domain.RenderedThumbnail, error)
+}
@@ -1,6 +1,10 @@
 package ports
 
-import "github.com/OICjangirrahul/students/internal/core/domain"
+import (
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+)
 
 // 学生リポジトリインターフェース：学生データの永続化操作を定義
 //
@@ -36,4 +40,224 @@ type TeacherRepository interface {
 	GetStudentsByTeacherID(teacherID int64) ([]domain.Student, error)
 	// 教師のログイン認証を行い、JWTトークンを返す
 	LoginTeacher(email, password string) (string, error)
+	// 学生が教師に割り当てられているかを確認する
+	IsStudentAssigned(teacherID, studentID int64) (bool, error)
+	// 学生を担当している教師一覧を取得する
+	GetTeachersByStudentID(studentID int64) ([]domain.Teacher, error)
+}
+
+// 課題リポジトリインターフェース：課題と提出物の永続化操作を定義
+//
+//go:generate mockery --name=AssignmentRepository --output=mocks --outpkg=mocks --case=snake
+type AssignmentRepository interface {
+	// 新しい課題を作成し、作成された課題のIDを返す
+	CreateAssignment(assignment *domain.Assignment) (int64, error)
+	// 指定されたIDの課題を添付ファイルと共に取得する
+	GetAssignmentByID(id int64) (*domain.Assignment, error)
+	// 教師が作成した課題一覧を取得する
+	GetAssignmentsByTeacherID(teacherID int64) ([]domain.Assignment, error)
+	// 学生の担当教師が作成した課題一覧を取得する
+	GetAssignmentsByStudentID(studentID int64) ([]domain.Assignment, error)
+	// 課題にファイルを添付する
+	AddAssignmentFile(assignmentID int64, fileID string) error
+	// 学生の課題提出物を取得する（存在しない場合はErrNotFound）
+	GetSubmission(assignmentID, studentID int64) (*domain.Submission, error)
+	// 提出物を保存し、submissionのID、提出回数、作成日時を保存した値に書き換える
+	// 同じ学生・課題の提出物があれば提出回数を1増やして置き換え、置き換えた以前の提出ファイルのIDを返す
+	SaveSubmission(submission *domain.Submission) ([]string, error)
+	// 課題の提出物一覧を取得する
+	GetSubmissionsByAssignmentID(assignmentID int64) ([]domain.Submission, error)
+}
+
+// 成績簿リポジトリインターフェース：成績カテゴリ、成績記録、評定基準の永続化操作を定義
+//
+//go:generate mockery --name=GradebookRepository --output=mocks --outpkg=mocks --case=snake
+type GradebookRepository interface {
+	// 新しい成績カテゴリを作成し、作成されたカテゴリのIDを返す
+	CreateCategory(category *domain.GradeCategory) (int64, error)
+	// 指定されたIDの成績カテゴリを取得する
+	GetCategoryByID(id int64) (*domain.GradeCategory, error)
+	// 教師の成績カテゴリ一覧を取得する
+	GetCategoriesByTeacherID(teacherID int64) ([]domain.GradeCategory, error)
+	// 成績記録を保存する（同じ学生・課題の成績があれば上書きする）
+	SaveGradeEntry(entry *domain.GradeEntry) (int64, error)
+	// 教師が記録した成績一覧を取得する
+	GetGradeEntriesByTeacherID(teacherID int64) ([]domain.GradeEntry, error)
+	// 学生の成績一覧を取得する
+	GetGradeEntriesByStudentID(studentID int64) ([]domain.GradeEntry, error)
+	// 教師の評定基準を取得する（未設定の場合は空）
+	GetGradeScale(teacherID int64) ([]domain.GradeThreshold, error)
+	// 教師の評定基準を置き換える
+	SaveGradeScale(teacherID int64, scale []domain.GradeThreshold) error
+}
+
+// 通知表リポジトリインターフェース：通知表の所見と生成済みPDFの永続化操作を定義
+//
+//go:generate mockery --name=ReportCardRepository --output=mocks --outpkg=mocks --case=snake
+type ReportCardRepository interface {
+	// 所見を保存する（同じ教師・学生・学期の所見があれば上書きする）
+	SaveRemark(remark *domain.ReportCardRemark) (int64, error)
+	// 学生の指定学期の所見一覧を取得する
+	GetRemarksByStudentID(studentID int64, term string) ([]domain.ReportCardRemark, error)
+	// 生成済み通知表を保存する（同じ学生・学期の通知表があれば置き換える）
+	SaveReportCardFile(card *domain.ReportCardFile) (int64, error)
+	// 学生の指定学期の生成済み通知表を取得する（存在しない場合はErrNotFound）
+	GetReportCardFile(studentID int64, term string) (*domain.ReportCardFile, error)
+	// 指定されたIDの生成済み通知表を取得する
+	GetReportCardFileByID(id int64) (*domain.ReportCardFile, error)
+	// 教師の担当学生の生成済み通知表一覧を取得する（学期が空の場合は全学期）
+	GetReportCardFilesByTeacherID(teacherID int64, term string) ([]domain.ReportCardFile, error)
+	// 学生の生成済み通知表一覧を取得する
+	GetReportCardFilesByStudentID(studentID int64) ([]domain.ReportCardFile, error)
+}
+
+// 小テスト受験リポジトリインターフェース：小テストの受験記録の永続化操作を定義
+//
+//go:generate mockery --name=QuizRepository --output=mocks --outpkg=mocks --case=snake
+type QuizRepository interface {
+	// 新しい受験記録を作成し、作成された受験のIDを返す
+	CreateAttempt(attempt *domain.QuizAttempt) (int64, error)
+	// 指定されたIDの受験記録を取得する
+	GetAttemptByID(id int64) (*domain.QuizAttempt, error)
+	// 学生の小テストの受験記録一覧を回数順に取得する
+	GetAttemptsByQuizAndStudent(quizID string, studentID int64) ([]domain.QuizAttempt, error)
+	// 小テストの全学生の受験記録一覧を取得する
+	GetAttemptsByQuizID(quizID string) ([]domain.QuizAttempt, error)
+	// 受験中の受験記録の状態、回答、採点結果を更新する（受験中でない場合はErrConflictを返す）
+	UpdateAttempt(attempt *domain.QuizAttempt) error
+}
+
+// ファイルリポジトリインターフェース：アップロードされたファイルのメタデータ（ファイルカタログ）の永続化操作を定義
+//
+//go:generate mockery --name=FileRepository --output=mocks --outpkg=mocks --case=snake
+type FileRepository interface {
+	// ファイルのメタデータを最初のバージョンとして登録し、アップロードしたユーザーの使用量に加算する
+	// 加算後の使用量がクォータを超える場合は登録せずErrQuotaExceededを返す（同時に登録しても上限を超えない）
+	// 同じチェックサムの内容が既に保存されている場合は既存のオブジェクトを参照し、file.ObjectKeyをそのキーに書き換える
+	CreateFile(file *domain.File, quota domain.StorageQuota) error
+	// 直接アップロードされたオブジェクトをCreateFileと同様に登録し、file.ObjectKeyを完了したアップロードとして記録する
+	// 同じアップロードが既に登録されている場合はErrAlreadyExistsを返す（同時に完了しても1回だけ登録される）
+	CreateUploadedFile(file *domain.File, quota domain.StorageQuota) error
+	// 指定されたIDのファイルのメタデータを取得する（ゴミ箱にあるファイルは除く）
+	GetFileByID(id string) (*domain.File, error)
+	// 指定されたオブジェクトキーを参照しているファイルのメタデータを取得する
+	GetFileByObjectKey(objectKey string) (*domain.File, error)
+	// 条件に一致するファイルのメタデータ一覧をアップロード日時の新しい順に取得する（ゴミ箱にあるファイルは除く）
+	ListFiles(filter domain.FileFilter) ([]domain.File, error)
+	// 指定されたIDのファイルを指定されたフォルダへ移動する（folderIDがnilの場合はどのフォルダにも属さない）
+	// カタログのみを更新し、ファイルの実データは変わらない
+	MoveFile(id string, folderID *string) error
+	// 指定されたIDのファイルのタグを置き換える
+	SetFileTags(id string, tags []string) error
+	// 指定されたIDのファイルをゴミ箱に移動する
+	TrashFile(id string, at time.Time) error
+	// ゴミ箱にある指定されたIDのファイルを元に戻す
+	RestoreFile(id string) error
+	// ゴミ箱にある指定されたIDのファイルのメタデータを取得する
+	GetTrashedFileByID(id string) (*domain.File, error)
+	// ゴミ箱にあるファイルの一覧を、ゴミ箱に移動した日時の新しい順に取得する
+	ListTrashedFiles() ([]domain.File, error)
+	// アップロードしたユーザーのゴミ箱にあるファイルの一覧を、ゴミ箱に移動した日時の新しい順に取得する
+	ListTrashedFilesByUploader(uploaderRole string, uploaderID int64) ([]domain.File, error)
+	// 既存のファイル（file.ID）に新しいバージョンを追加し、ファイルの所有者の使用量に加算する
+	// retainを超える古いバージョンは削除し（0の場合は削除しない）、参照がなくなったオブジェクトのキーを返す
+	// file.ObjectKeyとfile.Versionは登録されたバージョンの値に書き換える
+	AddFileVersion(file *domain.File, quota domain.StorageQuota, retain int) ([]string, error)
+	// 指定されたIDのファイルのバージョン一覧を新しい順に取得する
+	ListFileVersions(id string) ([]domain.File, error)
+	// 指定されたIDのファイルの指定されたバージョンを取得する
+	GetFileVersion(id string, version int) (*domain.File, error)
+	// 指定されたIDのファイルのメタデータをすべてのバージョンとともに削除し、所有者の使用量から減算する（ゴミ箱にあるファイルも削除できる）
+	// 参照するファイルがなくなったオブジェクトのキーを返す
+	DeleteFile(id string) ([]string, error)
+	// ゴミ箱に移動した日時がtrashedBefore以前のファイルをDeleteFileと同様に削除し、参照がなくなったオブジェクトのキーを返す
+	// ファイルがゴミ箱にない、または移動した日時がtrashedBeforeより後の場合は削除せずErrNotFoundを返す
+	PurgeTrashedFile(id string, trashedBefore time.Time) ([]string, error)
+	// アップロードしたユーザーのストレージ使用量を取得する（ファイルがない場合は0）
+	GetStorageUsage(uploaderRole string, uploaderID int64) (*domain.StorageUsage, error)
+	// ストレージ使用量の多いユーザーを使用量の多い順に取得する
+	ListTopStorageUsage(limit int) ([]domain.StorageUsage, error)
+	// マルウェア検査を待っている内容を古い順に取得する
+	ListPendingScans(limit int) ([]domain.ScanTarget, error)
+	// 内容の検査結果を記録し、その内容を現在のバージョンとして参照しているファイルの一覧を返す
+	// 既に検査結果が記録されている場合は記録せず、空の一覧を返す
+	SetScanResult(checksum string, result domain.ScanResult, at time.Time) ([]domain.File, error)
+	// マルウェアを検出して隔離したファイルの一覧を取得する（ゴミ箱にあるファイルは除く）
+	ListQuarantinedFiles() ([]domain.File, error)
+}
+
+// ファイル共有リポジトリインターフェース：ファイルの共有の永続化操作を定義
+//
+//go:generate mockery --name=FileShareRepository --output=mocks --outpkg=mocks --case=snake
+type FileShareRepository interface {
+	// 新しい共有を作成し、作成された共有のIDを返す
+	CreateFileShare(share *domain.FileShare) (int64, error)
+	// ファイルの共有一覧を作成日時順に取得する
+	GetFileSharesByFileID(fileID string) ([]domain.FileShare, error)
+	// トークンのハッシュに一致するリンクの共有を取得する
+	GetFileShareByTokenHash(tokenHash string) (*domain.FileShare, error)
+	// ファイルの指定されたIDの共有を削除する
+	DeleteFileShare(fileID string, id int64) error
+	// 指定された日時に有効な共有により学生が閲覧できるファイルの一覧を、アップロード日時の新しい順に取得する（ゴミ箱にあるファイルは除く）
+	GetFilesSharedWithStudent(studentID int64, at time.Time) ([]domain.File, error)
+	// 指定された日時に有効な共有によりファイルを学生が閲覧できるかどうかを判定する
+	IsFileSharedWithStudent(fileID string, studentID int64, at time.Time) (bool, error)
+	// ファイルが学生の担当教師の課題に添付されているかどうかを判定する
+	IsAssignmentFileForStudent(assignmentID int64, fileID string, studentID int64) (bool, error)
+	// ファイルが教師の課題への提出物に含まれているかどうかを判定する
+	IsFileSubmittedToTeacher(fileID string, teacherID int64) (bool, error)
+}
+
+// サムネイルリポジトリインターフェース：画像ファイルから作成したサムネイルの永続化操作を定義
+//
+//go:generate mockery --name=ThumbnailRepository --output=mocks --outpkg=mocks --case=snake
+type ThumbnailRepository interface {
+	// 指定されたチェックサムの内容から作成したサムネイルの一覧をサイズ順に取得する
+	GetThumbnailsByChecksum(checksum string) ([]domain.Thumbnail, error)
+	// サムネイルを登録する
+	// 同じチェックサムとサイズのサムネイルが既にある場合や、内容が既に削除されている場合は登録せずfalseを返す
+	SaveThumbnail(thumbnail *domain.Thumbnail) (bool, error)
+}
+
+// 通知リポジトリインターフェース：ユーザーへのアプリ内の通知の永続化操作を定義
+//
+//go:generate mockery --name=NotificationRepository --output=mocks --outpkg=mocks --case=snake
+type NotificationRepository interface {
+	// 新しい通知を作成する
+	CreateNotification(notification *domain.Notification) error
+	// ユーザーへの通知を新しい順に最大limit件取得する
+	GetNotificationsByRecipient(role string, recipientID int64, limit int) ([]domain.Notification, error)
+}
+
+// ドキュメントスキーマリポジトリインターフェース：ドキュメントの種類ごとのJSON Schemaの永続化操作を定義
+//
+//go:generate mockery --name=DocumentSchemaRepository --output=mocks --outpkg=mocks --case=snake
+type DocumentSchemaRepository interface {
+	// スキーマを種類の次のバージョンとして登録し、採番したバージョンを設定する
+	CreateDocumentSchema(schema *domain.DocumentSchema) error
+	// 種類の最新のバージョンのスキーマを取得する（登録されていない場合はErrNotFound）
+	GetLatestDocumentSchema(docType string) (*domain.DocumentSchema, error)
+	// 種類の指定されたバージョンのスキーマを取得する
+	GetDocumentSchema(docType string, version int) (*domain.DocumentSchema, error)
+	// 種類のスキーマのすべてのバージョンを新しい順に取得する
+	ListDocumentSchemaVersions(docType string) ([]domain.DocumentSchema, error)
+	// 種類ごとの最新のバージョンのスキーマを種類の順に取得する
+	ListLatestDocumentSchemas() ([]domain.DocumentSchema, error)
+}
+
+// フォルダリポジトリインターフェース：ファイルを整理するフォルダの永続化操作を定義
+//
+//go:generate mockery --name=FolderRepository --output=mocks --outpkg=mocks --case=snake
+type FolderRepository interface {
+	// 新しいフォルダを作成する
+	CreateFolder(folder *domain.Folder) error
+	// 指定されたIDのフォルダを取得する
+	GetFolderByID(id string) (*domain.Folder, error)
+	// 教師が所有するフォルダの一覧を名前順に取得する
+	GetFoldersByOwnerID(ownerID int64) ([]domain.Folder, error)
+	// フォルダの名前と親フォルダを更新する
+	UpdateFolder(folder *domain.Folder) error
+	// 指定されたIDのフォルダを中のフォルダとともに削除する
+	// 削除するフォルダに属するファイルはtrashedAtの日時でゴミ箱に移動し、ゴミ箱に移動したファイル数を返す
+	DeleteFolder(id string, trashedAt time.Time) (int64, error)
 }
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+)
+
+// コンテンツスキャナーインターフェース：ファイルの内容のマルウェア検査を定義
+//
+//go:generate mockery --name=ContentScanner --output=mocks --outpkg=mocks --case=snake
+type ContentScanner interface {
+	// 内容を最後まで読み込んで検査し、結果を返す
+	// スキャナーに接続できない場合などはエラーを返す（検査結果として扱わない）
+	Scan(ctx context.Context, content io.Reader) (*domain.ScanResult, error)
+}
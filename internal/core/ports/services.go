@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"io"
 
 	"github.com/OICjangirrahul/students/internal/core/domain"
 )
@@ -33,3 +34,233 @@ type TeacherService interface {
 	// 教師に割り当てられた学生一覧を取得する
 	GetStudents(ctx context.Context, teacherID int64) ([]domain.Student, error)
 }
+
+// 課題サービスインターフェース：課題と提出に関する業務ロジックを定義
+type AssignmentService interface {
+	// 教師の新しい課題を作成する
+	Create(ctx context.Context, teacherID int64, input *domain.AssignmentCreate) (*domain.Assignment, error)
+	// 教師が作成した課題一覧を取得する
+	ListByTeacher(ctx context.Context, teacherID int64) ([]domain.Assignment, error)
+	// 学生の担当教師が作成した課題一覧を取得する
+	ListForStudent(ctx context.Context, studentID int64) ([]domain.Assignment, error)
+	// 課題にファイルを添付する
+	AttachFile(ctx context.Context, teacherID, assignmentID int64, file *domain.FileUpload) (*domain.Assignment, error)
+	// 学生が課題を提出する（再提出も含む）
+	Submit(ctx context.Context, studentID, assignmentID int64, input *domain.SubmissionCreate) (*domain.Submission, error)
+	// 課題の提出物一覧を取得する
+	ListSubmissions(ctx context.Context, teacherID, assignmentID int64) ([]domain.Submission, error)
+}
+
+// 成績簿サービスインターフェース：成績の記録と計算に関する業務ロジックを定義
+//
+//go:generate mockery --name=GradebookService --output=mocks --outpkg=mocks --case=snake
+type GradebookService interface {
+	// 教師の成績カテゴリを作成する
+	CreateCategory(ctx context.Context, teacherID int64, input *domain.GradeCategoryCreate) (*domain.GradeCategory, error)
+	// 教師の成績カテゴリ一覧を取得する
+	ListCategories(ctx context.Context, teacherID int64) ([]domain.GradeCategory, error)
+	// 学生の得点を記録する
+	RecordScore(ctx context.Context, teacherID int64, input *domain.GradeEntryInput) (*domain.GradeEntry, error)
+	// 教師の評定基準を設定する
+	SetScale(ctx context.Context, teacherID int64, scale []domain.GradeThreshold) ([]domain.GradeThreshold, error)
+	// 教師の成績簿（担当学生全員の計算済み成績）を取得する
+	GetGradebook(ctx context.Context, teacherID int64) (*domain.Gradebook, error)
+	// 学生の全教師分の計算済み成績を取得する
+	GetStudentGrades(ctx context.Context, studentID int64) ([]domain.StudentGrade, error)
+}
+
+// 通知表サービスインターフェース：通知表の作成とPDF生成に関する業務ロジックを定義
+type ReportCardService interface {
+	// 教師が学生の所見（コメントと出欠）を記入する
+	SaveRemark(ctx context.Context, teacherID int64, input *domain.ReportCardRemarkInput) (*domain.ReportCardRemark, error)
+	// 学生1人分の通知表PDFを生成して保存する
+	Generate(ctx context.Context, teacherID, studentID int64, term string) (*domain.ReportCardFile, error)
+	// 教師の担当学生全員分の通知表PDFを生成して保存する
+	GenerateForClass(ctx context.Context, teacherID int64, term string) (*domain.ReportCardBatch, error)
+	// 教師の担当学生の生成済み通知表一覧を取得する
+	ListByTeacher(ctx context.Context, teacherID int64, term string) ([]domain.ReportCardFile, error)
+	// 学生自身の生成済み通知表一覧を取得する
+	ListForStudent(ctx context.Context, studentID int64) ([]domain.ReportCardFile, error)
+	// 教師が担当学生の通知表PDFをダウンロードする
+	DownloadForTeacher(ctx context.Context, teacherID, reportCardID int64) (*domain.File, io.ReadCloser, error)
+	// 学生が自身の通知表PDFをダウンロードする
+	DownloadForStudent(ctx context.Context, studentID, reportCardID int64) (*domain.File, io.ReadCloser, error)
+}
+
+// 小テストサービスインターフェース：小テストの作成、受験、自動採点に関する業務ロジックを定義
+type QuizService interface {
+	// 教師の小テストを作成する
+	CreateQuiz(ctx context.Context, teacherID int64, input *domain.QuizCreate) (*domain.Quiz, error)
+	// 教師が作成した小テスト一覧を取得する
+	ListQuizzesByTeacher(ctx context.Context, teacherID int64) ([]domain.Quiz, error)
+	// 教師が作成した小テストを正答付きで取得する
+	GetQuiz(ctx context.Context, teacherID int64, quizID string) (*domain.Quiz, error)
+	// 教師の小テストの全学生の受験記録を取得する
+	ListAttempts(ctx context.Context, teacherID int64, quizID string) ([]domain.QuizAttempt, error)
+	// 学生の担当教師が作成した小テスト一覧を取得する
+	ListQuizzesForStudent(ctx context.Context, studentID int64) ([]domain.QuizSummary, error)
+	// 学生が小テストの受験を開始する（受験中の場合はその受験を再開する）
+	StartAttempt(ctx context.Context, studentID int64, quizID string) (*domain.QuizAttemptSession, error)
+	// 学生が回答を提出し、自動採点された受験記録を返す
+	SubmitAttempt(ctx context.Context, studentID int64, quizID string, attemptID int64, answers []domain.QuizAnswer) (*domain.QuizAttempt, error)
+	// 学生自身の小テストの受験記録を取得する
+	ListMyAttempts(ctx context.Context, studentID int64, quizID string) ([]domain.QuizAttempt, error)
+}
+
+// 問題バンクサービスインターフェース：問題バンクの管理とQTIパッケージの取り込み・書き出しに関する業務ロジックを定義
+type QuestionBankService interface {
+	// 教師の問題バンクの項目一覧を取得する
+	ListItems(ctx context.Context, teacherID int64) ([]domain.QuestionBankItem, error)
+	// 教師の問題バンクから項目を削除する
+	DeleteItem(ctx context.Context, teacherID int64, itemID string) error
+	// QTI 2.1パッケージを取り込み、対応している問題を問題バンクに追加する
+	ImportQTI(ctx context.Context, teacherID int64, upload *domain.FileUpload) (*domain.QTIImportResult, error)
+	// 問題バンクの項目をQTI 2.1パッケージとして書き出す（IDが空の場合は全項目）
+	ExportQTI(ctx context.Context, teacherID int64, itemIDs []string) ([]byte, error)
+}
+
+// ファイル転送サービスインターフェース：署名付きURLによるストレージとの直接転送に関する業務ロジックを定義
+type FileTransferService interface {
+	// ストレージへ直接アップロードするための署名付きURLを作成する
+	CreateUploadURL(ctx context.Context, request *domain.UploadURLRequest) (*domain.PresignedUpload, error)
+	// 直接アップロードの完了を受け取り、アップロードされたファイルをカタログに登録する
+	CompleteUpload(ctx context.Context, uploaderID int64, uploaderRole string, uploadID string) (*domain.File, error)
+	// カタログに登録されているファイルを直接ダウンロードするための署名付きURLを作成する
+	CreateDownloadURL(ctx context.Context, fileID string) (*domain.PresignedURL, error)
+}
+
+// アップロードポリシーサービスインターフェース：役割ごとのアップロードポリシーの適用を定義
+type UploadPolicyService interface {
+	// 宣言されたファイル名とサイズがポリシーを満たしているか検証する（サイズが負の場合はサイズを検証しない）
+	CheckDeclared(role string, name string, size int64) error
+	// アップロードにポリシーを適用し、内容から判定したMIMEタイプとサイズ制限付きの本文を持つアップロードを返す
+	Apply(upload *domain.FileUpload) (*domain.FileUpload, error)
+	// 内容の先頭からMIMEタイプを判定し、ポリシーで許可されているか検証する
+	DetectContentType(role string, head []byte) (string, error)
+	// 役割ごとの保存容量の上限を返す
+	Quota(role string) domain.StorageQuota
+	// 現在の使用量に指定されたサイズのファイルを追加してもクォータを超えないか検証する
+	CheckQuota(role string, uploaderID int64, size int64) error
+}
+
+// ストレージ使用量サービスインターフェース：アップロードしたユーザーごとの保存容量の使用状況に関する業務ロジックを定義
+type StorageUsageService interface {
+	// ユーザーのストレージ使用量とクォータを取得する
+	GetUsage(ctx context.Context, uploaderRole string, uploaderID int64) (*domain.StorageUsage, error)
+	// ストレージ使用量の多いユーザーを使用量の多い順に取得する
+	TopConsumers(ctx context.Context, limit int) ([]domain.StorageUsage, error)
+}
+
+// フォルダサービスインターフェース：フォルダとタグによるファイルの整理に関する業務ロジックを定義
+type FolderService interface {
+	// 教師のフォルダを作成する
+	CreateFolder(ctx context.Context, ownerID int64, input *domain.FolderCreate) (*domain.Folder, error)
+	// 教師のフォルダ一覧を取得する
+	ListFolders(ctx context.Context, ownerID int64) ([]domain.Folder, error)
+	// 教師のフォルダの名前を変更する
+	RenameFolder(ctx context.Context, ownerID int64, id string, input *domain.FolderRename) (*domain.Folder, error)
+	// 教師のフォルダを別の親フォルダへ移動する
+	MoveFolder(ctx context.Context, ownerID int64, id string, input *domain.FolderMove) (*domain.Folder, error)
+	// 教師のフォルダを削除する
+	DeleteFolder(ctx context.Context, ownerID int64, id string, input domain.FolderDelete) error
+	// 教師がアップロードしたファイルを別のフォルダへ移動する
+	MoveFile(ctx context.Context, ownerID int64, id string, input *domain.FileMove) (*domain.File, error)
+	// 教師がアップロードしたファイルのタグを置き換える
+	SetFileTags(ctx context.Context, ownerID int64, id string, input *domain.FileTagsUpdate) (*domain.File, error)
+	// 教師がアップロードしたファイル一覧をフォルダとタグで絞り込む
+	ListFiles(ctx context.Context, ownerID int64, filter domain.FileFilter) ([]domain.File, error)
+}
+
+// ファイル共有サービスインターフェース：教師のファイルを学生やリンクで共有する業務ロジックを定義
+type FileSharingService interface {
+	// 教師がアップロードしたファイルを共有する
+	ShareFile(ctx context.Context, teacherID int64, fileID string, input *domain.FileShareCreate) (*domain.FileShare, error)
+	// 教師がアップロードしたファイルの共有一覧を取得する
+	ListShares(ctx context.Context, teacherID int64, fileID string) ([]domain.FileShare, error)
+	// 教師がアップロードしたファイルの共有を取り消す
+	RevokeShare(ctx context.Context, teacherID int64, fileID string, shareID int64) error
+	// 学生と共有されているファイルの一覧を取得する
+	ListSharedWithStudent(ctx context.Context, studentID int64) ([]domain.File, error)
+	// 学生がファイルを閲覧できることを確認する
+	AuthorizeStudent(ctx context.Context, studentID int64, fileID string) error
+	// 学生が課題の添付ファイルを閲覧できることを確認する
+	AuthorizeAssignmentFile(ctx context.Context, studentID, assignmentID int64, fileID string) error
+	// 教師がファイルを閲覧できることを確認する
+	AuthorizeTeacher(ctx context.Context, teacherID int64, fileID string) error
+	// 教師がファイルをアップロードした本人であることを確認する
+	AuthorizeOwner(ctx context.Context, teacherID int64, fileID string) error
+	// 共有リンクのトークンから共有されているファイルのIDを取得する
+	ResolveLink(ctx context.Context, token string) (string, error)
+}
+
+// ファイルアーカイブサービスインターフェース：複数のファイルをZIPにまとめてダウンロードする業務ロジックを定義
+type FileArchiveService interface {
+	// アーカイブに含めるファイルとその配置を決定する（見つからないファイルは一覧に記録する）
+	PrepareArchive(ctx context.Context, teacherID int64, input *domain.FileArchiveRequest) (*domain.FileArchive, error)
+	// アーカイブをZIPとして書き込む（ファイルの内容はストレージから順に読み込んで書き込む）
+	WriteArchive(ctx context.Context, archive *domain.FileArchive, w io.Writer) error
+}
+
+// コンテンツ検査サービスインターフェース：アップロードされた内容のマルウェア検査と隔離に関する業務ロジックを定義
+type ContentScanService interface {
+	// ファイルの内容の検査を予約する（検査済みの内容の場合は検査後の処理のみを行う）
+	Enqueue(file *domain.File)
+	// マルウェアを検出して隔離したファイルの一覧を取得する
+	ListQuarantinedFiles(ctx context.Context) ([]domain.File, error)
+}
+
+// ドキュメントサービスインターフェース：ドキュメントの作成・更新とスキーマによる検証に関する業務ロジックを定義
+type DocumentService interface {
+	// 種類の最新のスキーマで検証してドキュメントを作成する（スキーマがない種類は検証しない）
+	// データがスキーマに適合しない場合はDocumentValidationErrorを返す
+	CreateDocument(ctx context.Context, doc *domain.DocumentCreate) (*domain.Document, error)
+	// ドキュメントの種類の最新のスキーマで検証してドキュメントを更新する
+	// 前提となるバージョンが現在のバージョンと異なる場合はDocumentVersionConflictErrorを返す
+	UpdateDocument(ctx context.Context, id string, update *domain.DocumentUpdate) (*domain.Document, error)
+	// ドキュメントのdataにパッチ（JSON Merge PatchまたはJSON Patch）を適用し、適用後のデータをスキーマで検証する
+	// パッチが不正な場合はErrInvalidInput、testが一致しない場合や対象が存在しない場合はErrConflictを返す
+	PatchDocument(ctx context.Context, id string, patch *domain.DocumentPatch) (*domain.Document, error)
+	// 種類の新しいバージョンのスキーマを登録する
+	RegisterSchema(ctx context.Context, docType string, input *domain.DocumentSchemaCreate, adminID int64) (*domain.DocumentSchema, error)
+	// 種類のスキーマを取得する（バージョンが0の場合は最新のバージョン）
+	GetSchema(ctx context.Context, docType string, version int) (*domain.DocumentSchema, error)
+	// 種類のスキーマのすべてのバージョンを新しい順に取得する
+	ListSchemaVersions(ctx context.Context, docType string) ([]domain.DocumentSchema, error)
+	// 種類ごとの最新のスキーマを取得する
+	ListSchemas(ctx context.Context) ([]domain.DocumentSchema, error)
+}
+
+// 通知サービスインターフェース：ユーザーへのアプリ内の通知に関する業務ロジックを定義
+type NotificationService interface {
+	// ユーザーへの通知を新しい順に取得する
+	ListNotifications(ctx context.Context, role string, userID int64) ([]domain.Notification, error)
+}
+
+// サムネイルサービスインターフェース：画像ファイルのサムネイルの作成と取得に関する業務ロジックを定義
+//
+//go:generate mockery --name=ThumbnailService --output=mocks --outpkg=mocks --case=snake
+type ThumbnailService interface {
+	// ファイルのサムネイルの作成を予約する（画像でないファイルは無視する）
+	Enqueue(file *domain.File)
+	// ファイルの指定されたサイズのサムネイルを取得し、サムネイルの情報と内容を読み込むストリームを返す
+	// サイズが0の場合は最小のサイズを使用する。ストリームは呼び出し側で閉じる必要がある
+	Open(ctx context.Context, fileID string, size int) (*domain.Thumbnail, io.ReadCloser, error)
+}
+
+// ゴミ箱サービスインターフェース：ファイルとドキュメントの論理削除、復元、完全削除に関する業務ロジックを定義
+type TrashService interface {
+	// 教師がアップロードしたファイルをゴミ箱に移動する
+	TrashFile(ctx context.Context, teacherID int64, id string) error
+	// 教師がアップロードしたゴミ箱にあるファイルを元に戻す
+	RestoreFile(ctx context.Context, teacherID int64, id string) error
+	// 教師がアップロードしたゴミ箱にあるファイルの一覧を取得する
+	ListTrashedFiles(ctx context.Context, teacherID int64) ([]domain.File, error)
+	// ドキュメントをゴミ箱に移動する
+	TrashDocument(ctx context.Context, id string) error
+	// ゴミ箱にあるドキュメントを元に戻す
+	RestoreDocument(ctx context.Context, id string) error
+	// ゴミ箱にあるドキュメントの一覧を取得する
+	ListTrashedDocuments(ctx context.Context) ([]domain.Document, error)
+	// 保持期間を過ぎたファイルとドキュメントを完全に削除する
+	PurgeExpired(ctx context.Context) (*domain.TrashPurgeResult, error)
+}
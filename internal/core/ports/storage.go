@@ -2,16 +2,24 @@ package ports
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/OICjangirrahul/students/internal/core/domain"
 )
 
 // ファイルストレージインターフェース：S3を使用したファイル操作を定義
+//
+//go:generate mockery --name=FileStorage --output=mocks --outpkg=mocks --case=snake
 type FileStorage interface {
-	// ファイルをアップロードし、保存されたファイルの情報を返す
+	// ファイルの内容を読み込みながらアップロードし、保存されたファイルの情報を返す
 	Upload(ctx context.Context, file *domain.FileUpload) (*domain.File, error)
-	// 指定されたIDのファイルをダウンロードし、ファイル情報とデータを返す
-	Download(ctx context.Context, id string) (*domain.File, []byte, error)
+	// 指定されたIDのファイルをダウンロードし、ファイル情報と内容を読み込むストリームを返す
+	// ストリームは呼び出し側で閉じる必要がある
+	Download(ctx context.Context, id string) (*domain.File, io.ReadCloser, error)
+	// 指定されたIDのファイルの指定された範囲のみをダウンロードし、ファイル情報と範囲の内容を読み込むストリームを返す
+	// ストリームは呼び出し側で閉じる必要がある
+	DownloadRange(ctx context.Context, id string, byteRange domain.ByteRange) (*domain.File, io.ReadCloser, error)
 	// 指定されたIDのファイルを削除する
 	Delete(ctx context.Context, id string) error
 	// 保存されているファイルの一覧を取得する
@@ -20,16 +28,62 @@ type FileStorage interface {
 	Get(ctx context.Context, id string) (*domain.File, error)
 }
 
+// バージョン管理ファイルストレージインターフェース：同じファイルIDでのバージョンの追加と履歴の操作を定義
+// バージョン管理に対応したファイルストレージのみが実装する任意のインターフェース
+//
+//go:generate mockery --name=VersionedFileStorage --output=mocks --outpkg=mocks --case=snake
+type VersionedFileStorage interface {
+	// 指定されたIDのファイルに新しいバージョンをアップロードし、更新後のファイル情報を返す
+	UploadVersion(ctx context.Context, id string, file *domain.FileUpload) (*domain.File, error)
+	// 指定されたIDのファイルのバージョン一覧を新しい順に取得する
+	ListVersions(ctx context.Context, id string) ([]domain.File, error)
+	// 指定されたIDのファイルの指定されたバージョンをダウンロードし、バージョンの情報と内容を読み込むストリームを返す
+	// ストリームは呼び出し側で閉じる必要がある
+	DownloadVersion(ctx context.Context, id string, version int) (*domain.File, io.ReadCloser, error)
+	// 指定されたバージョンの内容を新しいバージョンとして復元し、更新後のファイル情報を返す
+	RestoreVersion(ctx context.Context, id string, version int) (*domain.File, error)
+}
+
+// URL署名インターフェース：ストレージへ直接転送するための署名付きURLの作成を定義
+// 署名付きURLに対応したファイルストレージのみが実装する任意のインターフェース
+//
+//go:generate mockery --name=URLSigner --output=mocks --outpkg=mocks --case=snake
+type URLSigner interface {
+	// 新しいオブジェクトキーへ直接アップロードするための署名付きURLを作成する
+	// MIMEタイプ、サイズ、アップロードしたユーザーは署名に含め、異なる内容のアップロードは拒否される
+	PresignUpload(ctx context.Context, request *domain.UploadURLRequest, expires time.Duration) (*domain.PresignedUpload, error)
+	// 指定されたオブジェクトキーを直接ダウンロードするための署名付きURLを作成する
+	PresignDownload(ctx context.Context, id string, fileName string, expires time.Duration) (*domain.PresignedURL, error)
+}
+
 // ドキュメントストレージインターフェース：DynamoDBを使用したドキュメント操作を定義
+//
+//go:generate mockery --name=DocumentStorage --output=mocks --outpkg=mocks --case=snake
 type DocumentStorage interface {
 	// 新しいドキュメントを作成し、作成されたドキュメントを返す
 	Create(ctx context.Context, doc *domain.DocumentCreate) (*domain.Document, error)
-	// 指定されたIDのドキュメントを取得する
+	// 指定されたIDのドキュメントを取得する（ゴミ箱にあるドキュメントは除く）
 	Get(ctx context.Context, id string) (*domain.Document, error)
-	// 指定されたIDのドキュメントを更新し、更新後のドキュメントを返す
+	// 指定されたIDのドキュメントを更新し、バージョンを1増やして更新後のドキュメントを返す
+	// 前提となるバージョンが指定され、現在のバージョンと異なる場合はDocumentVersionConflictErrorを返す
 	Update(ctx context.Context, id string, update *domain.DocumentUpdate) (*domain.Document, error)
-	// 指定されたIDのドキュメントを削除する
+	// 指定されたIDのドキュメントのdataにパッチを適用し、バージョンを1増やして更新後のドキュメントを返す
+	// testが一致しない場合や対象が存在しない場合はErrConflict、バージョンが異なる場合はDocumentVersionConflictErrorを返す
+	Patch(ctx context.Context, id string, patch *domain.DocumentPatch) (*domain.Document, error)
+	// 指定されたIDのドキュメントを完全に削除する（ゴミ箱にあるドキュメントも削除できる）
 	Delete(ctx context.Context, id string) error
-	// 指定されたタイプのドキュメント一覧を取得する
+	// 指定されたタイプのすべてのドキュメントを作成日時の古い順に取得する（ゴミ箱にあるドキュメントは除く）
 	List(ctx context.Context, docType string) ([]domain.Document, error)
+	// 検索条件に一致するドキュメントを作成日時順に1ページ分取得する（ゴミ箱にあるドキュメントは除く）
+	// カーソルが不正な場合はErrInvalidInputを返す
+	Query(ctx context.Context, query *domain.DocumentQuery) (*domain.DocumentPage, error)
+	// 指定されたIDのドキュメントをゴミ箱に移動する
+	Trash(ctx context.Context, id string, at time.Time) error
+	// ゴミ箱にある指定されたIDのドキュメントを元に戻す
+	Restore(ctx context.Context, id string) error
+	// ゴミ箱にあるドキュメントの一覧を取得する
+	ListTrashed(ctx context.Context) ([]domain.Document, error)
+	// ゴミ箱に移動した日時がdeletedAtのままのドキュメントを完全に削除する
+	// 元に戻された、またはゴミ箱に移動し直されたドキュメントは削除せずErrNotFoundを返す
+	PurgeTrashed(ctx context.Context, id string, deletedAt time.Time) error
 }
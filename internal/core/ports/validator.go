@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"encoding/json"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+)
+
+// スキーマバリデーターインターフェース：JSON Schemaによるドキュメントのデータの検証を定義
+//
+//go:generate mockery --name=SchemaValidator --output=mocks --outpkg=mocks --case=snake
+type SchemaValidator interface {
+	// JSON Schema（draft 2020-12）として使用できるか確認する（使用できない場合はErrInvalidInput）
+	Check(schema json.RawMessage) error
+	// データをスキーマで検証し、適合しなかった項目を返す（適合する場合は空）
+	Validate(schema *domain.DocumentSchema, data map[string]interface{}) ([]domain.FieldError, error)
+}
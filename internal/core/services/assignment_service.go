@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+)
+
+// 課題サービス構造体：課題と提出に関する業務ロジックを実装
+type AssignmentService struct {
+	// 課題リポジトリインターフェース
+	repo ports.AssignmentRepository
+	// 教師リポジトリインターフェース（担当関係の確認に使用）
+	teacherRepo ports.TeacherRepository
+	// ファイルストレージインターフェース（添付・提出ファイルの保存に使用）
+	fileStorage ports.FileStorage
+	// 現在時刻を返す関数（テストで差し替え可能）
+	now func() time.Time
+}
+
+// 新しい課題サービスインスタンスを作成する
+func NewAssignmentService(repo ports.AssignmentRepository, teacherRepo ports.TeacherRepository, fileStorage ports.FileStorage) *AssignmentService {
+	return &AssignmentService{
+		repo:        repo,
+		teacherRepo: teacherRepo,
+		fileStorage: fileStorage,
+		now:         time.Now,
+	}
+}
+
+// 教師の新しい課題を作成する
+func (s *AssignmentService) Create(ctx context.Context, teacherID int64, input *domain.AssignmentCreate) (*domain.Assignment, error) {
+	// 課題を作成
+	id, err := s.repo.CreateAssignment(&domain.Assignment{
+		TeacherID:    teacherID,
+		Title:        input.Title,
+		Instructions: input.Instructions,
+		DueDate:      input.DueDate,
+		MaxPoints:    input.MaxPoints,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// タイムスタンプを含む完全な課題情報を取得
+	return s.repo.GetAssignmentByID(id)
+}
+
+// 教師が作成した課題一覧を取得する
+func (s *AssignmentService) ListByTeacher(ctx context.Context, teacherID int64) ([]domain.Assignment, error) {
+	return s.repo.GetAssignmentsByTeacherID(teacherID)
+}
+
+// 学生の担当教師が作成した課題一覧を取得する
+func (s *AssignmentService) ListForStudent(ctx context.Context, studentID int64) ([]domain.Assignment, error) {
+	return s.repo.GetAssignmentsByStudentID(studentID)
+}
+
+// 課題にファイルを添付する
+// 課題を作成した教師のみが添付でき、添付に失敗した場合は保存したファイルを削除する
+func (s *AssignmentService) AttachFile(ctx context.Context, teacherID, assignmentID int64, file *domain.FileUpload) (*domain.Assignment, error) {
+	// 課題の所有者を確認
+	if _, err := s.getOwnedAssignment(teacherID, assignmentID); err != nil {
+		return nil, err
+	}
+
+	// ファイルをストレージに保存
+	file.UploaderID = teacherID
+	file.UploaderRole = domain.RoleTeacher
+	stored, err := s.fileStorage.Upload(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	// 課題にファイルを関連付け（失敗した場合は保存したファイルを削除する）
+	if err := s.repo.AddAssignmentFile(assignmentID, stored.ID); err != nil {
+		s.deleteFiles(ctx, []string{stored.ID})
+		return nil, err
+	}
+
+	return s.repo.GetAssignmentByID(assignmentID)
+}
+
+// 学生が課題を提出する
+// 担当教師の課題にのみ提出でき、期限後の提出と再提出を記録する
+// 再提出では以前の提出ファイルを削除し、提出に失敗した場合はこの提出で保存したファイルを削除する
+func (s *AssignmentService) Submit(ctx context.Context, studentID, assignmentID int64, input *domain.SubmissionCreate) (*domain.Submission, error) {
+	if input.Text == "" && len(input.Files) == 0 {
+		return nil, fmt.Errorf("%w: submission requires text or at least one file", domain.ErrInvalidInput)
+	}
+
+	// 課題を取得
+	assignment, err := s.repo.GetAssignmentByID(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 学生が課題の教師に割り当てられているかを確認
+	assigned, err := s.teacherRepo.IsStudentAssigned(assignment.TeacherID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if !assigned {
+		return nil, fmt.Errorf("%w: student %d is not assigned to the teacher of assignment %d", domain.ErrForbidden, studentID, assignmentID)
+	}
+
+	// 提出ファイルをストレージに保存
+	fileIDs := make([]string, 0, len(input.Files))
+	for _, file := range input.Files {
+		file.UploaderID = studentID
+		file.UploaderRole = domain.RoleStudent
+		stored, err := s.fileStorage.Upload(ctx, file)
+		if err != nil {
+			s.deleteFiles(ctx, fileIDs)
+			return nil, err
+		}
+		fileIDs = append(fileIDs, stored.ID)
+	}
+
+	now := s.now()
+	submission := &domain.Submission{
+		AssignmentID: assignmentID,
+		StudentID:    studentID,
+		Text:         input.Text,
+		FileIDs:      fileIDs,
+		Late:         now.After(assignment.DueDate),
+		SubmittedAt:  now,
+	}
+
+	// 提出物を保存（再提出の場合は提出回数をリポジトリで加算する）
+	replaced, err := s.repo.SaveSubmission(submission)
+	if err != nil {
+		s.deleteFiles(ctx, fileIDs)
+		return nil, err
+	}
+
+	// 再提出で置き換えられた以前の提出ファイルを削除
+	// 同時に提出された場合も、置き換えられたファイルは置き換えた提出だけが受け取るため一度だけ削除される
+	s.deleteFiles(ctx, replaced)
+
+	return s.repo.GetSubmission(assignmentID, studentID)
+}
+
+// 課題の提出物一覧を取得する
+// 課題を作成した教師のみが参照できる
+func (s *AssignmentService) ListSubmissions(ctx context.Context, teacherID, assignmentID int64) ([]domain.Submission, error) {
+	if _, err := s.getOwnedAssignment(teacherID, assignmentID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetSubmissionsByAssignmentID(assignmentID)
+}
+
+// 提出物から参照されていないファイルをストレージから削除する
+// 削除に失敗しても提出の結果は変わらないため、ログに記録して残りの削除を続ける
+// リクエストが中断された場合も削除できるよう、キャンセルを引き継がないコンテキストを使用する
+func (s *AssignmentService) deleteFiles(ctx context.Context, ids []string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, id := range ids {
+		if err := s.fileStorage.Delete(ctx, id); err != nil {
+			slog.Error("failed to delete submission file", slog.String("file_id", id), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// 教師が所有する課題を取得する
+// 課題が他の教師のものである場合はErrForbiddenを返す
+func (s *AssignmentService) getOwnedAssignment(teacherID, assignmentID int64) (*domain.Assignment, error) {
+	assignment, err := s.repo.GetAssignmentByID(assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment.TeacherID != teacherID {
+		return nil, fmt.Errorf("%w: assignment %d does not belong to teacher %d", domain.ErrForbidden, assignmentID, teacherID)
+	}
+
+	return assignment, nil
+}
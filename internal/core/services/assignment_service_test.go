@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestAssignmentService(now time.Time) (*AssignmentService, *mocks.AssignmentRepository, *mocks.TeacherRepository, *mocks.FileStorage) {
+	repo := new(mocks.AssignmentRepository)
+	teacherRepo := new(mocks.TeacherRepository)
+	fileStorage := new(mocks.FileStorage)
+	service := NewAssignmentService(repo, teacherRepo, fileStorage)
+	service.now = func() time.Time { return now }
+	return service, repo, teacherRepo, fileStorage
+}
+
+func TestAssignmentService_Create(t *testing.T) {
+	// Setup
+	service, repo, _, _ := newTestAssignmentService(time.Now())
+	ctx := context.Background()
+
+	due := time.Date(2024, 4, 1, 23, 59, 0, 0, time.UTC)
+	input := &domain.AssignmentCreate{
+		Title:     "Chapter 3",
+		DueDate:   due,
+		MaxPoints: 100,
+	}
+	expected := &domain.Assignment{ID: 1, TeacherID: 7, Title: "Chapter 3", DueDate: due, MaxPoints: 100}
+
+	// Mock expectations
+	repo.On("CreateAssignment", mock.MatchedBy(func(a *domain.Assignment) bool {
+		return a.TeacherID == 7 && a.Title == "Chapter 3" && a.DueDate.Equal(due)
+	})).Return(int64(1), nil)
+	repo.On("GetAssignmentByID", int64(1)).Return(expected, nil)
+
+	// Test
+	result, err := service.Create(ctx, 7, input)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	repo.AssertExpectations(t)
+}
+
+func TestAssignmentService_AttachFile_NotOwner(t *testing.T) {
+	// Setup
+	service, repo, _, fileStorage := newTestAssignmentService(time.Now())
+	ctx := context.Background()
+
+	// Mock expectations
+	repo.On("GetAssignmentByID", int64(1)).Return(&domain.Assignment{ID: 1, TeacherID: 2}, nil)
+
+	// Test
+	_, err := service.AttachFile(ctx, 3, 1, &domain.FileUpload{Name: "sheet.pdf"})
+
+	// Assertions
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	fileStorage.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
+
+func TestAssignmentService_AttachFile_LinkFailure(t *testing.T) {
+	// Setup
+	service, repo, _, fileStorage := newTestAssignmentService(time.Now())
+	ctx := context.Background()
+	upload := &domain.FileUpload{Name: "sheet.pdf"}
+
+	// Mock expectations
+	repo.On("GetAssignmentByID", int64(1)).Return(&domain.Assignment{ID: 1, TeacherID: 3}, nil)
+	fileStorage.On("Upload", ctx, upload).Return(&domain.File{ID: "file-1"}, nil)
+	repo.On("AddAssignmentFile", int64(1), "file-1").Return(errors.New("connection reset"))
+	fileStorage.On("Delete", mock.Anything, "file-1").Return(nil)
+
+	// Test
+	_, err := service.AttachFile(ctx, 3, 1, upload)
+
+	// Assertions
+	assert.Error(t, err)
+	fileStorage.AssertExpectations(t)
+}
+
+func TestAssignmentService_Submit_OnTime(t *testing.T) {
+	// Setup
+	now := time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC)
+	service, repo, teacherRepo, fileStorage := newTestAssignmentService(now)
+	ctx := context.Background()
+
+	assignment := &domain.Assignment{ID: 1, TeacherID: 2, DueDate: now.Add(24 * time.Hour)}
+	upload := &domain.FileUpload{Name: "answer.pdf"}
+	saved := &domain.Submission{ID: 5, AssignmentID: 1, StudentID: 9, Attempt: 1, FileIDs: []string{"file-1"}}
+
+	// Mock expectations
+	repo.On("GetAssignmentByID", int64(1)).Return(assignment, nil)
+	teacherRepo.On("IsStudentAssigned", int64(2), int64(9)).Return(true, nil)
+	fileStorage.On("Upload", ctx, upload).Return(&domain.File{ID: "file-1"}, nil)
+	repo.On("SaveSubmission", mock.MatchedBy(func(s *domain.Submission) bool {
+		return !s.Late && s.SubmittedAt.Equal(now) && len(s.FileIDs) == 1 && s.FileIDs[0] == "file-1"
+	})).Return(nil, nil)
+	repo.On("GetSubmission", int64(1), int64(9)).Return(saved, nil)
+
+	// Test
+	result, err := service.Submit(ctx, 9, 1, &domain.SubmissionCreate{Files: []*domain.FileUpload{upload}})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, saved, result)
+	assert.Equal(t, int64(9), upload.UploaderID)
+	assert.Equal(t, domain.RoleStudent, upload.UploaderRole)
+	repo.AssertExpectations(t)
+	teacherRepo.AssertExpectations(t)
+	fileStorage.AssertExpectations(t)
+}
+
+func TestAssignmentService_Submit_LateResubmission(t *testing.T) {
+	// Setup
+	now := time.Date(2024, 4, 2, 9, 0, 0, 0, time.UTC)
+	service, repo, teacherRepo, fileStorage := newTestAssignmentService(now)
+	ctx := context.Background()
+
+	assignment := &domain.Assignment{ID: 1, TeacherID: 2, DueDate: now.Add(-time.Hour)}
+	saved := &domain.Submission{ID: 5, AssignmentID: 1, StudentID: 9, Attempt: 2, Resubmitted: true, Late: true, Text: "v2"}
+
+	// Mock expectations
+	repo.On("GetAssignmentByID", int64(1)).Return(assignment, nil)
+	teacherRepo.On("IsStudentAssigned", int64(2), int64(9)).Return(true, nil)
+	repo.On("SaveSubmission", mock.MatchedBy(func(s *domain.Submission) bool {
+		return s.Late && s.Text == "v2"
+	})).Return([]string{"old-1", "old-2"}, nil)
+	repo.On("GetSubmission", int64(1), int64(9)).Return(saved, nil)
+	fileStorage.On("Delete", mock.Anything, "old-1").Return(nil)
+	fileStorage.On("Delete", mock.Anything, "old-2").Return(nil)
+
+	// Test
+	result, err := service.Submit(ctx, 9, 1, &domain.SubmissionCreate{Text: "v2"})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, saved, result)
+	repo.AssertExpectations(t)
+	fileStorage.AssertExpectations(t)
+}
+
+func TestAssignmentService_Submit_SaveFailure(t *testing.T) {
+	// Setup
+	now := time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC)
+	service, repo, teacherRepo, fileStorage := newTestAssignmentService(now)
+	ctx := context.Background()
+
+	first := &domain.FileUpload{Name: "answer.pdf"}
+	second := &domain.FileUpload{Name: "appendix.pdf"}
+
+	// Mock expectations
+	repo.On("GetAssignmentByID", int64(1)).Return(&domain.Assignment{ID: 1, TeacherID: 2, DueDate: now}, nil)
+	teacherRepo.On("IsStudentAssigned", int64(2), int64(9)).Return(true, nil)
+	fileStorage.On("Upload", ctx, first).Return(&domain.File{ID: "file-1"}, nil)
+	fileStorage.On("Upload", ctx, second).Return(&domain.File{ID: "file-2"}, nil)
+	repo.On("SaveSubmission", mock.Anything).Return(nil, errors.New("connection reset"))
+	fileStorage.On("Delete", mock.Anything, "file-1").Return(nil)
+	fileStorage.On("Delete", mock.Anything, "file-2").Return(nil)
+
+	// Test
+	_, err := service.Submit(ctx, 9, 1, &domain.SubmissionCreate{Files: []*domain.FileUpload{first, second}})
+
+	// Assertions
+	assert.Error(t, err)
+	fileStorage.AssertExpectations(t)
+	repo.AssertNotCalled(t, "GetSubmission", mock.Anything, mock.Anything)
+}
+
+func TestAssignmentService_Submit_UploadFailure(t *testing.T) {
+	// Setup
+	now := time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC)
+	service, repo, teacherRepo, fileStorage := newTestAssignmentService(now)
+	ctx := context.Background()
+
+	first := &domain.FileUpload{Name: "answer.pdf"}
+	second := &domain.FileUpload{Name: "huge.mov"}
+
+	// Mock expectations
+	repo.On("GetAssignmentByID", int64(1)).Return(&domain.Assignment{ID: 1, TeacherID: 2, DueDate: now}, nil)
+	teacherRepo.On("IsStudentAssigned", int64(2), int64(9)).Return(true, nil)
+	fileStorage.On("Upload", ctx, first).Return(&domain.File{ID: "file-1"}, nil)
+	fileStorage.On("Upload", ctx, second).Return(nil, domain.ErrQuotaExceeded)
+	fileStorage.On("Delete", mock.Anything, "file-1").Return(nil)
+
+	// Test
+	_, err := service.Submit(ctx, 9, 1, &domain.SubmissionCreate{Files: []*domain.FileUpload{first, second}})
+
+	// Assertions
+	assert.ErrorIs(t, err, domain.ErrQuotaExceeded)
+	fileStorage.AssertExpectations(t)
+	repo.AssertNotCalled(t, "SaveSubmission", mock.Anything)
+}
+
+func TestAssignmentService_Submit_NotAssigned(t *testing.T) {
+	// Setup
+	service, repo, teacherRepo, fileStorage := newTestAssignmentService(time.Now())
+	ctx := context.Background()
+
+	// Mock expectations
+	repo.On("GetAssignmentByID", int64(1)).Return(&domain.Assignment{ID: 1, TeacherID: 2}, nil)
+	teacherRepo.On("IsStudentAssigned", int64(2), int64(9)).Return(false, nil)
+
+	// Test
+	_, err := service.Submit(ctx, 9, 1, &domain.SubmissionCreate{Text: "answer"})
+
+	// Assertions
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	fileStorage.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "SaveSubmission", mock.Anything)
+}
+
+func TestAssignmentService_Submit_Empty(t *testing.T) {
+	// Setup
+	service, repo, _, _ := newTestAssignmentService(time.Now())
+	ctx := context.Background()
+
+	// Test
+	_, err := service.Submit(ctx, 9, 1, &domain.SubmissionCreate{})
+
+	// Assertions
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	repo.AssertNotCalled(t, "GetAssignmentByID", mock.Anything)
+}
+
+func TestAssignmentService_ListSubmissions(t *testing.T) {
+	// Setup
+	service, repo, _, _ := newTestAssignmentService(time.Now())
+	ctx := context.Background()
+
+	submissions := []domain.Submission{{ID: 1, StudentID: 9}, {ID: 2, StudentID: 10, Late: true}}
+
+	// Mock expectations
+	repo.On("GetAssignmentByID", int64(1)).Return(&domain.Assignment{ID: 1, TeacherID: 2}, nil)
+	repo.On("GetSubmissionsByAssignmentID", int64(1)).Return(submissions, nil)
+
+	// Test
+	result, err := service.ListSubmissions(ctx, 2, 1)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, submissions, result)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+)
+
+const (
+	// 検査を待つ内容の最大数（超えた分は定期的な確認で改めて予約する）
+	scanQueueSize = 256
+	// 定期的な確認で一度に予約する検査待ちの内容の最大数
+	scanSweepLimit = 100
+	// 検査待ちの内容を確認する間隔が指定されていない場合に使用する間隔
+	defaultScanInterval = 30 * time.Second
+)
+
+// コンテンツ検査サービス構造体：アップロードされた内容の非同期のマルウェア検査と隔離を実装
+// 検査は重複排除された内容ごとに一度だけ行い、結果はその内容を参照するすべてのファイルに適用される
+// 検査待ちの内容はデータベースに記録されているため、再起動や予約の取りこぼしがあっても定期的な確認で検査される
+type ContentScanService struct {
+	// 内容を読み込むストレージ（IDはオブジェクトキー）
+	objects ports.FileStorage
+	// ファイルカタログ
+	catalog ports.FileRepository
+	// コンテンツスキャナーインターフェース
+	scanner ports.ContentScanner
+	// 通知リポジトリインターフェース（隔離したファイルのアップロードしたユーザーへの通知に使用）
+	notifications ports.NotificationRepository
+	// サムネイルサービスインターフェース（検査で問題がなかった画像ファイルのサムネイルを作成する）
+	thumbnails ports.ThumbnailService
+	// 検査を待っている内容
+	queue chan domain.ScanTarget
+	// 検査を予約済みのチェックサムへのアクセスを保護するロック
+	mu sync.Mutex
+	// 検査を予約済みのチェックサム（同じ内容の重複した検査を防ぐ）
+	pending map[string]bool
+	// 現在時刻を返す関数（テストで差し替え可能）
+	now func() time.Time
+}
+
+// 新しいコンテンツ検査サービスインスタンスを作成する
+func NewContentScanService(objects ports.FileStorage, catalog ports.FileRepository, scanner ports.ContentScanner, notifications ports.NotificationRepository, thumbnails ports.ThumbnailService) *ContentScanService {
+	return &ContentScanService{
+		objects:       objects,
+		catalog:       catalog,
+		scanner:       scanner,
+		notifications: notifications,
+		thumbnails:    thumbnails,
+		queue:         make(chan domain.ScanTarget, scanQueueSize),
+		pending:       make(map[string]bool),
+		now:           time.Now,
+	}
+}
+
+// ファイルの内容の検査を予約する
+// 検査済みで問題のない内容の場合は、検査後の処理（サムネイルの作成）のみを予約する
+func (s *ContentScanService) Enqueue(file *domain.File) {
+	switch file.ScanStatus {
+	case domain.ScanPending:
+		s.schedule(domain.ScanTarget{Checksum: file.Checksum, ObjectKey: file.ObjectKey, Size: file.Size})
+	case domain.ScanInfected:
+		return
+	default:
+		s.thumbnails.Enqueue(file)
+	}
+}
+
+// マルウェアを検出して隔離したファイルの一覧を取得する
+func (s *ContentScanService) ListQuarantinedFiles(ctx context.Context) ([]domain.File, error) {
+	return s.catalog.ListQuarantinedFiles()
+}
+
+// 指定された数のワーカーで予約された内容を検査し、指定された間隔で検査待ちの内容を確認して予約する
+// 間隔が正でない場合は既定の間隔で確認する
+// コンテキストが終了するまで処理を続けるため、ゴルーチンで実行する
+func (s *ContentScanService) Run(ctx context.Context, workers int, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultScanInterval
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < max(workers, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case target := <-s.queue:
+					if err := s.scan(ctx, target); err != nil {
+						slog.Error("failed to scan content", slog.String("checksum", target.Checksum), slog.String("error", err.Error()))
+					}
+				}
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.sweep(); err != nil {
+			slog.Error("failed to list pending scans", slog.String("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// 検査待ちの内容をデータベースから取得し、まだ予約されていないものを予約する
+// 検査に失敗した内容も検査待ちのまま残るため、次の確認で再び検査される
+func (s *ContentScanService) sweep() error {
+	targets, err := s.catalog.ListPendingScans(scanSweepLimit)
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		s.schedule(target)
+	}
+	return nil
+}
+
+// 内容の検査を予約する（予約済みの内容は無視し、待ちが上限に達している場合は次の確認に任せる）
+func (s *ContentScanService) schedule(target domain.ScanTarget) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending[target.Checksum] {
+		return
+	}
+
+	select {
+	case s.queue <- target:
+		s.pending[target.Checksum] = true
+	default:
+		slog.Warn("scan queue is full", slog.String("checksum", target.Checksum))
+	}
+}
+
+// 内容をストレージから読み込んで検査し、結果を記録する
+// 問題がない場合は画像ファイルのサムネイルの作成を予約し、マルウェアを検出した場合はアップロードしたユーザーに通知する
+func (s *ContentScanService) scan(ctx context.Context, target domain.ScanTarget) error {
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, target.Checksum)
+		s.mu.Unlock()
+	}()
+
+	_, body, err := s.objects.Download(ctx, target.ObjectKey)
+	if err != nil {
+		return err
+	}
+	result, err := s.scanner.Scan(ctx, body)
+	body.Close()
+	if err != nil {
+		return err
+	}
+
+	files, err := s.catalog.SetScanResult(target.Checksum, *result, s.now().UTC())
+	if err != nil {
+		return err
+	}
+
+	if !result.Infected {
+		for i := range files {
+			if files[i].DeletedAt == nil {
+				s.thumbnails.Enqueue(&files[i])
+			}
+		}
+		return nil
+	}
+
+	slog.Warn("malware detected, content quarantined", slog.String("checksum", target.Checksum), slog.String("signature", result.Signature))
+	for _, file := range files {
+		if err := s.notifyQuarantined(file, result.Signature); err != nil {
+			slog.Error("failed to notify uploader", slog.String("file_id", file.ID), slog.String("error", err.Error()))
+		}
+	}
+	return nil
+}
+
+// 隔離したファイルをアップロードしたユーザーに通知する
+func (s *ContentScanService) notifyQuarantined(file domain.File, signature string) error {
+	if file.UploaderID == 0 {
+		return nil
+	}
+	return s.notifications.CreateNotification(&domain.Notification{
+		RecipientRole: file.UploaderRole,
+		RecipientID:   file.UploaderID,
+		Kind:          domain.NotificationFileQuarantined,
+		Message:       fmt.Sprintf("%s was quarantined because malware was detected (%s)", file.Name, signature),
+		FileID:        file.ID,
+		CreatedAt:     s.now().UTC(),
+	})
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestContentScanService(now time.Time) (*ContentScanService, *mocks.FileStorage, *mocks.FileRepository, *mocks.ContentScanner, *mocks.NotificationRepository, *mocks.ThumbnailService) {
+	objects := new(mocks.FileStorage)
+	catalog := new(mocks.FileRepository)
+	scanner := new(mocks.ContentScanner)
+	notifications := new(mocks.NotificationRepository)
+	thumbnails := new(mocks.ThumbnailService)
+	service := NewContentScanService(objects, catalog, scanner, notifications, thumbnails)
+	service.now = func() time.Time { return now }
+	return service, objects, catalog, scanner, notifications, thumbnails
+}
+
+func TestContentScanService_Enqueue(t *testing.T) {
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+
+	t.Run("pending content is queued once", func(t *testing.T) {
+		// Setup
+		service, _, _, _, _, thumbnails := newTestContentScanService(now)
+		file := &domain.File{ID: "file-1", Checksum: "abc", ObjectKey: "object-1", ScanStatus: domain.ScanPending}
+
+		// Test
+		service.Enqueue(file)
+		service.Enqueue(&domain.File{ID: "file-2", Checksum: "abc", ObjectKey: "object-1", ScanStatus: domain.ScanPending})
+
+		// Assertions
+		require.Len(t, service.queue, 1)
+		assert.Equal(t, domain.ScanTarget{Checksum: "abc", ObjectKey: "object-1"}, <-service.queue)
+		thumbnails.AssertNotCalled(t, "Enqueue", mock.Anything)
+	})
+
+	t.Run("clean content goes straight to thumbnails", func(t *testing.T) {
+		service, _, _, _, _, thumbnails := newTestContentScanService(now)
+		file := &domain.File{ID: "file-1", Checksum: "abc", ScanStatus: domain.ScanClean}
+		thumbnails.On("Enqueue", file).Return()
+
+		service.Enqueue(file)
+
+		assert.Empty(t, service.queue)
+		thumbnails.AssertExpectations(t)
+	})
+
+	t.Run("sweep queues pending content from the catalog", func(t *testing.T) {
+		service, _, catalog, _, _, _ := newTestContentScanService(now)
+		catalog.On("ListPendingScans", scanSweepLimit).Return([]domain.ScanTarget{{Checksum: "abc"}, {Checksum: "def"}}, nil)
+
+		err := service.sweep()
+
+		require.NoError(t, err)
+		assert.Len(t, service.queue, 2)
+	})
+}
+
+func TestContentScanService_Scan(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+	target := domain.ScanTarget{Checksum: "abc", ObjectKey: "object-1", Size: 5}
+
+	t.Run("clean content is released and thumbnailed", func(t *testing.T) {
+		// Setup
+		service, objects, catalog, scanner, notifications, thumbnails := newTestContentScanService(now)
+		service.pending["abc"] = true
+		trashedAt := now.Add(-time.Hour)
+
+		// Mock expectations
+		objects.On("Download", ctx, "object-1").Return(&domain.File{ID: "object-1"}, io.NopCloser(strings.NewReader("hello")), nil)
+		scanner.On("Scan", ctx, mock.Anything).Return(&domain.ScanResult{}, nil)
+		catalog.On("SetScanResult", "abc", domain.ScanResult{}, now).Return([]domain.File{
+			{ID: "file-1", ScanStatus: domain.ScanClean},
+			{ID: "file-2", ScanStatus: domain.ScanClean, DeletedAt: &trashedAt},
+		}, nil)
+		thumbnails.On("Enqueue", mock.MatchedBy(func(f *domain.File) bool { return f.ID == "file-1" })).Return()
+
+		// Test
+		err := service.scan(ctx, target)
+
+		// Assertions
+		require.NoError(t, err)
+		thumbnails.AssertNumberOfCalls(t, "Enqueue", 1)
+		notifications.AssertNotCalled(t, "CreateNotification", mock.Anything)
+		assert.Empty(t, service.pending)
+	})
+
+	t.Run("infected content notifies every uploader", func(t *testing.T) {
+		// Setup
+		service, objects, catalog, scanner, notifications, thumbnails := newTestContentScanService(now)
+		result := domain.ScanResult{Infected: true, Signature: "Eicar-Test-Signature"}
+
+		// Mock expectations
+		objects.On("Download", ctx, "object-1").Return(&domain.File{ID: "object-1"}, io.NopCloser(strings.NewReader("hello")), nil)
+		scanner.On("Scan", ctx, mock.Anything).Return(&result, nil)
+		catalog.On("SetScanResult", "abc", result, now).Return([]domain.File{
+			{ID: "file-1", Name: "essay.docx", UploaderID: 12, UploaderRole: domain.RoleStudent},
+			{ID: "file-2", Name: "copy.docx", UploaderID: 3, UploaderRole: domain.RoleTeacher},
+		}, nil)
+		notifications.On("CreateNotification", mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.RecipientRole == domain.RoleStudent && n.RecipientID == 12 && n.FileID == "file-1" &&
+				n.Kind == domain.NotificationFileQuarantined && strings.Contains(n.Message, "Eicar-Test-Signature")
+		})).Return(nil)
+		notifications.On("CreateNotification", mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.RecipientRole == domain.RoleTeacher && n.RecipientID == 3 && n.FileID == "file-2"
+		})).Return(nil)
+
+		// Test
+		err := service.scan(ctx, target)
+
+		// Assertions
+		require.NoError(t, err)
+		notifications.AssertExpectations(t)
+		thumbnails.AssertNotCalled(t, "Enqueue", mock.Anything)
+	})
+
+	t.Run("scanner failure leaves the content pending", func(t *testing.T) {
+		service, objects, catalog, scanner, _, _ := newTestContentScanService(now)
+		objects.On("Download", ctx, "object-1").Return(&domain.File{ID: "object-1"}, io.NopCloser(strings.NewReader("hello")), nil)
+		scanner.On("Scan", ctx, mock.Anything).Return(nil, errors.New("failed to connect to clamd"))
+
+		err := service.scan(ctx, target)
+
+		assert.Error(t, err)
+		catalog.AssertNotCalled(t, "SetScanResult", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestContentScanService_Run_ZeroInterval(t *testing.T) {
+	// Setup
+	service, _, catalog, _, _, _ := newTestContentScanService(time.Now())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Mock expectations
+	catalog.On("ListPendingScans", scanSweepLimit).Return([]domain.ScanTarget{}, nil)
+
+	// Test
+	assert.NotPanics(t, func() { service.Run(ctx, 1, 0) })
+
+	// Assertions
+	catalog.AssertCalled(t, "ListPendingScans", scanSweepLimit)
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+)
+
+// ドキュメントサービス構造体：ドキュメントの作成・更新と、種類ごとのJSON Schemaによる検証を実装
+// スキーマが登録されている種類のドキュメントは最新のスキーマで検証し、検証したバージョンをドキュメントに記録する
+type DocumentService struct {
+	// ドキュメントストレージ
+	documents ports.DocumentStorage
+	// ドキュメントスキーマリポジトリ
+	schemas ports.DocumentSchemaRepository
+	// スキーマによる検証を行うバリデーター
+	validator ports.SchemaValidator
+	// 現在時刻を返す関数（テストで差し替え可能）
+	now func() time.Time
+}
+
+// 新しいドキュメントサービスインスタンスを作成する
+func NewDocumentService(documents ports.DocumentStorage, schemas ports.DocumentSchemaRepository, validator ports.SchemaValidator) *DocumentService {
+	return &DocumentService{
+		documents: documents,
+		schemas:   schemas,
+		validator: validator,
+		now:       time.Now,
+	}
+}
+
+// 種類の最新のスキーマで検証してドキュメントを作成する
+func (s *DocumentService) CreateDocument(ctx context.Context, doc *domain.DocumentCreate) (*domain.Document, error) {
+	version, err := s.validate(doc.Type, doc.Data)
+	if err != nil {
+		return nil, err
+	}
+	doc.SchemaVersion = version
+	return s.documents.Create(ctx, doc)
+}
+
+// ドキュメントの種類の最新のスキーマで検証してドキュメントを更新する
+// 前提となるバージョンが指定されている場合、読み込んだ時点で異なっていれば検証せずに競合エラーを返す
+// （読み込んだ後に他の更新が入った場合は、ストレージの条件付き更新で検出される）
+func (s *DocumentService) UpdateDocument(ctx context.Context, id string, update *domain.DocumentUpdate) (*domain.Document, error) {
+	current, err := s.documents.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if update.ExpectedVersion != nil && *update.ExpectedVersion != current.Version {
+		return nil, &domain.DocumentVersionConflictError{ID: id, ExpectedVersion: *update.ExpectedVersion, CurrentVersion: current.Version}
+	}
+
+	version, err := s.validate(current.Type, update.Data)
+	if err != nil {
+		return nil, err
+	}
+	update.SchemaVersion = version
+	return s.documents.Update(ctx, id, update)
+}
+
+// ドキュメントのdataにパッチを適用する
+// 適用後のデータを種類の最新のスキーマで検証し、スキーマがある場合は検証したバージョンのドキュメントにだけ書き込む
+func (s *DocumentService) PatchDocument(ctx context.Context, id string, patch *domain.DocumentPatch) (*domain.Document, error) {
+	current, err := s.documents.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if patch.ExpectedVersion != nil && *patch.ExpectedVersion != current.Version {
+		return nil, &domain.DocumentVersionConflictError{ID: id, ExpectedVersion: *patch.ExpectedVersion, CurrentVersion: current.Version}
+	}
+
+	data, err := patch.Apply(current.Data)
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.validate(current.Type, data)
+	if err != nil {
+		return nil, err
+	}
+	patch.SchemaVersion = version
+	// 検証した結果が書き込まれるよう、他の更新が先に書き込んだ場合は競合とする
+	if version > 0 && patch.ExpectedVersion == nil {
+		patch.ExpectedVersion = &current.Version
+	}
+	return s.documents.Patch(ctx, id, patch)
+}
+
+// 種類の新しいバージョンのスキーマを登録する
+func (s *DocumentService) RegisterSchema(ctx context.Context, docType string, input *domain.DocumentSchemaCreate, adminID int64) (*domain.DocumentSchema, error) {
+	docType = strings.TrimSpace(docType)
+	if docType == "" {
+		return nil, fmt.Errorf("%w: document type is required", domain.ErrInvalidInput)
+	}
+	if err := s.validator.Check(input.Schema); err != nil {
+		return nil, err
+	}
+
+	schema := &domain.DocumentSchema{
+		Type:      docType,
+		Schema:    input.Schema,
+		CreatedBy: adminID,
+		CreatedAt: s.now().UTC(),
+	}
+	if err := s.schemas.CreateDocumentSchema(schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// 種類のスキーマを取得する（バージョンが0の場合は最新のバージョン）
+func (s *DocumentService) GetSchema(ctx context.Context, docType string, version int) (*domain.DocumentSchema, error) {
+	if version == 0 {
+		return s.schemas.GetLatestDocumentSchema(docType)
+	}
+	return s.schemas.GetDocumentSchema(docType, version)
+}
+
+// 種類のスキーマのすべてのバージョンを新しい順に取得する
+func (s *DocumentService) ListSchemaVersions(ctx context.Context, docType string) ([]domain.DocumentSchema, error) {
+	schemas, err := s.schemas.ListDocumentSchemaVersions(docType)
+	if err != nil {
+		return nil, err
+	}
+	if len(schemas) == 0 {
+		return nil, fmt.Errorf("%w: no schema registered for document type: %s", domain.ErrNotFound, docType)
+	}
+	return schemas, nil
+}
+
+// 種類ごとの最新のスキーマを取得する
+func (s *DocumentService) ListSchemas(ctx context.Context) ([]domain.DocumentSchema, error) {
+	return s.schemas.ListLatestDocumentSchemas()
+}
+
+// データを種類の最新のスキーマで検証し、検証したスキーマのバージョンを返す
+// スキーマが登録されていない種類の場合は検証せずに0を返す
+func (s *DocumentService) validate(docType string, data map[string]interface{}) (int, error) {
+	schema, err := s.schemas.GetLatestDocumentSchema(docType)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	fields, err := s.validator.Validate(schema, data)
+	if err != nil {
+		return 0, err
+	}
+	if len(fields) > 0 {
+		return 0, &domain.DocumentValidationError{Type: docType, Version: schema.Version, Fields: fields}
+	}
+	return schema.Version, nil
+}
@@ -0,0 +1,402 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDocumentService(now time.Time) (*DocumentService, *mocks.DocumentStorage, *mocks.DocumentSchemaRepository, *mocks.SchemaValidator) {
+	documents := new(mocks.DocumentStorage)
+	schemas := new(mocks.DocumentSchemaRepository)
+	validator := new(mocks.SchemaValidator)
+	service := NewDocumentService(documents, schemas, validator)
+	service.now = func() time.Time { return now }
+	return service, documents, schemas, validator
+}
+
+func TestDocumentService_CreateDocument(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+	schema := &domain.DocumentSchema{Type: "assignment", Version: 3, Schema: json.RawMessage(`{"type":"object"}`)}
+
+	t.Run("document matching the latest schema records its version", func(t *testing.T) {
+		// Setup
+		service, documents, schemas, validator := newTestDocumentService(now)
+		doc := &domain.DocumentCreate{Type: "assignment", Data: map[string]interface{}{"title": "Essay"}}
+
+		// Mock expectations
+		schemas.On("GetLatestDocumentSchema", "assignment").Return(schema, nil)
+		validator.On("Validate", schema, doc.Data).Return(nil, nil)
+		documents.On("Create", ctx, mock.MatchedBy(func(d *domain.DocumentCreate) bool {
+			return d.SchemaVersion == 3
+		})).Return(&domain.Document{ID: "doc-1", Type: "assignment", SchemaVersion: 3}, nil)
+
+		// Test
+		result, err := service.CreateDocument(ctx, doc)
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.SchemaVersion)
+		documents.AssertExpectations(t)
+	})
+
+	t.Run("document not matching the schema returns field errors", func(t *testing.T) {
+		// Setup
+		service, documents, schemas, validator := newTestDocumentService(now)
+		doc := &domain.DocumentCreate{Type: "assignment", Data: map[string]interface{}{"points": -1}}
+		fields := []domain.FieldError{
+			{Field: "/data/points", Message: "minimum: got -1, want 0"},
+			{Field: "/data/title", Message: "is required"},
+		}
+
+		// Mock expectations
+		schemas.On("GetLatestDocumentSchema", "assignment").Return(schema, nil)
+		validator.On("Validate", schema, doc.Data).Return(fields, nil)
+
+		// Test
+		result, err := service.CreateDocument(ctx, doc)
+
+		// Assertions
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		var validationErr *domain.DocumentValidationError
+		require.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, 3, validationErr.Version)
+		assert.Equal(t, fields, validationErr.Fields)
+		documents.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("type without a schema is not validated", func(t *testing.T) {
+		// Setup
+		service, documents, schemas, validator := newTestDocumentService(now)
+		doc := &domain.DocumentCreate{Type: "material", Data: map[string]interface{}{"anything": true}}
+
+		// Mock expectations
+		schemas.On("GetLatestDocumentSchema", "material").Return(nil, fmt.Errorf("%w: no schema", domain.ErrNotFound))
+		documents.On("Create", ctx, mock.MatchedBy(func(d *domain.DocumentCreate) bool {
+			return d.SchemaVersion == 0
+		})).Return(&domain.Document{ID: "doc-2", Type: "material"}, nil)
+
+		// Test
+		result, err := service.CreateDocument(ctx, doc)
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, "doc-2", result.ID)
+		validator.AssertNotCalled(t, "Validate", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDocumentService_UpdateDocument(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+
+	t.Run("update is validated against the schema of the stored type", func(t *testing.T) {
+		// Setup
+		service, documents, schemas, validator := newTestDocumentService(now)
+		schema := &domain.DocumentSchema{Type: "test", Version: 2, Schema: json.RawMessage(`{"type":"object"}`)}
+		update := &domain.DocumentUpdate{Data: map[string]interface{}{"title": "Quiz 1"}}
+
+		// Mock expectations
+		documents.On("Get", ctx, "doc-1").Return(&domain.Document{ID: "doc-1", Type: "test", SchemaVersion: 1}, nil)
+		schemas.On("GetLatestDocumentSchema", "test").Return(schema, nil)
+		validator.On("Validate", schema, update.Data).Return(nil, nil)
+		documents.On("Update", ctx, "doc-1", mock.MatchedBy(func(u *domain.DocumentUpdate) bool {
+			return u.SchemaVersion == 2
+		})).Return(&domain.Document{ID: "doc-1", Type: "test", SchemaVersion: 2}, nil)
+
+		// Test
+		result, err := service.UpdateDocument(ctx, "doc-1", update)
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.SchemaVersion)
+		documents.AssertExpectations(t)
+	})
+
+	t.Run("stale expected version is rejected before validation", func(t *testing.T) {
+		// Setup
+		service, documents, schemas, _ := newTestDocumentService(now)
+		expected := 3
+		update := &domain.DocumentUpdate{Data: map[string]interface{}{"title": "Quiz 1"}, ExpectedVersion: &expected}
+
+		// Mock expectations
+		documents.On("Get", ctx, "doc-1").Return(&domain.Document{ID: "doc-1", Type: "test", Version: 5}, nil)
+
+		// Test
+		result, err := service.UpdateDocument(ctx, "doc-1", update)
+
+		// Assertions
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+		var conflictErr *domain.DocumentVersionConflictError
+		require.True(t, errors.As(err, &conflictErr))
+		assert.Equal(t, 5, conflictErr.CurrentVersion)
+		schemas.AssertNotCalled(t, "GetLatestDocumentSchema", mock.Anything)
+		documents.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("matching expected version is passed to the conditional update", func(t *testing.T) {
+		// Setup
+		service, documents, schemas, _ := newTestDocumentService(now)
+		expected := 5
+		update := &domain.DocumentUpdate{Data: map[string]interface{}{"title": "Quiz 1"}, ExpectedVersion: &expected}
+		conflict := &domain.DocumentVersionConflictError{ID: "doc-1", ExpectedVersion: 5, CurrentVersion: 6}
+
+		// Mock expectations
+		documents.On("Get", ctx, "doc-1").Return(&domain.Document{ID: "doc-1", Type: "material", Version: 5}, nil)
+		schemas.On("GetLatestDocumentSchema", "material").Return(nil, fmt.Errorf("%w: no schema", domain.ErrNotFound))
+		documents.On("Update", ctx, "doc-1", mock.MatchedBy(func(u *domain.DocumentUpdate) bool {
+			return u.ExpectedVersion != nil && *u.ExpectedVersion == 5
+		})).Return(nil, conflict)
+
+		// Test
+		_, err := service.UpdateDocument(ctx, "doc-1", update)
+
+		// Assertions
+		assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+		documents.AssertExpectations(t)
+	})
+
+	t.Run("missing document", func(t *testing.T) {
+		// Setup
+		service, documents, schemas, _ := newTestDocumentService(now)
+
+		// Mock expectations
+		documents.On("Get", ctx, "missing").Return(nil, fmt.Errorf("%w: document not found", domain.ErrNotFound))
+
+		// Test
+		_, err := service.UpdateDocument(ctx, "missing", &domain.DocumentUpdate{Data: map[string]interface{}{}})
+
+		// Assertions
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		schemas.AssertNotCalled(t, "GetLatestDocumentSchema", mock.Anything)
+	})
+}
+
+func TestDocumentService_PatchDocument(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+	noSchema := fmt.Errorf("%w: no schema", domain.ErrNotFound)
+	stored := func() *domain.Document {
+		return &domain.Document{
+			ID:      "doc-1",
+			Type:    "material",
+			Version: 4,
+			Data: map[string]interface{}{
+				"title": "Week 1",
+				"tags":  []interface{}{"intro"},
+				"meta":  map[string]interface{}{"author": "kim", "draft": true},
+			},
+		}
+	}
+
+	t.Run("merge patch result is validated and passed to storage", func(t *testing.T) {
+		// Setup
+		service, documents, schemas, validator := newTestDocumentService(now)
+		schema := &domain.DocumentSchema{Type: "material", Version: 2, Schema: json.RawMessage(`{"type":"object"}`)}
+		patch := &domain.DocumentPatch{
+			Format: domain.PatchFormatMerge,
+			Merge:  map[string]interface{}{"title": "Week 2", "meta": map[string]interface{}{"draft": nil}},
+		}
+		expected := map[string]interface{}{
+			"title": "Week 2",
+			"tags":  []interface{}{"intro"},
+			"meta":  map[string]interface{}{"author": "kim"},
+		}
+
+		// Mock expectations
+		documents.On("Get", ctx, "doc-1").Return(stored(), nil)
+		schemas.On("GetLatestDocumentSchema", "material").Return(schema, nil)
+		validator.On("Validate", schema, expected).Return(nil, nil)
+		documents.On("Patch", ctx, "doc-1", mock.MatchedBy(func(p *domain.DocumentPatch) bool {
+			// スキーマで検証した場合は読み込んだバージョンを前提に書き込む
+			return p.SchemaVersion == 2 && p.ExpectedVersion != nil && *p.ExpectedVersion == 4
+		})).Return(&domain.Document{ID: "doc-1", Version: 5, Data: expected}, nil)
+
+		// Test
+		result, err := service.PatchDocument(ctx, "doc-1", patch)
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, 5, result.Version)
+		documents.AssertExpectations(t)
+		validator.AssertExpectations(t)
+	})
+
+	t.Run("json patch without schema is not pinned to the read version", func(t *testing.T) {
+		// Setup
+		service, documents, schemas, _ := newTestDocumentService(now)
+		patch := &domain.DocumentPatch{
+			Format: domain.PatchFormatJSON,
+			Operations: []domain.PatchOperation{
+				{Op: "test", Path: "/title", Value: json.RawMessage(`"Week 1"`)},
+				{Op: "add", Path: "/tags/-", Value: json.RawMessage(`"reading"`)},
+				{Op: "move", From: "/meta/author", Path: "/author"},
+			},
+		}
+
+		// Mock expectations
+		documents.On("Get", ctx, "doc-1").Return(stored(), nil)
+		schemas.On("GetLatestDocumentSchema", "material").Return(nil, noSchema)
+		documents.On("Patch", ctx, "doc-1", mock.MatchedBy(func(p *domain.DocumentPatch) bool {
+			return p.SchemaVersion == 0 && p.ExpectedVersion == nil
+		})).Return(&domain.Document{ID: "doc-1", Version: 5}, nil)
+
+		// Test
+		_, err := service.PatchDocument(ctx, "doc-1", patch)
+
+		// Assertions
+		require.NoError(t, err)
+		documents.AssertExpectations(t)
+	})
+
+	t.Run("failed test operation is a conflict", func(t *testing.T) {
+		// Setup
+		service, documents, schemas, _ := newTestDocumentService(now)
+		patch := &domain.DocumentPatch{
+			Format: domain.PatchFormatJSON,
+			Operations: []domain.PatchOperation{
+				{Op: "test", Path: "/meta/draft", Value: json.RawMessage(`false`)},
+				{Op: "remove", Path: "/meta/draft"},
+			},
+		}
+
+		// Mock expectations
+		documents.On("Get", ctx, "doc-1").Return(stored(), nil)
+
+		// Test
+		_, err := service.PatchDocument(ctx, "doc-1", patch)
+
+		// Assertions
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		schemas.AssertNotCalled(t, "GetLatestDocumentSchema", mock.Anything)
+		documents.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("malformed operation is invalid input", func(t *testing.T) {
+		// Setup
+		service, documents, _, _ := newTestDocumentService(now)
+		patch := &domain.DocumentPatch{
+			Format:     domain.PatchFormatJSON,
+			Operations: []domain.PatchOperation{{Op: "replace", Path: "title", Value: json.RawMessage(`"Week 2"`)}},
+		}
+
+		// Mock expectations
+		documents.On("Get", ctx, "doc-1").Return(stored(), nil)
+
+		// Test
+		_, err := service.PatchDocument(ctx, "doc-1", patch)
+
+		// Assertions
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		documents.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("stale expected version is rejected before applying", func(t *testing.T) {
+		// Setup
+		service, documents, _, _ := newTestDocumentService(now)
+		expected := 3
+		patch := &domain.DocumentPatch{
+			Format:          domain.PatchFormatMerge,
+			Merge:           map[string]interface{}{"title": "Week 2"},
+			ExpectedVersion: &expected,
+		}
+
+		// Mock expectations
+		documents.On("Get", ctx, "doc-1").Return(stored(), nil)
+
+		// Test
+		_, err := service.PatchDocument(ctx, "doc-1", patch)
+
+		// Assertions
+		assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+		documents.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("patched data not matching the schema returns field errors", func(t *testing.T) {
+		// Setup
+		service, documents, schemas, validator := newTestDocumentService(now)
+		schema := &domain.DocumentSchema{Type: "material", Version: 2, Schema: json.RawMessage(`{"type":"object"}`)}
+		patch := &domain.DocumentPatch{Format: domain.PatchFormatMerge, Merge: map[string]interface{}{"title": nil}}
+		fields := []domain.FieldError{{Field: "/data/title", Message: "is required"}}
+
+		// Mock expectations
+		documents.On("Get", ctx, "doc-1").Return(stored(), nil)
+		schemas.On("GetLatestDocumentSchema", "material").Return(schema, nil)
+		validator.On("Validate", schema, mock.Anything).Return(fields, nil)
+
+		// Test
+		_, err := service.PatchDocument(ctx, "doc-1", patch)
+
+		// Assertions
+		var validationErr *domain.DocumentValidationError
+		require.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, fields, validationErr.Fields)
+		documents.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDocumentService_RegisterSchema(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+	body := json.RawMessage(`{"type":"object","required":["title"]}`)
+
+	t.Run("valid schema is registered as the next version", func(t *testing.T) {
+		// Setup
+		service, _, schemas, validator := newTestDocumentService(now)
+
+		// Mock expectations
+		validator.On("Check", body).Return(nil)
+		schemas.On("CreateDocumentSchema", mock.MatchedBy(func(s *domain.DocumentSchema) bool {
+			return s.Type == "assignment" && s.CreatedBy == 7 && s.CreatedAt.Equal(now)
+		})).Run(func(args mock.Arguments) {
+			args.Get(0).(*domain.DocumentSchema).Version = 4
+		}).Return(nil)
+
+		// Test
+		schema, err := service.RegisterSchema(ctx, "assignment", &domain.DocumentSchemaCreate{Schema: body}, 7)
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, 4, schema.Version)
+		schemas.AssertExpectations(t)
+	})
+
+	t.Run("invalid schema is rejected", func(t *testing.T) {
+		// Setup
+		service, _, schemas, validator := newTestDocumentService(now)
+
+		// Mock expectations
+		validator.On("Check", body).Return(fmt.Errorf("%w: invalid schema", domain.ErrInvalidInput))
+
+		// Test
+		_, err := service.RegisterSchema(ctx, "assignment", &domain.DocumentSchemaCreate{Schema: body}, 7)
+
+		// Assertions
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		schemas.AssertNotCalled(t, "CreateDocumentSchema", mock.Anything)
+	})
+
+	t.Run("blank type is rejected", func(t *testing.T) {
+		// Setup
+		service, _, _, validator := newTestDocumentService(now)
+
+		// Test
+		_, err := service.RegisterSchema(ctx, " ", &domain.DocumentSchemaCreate{Schema: body}, 7)
+
+		// Assertions
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		validator.AssertNotCalled(t, "Check", mock.Anything)
+	})
+}
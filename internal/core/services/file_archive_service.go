@@ -0,0 +1,257 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+)
+
+const (
+	// 1つのアーカイブに指定できるファイルIDの最大数
+	maxArchiveFileIDs = 1000
+	// ファイルIDを指定した場合のアーカイブのファイル名
+	defaultArchiveName = "files.zip"
+	// 見つからなかったファイルを記録するアーカイブ内のファイル名
+	missingFilesReportName = "MISSING.txt"
+)
+
+// ファイルアーカイブサービス構造体：複数のファイルのZIPでのダウンロードを実装
+// ZIPはストレージから読み込みながら書き込むため、ファイルの内容をメモリや一時ファイルに保持しない
+type FileArchiveService struct {
+	// ファイルの内容を読み込むファイルストレージ
+	files ports.FileStorage
+	// ファイルカタログ
+	catalog ports.FileRepository
+	// フォルダリポジトリインターフェース
+	folders ports.FolderRepository
+	// ファイル共有リポジトリインターフェース（教師が閲覧できるファイルの確認に使用）
+	shares ports.FileShareRepository
+}
+
+// 新しいファイルアーカイブサービスインスタンスを作成する
+func NewFileArchiveService(files ports.FileStorage, catalog ports.FileRepository, folders ports.FolderRepository, shares ports.FileShareRepository) *FileArchiveService {
+	return &FileArchiveService{
+		files:   files,
+		catalog: catalog,
+		folders: folders,
+		shares:  shares,
+	}
+}
+
+// アーカイブに含めるファイルとその配置を決定する
+// ファイルIDを指定した場合はすべてを最上位に配置し、見つからないファイルと教師が閲覧できないファイルは一覧に記録する
+// フォルダを指定した場合は教師が所有している必要があり、中のフォルダはアーカイブ内のフォルダとして再現する
+func (s *FileArchiveService) PrepareArchive(ctx context.Context, teacherID int64, input *domain.FileArchiveRequest) (*domain.FileArchive, error) {
+	switch {
+	case input.FolderID != "" && len(input.FileIDs) > 0:
+		return nil, fmt.Errorf("%w: specify either file_ids or folder_id, not both", domain.ErrInvalidInput)
+	case input.FolderID != "":
+		return s.prepareFolder(teacherID, input.FolderID)
+	case len(input.FileIDs) > maxArchiveFileIDs:
+		return nil, fmt.Errorf("%w: at most %d files can be archived at once", domain.ErrInvalidInput, maxArchiveFileIDs)
+	case len(input.FileIDs) > 0:
+		return s.prepareFiles(teacherID, input.FileIDs)
+	default:
+		return nil, fmt.Errorf("%w: file_ids or folder_id is required", domain.ErrInvalidInput)
+	}
+}
+
+// アーカイブをZIPとして書き込む
+// 作成後に削除されたファイルやマルウェア検査によりダウンロードできないファイルは含めず、
+// 含めなかったファイルがある場合は理由とともに一覧をZIPに含める
+func (s *FileArchiveService) WriteArchive(ctx context.Context, archive *domain.FileArchive, w io.Writer) error {
+	writer := zip.NewWriter(w)
+	used := make(map[string]bool, len(archive.Entries))
+	var skipped []string
+	for _, id := range archive.Missing {
+		skipped = append(skipped, id+"\tnot found")
+	}
+
+	for _, entry := range archive.Entries {
+		used[strings.ToLower(entry.Path)] = true
+		reason, err := s.writeEntry(ctx, writer, entry)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			skipped = append(skipped, entry.File.ID+"\t"+reason)
+		}
+	}
+
+	if len(skipped) > 0 {
+		report, err := writer.Create(uniqueArchivePath(used, "", missingFilesReportName))
+		if err != nil {
+			return err
+		}
+		for _, line := range skipped {
+			if _, err := fmt.Fprintln(report, line); err != nil {
+				return err
+			}
+		}
+	}
+	return writer.Close()
+}
+
+// 1つのファイルをストレージから読み込んでZIPに書き込む
+// ファイルをダウンロードできない場合は何も書き込まずに理由を返す
+func (s *FileArchiveService) writeEntry(ctx context.Context, writer *zip.Writer, entry domain.FileArchiveEntry) (string, error) {
+	_, body, err := s.files.Download(ctx, entry.File.ID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return "not found", nil
+	case errors.Is(err, domain.ErrScanPending):
+		return "waiting for malware scan", nil
+	case errors.Is(err, domain.ErrQuarantined):
+		return "quarantined", nil
+	case err != nil:
+		return "", err
+	}
+	defer body.Close()
+
+	part, err := writer.CreateHeader(&zip.FileHeader{
+		Name:     entry.Path,
+		Method:   archiveMethod(entry.File.ContentType),
+		Modified: entry.File.UploadedAt,
+	})
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(part, body)
+	return "", err
+}
+
+// 指定されたIDのファイルを最上位に配置する（同じIDは1回だけ含める）
+// 教師が閲覧できないファイルは、存在を知られないよう見つからないファイルと同じく扱う
+func (s *FileArchiveService) prepareFiles(teacherID int64, ids []string) (*domain.FileArchive, error) {
+	archive := &domain.FileArchive{Name: defaultArchiveName}
+	used := make(map[string]bool)
+	seen := make(map[string]bool, len(ids))
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		file, err := teacherReadableFile(s.catalog, s.shares, teacherID, id)
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrForbidden) {
+			archive.Missing = append(archive.Missing, id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		archive.Entries = append(archive.Entries, domain.FileArchiveEntry{
+			Path: uniqueArchivePath(used, "", file.Name),
+			File: *file,
+		})
+	}
+
+	if len(archive.Entries) == 0 {
+		return nil, fmt.Errorf("%w: none of the requested files were found", domain.ErrNotFound)
+	}
+	return archive, nil
+}
+
+// フォルダとその中のフォルダのファイルを、フォルダ構造を保ったまま配置する
+func (s *FileArchiveService) prepareFolder(teacherID int64, folderID string) (*domain.FileArchive, error) {
+	root, err := teacherFolder(s.folders, teacherID, folderID)
+	if err != nil {
+		return nil, err
+	}
+	folders, err := s.folders.GetFoldersByOwnerID(teacherID)
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[string][]domain.Folder)
+	for _, folder := range folders {
+		if folder.ParentID != nil {
+			children[*folder.ParentID] = append(children[*folder.ParentID], folder)
+		}
+	}
+
+	archive := &domain.FileArchive{Name: archiveFileName(root.Name) + ".zip"}
+	used := make(map[string]bool)
+	if err := s.addFolder(archive, used, children, root.ID, ""); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// フォルダ直下のファイルをdirに配置し、中のフォルダを再帰的に追加する
+// ファイルが中のフォルダと同じ名前にならないよう、先にフォルダの名前を確保する
+func (s *FileArchiveService) addFolder(archive *domain.FileArchive, used map[string]bool, children map[string][]domain.Folder, folderID, dir string) error {
+	subfolders := children[folderID]
+	sort.Slice(subfolders, func(i, j int) bool { return subfolders[i].Name < subfolders[j].Name })
+	subdirs := make([]string, len(subfolders))
+	for i, folder := range subfolders {
+		subdirs[i] = uniqueArchivePath(used, dir, folder.Name)
+	}
+
+	files, err := s.catalog.ListFiles(domain.FileFilter{FolderID: folderID})
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		archive.Entries = append(archive.Entries, domain.FileArchiveEntry{
+			Path: uniqueArchivePath(used, dir, file.Name),
+			File: file,
+		})
+	}
+
+	for i, folder := range subfolders {
+		if err := s.addFolder(archive, used, children, folder.ID, subdirs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dir内でまだ使われていないパスを返し、使用済みとして記録する
+// 名前が重複する場合は拡張子の前に「 (2)」「 (3)」…を付ける（大文字小文字は区別しない）
+func uniqueArchivePath(used map[string]bool, dir, name string) string {
+	name = archiveFileName(name)
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	if base == "" {
+		base, ext = name, ""
+	}
+
+	candidate := path.Join(dir, name)
+	for i := 2; used[strings.ToLower(candidate)]; i++ {
+		candidate = path.Join(dir, fmt.Sprintf("%s (%d)%s", base, i, ext))
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// アーカイブ内で安全に使用できるファイル名に変換する
+// パスの区切り文字を置き換え、展開時に別の場所を指す名前を使わないようにする
+func archiveFileName(name string) string {
+	name = strings.TrimSpace(strings.NewReplacer("/", "_", "\\", "_").Replace(name))
+	if name == "" || name == "." || name == ".." {
+		return "file"
+	}
+	return name
+}
+
+// ファイルの種類に応じたZIPの圧縮方式を返す
+// 画像・動画・音声や圧縮済みの形式は圧縮しても小さくならないため、そのまま格納する
+func archiveMethod(contentType string) uint16 {
+	switch {
+	case strings.HasPrefix(contentType, "image/"), strings.HasPrefix(contentType, "video/"), strings.HasPrefix(contentType, "audio/"):
+		return zip.Store
+	case contentType == "application/zip", contentType == "application/gzip", contentType == "application/x-7z-compressed":
+		return zip.Store
+	default:
+		return zip.Deflate
+	}
+}
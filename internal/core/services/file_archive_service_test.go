@@ -0,0 +1,168 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFileArchiveService() (*FileArchiveService, *mocks.FileStorage, *mocks.FileRepository, *mocks.FolderRepository, *mocks.FileShareRepository) {
+	files := new(mocks.FileStorage)
+	catalog := new(mocks.FileRepository)
+	folders := new(mocks.FolderRepository)
+	shares := new(mocks.FileShareRepository)
+	return NewFileArchiveService(files, catalog, folders, shares), files, catalog, folders, shares
+}
+
+func archivePaths(archive *domain.FileArchive) []string {
+	paths := make([]string, len(archive.Entries))
+	for i, entry := range archive.Entries {
+		paths[i] = entry.Path
+	}
+	return paths
+}
+
+func TestFileArchiveService_PrepareArchive(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("duplicate names are numbered and missing files are reported", func(t *testing.T) {
+		// Setup
+		service, _, catalog, _, _ := newTestFileArchiveService()
+
+		// Mock expectations
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1", Name: "essay.docx", UploaderID: 1, UploaderRole: "teacher"}, nil)
+		catalog.On("GetFileByID", "file-2").Return(&domain.File{ID: "file-2", Name: "Essay.docx", UploaderID: 1, UploaderRole: "teacher"}, nil)
+		catalog.On("GetFileByID", "file-3").Return(&domain.File{ID: "file-3", Name: "../notes.txt", UploaderID: 1, UploaderRole: "teacher"}, nil)
+		catalog.On("GetFileByID", "file-4").Return(nil, domain.ErrNotFound)
+
+		// Test
+		archive, err := service.PrepareArchive(ctx, 1, &domain.FileArchiveRequest{FileIDs: []string{"file-1", "file-2", "file-3", "file-4", "file-1"}})
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, "files.zip", archive.Name)
+		assert.Equal(t, []string{"essay.docx", "Essay (2).docx", ".._notes.txt"}, archivePaths(archive))
+		assert.Equal(t, []string{"file-4"}, archive.Missing)
+	})
+
+	t.Run("files the teacher cannot read are reported as missing", func(t *testing.T) {
+		// Setup
+		service, _, catalog, _, shares := newTestFileArchiveService()
+
+		// Mock expectations
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1", Name: "essay.docx", UploaderID: 7, UploaderRole: "student"}, nil)
+		catalog.On("GetFileByID", "file-2").Return(&domain.File{ID: "file-2", Name: "plan.pdf", UploaderID: 2, UploaderRole: "teacher"}, nil)
+		catalog.On("GetFileByID", "file-3").Return(&domain.File{ID: "file-3", Name: "draft.docx", UploaderID: 8, UploaderRole: "student"}, nil)
+		shares.On("IsFileSubmittedToTeacher", "file-1", int64(1)).Return(true, nil)
+		shares.On("IsFileSubmittedToTeacher", "file-3", int64(1)).Return(false, nil)
+
+		// Test
+		archive, err := service.PrepareArchive(ctx, 1, &domain.FileArchiveRequest{FileIDs: []string{"file-1", "file-2", "file-3"}})
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, []string{"essay.docx"}, archivePaths(archive))
+		assert.Equal(t, []string{"file-2", "file-3"}, archive.Missing)
+	})
+
+	t.Run("no files found", func(t *testing.T) {
+		service, _, catalog, _, _ := newTestFileArchiveService()
+		catalog.On("GetFileByID", "file-4").Return(nil, domain.ErrNotFound)
+
+		_, err := service.PrepareArchive(ctx, 1, &domain.FileArchiveRequest{FileIDs: []string{"file-4"}})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("folder keeps its structure", func(t *testing.T) {
+		// Setup
+		service, _, catalog, folders, _ := newTestFileArchiveService()
+		rootID, subID := "folder-1", "folder-2"
+
+		// Mock expectations
+		folders.On("GetFolderByID", rootID).Return(&domain.Folder{ID: rootID, Name: "Unit 3", OwnerID: 1}, nil)
+		folders.On("GetFoldersByOwnerID", int64(1)).Return([]domain.Folder{
+			{ID: rootID, Name: "Unit 3", OwnerID: 1},
+			{ID: subID, Name: "handouts", ParentID: &rootID, OwnerID: 1},
+		}, nil)
+		catalog.On("ListFiles", domain.FileFilter{FolderID: rootID}).Return([]domain.File{{ID: "file-1", Name: "handouts"}, {ID: "file-2", Name: "plan.pdf"}}, nil)
+		catalog.On("ListFiles", domain.FileFilter{FolderID: subID}).Return([]domain.File{{ID: "file-3", Name: "sheet.pdf"}}, nil)
+
+		// Test
+		archive, err := service.PrepareArchive(ctx, 1, &domain.FileArchiveRequest{FolderID: rootID})
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, "Unit 3.zip", archive.Name)
+		assert.Equal(t, []string{"handouts (2)", "plan.pdf", "handouts/sheet.pdf"}, archivePaths(archive))
+	})
+
+	t.Run("folder of another teacher", func(t *testing.T) {
+		service, _, _, folders, _ := newTestFileArchiveService()
+		folders.On("GetFolderByID", "folder-1").Return(&domain.Folder{ID: "folder-1", OwnerID: 2}, nil)
+
+		_, err := service.PrepareArchive(ctx, 1, &domain.FileArchiveRequest{FolderID: "folder-1"})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("files and folder together are rejected", func(t *testing.T) {
+		service, _, _, _, _ := newTestFileArchiveService()
+
+		_, err := service.PrepareArchive(ctx, 1, &domain.FileArchiveRequest{FileIDs: []string{"file-1"}, FolderID: "folder-1"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+}
+
+func TestFileArchiveService_WriteArchive(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("contents are streamed and skipped files are listed", func(t *testing.T) {
+		// Setup
+		service, files, _, _, _ := newTestFileArchiveService()
+		archive := &domain.FileArchive{
+			Name: "files.zip",
+			Entries: []domain.FileArchiveEntry{
+				{Path: "essay.txt", File: domain.File{ID: "file-1", ContentType: "text/plain"}},
+				{Path: "photo.jpg", File: domain.File{ID: "file-2", ContentType: "image/jpeg"}},
+			},
+			Missing: []string{"file-3"},
+		}
+
+		// Mock expectations
+		files.On("Download", ctx, "file-1").Return(&domain.File{ID: "file-1"}, io.NopCloser(strings.NewReader("hello")), nil)
+		files.On("Download", ctx, "file-2").Return(nil, nil, domain.ErrQuarantined)
+
+		// Test
+		var buf bytes.Buffer
+		err := service.WriteArchive(ctx, archive, &buf)
+
+		// Assertions
+		require.NoError(t, err)
+		reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		require.Len(t, reader.File, 2)
+		assert.Equal(t, "essay.txt", reader.File[0].Name)
+		assert.Equal(t, "hello", readZipEntry(t, reader.File[0]))
+		assert.Equal(t, "MISSING.txt", reader.File[1].Name)
+		assert.Equal(t, "file-3\tnot found\nfile-2\tquarantined\n", readZipEntry(t, reader.File[1]))
+	})
+}
+
+func readZipEntry(t *testing.T, file *zip.File) string {
+	body, err := file.Open()
+	require.NoError(t, err)
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	return string(data)
+}
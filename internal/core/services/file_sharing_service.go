@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+)
+
+// 共有リンクに設定できる有効期限の上限（作成日時からの期間）
+const maxShareLinkLifetime = 30 * 24 * time.Hour
+
+// ファイル共有サービス構造体：教師のファイルを学生や期限付きリンクで共有する業務ロジックを実装
+type FileSharingService struct {
+	// ファイル共有リポジトリインターフェース
+	repo ports.FileShareRepository
+	// ファイルカタログ
+	catalog ports.FileRepository
+	// 教師リポジトリインターフェース（共有する学生が担当学生であることの確認に使用）
+	teacherRepo ports.TeacherRepository
+	// 現在時刻を返す関数（テストで差し替え可能）
+	now func() time.Time
+}
+
+// 新しいファイル共有サービスインスタンスを作成する
+func NewFileSharingService(repo ports.FileShareRepository, catalog ports.FileRepository, teacherRepo ports.TeacherRepository) *FileSharingService {
+	return &FileSharingService{
+		repo:        repo,
+		catalog:     catalog,
+		teacherRepo: teacherRepo,
+		now:         time.Now,
+	}
+}
+
+// 教師がアップロードしたファイルを共有する
+// 学生との共有は担当学生のみ、リンクの共有は有効期限が必須で、作成したリンクのトークンは作成時のみ返す
+func (s *FileSharingService) ShareFile(ctx context.Context, teacherID int64, fileID string, input *domain.FileShareCreate) (*domain.FileShare, error) {
+	if _, err := teacherFile(s.catalog, teacherID, fileID); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future", domain.ErrInvalidInput)
+	}
+	share := &domain.FileShare{
+		FileID:    fileID,
+		Kind:      input.Kind,
+		TeacherID: teacherID,
+		ExpiresAt: input.ExpiresAt,
+		CreatedAt: now,
+	}
+
+	switch input.Kind {
+	case domain.FileShareStudent:
+		if input.StudentID == nil {
+			return nil, fmt.Errorf("%w: student_id is required to share with a student", domain.ErrInvalidInput)
+		}
+		assigned, err := s.teacherRepo.IsStudentAssigned(teacherID, *input.StudentID)
+		if err != nil {
+			return nil, err
+		}
+		if !assigned {
+			return nil, fmt.Errorf("%w: student %d is not assigned to teacher %d", domain.ErrForbidden, *input.StudentID, teacherID)
+		}
+		share.StudentID = input.StudentID
+		if err := s.checkDuplicate(share, now); err != nil {
+			return nil, err
+		}
+	case domain.FileShareRoster:
+		if err := s.checkDuplicate(share, now); err != nil {
+			return nil, err
+		}
+	case domain.FileShareLink:
+		if input.ExpiresAt == nil {
+			return nil, fmt.Errorf("%w: expires_at is required for a shared link", domain.ErrInvalidInput)
+		}
+		if input.ExpiresAt.After(now.Add(maxShareLinkLifetime)) {
+			return nil, fmt.Errorf("%w: a shared link can be valid for at most %s", domain.ErrInvalidInput, maxShareLinkLifetime)
+		}
+		token, err := newShareToken()
+		if err != nil {
+			return nil, err
+		}
+		share.Token = token
+		share.TokenHash = hashShareToken(token)
+	default:
+		return nil, fmt.Errorf("%w: unknown share kind %q", domain.ErrInvalidInput, input.Kind)
+	}
+
+	id, err := s.repo.CreateFileShare(share)
+	if err != nil {
+		return nil, err
+	}
+	share.ID = id
+	return share, nil
+}
+
+// 教師がアップロードしたファイルの共有一覧を取得する
+func (s *FileSharingService) ListShares(ctx context.Context, teacherID int64, fileID string) ([]domain.FileShare, error) {
+	if _, err := teacherFile(s.catalog, teacherID, fileID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetFileSharesByFileID(fileID)
+}
+
+// 教師がアップロードしたファイルの共有を取り消す
+func (s *FileSharingService) RevokeShare(ctx context.Context, teacherID int64, fileID string, shareID int64) error {
+	if _, err := teacherFile(s.catalog, teacherID, fileID); err != nil {
+		return err
+	}
+	return s.repo.DeleteFileShare(fileID, shareID)
+}
+
+// 学生と共有されているファイルの一覧を取得する（期限切れの共有とゴミ箱にあるファイルは除く）
+func (s *FileSharingService) ListSharedWithStudent(ctx context.Context, studentID int64) ([]domain.File, error) {
+	return s.repo.GetFilesSharedWithStudent(studentID, s.now().UTC())
+}
+
+// 学生がファイルを閲覧できることを確認する
+// 学生個人または担当学生全員への有効な共有がない場合はErrForbiddenを返す
+func (s *FileSharingService) AuthorizeStudent(ctx context.Context, studentID int64, fileID string) error {
+	if _, err := s.catalog.GetFileByID(fileID); err != nil {
+		return err
+	}
+
+	shared, err := s.repo.IsFileSharedWithStudent(fileID, studentID, s.now().UTC())
+	if err != nil {
+		return err
+	}
+	if !shared {
+		return fmt.Errorf("%w: file %s is not shared with student %d", domain.ErrForbidden, fileID, studentID)
+	}
+	return nil
+}
+
+// 学生が課題の添付ファイルを閲覧できることを確認する
+// 担当教師の課題に添付されたファイルのみ閲覧でき、それ以外はErrForbiddenを返す
+func (s *FileSharingService) AuthorizeAssignmentFile(ctx context.Context, studentID, assignmentID int64, fileID string) error {
+	if _, err := s.catalog.GetFileByID(fileID); err != nil {
+		return err
+	}
+
+	attached, err := s.repo.IsAssignmentFileForStudent(assignmentID, fileID, studentID)
+	if err != nil {
+		return err
+	}
+	if !attached {
+		return fmt.Errorf("%w: file %s is not attached to an assignment of student %d", domain.ErrForbidden, fileID, studentID)
+	}
+	return nil
+}
+
+// 教師がファイルを閲覧できることを確認する
+// 自身がアップロードしたファイルと、自身の課題に学生が提出したファイルのみ閲覧でき、それ以外はErrForbiddenを返す
+func (s *FileSharingService) AuthorizeTeacher(ctx context.Context, teacherID int64, fileID string) error {
+	_, err := teacherReadableFile(s.catalog, s.repo, teacherID, fileID)
+	return err
+}
+
+// 教師がファイルをアップロードした本人であることを確認する
+// バージョンの追加や復元など、ファイルの内容と所有者の使用量を変更する操作の前に使用する
+func (s *FileSharingService) AuthorizeOwner(ctx context.Context, teacherID int64, fileID string) error {
+	_, err := teacherFile(s.catalog, teacherID, fileID)
+	return err
+}
+
+// 共有リンクのトークンから共有されているファイルのIDを取得する
+// 期限切れのリンクは存在しないリンクと同じくErrNotFoundを返す
+func (s *FileSharingService) ResolveLink(ctx context.Context, token string) (string, error) {
+	share, err := s.repo.GetFileShareByTokenHash(hashShareToken(token))
+	if err != nil {
+		return "", err
+	}
+	if share.ExpiresAt == nil || !share.ExpiresAt.After(s.now()) {
+		return "", fmt.Errorf("%w: shared link has expired", domain.ErrNotFound)
+	}
+	return share.FileID, nil
+}
+
+// 指定されたIDのファイルをカタログから取得し、教師が閲覧できることを確認する
+// 教師がアップロードしたファイルか、教師の課題に学生が提出したファイルのみ閲覧できる
+func teacherReadableFile(catalog ports.FileRepository, shares ports.FileShareRepository, teacherID int64, id string) (*domain.File, error) {
+	file, err := catalog.GetFileByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if file.UploaderRole == domain.RoleTeacher && file.UploaderID == teacherID {
+		return file, nil
+	}
+	if file.UploaderRole == domain.RoleStudent {
+		submitted, err := shares.IsFileSubmittedToTeacher(id, teacherID)
+		if err != nil {
+			return nil, err
+		}
+		if submitted {
+			return file, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: file %s is not available to teacher %d", domain.ErrForbidden, id, teacherID)
+}
+
+// 同じ相手への有効な共有が既にないことを確認する
+func (s *FileSharingService) checkDuplicate(share *domain.FileShare, now time.Time) error {
+	existing, err := s.repo.GetFileSharesByFileID(share.FileID)
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if other.Kind != share.Kind || (other.ExpiresAt != nil && !other.ExpiresAt.After(now)) {
+			continue
+		}
+		if share.Kind == domain.FileShareRoster || *other.StudentID == *share.StudentID {
+			return fmt.Errorf("%w: file %s is already shared this way", domain.ErrAlreadyExists, share.FileID)
+		}
+	}
+	return nil
+}
+
+// 共有リンクのトークンを作成する
+func newShareToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// 共有リンクのトークンを保存用のSHA-256ハッシュ（16進数）に変換する
+func hashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
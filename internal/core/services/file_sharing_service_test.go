@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFileSharingService(now time.Time) (*FileSharingService, *mocks.FileShareRepository, *mocks.FileRepository, *mocks.TeacherRepository) {
+	repo := new(mocks.FileShareRepository)
+	catalog := new(mocks.FileRepository)
+	teacherRepo := new(mocks.TeacherRepository)
+	service := NewFileSharingService(repo, catalog, teacherRepo)
+	service.now = func() time.Time { return now }
+	return service, repo, catalog, teacherRepo
+}
+
+func TestFileSharingService_ShareFile(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+	file := &domain.File{ID: "file-1", UploaderID: 1, UploaderRole: "teacher"}
+
+	t.Run("share with an assigned student", func(t *testing.T) {
+		// Setup
+		service, repo, catalog, teacherRepo := newTestFileSharingService(now)
+		studentID := int64(12)
+
+		// Mock expectations
+		catalog.On("GetFileByID", "file-1").Return(file, nil)
+		teacherRepo.On("IsStudentAssigned", int64(1), int64(12)).Return(true, nil)
+		repo.On("GetFileSharesByFileID", "file-1").Return([]domain.FileShare{}, nil)
+		repo.On("CreateFileShare", mock.MatchedBy(func(s *domain.FileShare) bool {
+			return s.Kind == domain.FileShareStudent && *s.StudentID == 12 && s.TeacherID == 1 && s.TokenHash == ""
+		})).Return(int64(3), nil)
+
+		// Test
+		share, err := service.ShareFile(ctx, 1, "file-1", &domain.FileShareCreate{Kind: domain.FileShareStudent, StudentID: &studentID})
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), share.ID)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("unassigned student is rejected", func(t *testing.T) {
+		service, repo, catalog, teacherRepo := newTestFileSharingService(now)
+		studentID := int64(99)
+		catalog.On("GetFileByID", "file-1").Return(file, nil)
+		teacherRepo.On("IsStudentAssigned", int64(1), int64(99)).Return(false, nil)
+
+		_, err := service.ShareFile(ctx, 1, "file-1", &domain.FileShareCreate{Kind: domain.FileShareStudent, StudentID: &studentID})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+		repo.AssertNotCalled(t, "CreateFileShare", mock.Anything)
+	})
+
+	t.Run("roster already shared is rejected", func(t *testing.T) {
+		service, repo, catalog, _ := newTestFileSharingService(now)
+		catalog.On("GetFileByID", "file-1").Return(file, nil)
+		repo.On("GetFileSharesByFileID", "file-1").Return([]domain.FileShare{{ID: 1, FileID: "file-1", Kind: domain.FileShareRoster}}, nil)
+
+		_, err := service.ShareFile(ctx, 1, "file-1", &domain.FileShareCreate{Kind: domain.FileShareRoster})
+
+		assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+	})
+
+	t.Run("link gets a token and only its hash is stored", func(t *testing.T) {
+		service, repo, catalog, _ := newTestFileSharingService(now)
+		expiresAt := now.Add(7 * 24 * time.Hour)
+		catalog.On("GetFileByID", "file-1").Return(file, nil)
+		repo.On("CreateFileShare", mock.MatchedBy(func(s *domain.FileShare) bool {
+			return s.Kind == domain.FileShareLink && s.TokenHash == hashShareToken(s.Token)
+		})).Return(int64(4), nil)
+
+		share, err := service.ShareFile(ctx, 1, "file-1", &domain.FileShareCreate{Kind: domain.FileShareLink, ExpiresAt: &expiresAt})
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, share.Token)
+		assert.NotEqual(t, share.Token, share.TokenHash)
+	})
+
+	t.Run("link without expiry is rejected", func(t *testing.T) {
+		service, _, catalog, _ := newTestFileSharingService(now)
+		catalog.On("GetFileByID", "file-1").Return(file, nil)
+
+		_, err := service.ShareFile(ctx, 1, "file-1", &domain.FileShareCreate{Kind: domain.FileShareLink})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("link beyond the maximum lifetime is rejected", func(t *testing.T) {
+		service, _, catalog, _ := newTestFileSharingService(now)
+		expiresAt := now.Add(maxShareLinkLifetime + time.Hour)
+		catalog.On("GetFileByID", "file-1").Return(file, nil)
+
+		_, err := service.ShareFile(ctx, 1, "file-1", &domain.FileShareCreate{Kind: domain.FileShareLink, ExpiresAt: &expiresAt})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("file uploaded by another teacher is rejected", func(t *testing.T) {
+		service, _, catalog, _ := newTestFileSharingService(now)
+		catalog.On("GetFileByID", "file-1").Return(file, nil)
+
+		_, err := service.ShareFile(ctx, 2, "file-1", &domain.FileShareCreate{Kind: domain.FileShareRoster})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+}
+
+func TestFileSharingService_AuthorizeStudent(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+
+	t.Run("shared file is allowed", func(t *testing.T) {
+		service, repo, catalog, _ := newTestFileSharingService(now)
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1"}, nil)
+		repo.On("IsFileSharedWithStudent", "file-1", int64(12), now).Return(true, nil)
+
+		err := service.AuthorizeStudent(ctx, 12, "file-1")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("file not shared is forbidden", func(t *testing.T) {
+		service, repo, catalog, _ := newTestFileSharingService(now)
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1"}, nil)
+		repo.On("IsFileSharedWithStudent", "file-1", int64(12), now).Return(false, nil)
+
+		err := service.AuthorizeStudent(ctx, 12, "file-1")
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+}
+
+func TestFileSharingService_AuthorizeAssignmentFile(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+
+	t.Run("attachment of an assignment of the student's teacher is allowed", func(t *testing.T) {
+		service, repo, catalog, _ := newTestFileSharingService(now)
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1"}, nil)
+		repo.On("IsAssignmentFileForStudent", int64(3), "file-1", int64(12)).Return(true, nil)
+
+		err := service.AuthorizeAssignmentFile(ctx, 12, 3, "file-1")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("file not attached for the student is forbidden", func(t *testing.T) {
+		service, repo, catalog, _ := newTestFileSharingService(now)
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1"}, nil)
+		repo.On("IsAssignmentFileForStudent", int64(3), "file-1", int64(12)).Return(false, nil)
+
+		err := service.AuthorizeAssignmentFile(ctx, 12, 3, "file-1")
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+}
+
+func TestFileSharingService_AuthorizeTeacher(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+
+	t.Run("own upload is allowed", func(t *testing.T) {
+		service, _, catalog, _ := newTestFileSharingService(now)
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1", UploaderID: 1, UploaderRole: "teacher"}, nil)
+
+		err := service.AuthorizeTeacher(ctx, 1, "file-1")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("submission to the teacher's assignment is allowed", func(t *testing.T) {
+		service, repo, catalog, _ := newTestFileSharingService(now)
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1", UploaderID: 12, UploaderRole: "student"}, nil)
+		repo.On("IsFileSubmittedToTeacher", "file-1", int64(1)).Return(true, nil)
+
+		err := service.AuthorizeTeacher(ctx, 1, "file-1")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("submission to another teacher is forbidden", func(t *testing.T) {
+		service, repo, catalog, _ := newTestFileSharingService(now)
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1", UploaderID: 12, UploaderRole: "student"}, nil)
+		repo.On("IsFileSubmittedToTeacher", "file-1", int64(1)).Return(false, nil)
+
+		err := service.AuthorizeTeacher(ctx, 1, "file-1")
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("private file of another teacher is forbidden", func(t *testing.T) {
+		service, repo, catalog, _ := newTestFileSharingService(now)
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1", UploaderID: 2, UploaderRole: "teacher"}, nil)
+
+		err := service.AuthorizeTeacher(ctx, 1, "file-1")
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+		repo.AssertNotCalled(t, "IsFileSubmittedToTeacher", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFileSharingService_AuthorizeOwner(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+
+	t.Run("uploader is allowed", func(t *testing.T) {
+		service, _, catalog, _ := newTestFileSharingService(now)
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1", UploaderID: 1, UploaderRole: "teacher"}, nil)
+
+		err := service.AuthorizeOwner(ctx, 1, "file-1")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("file of another teacher is forbidden", func(t *testing.T) {
+		service, _, catalog, _ := newTestFileSharingService(now)
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1", UploaderID: 2, UploaderRole: "teacher"}, nil)
+
+		err := service.AuthorizeOwner(ctx, 1, "file-1")
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("submission of a student with the same ID is forbidden", func(t *testing.T) {
+		service, _, catalog, _ := newTestFileSharingService(now)
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1", UploaderID: 1, UploaderRole: "student"}, nil)
+
+		err := service.AuthorizeOwner(ctx, 1, "file-1")
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+}
+
+func TestFileSharingService_ResolveLink(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+
+	t.Run("valid link resolves to the file", func(t *testing.T) {
+		service, repo, _, _ := newTestFileSharingService(now)
+		expiresAt := now.Add(time.Hour)
+		repo.On("GetFileShareByTokenHash", hashShareToken("token")).Return(&domain.FileShare{FileID: "file-1", ExpiresAt: &expiresAt}, nil)
+
+		fileID, err := service.ResolveLink(ctx, "token")
+
+		require.NoError(t, err)
+		assert.Equal(t, "file-1", fileID)
+	})
+
+	t.Run("expired link is not found", func(t *testing.T) {
+		service, repo, _, _ := newTestFileSharingService(now)
+		expiresAt := now.Add(-time.Minute)
+		repo.On("GetFileShareByTokenHash", hashShareToken("token")).Return(&domain.FileShare{FileID: "file-1", ExpiresAt: &expiresAt}, nil)
+
+		_, err := service.ResolveLink(ctx, "token")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
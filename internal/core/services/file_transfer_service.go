@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// ファイル転送サービス構造体：署名付きURLによるストレージとの直接転送を実装
+// ファイルの内容はクライアントとストレージの間で直接転送し、このサービスはURLの発行とカタログへの登録のみを行う
+type FileTransferService struct {
+	// オブジェクトを保存するファイルストレージ（IDはオブジェクトキー）
+	objects ports.FileStorage
+	// 署名付きURLを作成するストレージ（ファイルストレージが対応していない場合はnil）
+	signer ports.URLSigner
+	// ファイルカタログ
+	catalog ports.FileRepository
+	// アップロードポリシー（nilの場合は制限しない）
+	policy ports.UploadPolicyService
+	// コンテンツ検査サービス（nilの場合は定期的な確認による検査のみ）
+	scans ports.ContentScanService
+	// 署名付きURLの有効期間
+	expiry time.Duration
+	// 現在時刻を返す関数（テストで差し替え可能）
+	now func() time.Time
+}
+
+// 新しいファイル転送サービスインスタンスを作成する
+// ファイルストレージがports.URLSignerを実装していない場合、直接転送は利用できない
+func NewFileTransferService(objects ports.FileStorage, catalog ports.FileRepository, policy ports.UploadPolicyService, scans ports.ContentScanService, expiry time.Duration) *FileTransferService {
+	signer, _ := objects.(ports.URLSigner)
+	return &FileTransferService{
+		objects: objects,
+		signer:  signer,
+		catalog: catalog,
+		policy:  policy,
+		scans:   scans,
+		expiry:  expiry,
+		now:     time.Now,
+	}
+}
+
+// ストレージへ直接アップロードするための署名付きURLを作成する
+func (s *FileTransferService) CreateUploadURL(ctx context.Context, request *domain.UploadURLRequest) (*domain.PresignedUpload, error) {
+	if s.signer == nil {
+		return nil, fmt.Errorf("%w: file storage does not support presigned URLs", domain.ErrNotSupported)
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	request.ContentType = strings.TrimSpace(request.ContentType)
+	if request.Name == "" || request.ContentType == "" {
+		return nil, fmt.Errorf("%w: name and content_type are required", domain.ErrInvalidInput)
+	}
+	if request.Size <= 0 {
+		return nil, fmt.Errorf("%w: size must be positive", domain.ErrInvalidInput)
+	}
+	if s.policy != nil {
+		if err := s.policy.CheckDeclared(request.UploaderRole, request.Name, request.Size); err != nil {
+			return nil, err
+		}
+		if err := s.policy.CheckQuota(request.UploaderRole, request.UploaderID, request.Size); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.signer.PresignUpload(ctx, request, s.expiry)
+}
+
+// 直接アップロードの完了を受け取り、アップロードされたオブジェクトをカタログに登録する
+// オブジェクトはURLを発行したユーザー自身がアップロードしたものである必要がある
+// チェックサムとMIMEタイプはストレージ上のオブジェクトを読み込んで判定し、
+// アップロードポリシーやクォータを満たさないオブジェクトは削除する
+// 同じ内容のファイルが既に保存されている場合は既存のオブジェクトを共有し、アップロードされたオブジェクトは削除する
+func (s *FileTransferService) CompleteUpload(ctx context.Context, uploaderID int64, uploaderRole string, uploadID string) (*domain.File, error) {
+	if s.signer == nil {
+		return nil, fmt.Errorf("%w: file storage does not support presigned URLs", domain.ErrNotSupported)
+	}
+
+	// 登録済みのアップロードはオブジェクトを読み込む前に拒否する（同時の完了はCreateUploadedFileで防ぐ）
+	if _, err := s.catalog.GetFileByObjectKey(uploadID); err == nil {
+		return nil, fmt.Errorf("%w: upload %s has already been completed", domain.ErrAlreadyExists, uploadID)
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	object, err := s.objects.Get(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if object.UploaderID != uploaderID || object.UploaderRole != uploaderRole {
+		return nil, fmt.Errorf("%w: upload %s was not issued to this user", domain.ErrForbidden, uploadID)
+	}
+
+	checksum, size, head, err := s.readObject(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := object.ContentType
+	var quota domain.StorageQuota
+	if s.policy != nil {
+		quota = s.policy.Quota(uploaderRole)
+		contentType, err = s.checkPolicy(uploaderRole, object.Name, size, head)
+		if err != nil {
+			if deleteErr := s.objects.Delete(ctx, uploadID); deleteErr != nil {
+				return nil, fmt.Errorf("%w (rejected object was not deleted: %v)", err, deleteErr)
+			}
+			return nil, err
+		}
+	}
+
+	record := &domain.File{
+		ID:           uuid.New().String(),
+		Name:         object.Name,
+		Size:         size,
+		ContentType:  contentType,
+		UploadedAt:   s.now().UTC(),
+		ObjectKey:    uploadID,
+		Checksum:     checksum,
+		UploaderID:   uploaderID,
+		UploaderRole: uploaderRole,
+	}
+	if err := s.catalog.CreateUploadedFile(record, quota); err != nil {
+		// 同時に完了した別のリクエストが登録したオブジェクトは削除しない
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return nil, err
+		}
+		// クォータを超えた場合など、登録できなかったオブジェクトは残さない
+		if deleteErr := s.objects.Delete(ctx, uploadID); deleteErr != nil {
+			return nil, fmt.Errorf("%w (rejected object was not deleted: %v)", err, deleteErr)
+		}
+		return nil, err
+	}
+
+	// 同じ内容のオブジェクトが既に保存されていた場合、アップロードされたオブジェクトは不要になる
+	if record.ObjectKey != uploadID {
+		if err := s.objects.Delete(ctx, uploadID); err != nil {
+			slog.Error("failed to remove duplicate object", slog.String("key", uploadID), slog.String("error", err.Error()))
+		}
+	}
+	if s.scans != nil {
+		s.scans.Enqueue(record)
+	}
+	return record, nil
+}
+
+// カタログに登録されているファイルを直接ダウンロードするための署名付きURLを作成する
+func (s *FileTransferService) CreateDownloadURL(ctx context.Context, fileID string) (*domain.PresignedURL, error) {
+	if s.signer == nil {
+		return nil, fmt.Errorf("%w: file storage does not support presigned URLs", domain.ErrNotSupported)
+	}
+
+	record, err := s.catalog.GetFileByID(fileID)
+	if err != nil {
+		return nil, err
+	}
+	// 署名付きURLはカタログを経由しないため、発行前にマルウェア検査の状態を確認する
+	if err := record.CheckServable(); err != nil {
+		return nil, fmt.Errorf("%w: file %s", err, fileID)
+	}
+	return s.signer.PresignDownload(ctx, record.ObjectKey, record.Name, s.expiry)
+}
+
+// アップロードされたオブジェクトがポリシーを満たしているか検証し、内容から判定したMIMEタイプを返す
+func (s *FileTransferService) checkPolicy(role string, name string, size int64, head []byte) (string, error) {
+	if err := s.policy.CheckDeclared(role, name, size); err != nil {
+		return "", err
+	}
+	return s.policy.DetectContentType(role, head)
+}
+
+// ストレージ上のオブジェクトを読み込み、SHA-256チェックサム、サイズ、MIMEタイプの判定に使用する先頭の内容を返す
+func (s *FileTransferService) readObject(ctx context.Context, key string) (string, int64, []byte, error) {
+	_, body, err := s.objects.Download(ctx, key)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	defer body.Close()
+
+	hash := sha256.New()
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(body, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", 0, nil, fmt.Errorf("failed to read uploaded object: %w", err)
+	}
+	head = head[:n]
+	hash.Write(head)
+
+	rest, err := io.Copy(hash, body)
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("failed to read uploaded object: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), int64(n) + rest, head, nil
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// 署名付きURLに対応したファイルストレージのモック
+type signingFileStorage struct {
+	*mocks.FileStorage
+	*mocks.URLSigner
+}
+
+func newTestFileTransferService(now time.Time) (*FileTransferService, *mocks.FileStorage, *mocks.URLSigner, *mocks.FileRepository) {
+	objects := new(mocks.FileStorage)
+	signer := new(mocks.URLSigner)
+	catalog := new(mocks.FileRepository)
+	service := NewFileTransferService(signingFileStorage{FileStorage: objects, URLSigner: signer}, catalog, nil, nil, 15*time.Minute)
+	service.now = func() time.Time { return now }
+	return service, objects, signer, catalog
+}
+
+func TestFileTransferService_CreateUploadURL(t *testing.T) {
+	// Setup
+	service, _, signer, _ := newTestFileTransferService(time.Now())
+	request := &domain.UploadURLRequest{Name: " lecture.mp4 ", ContentType: "video/mp4", Size: 1024, UploaderID: 7, UploaderRole: domain.RoleTeacher}
+
+	// Mock expectations
+	signer.On("PresignUpload", mock.Anything, mock.MatchedBy(func(r *domain.UploadURLRequest) bool {
+		return r.Name == "lecture.mp4" && r.Size == 1024 && r.UploaderID == 7
+	}), 15*time.Minute).Return(&domain.PresignedUpload{UploadID: "key"}, nil)
+
+	// Test
+	upload, err := service.CreateUploadURL(context.Background(), request)
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, "key", upload.UploadID)
+}
+
+func TestFileTransferService_NotSupported(t *testing.T) {
+	// Setup
+	service := NewFileTransferService(new(mocks.FileStorage), new(mocks.FileRepository), nil, nil, time.Minute)
+	ctx := context.Background()
+
+	// Test
+	_, uploadErr := service.CreateUploadURL(ctx, &domain.UploadURLRequest{Name: "a.pdf", ContentType: "application/pdf", Size: 1})
+	_, completeErr := service.CompleteUpload(ctx, 7, domain.RoleTeacher, "key")
+	_, downloadErr := service.CreateDownloadURL(ctx, "file-1")
+
+	// Assertions
+	assert.ErrorIs(t, uploadErr, domain.ErrNotSupported)
+	assert.ErrorIs(t, completeErr, domain.ErrNotSupported)
+	assert.ErrorIs(t, downloadErr, domain.ErrNotSupported)
+}
+
+func TestFileTransferService_CompleteUpload(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+	key := "key"
+
+	t.Run("registers the uploaded object", func(t *testing.T) {
+		service, objects, _, catalog := newTestFileTransferService(now)
+		catalog.On("GetFileByObjectKey", key).Return(nil, fmt.Errorf("%w: no file", domain.ErrNotFound))
+		objects.On("Get", mock.Anything, key).Return(&domain.File{
+			ID: key, Name: "notes.pdf", Size: 4, ContentType: "application/pdf", UploaderID: 7, UploaderRole: domain.RoleTeacher,
+		}, nil)
+		objects.On("Download", mock.Anything, key).Return(&domain.File{ID: key}, io.NopCloser(strings.NewReader("test")), nil)
+		catalog.On("CreateUploadedFile", mock.AnythingOfType("*domain.File"), domain.StorageQuota{}).Return(nil)
+
+		file, err := service.CompleteUpload(ctx, 7, domain.RoleTeacher, key)
+
+		require.NoError(t, err)
+		assert.Equal(t, "notes.pdf", file.Name)
+		assert.Equal(t, key, file.ObjectKey)
+		assert.Equal(t, int64(4), file.Size)
+		assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", file.Checksum)
+		assert.Equal(t, now, file.UploadedAt)
+		assert.NotEmpty(t, file.ID)
+		catalog.AssertExpectations(t)
+	})
+
+	t.Run("duplicate content shares the existing object", func(t *testing.T) {
+		service, objects, _, catalog := newTestFileTransferService(now)
+		catalog.On("GetFileByObjectKey", key).Return(nil, fmt.Errorf("%w: no file", domain.ErrNotFound))
+		objects.On("Get", mock.Anything, key).Return(&domain.File{ID: key, Name: "worksheet.pdf", UploaderID: 7, UploaderRole: domain.RoleTeacher}, nil)
+		objects.On("Download", mock.Anything, key).Return(&domain.File{ID: key}, io.NopCloser(strings.NewReader("test")), nil)
+		catalog.On("CreateUploadedFile", mock.AnythingOfType("*domain.File"), domain.StorageQuota{}).
+			Run(func(args mock.Arguments) { args.Get(0).(*domain.File).ObjectKey = "existing" }).
+			Return(nil)
+		objects.On("Delete", mock.Anything, key).Return(nil)
+
+		file, err := service.CompleteUpload(ctx, 7, domain.RoleTeacher, key)
+
+		require.NoError(t, err)
+		assert.Equal(t, "existing", file.ObjectKey)
+		objects.AssertCalled(t, "Delete", mock.Anything, key)
+	})
+
+	t.Run("object uploaded by another user", func(t *testing.T) {
+		service, objects, _, catalog := newTestFileTransferService(now)
+		catalog.On("GetFileByObjectKey", key).Return(nil, fmt.Errorf("%w: no file", domain.ErrNotFound))
+		objects.On("Get", mock.Anything, key).Return(&domain.File{ID: key, UploaderID: 8, UploaderRole: domain.RoleTeacher}, nil)
+
+		_, err := service.CompleteUpload(ctx, 7, domain.RoleTeacher, key)
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+		catalog.AssertNotCalled(t, "CreateUploadedFile", mock.Anything, mock.Anything)
+	})
+
+	t.Run("object violating the upload policy is deleted", func(t *testing.T) {
+		service, objects, _, catalog := newTestFileTransferService(now)
+		service.policy = newTestUploadPolicyService()
+		catalog.On("GetFileByObjectKey", key).Return(nil, fmt.Errorf("%w: no file", domain.ErrNotFound))
+		objects.On("Get", mock.Anything, key).Return(&domain.File{ID: key, Name: "page.pdf", UploaderID: 12, UploaderRole: domain.RoleStudent}, nil)
+		objects.On("Download", mock.Anything, key).Return(&domain.File{ID: key}, io.NopCloser(strings.NewReader("<html></html>")), nil)
+		objects.On("Delete", mock.Anything, key).Return(nil)
+
+		_, err := service.CompleteUpload(ctx, 12, domain.RoleStudent, key)
+
+		assert.ErrorIs(t, err, domain.ErrUnsupportedMedia)
+		objects.AssertCalled(t, "Delete", mock.Anything, key)
+		catalog.AssertNotCalled(t, "CreateUploadedFile", mock.Anything, mock.Anything)
+	})
+
+	t.Run("object exceeding the quota is deleted", func(t *testing.T) {
+		service, objects, _, catalog := newTestFileTransferService(now)
+		service.policy = newTestUploadPolicyService()
+		catalog.On("GetFileByObjectKey", key).Return(nil, fmt.Errorf("%w: no file", domain.ErrNotFound))
+		objects.On("Get", mock.Anything, key).Return(&domain.File{ID: key, Name: "essay.pdf", UploaderID: 12, UploaderRole: domain.RoleStudent}, nil)
+		objects.On("Download", mock.Anything, key).Return(&domain.File{ID: key}, io.NopCloser(strings.NewReader("%PDF-1.4 essay")), nil)
+		catalog.On("CreateUploadedFile", mock.AnythingOfType("*domain.File"), domain.StorageQuota{MaxBytes: 64, MaxFiles: 3}).
+			Return(fmt.Errorf("%w: over quota", domain.ErrQuotaExceeded))
+		objects.On("Delete", mock.Anything, key).Return(nil)
+
+		_, err := service.CompleteUpload(ctx, 12, domain.RoleStudent, key)
+
+		assert.ErrorIs(t, err, domain.ErrQuotaExceeded)
+		objects.AssertCalled(t, "Delete", mock.Anything, key)
+	})
+
+	t.Run("already completed", func(t *testing.T) {
+		service, objects, _, catalog := newTestFileTransferService(now)
+		catalog.On("GetFileByObjectKey", key).Return(&domain.File{ID: "file-1", ObjectKey: key}, nil)
+
+		_, err := service.CompleteUpload(ctx, 7, domain.RoleTeacher, key)
+
+		assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+		objects.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	})
+
+	t.Run("completed concurrently by another request", func(t *testing.T) {
+		service, objects, _, catalog := newTestFileTransferService(now)
+		catalog.On("GetFileByObjectKey", key).Return(nil, fmt.Errorf("%w: no file", domain.ErrNotFound))
+		objects.On("Get", mock.Anything, key).Return(&domain.File{ID: key, Name: "notes.pdf", UploaderID: 7, UploaderRole: domain.RoleTeacher}, nil)
+		objects.On("Download", mock.Anything, key).Return(&domain.File{ID: key}, io.NopCloser(strings.NewReader("test")), nil)
+		catalog.On("CreateUploadedFile", mock.AnythingOfType("*domain.File"), domain.StorageQuota{}).
+			Return(fmt.Errorf("%w: upload %s has already been completed", domain.ErrAlreadyExists, key))
+
+		_, err := service.CompleteUpload(ctx, 7, domain.RoleTeacher, key)
+
+		// 先に完了したリクエストが登録したオブジェクトは削除しない
+		assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+		objects.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFileTransferService_CreateDownloadURL(t *testing.T) {
+	// Setup
+	service, _, signer, catalog := newTestFileTransferService(time.Now())
+
+	// Mock expectations
+	catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1", Name: "notes.pdf", ObjectKey: "key"}, nil)
+	signer.On("PresignDownload", mock.Anything, "key", "notes.pdf", 15*time.Minute).
+		Return(&domain.PresignedURL{URL: "https://bucket/key", Method: "GET"}, nil)
+
+	// Test
+	download, err := service.CreateDownloadURL(context.Background(), "file-1")
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, "https://bucket/key", download.URL)
+}
+
+func TestFileTransferService_CreateDownloadURL_ScanPending(t *testing.T) {
+	// Setup
+	service, _, signer, catalog := newTestFileTransferService(time.Now())
+
+	// Mock expectations
+	catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1", Name: "notes.pdf", ObjectKey: "key", ScanStatus: domain.ScanPending}, nil)
+
+	// Test
+	_, err := service.CreateDownloadURL(context.Background(), "file-1")
+
+	// Assertions
+	assert.ErrorIs(t, err, domain.ErrScanPending)
+	signer.AssertNotCalled(t, "PresignDownload", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
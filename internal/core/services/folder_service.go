@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const (
+	// フォルダ名の最大文字数
+	maxFolderNameLength = 255
+	// タグの最大文字数
+	maxTagLength = 64
+	// 1つのファイルに付けられるタグの最大数
+	maxFileTags = 20
+)
+
+// フォルダサービス構造体：フォルダとタグによるファイルの整理を実装
+// フォルダとタグはファイルカタログ上の分類のみを変更し、ファイルの実データには触れない
+type FolderService struct {
+	// フォルダリポジトリインターフェース
+	repo ports.FolderRepository
+	// ファイルカタログ
+	catalog ports.FileRepository
+	// 現在時刻を返す関数（テストで差し替え可能）
+	now func() time.Time
+}
+
+// 新しいフォルダサービスインスタンスを作成する
+func NewFolderService(repo ports.FolderRepository, catalog ports.FileRepository) *FolderService {
+	return &FolderService{
+		repo:    repo,
+		catalog: catalog,
+		now:     time.Now,
+	}
+}
+
+// 教師のフォルダを作成する
+// 親フォルダは教師が所有している必要があり、同じ親フォルダ内に同じ名前のフォルダがある場合はエラーを返す
+func (s *FolderService) CreateFolder(ctx context.Context, ownerID int64, input *domain.FolderCreate) (*domain.Folder, error) {
+	name, err := normalizeFolderName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	if input.ParentID != nil {
+		if _, err := teacherFolder(s.repo, ownerID, *input.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkSiblingName(ownerID, input.ParentID, name, ""); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	folder := &domain.Folder{
+		ID:        uuid.New().String(),
+		Name:      name,
+		ParentID:  input.ParentID,
+		OwnerID:   ownerID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.CreateFolder(folder); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+// 教師のフォルダ一覧を取得する
+func (s *FolderService) ListFolders(ctx context.Context, ownerID int64) ([]domain.Folder, error) {
+	return s.repo.GetFoldersByOwnerID(ownerID)
+}
+
+// 教師のフォルダの名前を変更する
+func (s *FolderService) RenameFolder(ctx context.Context, ownerID int64, id string, input *domain.FolderRename) (*domain.Folder, error) {
+	name, err := normalizeFolderName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	folder, err := teacherFolder(s.repo, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSiblingName(ownerID, folder.ParentID, name, folder.ID); err != nil {
+		return nil, err
+	}
+
+	folder.Name = name
+	folder.UpdatedAt = s.now().UTC()
+	if err := s.repo.UpdateFolder(folder); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+// 教師のフォルダを別の親フォルダへ移動する
+// フォルダ自身やその中のフォルダへは移動できない
+func (s *FolderService) MoveFolder(ctx context.Context, ownerID int64, id string, input *domain.FolderMove) (*domain.Folder, error) {
+	folder, err := teacherFolder(s.repo, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.ParentID != nil {
+		if _, err := teacherFolder(s.repo, ownerID, *input.ParentID); err != nil {
+			return nil, err
+		}
+		// 移動先から最上位までたどり、移動するフォルダが含まれていないことを確認
+		folders, err := s.repo.GetFoldersByOwnerID(ownerID)
+		if err != nil {
+			return nil, err
+		}
+		parents := make(map[string]*string, len(folders))
+		for _, f := range folders {
+			parents[f.ID] = f.ParentID
+		}
+		for current := input.ParentID; current != nil; current = parents[*current] {
+			if *current == folder.ID {
+				return nil, fmt.Errorf("%w: cannot move folder %s into itself or one of its subfolders", domain.ErrInvalidInput, folder.ID)
+			}
+		}
+	}
+	if err := s.checkSiblingName(ownerID, input.ParentID, folder.Name, folder.ID); err != nil {
+		return nil, err
+	}
+
+	folder.ParentID = input.ParentID
+	folder.UpdatedAt = s.now().UTC()
+	if err := s.repo.UpdateFolder(folder); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+// 教師のフォルダを削除する
+// 空でないフォルダは中身ごと削除する指定と、確認のためのフォルダ名が一致する場合のみ削除できる
+// 中のフォルダは削除し、中のファイルはゴミ箱に移動する
+func (s *FolderService) DeleteFolder(ctx context.Context, ownerID int64, id string, input domain.FolderDelete) error {
+	folder, err := teacherFolder(s.repo, ownerID, id)
+	if err != nil {
+		return err
+	}
+
+	if input.Recursive {
+		if input.Confirm != folder.Name {
+			return fmt.Errorf("%w: confirm must match the folder name to delete %q with its contents", domain.ErrInvalidInput, folder.Name)
+		}
+	} else {
+		empty, err := s.isEmpty(ownerID, folder.ID)
+		if err != nil {
+			return err
+		}
+		if !empty {
+			return fmt.Errorf("%w: folder %q is not empty; delete it recursively to remove its contents", domain.ErrConflict, folder.Name)
+		}
+	}
+
+	_, err = s.repo.DeleteFolder(folder.ID, s.now().UTC())
+	return err
+}
+
+// 教師がアップロードしたファイルを別のフォルダへ移動する
+// カタログのフォルダのみを更新し、ファイルの実データは再アップロードしない
+func (s *FolderService) MoveFile(ctx context.Context, ownerID int64, id string, input *domain.FileMove) (*domain.File, error) {
+	if _, err := teacherFile(s.catalog, ownerID, id); err != nil {
+		return nil, err
+	}
+	if input.FolderID != nil {
+		if _, err := teacherFolder(s.repo, ownerID, *input.FolderID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.catalog.MoveFile(id, input.FolderID); err != nil {
+		return nil, err
+	}
+	return s.catalog.GetFileByID(id)
+}
+
+// 教師がアップロードしたファイルのタグを置き換える
+// タグは前後の空白を除いて小文字に揃え、重複を除いて名前順に保存する
+func (s *FolderService) SetFileTags(ctx context.Context, ownerID int64, id string, input *domain.FileTagsUpdate) (*domain.File, error) {
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) > maxFileTags {
+		return nil, fmt.Errorf("%w: a file can have at most %d tags", domain.ErrInvalidInput, maxFileTags)
+	}
+	if _, err := teacherFile(s.catalog, ownerID, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.catalog.SetFileTags(id, tags); err != nil {
+		return nil, err
+	}
+	return s.catalog.GetFileByID(id)
+}
+
+// 教師がアップロードしたファイル一覧をフォルダとタグで絞り込む
+// フォルダを指定する場合は教師が所有している必要があり、タグはすべて付いているファイルのみを返す
+func (s *FolderService) ListFiles(ctx context.Context, ownerID int64, filter domain.FileFilter) ([]domain.File, error) {
+	if filter.FolderID != "" {
+		if _, err := teacherFolder(s.repo, ownerID, filter.FolderID); err != nil {
+			return nil, err
+		}
+	}
+	tags, err := normalizeTags(filter.Tags)
+	if err != nil {
+		return nil, err
+	}
+	filter.Tags = tags
+	// フォルダを指定しない場合も他のユーザーのファイルを含めない
+	filter.UploaderID = ownerID
+	filter.UploaderRole = domain.RoleTeacher
+
+	return s.catalog.ListFiles(filter)
+}
+
+// 指定されたIDのフォルダを取得し、教師が所有していることを確認する
+func teacherFolder(repo ports.FolderRepository, ownerID int64, id string) (*domain.Folder, error) {
+	folder, err := repo.GetFolderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if folder.OwnerID != ownerID {
+		return nil, fmt.Errorf("%w: folder %s does not belong to teacher %d", domain.ErrForbidden, id, ownerID)
+	}
+	return folder, nil
+}
+
+// 同じ親フォルダ内に同じ名前のフォルダ（excludeIDのフォルダを除く）がないことを確認する
+func (s *FolderService) checkSiblingName(ownerID int64, parentID *string, name string, excludeID string) error {
+	folders, err := s.repo.GetFoldersByOwnerID(ownerID)
+	if err != nil {
+		return err
+	}
+	for _, folder := range folders {
+		if folder.ID != excludeID && sameFolder(folder.ParentID, parentID) && strings.EqualFold(folder.Name, name) {
+			return fmt.Errorf("%w: folder %q", domain.ErrAlreadyExists, name)
+		}
+	}
+	return nil
+}
+
+// フォルダの中にフォルダもファイルもないかどうかを判定する
+func (s *FolderService) isEmpty(ownerID int64, id string) (bool, error) {
+	folders, err := s.repo.GetFoldersByOwnerID(ownerID)
+	if err != nil {
+		return false, err
+	}
+	for _, folder := range folders {
+		if folder.ParentID != nil && *folder.ParentID == id {
+			return false, nil
+		}
+	}
+
+	files, err := s.catalog.ListFiles(domain.FileFilter{FolderID: id})
+	if err != nil {
+		return false, err
+	}
+	return len(files) == 0, nil
+}
+
+// 指定されたIDのファイルをカタログから取得し、教師がアップロードしたものであることを確認する
+func teacherFile(catalog ports.FileRepository, teacherID int64, id string) (*domain.File, error) {
+	file, err := catalog.GetFileByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if file.UploaderRole != "teacher" || file.UploaderID != teacherID {
+		return nil, fmt.Errorf("%w: file %s was not uploaded by teacher %d", domain.ErrForbidden, id, teacherID)
+	}
+	return file, nil
+}
+
+// 2つの親フォルダのIDが同じフォルダを指しているかどうかを判定する（nilは最上位）
+func sameFolder(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// フォルダ名の前後の空白を除き、使用できる名前か検証する
+func normalizeFolderName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: folder name must not be empty", domain.ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(name) > maxFolderNameLength {
+		return "", fmt.Errorf("%w: folder name must be at most %d characters", domain.ErrInvalidInput, maxFolderNameLength)
+	}
+	if strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("%w: folder name must not contain slashes", domain.ErrInvalidInput)
+	}
+	return name, nil
+}
+
+// タグの前後の空白を除いて小文字に揃え、重複を除いて名前順に並べる
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			return nil, fmt.Errorf("%w: tags must not be empty", domain.ErrInvalidInput)
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, fmt.Errorf("%w: tag %q must be at most %d characters", domain.ErrInvalidInput, tag, maxTagLength)
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFolderService(now time.Time) (*FolderService, *mocks.FolderRepository, *mocks.FileRepository) {
+	repo := new(mocks.FolderRepository)
+	catalog := new(mocks.FileRepository)
+	service := NewFolderService(repo, catalog)
+	service.now = func() time.Time { return now }
+	return service, repo, catalog
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func TestFolderService_CreateFolder(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+	parent := domain.Folder{ID: "unit-3", Name: "Unit 3", OwnerID: 1}
+
+	t.Run("folder is created inside the parent", func(t *testing.T) {
+		// Setup
+		service, repo, _ := newTestFolderService(now)
+
+		// Mock expectations
+		repo.On("GetFolderByID", "unit-3").Return(&parent, nil)
+		repo.On("GetFoldersByOwnerID", int64(1)).Return([]domain.Folder{parent}, nil)
+		repo.On("CreateFolder", mock.MatchedBy(func(f *domain.Folder) bool {
+			return f.Name == "Worksheets" && *f.ParentID == "unit-3" && f.OwnerID == 1 && f.CreatedAt.Equal(now)
+		})).Return(nil)
+
+		// Test
+		folder, err := service.CreateFolder(ctx, 1, &domain.FolderCreate{Name: "  Worksheets ", ParentID: stringPtr("unit-3")})
+
+		// Assertions
+		require.NoError(t, err)
+		assert.NotEmpty(t, folder.ID)
+		assert.Equal(t, "Worksheets", folder.Name)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("duplicate name in the same parent is rejected", func(t *testing.T) {
+		service, repo, _ := newTestFolderService(now)
+		repo.On("GetFoldersByOwnerID", int64(1)).Return([]domain.Folder{parent}, nil)
+
+		_, err := service.CreateFolder(ctx, 1, &domain.FolderCreate{Name: "unit 3"})
+
+		assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+		repo.AssertNotCalled(t, "CreateFolder", mock.Anything)
+	})
+
+	t.Run("parent owned by another teacher is rejected", func(t *testing.T) {
+		service, repo, _ := newTestFolderService(now)
+		repo.On("GetFolderByID", "unit-3").Return(&parent, nil)
+
+		_, err := service.CreateFolder(ctx, 2, &domain.FolderCreate{Name: "Worksheets", ParentID: stringPtr("unit-3")})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("name with a slash is rejected", func(t *testing.T) {
+		service, _, _ := newTestFolderService(now)
+
+		_, err := service.CreateFolder(ctx, 1, &domain.FolderCreate{Name: "a/b"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+}
+
+func TestFolderService_MoveFolder(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+	folders := []domain.Folder{
+		{ID: "a", Name: "A", OwnerID: 1},
+		{ID: "b", Name: "B", ParentID: stringPtr("a"), OwnerID: 1},
+		{ID: "c", Name: "C", ParentID: stringPtr("b"), OwnerID: 1},
+		{ID: "d", Name: "D", OwnerID: 1},
+	}
+
+	t.Run("folder is moved under another folder", func(t *testing.T) {
+		// Setup
+		service, repo, _ := newTestFolderService(now)
+		b := folders[1]
+
+		// Mock expectations
+		repo.On("GetFolderByID", "b").Return(&b, nil)
+		repo.On("GetFolderByID", "d").Return(&folders[3], nil)
+		repo.On("GetFoldersByOwnerID", int64(1)).Return(folders, nil)
+		repo.On("UpdateFolder", mock.MatchedBy(func(f *domain.Folder) bool {
+			return f.ID == "b" && *f.ParentID == "d" && f.UpdatedAt.Equal(now)
+		})).Return(nil)
+
+		// Test
+		folder, err := service.MoveFolder(ctx, 1, "b", &domain.FolderMove{ParentID: stringPtr("d")})
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, "d", *folder.ParentID)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("folder cannot be moved into its own subfolder", func(t *testing.T) {
+		service, repo, _ := newTestFolderService(now)
+		a := folders[0]
+		repo.On("GetFolderByID", "a").Return(&a, nil)
+		repo.On("GetFolderByID", "c").Return(&folders[2], nil)
+		repo.On("GetFoldersByOwnerID", int64(1)).Return(folders, nil)
+
+		_, err := service.MoveFolder(ctx, 1, "a", &domain.FolderMove{ParentID: stringPtr("c")})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		repo.AssertNotCalled(t, "UpdateFolder", mock.Anything)
+	})
+
+	t.Run("folder is moved to the top level", func(t *testing.T) {
+		service, repo, _ := newTestFolderService(now)
+		c := folders[2]
+		repo.On("GetFolderByID", "c").Return(&c, nil)
+		repo.On("GetFoldersByOwnerID", int64(1)).Return(folders, nil)
+		repo.On("UpdateFolder", mock.MatchedBy(func(f *domain.Folder) bool { return f.ParentID == nil })).Return(nil)
+
+		folder, err := service.MoveFolder(ctx, 1, "c", &domain.FolderMove{})
+
+		require.NoError(t, err)
+		assert.Nil(t, folder.ParentID)
+	})
+}
+
+func TestFolderService_DeleteFolder(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+	folder := domain.Folder{ID: "unit-3", Name: "Unit 3", OwnerID: 1}
+
+	t.Run("empty folder is deleted without confirmation", func(t *testing.T) {
+		// Setup
+		service, repo, catalog := newTestFolderService(now)
+
+		// Mock expectations
+		repo.On("GetFolderByID", "unit-3").Return(&folder, nil)
+		repo.On("GetFoldersByOwnerID", int64(1)).Return([]domain.Folder{folder}, nil)
+		catalog.On("ListFiles", domain.FileFilter{FolderID: "unit-3"}).Return([]domain.File{}, nil)
+		repo.On("DeleteFolder", "unit-3", now).Return(int64(0), nil)
+
+		// Test
+		err := service.DeleteFolder(ctx, 1, "unit-3", domain.FolderDelete{})
+
+		// Assertions
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("folder with files is not deleted without recursive", func(t *testing.T) {
+		service, repo, catalog := newTestFolderService(now)
+		repo.On("GetFolderByID", "unit-3").Return(&folder, nil)
+		repo.On("GetFoldersByOwnerID", int64(1)).Return([]domain.Folder{folder}, nil)
+		catalog.On("ListFiles", domain.FileFilter{FolderID: "unit-3"}).Return([]domain.File{{ID: "file-1"}}, nil)
+
+		err := service.DeleteFolder(ctx, 1, "unit-3", domain.FolderDelete{})
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		repo.AssertNotCalled(t, "DeleteFolder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("recursive delete requires the folder name as confirmation", func(t *testing.T) {
+		service, repo, _ := newTestFolderService(now)
+		repo.On("GetFolderByID", "unit-3").Return(&folder, nil)
+
+		err := service.DeleteFolder(ctx, 1, "unit-3", domain.FolderDelete{Recursive: true, Confirm: "yes"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		repo.AssertNotCalled(t, "DeleteFolder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("recursive delete with confirmation trashes the contents", func(t *testing.T) {
+		service, repo, _ := newTestFolderService(now)
+		repo.On("GetFolderByID", "unit-3").Return(&folder, nil)
+		repo.On("DeleteFolder", "unit-3", now).Return(int64(4), nil)
+
+		err := service.DeleteFolder(ctx, 1, "unit-3", domain.FolderDelete{Recursive: true, Confirm: "Unit 3"})
+
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestFolderService_MoveFile(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+	file := domain.File{ID: "file-1", ObjectKey: "objects/file-1", UploaderID: 1, UploaderRole: "teacher"}
+	folder := domain.Folder{ID: "unit-3", Name: "Unit 3", OwnerID: 1}
+
+	t.Run("only the catalog is updated", func(t *testing.T) {
+		// Setup
+		service, repo, catalog := newTestFolderService(now)
+		moved := file
+		moved.FolderID = stringPtr("unit-3")
+
+		// Mock expectations
+		catalog.On("GetFileByID", "file-1").Return(&file, nil).Once()
+		repo.On("GetFolderByID", "unit-3").Return(&folder, nil)
+		catalog.On("MoveFile", "file-1", stringPtr("unit-3")).Return(nil)
+		catalog.On("GetFileByID", "file-1").Return(&moved, nil).Once()
+
+		// Test
+		result, err := service.MoveFile(ctx, 1, "file-1", &domain.FileMove{FolderID: stringPtr("unit-3")})
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, "unit-3", *result.FolderID)
+		assert.Equal(t, "objects/file-1", result.ObjectKey)
+		catalog.AssertExpectations(t)
+	})
+
+	t.Run("file uploaded by another teacher is rejected", func(t *testing.T) {
+		service, _, catalog := newTestFolderService(now)
+		catalog.On("GetFileByID", "file-1").Return(&file, nil)
+
+		_, err := service.MoveFile(ctx, 2, "file-1", &domain.FileMove{})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+		catalog.AssertNotCalled(t, "MoveFile", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFolderService_SetFileTags(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+	file := domain.File{ID: "file-1", UploaderID: 1, UploaderRole: "teacher"}
+
+	t.Run("tags are normalized", func(t *testing.T) {
+		// Setup
+		service, _, catalog := newTestFolderService(now)
+
+		// Mock expectations
+		catalog.On("GetFileByID", "file-1").Return(&file, nil)
+		catalog.On("SetFileTags", "file-1", []string{"homework", "unit-3"}).Return(nil)
+
+		// Test
+		_, err := service.SetFileTags(ctx, 1, "file-1", &domain.FileTagsUpdate{Tags: []string{" Unit-3", "homework", "HOMEWORK "}})
+
+		// Assertions
+		require.NoError(t, err)
+		catalog.AssertExpectations(t)
+	})
+
+	t.Run("empty tag is rejected", func(t *testing.T) {
+		service, _, catalog := newTestFolderService(now)
+
+		_, err := service.SetFileTags(ctx, 1, "file-1", &domain.FileTagsUpdate{Tags: []string{"homework", " "}})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		catalog.AssertNotCalled(t, "SetFileTags", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFolderService_ListFiles(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+
+	t.Run("filter by folder and tags", func(t *testing.T) {
+		// Setup
+		service, repo, catalog := newTestFolderService(now)
+		folder := domain.Folder{ID: "unit-3", Name: "Unit 3", OwnerID: 1}
+		files := []domain.File{{ID: "file-1"}}
+
+		// Mock expectations
+		repo.On("GetFolderByID", "unit-3").Return(&folder, nil)
+		catalog.On("ListFiles", domain.FileFilter{FolderID: "unit-3", Tags: []string{"homework", "quiz"}, UploaderID: 1, UploaderRole: "teacher"}).Return(files, nil)
+
+		// Test
+		result, err := service.ListFiles(ctx, 1, domain.FileFilter{FolderID: "unit-3", Tags: []string{"Quiz", "homework"}})
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, files, result)
+	})
+
+	t.Run("tag-only query is limited to the teacher's files", func(t *testing.T) {
+		// Setup
+		service, _, catalog := newTestFolderService(now)
+
+		// Mock expectations
+		catalog.On("ListFiles", domain.FileFilter{Tags: []string{"homework"}, UploaderID: 1, UploaderRole: "teacher"}).Return([]domain.File{}, nil)
+
+		// Test
+		_, err := service.ListFiles(ctx, 1, domain.FileFilter{Tags: []string{"homework"}})
+
+		// Assertions
+		require.NoError(t, err)
+		catalog.AssertExpectations(t)
+	})
+
+	t.Run("unfiled query is limited to the teacher's files", func(t *testing.T) {
+		// Setup
+		service, _, catalog := newTestFolderService(now)
+
+		// Mock expectations
+		catalog.On("ListFiles", domain.FileFilter{Unfiled: true, Tags: []string{}, UploaderID: 1, UploaderRole: "teacher"}).Return([]domain.File{}, nil)
+
+		// Test
+		_, err := service.ListFiles(ctx, 1, domain.FileFilter{Unfiled: true})
+
+		// Assertions
+		require.NoError(t, err)
+		catalog.AssertExpectations(t)
+	})
+
+	t.Run("folder owned by another teacher is rejected", func(t *testing.T) {
+		service, repo, _ := newTestFolderService(now)
+		repo.On("GetFolderByID", "unit-3").Return(&domain.Folder{ID: "unit-3", OwnerID: 2}, nil)
+
+		_, err := service.ListFiles(ctx, 1, domain.FileFilter{FolderID: "unit-3"})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+}
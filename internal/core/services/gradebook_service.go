@@ -0,0 +1,407 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+)
+
+// 成績簿サービス構造体：成績の記録と計算に関する業務ロジックを実装
+type GradebookService struct {
+	// 成績簿リポジトリインターフェース
+	repo ports.GradebookRepository
+	// 教師リポジトリインターフェース（担当学生・担当教師の取得に使用）
+	teacherRepo ports.TeacherRepository
+	// 課題リポジトリインターフェース（課題の満点とタイトルの補完に使用）
+	assignmentRepo ports.AssignmentRepository
+	// 現在時刻を返す関数（テストで差し替え可能）
+	now func() time.Time
+}
+
+// 新しい成績簿サービスインスタンスを作成する
+func NewGradebookService(repo ports.GradebookRepository, teacherRepo ports.TeacherRepository, assignmentRepo ports.AssignmentRepository) *GradebookService {
+	return &GradebookService{
+		repo:           repo,
+		teacherRepo:    teacherRepo,
+		assignmentRepo: assignmentRepo,
+		now:            time.Now,
+	}
+}
+
+// 教師の成績カテゴリを作成する
+// カテゴリの重みの合計が100%を超える場合はエラーを返す
+func (s *GradebookService) CreateCategory(ctx context.Context, teacherID int64, input *domain.GradeCategoryCreate) (*domain.GradeCategory, error) {
+	if input.Weight <= 0 || input.DropLowest < 0 {
+		return nil, fmt.Errorf("%w: weight must be positive and drop_lowest must not be negative", domain.ErrInvalidInput)
+	}
+
+	// 既存カテゴリの重みの合計を確認
+	categories, err := s.repo.GetCategoriesByTeacherID(teacherID)
+	if err != nil {
+		return nil, err
+	}
+	total := input.Weight
+	for _, category := range categories {
+		if strings.EqualFold(category.Name, input.Name) {
+			return nil, fmt.Errorf("%w: category %q", domain.ErrAlreadyExists, input.Name)
+		}
+		total += category.Weight
+	}
+	if total > 100 {
+		return nil, fmt.Errorf("%w: category weights would total %.2f%%, which exceeds 100%%", domain.ErrInvalidInput, total)
+	}
+
+	category := &domain.GradeCategory{
+		TeacherID:  teacherID,
+		Name:       input.Name,
+		Weight:     input.Weight,
+		DropLowest: input.DropLowest,
+	}
+	id, err := s.repo.CreateCategory(category)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetCategoryByID(id)
+}
+
+// 教師の成績カテゴリ一覧を取得する
+func (s *GradebookService) ListCategories(ctx context.Context, teacherID int64) ([]domain.GradeCategory, error) {
+	return s.repo.GetCategoriesByTeacherID(teacherID)
+}
+
+// 学生の得点を記録する
+// カテゴリと課題は教師のもの、学生は教師の担当である必要がある
+func (s *GradebookService) RecordScore(ctx context.Context, teacherID int64, input *domain.GradeEntryInput) (*domain.GradeEntry, error) {
+	// カテゴリの所有者を確認
+	category, err := s.repo.GetCategoryByID(input.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category.TeacherID != teacherID {
+		return nil, fmt.Errorf("%w: category %d does not belong to teacher %d", domain.ErrForbidden, input.CategoryID, teacherID)
+	}
+
+	// 学生が教師の担当であることを確認
+	assigned, err := s.teacherRepo.IsStudentAssigned(teacherID, input.StudentID)
+	if err != nil {
+		return nil, err
+	}
+	if !assigned {
+		return nil, fmt.Errorf("%w: student %d is not assigned to teacher %d", domain.ErrForbidden, input.StudentID, teacherID)
+	}
+
+	entry := &domain.GradeEntry{
+		TeacherID:    teacherID,
+		StudentID:    input.StudentID,
+		CategoryID:   input.CategoryID,
+		AssignmentID: input.AssignmentID,
+		Title:        input.Title,
+		Score:        input.Score,
+		MaxPoints:    input.MaxPoints,
+		GradedAt:     s.now(),
+	}
+
+	// 課題に紐付く成績の場合、満点とタイトルを課題から補完
+	if input.AssignmentID != nil {
+		assignment, err := s.assignmentRepo.GetAssignmentByID(*input.AssignmentID)
+		if err != nil {
+			return nil, err
+		}
+		if assignment.TeacherID != teacherID {
+			return nil, fmt.Errorf("%w: assignment %d does not belong to teacher %d", domain.ErrForbidden, assignment.ID, teacherID)
+		}
+		if entry.MaxPoints == 0 {
+			entry.MaxPoints = assignment.MaxPoints
+		}
+		if entry.Title == "" {
+			entry.Title = assignment.Title
+		}
+	}
+
+	if entry.Title == "" {
+		return nil, fmt.Errorf("%w: title is required when no assignment is given", domain.ErrInvalidInput)
+	}
+	if entry.MaxPoints <= 0 {
+		return nil, fmt.Errorf("%w: max_points must be positive", domain.ErrInvalidInput)
+	}
+	if entry.Score < 0 {
+		return nil, fmt.Errorf("%w: score must not be negative", domain.ErrInvalidInput)
+	}
+
+	id, err := s.repo.SaveGradeEntry(entry)
+	if err != nil {
+		return nil, err
+	}
+	entry.ID = id
+
+	return entry, nil
+}
+
+// 教師の評定基準を設定する
+// 評定は重複不可で、0%から始まる段階が必要
+func (s *GradebookService) SetScale(ctx context.Context, teacherID int64, scale []domain.GradeThreshold) ([]domain.GradeThreshold, error) {
+	sorted, err := normalizeGradeScale(scale)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveGradeScale(teacherID, sorted); err != nil {
+		return nil, err
+	}
+
+	return sorted, nil
+}
+
+// 教師の成績簿を取得する
+// 担当学生全員について、カテゴリ別平均、最終成績、評定を計算する
+func (s *GradebookService) GetGradebook(ctx context.Context, teacherID int64) (*domain.Gradebook, error) {
+	categories, err := s.repo.GetCategoriesByTeacherID(teacherID)
+	if err != nil {
+		return nil, err
+	}
+	scale, err := s.scaleFor(teacherID)
+	if err != nil {
+		return nil, err
+	}
+	students, err := s.teacherRepo.GetStudentsByTeacherID(teacherID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.GetGradeEntriesByTeacherID(teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 学生ごとに成績記録をまとめる
+	entriesByStudent := make(map[int64][]domain.GradeEntry)
+	for _, entry := range entries {
+		entriesByStudent[entry.StudentID] = append(entriesByStudent[entry.StudentID], entry)
+	}
+
+	gradebook := &domain.Gradebook{
+		TeacherID:  teacherID,
+		Categories: categories,
+		Scale:      scale,
+		Students:   make([]domain.StudentGrade, 0, len(students)),
+	}
+	for _, student := range students {
+		grade := computeStudentGrade(categories, entriesByStudent[student.ID], scale)
+		grade.StudentID = student.ID
+		grade.StudentName = student.Name
+		grade.TeacherID = teacherID
+		gradebook.Students = append(gradebook.Students, grade)
+	}
+
+	return gradebook, nil
+}
+
+// 学生の全教師分の成績を取得する
+func (s *GradebookService) GetStudentGrades(ctx context.Context, studentID int64) ([]domain.StudentGrade, error) {
+	teachers, err := s.teacherRepo.GetTeachersByStudentID(studentID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.GetGradeEntriesByStudentID(studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 教師ごとに成績記録をまとめる
+	entriesByTeacher := make(map[int64][]domain.GradeEntry)
+	for _, entry := range entries {
+		entriesByTeacher[entry.TeacherID] = append(entriesByTeacher[entry.TeacherID], entry)
+	}
+
+	grades := make([]domain.StudentGrade, 0, len(teachers))
+	for _, teacher := range teachers {
+		categories, err := s.repo.GetCategoriesByTeacherID(teacher.ID)
+		if err != nil {
+			return nil, err
+		}
+		scale, err := s.scaleFor(teacher.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		grade := computeStudentGrade(categories, entriesByTeacher[teacher.ID], scale)
+		grade.StudentID = studentID
+		grade.TeacherID = teacher.ID
+		grade.Subject = teacher.Subject
+		grades = append(grades, grade)
+	}
+
+	return grades, nil
+}
+
+// 教師の評定基準を取得する（未設定の場合は標準の評定基準）
+func (s *GradebookService) scaleFor(teacherID int64) ([]domain.GradeThreshold, error) {
+	scale, err := s.repo.GetGradeScale(teacherID)
+	if err != nil {
+		return nil, err
+	}
+	if len(scale) == 0 {
+		return domain.DefaultGradeScale, nil
+	}
+	return scale, nil
+}
+
+// 学生1人分の成績を計算する
+// カテゴリごとに最低点を除外した得点率を求め、成績のあるカテゴリの重みで正規化した加重平均を最終成績とする
+// そのため成績が一部しか入力されていない学期途中でも、現時点での成績（累積平均）が得られる
+func computeStudentGrade(categories []domain.GradeCategory, entries []domain.GradeEntry, scale []domain.GradeThreshold) domain.StudentGrade {
+	// カテゴリごとに成績記録をまとめる
+	entriesByCategory := make(map[int64][]domain.GradeEntry)
+	for _, entry := range entries {
+		entriesByCategory[entry.CategoryID] = append(entriesByCategory[entry.CategoryID], entry)
+	}
+
+	grade := domain.StudentGrade{
+		Categories: make([]domain.CategoryGrade, 0, len(categories)),
+		Entries:    entries,
+	}
+	if grade.Entries == nil {
+		grade.Entries = []domain.GradeEntry{}
+	}
+
+	var weighted, totalWeight float64
+	for _, category := range categories {
+		percent, counted, dropped := categoryPercent(entriesByCategory[category.ID], category.DropLowest)
+		categoryGrade := domain.CategoryGrade{
+			CategoryID: category.ID,
+			Name:       category.Name,
+			Weight:     category.Weight,
+			Counted:    counted,
+			Dropped:    dropped,
+		}
+		if counted > 0 {
+			rounded := roundPercent(percent)
+			categoryGrade.Percent = &rounded
+			weighted += percent * category.Weight
+			totalWeight += category.Weight
+		}
+		grade.Categories = append(grade.Categories, categoryGrade)
+	}
+
+	if totalWeight > 0 {
+		final := roundPercent(weighted / totalWeight)
+		grade.Percent = &final
+		grade.Letter = letterGrade(final, scale)
+	}
+
+	return grade
+}
+
+// カテゴリ内の得点率（パーセント）を計算する
+// 得点率の低い成績からdropLowest件を除外し、残りの得点合計を満点合計で割る
+// 除外によって成績が1件も残らない場合は、最も高い1件を残す
+// 戻り値は得点率、計算に含めた件数、除外した件数
+func categoryPercent(entries []domain.GradeEntry, dropLowest int) (float64, int, int) {
+	// 満点が0以下の成績は計算できないため除く
+	valid := make([]domain.GradeEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.MaxPoints > 0 {
+			valid = append(valid, entry)
+		}
+	}
+	if len(valid) == 0 {
+		return 0, 0, 0
+	}
+
+	// 除外する件数を決定（最低1件は残す）
+	drop := dropLowest
+	if drop < 0 {
+		drop = 0
+	}
+	if drop > len(valid)-1 {
+		drop = len(valid) - 1
+	}
+
+	// 得点率の低い順に並べて先頭を除外
+	sort.SliceStable(valid, func(i, j int) bool {
+		return valid[i].Score/valid[i].MaxPoints < valid[j].Score/valid[j].MaxPoints
+	})
+	kept := valid[drop:]
+
+	var score, maxPoints float64
+	for _, entry := range kept {
+		score += entry.Score
+		maxPoints += entry.MaxPoints
+	}
+
+	return score / maxPoints * 100, len(kept), drop
+}
+
+// パーセントに対応する評定を返す
+// 評定基準は下限の高い順に評価され、どの下限にも達しない場合は最も低い評定を返す
+func letterGrade(percent float64, scale []domain.GradeThreshold) string {
+	if len(scale) == 0 {
+		scale = domain.DefaultGradeScale
+	}
+
+	sorted := make([]domain.GradeThreshold, len(scale))
+	copy(sorted, scale)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].MinPercent > sorted[j].MinPercent
+	})
+
+	for _, threshold := range sorted {
+		if percent >= threshold.MinPercent {
+			return threshold.Letter
+		}
+	}
+	return sorted[len(sorted)-1].Letter
+}
+
+// 評定基準を検証し、下限の高い順に並べ替えて返す
+func normalizeGradeScale(scale []domain.GradeThreshold) ([]domain.GradeThreshold, error) {
+	if len(scale) == 0 {
+		return nil, fmt.Errorf("%w: grade scale must not be empty", domain.ErrInvalidInput)
+	}
+
+	letters := make(map[string]bool)
+	mins := make(map[float64]bool)
+	hasZero := false
+	for _, threshold := range scale {
+		letter := strings.TrimSpace(threshold.Letter)
+		switch {
+		case letter == "":
+			return nil, fmt.Errorf("%w: grade letter must not be empty", domain.ErrInvalidInput)
+		case letters[letter]:
+			return nil, fmt.Errorf("%w: duplicate grade letter %q", domain.ErrInvalidInput, letter)
+		case threshold.MinPercent < 0 || threshold.MinPercent > 100:
+			return nil, fmt.Errorf("%w: min_percent for %q must be between 0 and 100", domain.ErrInvalidInput, letter)
+		case mins[threshold.MinPercent]:
+			return nil, fmt.Errorf("%w: duplicate min_percent %.2f", domain.ErrInvalidInput, threshold.MinPercent)
+		}
+		letters[letter] = true
+		mins[threshold.MinPercent] = true
+		if threshold.MinPercent == 0 {
+			hasZero = true
+		}
+	}
+	if !hasZero {
+		return nil, fmt.Errorf("%w: grade scale needs a grade starting at 0%%", domain.ErrInvalidInput)
+	}
+
+	sorted := make([]domain.GradeThreshold, len(scale))
+	for i, threshold := range scale {
+		sorted[i] = domain.GradeThreshold{Letter: strings.TrimSpace(threshold.Letter), MinPercent: threshold.MinPercent}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].MinPercent > sorted[j].MinPercent
+	})
+
+	return sorted, nil
+}
+
+// パーセントを小数点以下2桁に丸める
+func roundPercent(percent float64) float64 {
+	return math.Round(percent*100) / 100
+}
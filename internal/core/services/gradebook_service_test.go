@@ -0,0 +1,465 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGradebookService() (*GradebookService, *mocks.GradebookRepository, *mocks.TeacherRepository, *mocks.AssignmentRepository) {
+	repo := new(mocks.GradebookRepository)
+	teacherRepo := new(mocks.TeacherRepository)
+	assignmentRepo := new(mocks.AssignmentRepository)
+	service := NewGradebookService(repo, teacherRepo, assignmentRepo)
+	service.now = func() time.Time { return time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC) }
+	return service, repo, teacherRepo, assignmentRepo
+}
+
+func entry(categoryID int64, score, maxPoints float64) domain.GradeEntry {
+	return domain.GradeEntry{CategoryID: categoryID, Score: score, MaxPoints: maxPoints}
+}
+
+func TestCategoryPercent(t *testing.T) {
+	tests := []struct {
+		name        string
+		entries     []domain.GradeEntry
+		dropLowest  int
+		wantPercent float64
+		wantCounted int
+		wantDropped int
+	}{
+		{
+			name:    "no entries",
+			entries: nil,
+		},
+		{
+			name:        "single entry",
+			entries:     []domain.GradeEntry{entry(1, 45, 50)},
+			wantPercent: 90,
+			wantCounted: 1,
+		},
+		{
+			name:        "points based, not average of percentages",
+			entries:     []domain.GradeEntry{entry(1, 10, 10), entry(1, 50, 100)},
+			wantPercent: 60 / 110.0 * 100,
+			wantCounted: 2,
+		},
+		{
+			name:        "drop lowest by percentage",
+			entries:     []domain.GradeEntry{entry(1, 9, 10), entry(1, 2, 10), entry(1, 8, 10)},
+			dropLowest:  1,
+			wantPercent: 85,
+			wantCounted: 2,
+			wantDropped: 1,
+		},
+		{
+			name:        "drop lowest compares percentages across different max points",
+			entries:     []domain.GradeEntry{entry(1, 30, 100), entry(1, 5, 10), entry(1, 18, 20)},
+			dropLowest:  1,
+			wantPercent: 23 / 30.0 * 100,
+			wantCounted: 2,
+			wantDropped: 1,
+		},
+		{
+			name:        "drop two",
+			entries:     []domain.GradeEntry{entry(1, 1, 10), entry(1, 2, 10), entry(1, 10, 10), entry(1, 6, 10)},
+			dropLowest:  2,
+			wantPercent: 80,
+			wantCounted: 2,
+			wantDropped: 2,
+		},
+		{
+			name:        "drop never removes the last entry",
+			entries:     []domain.GradeEntry{entry(1, 4, 10), entry(1, 7, 10)},
+			dropLowest:  5,
+			wantPercent: 70,
+			wantCounted: 1,
+			wantDropped: 1,
+		},
+		{
+			name:        "entries without max points are ignored",
+			entries:     []domain.GradeEntry{entry(1, 5, 0), entry(1, 8, 10)},
+			wantPercent: 80,
+			wantCounted: 1,
+		},
+		{
+			name:        "extra credit above max points",
+			entries:     []domain.GradeEntry{entry(1, 12, 10)},
+			wantPercent: 120,
+			wantCounted: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percent, counted, dropped := categoryPercent(tt.entries, tt.dropLowest)
+			assert.InDelta(t, tt.wantPercent, percent, 1e-9)
+			assert.Equal(t, tt.wantCounted, counted)
+			assert.Equal(t, tt.wantDropped, dropped)
+		})
+	}
+}
+
+func TestCategoryPercent_DoesNotReorderInput(t *testing.T) {
+	entries := []domain.GradeEntry{entry(1, 9, 10), entry(1, 2, 10)}
+
+	categoryPercent(entries, 1)
+
+	assert.Equal(t, 9.0, entries[0].Score)
+	assert.Equal(t, 2.0, entries[1].Score)
+}
+
+func TestLetterGrade(t *testing.T) {
+	custom := []domain.GradeThreshold{
+		{Letter: "Pass", MinPercent: 50},
+		{Letter: "Distinction", MinPercent: 85},
+		{Letter: "Fail", MinPercent: 0},
+	}
+
+	tests := []struct {
+		name    string
+		percent float64
+		scale   []domain.GradeThreshold
+		want    string
+	}{
+		{name: "default A boundary", percent: 90, want: "A"},
+		{name: "default just below A", percent: 89.99, want: "B"},
+		{name: "default D boundary", percent: 60, want: "D"},
+		{name: "default F", percent: 12, want: "F"},
+		{name: "default above 100", percent: 104, want: "A"},
+		{name: "custom unsorted scale high", percent: 91, scale: custom, want: "Distinction"},
+		{name: "custom unsorted scale middle", percent: 50, scale: custom, want: "Pass"},
+		{name: "custom unsorted scale low", percent: 49.5, scale: custom, want: "Fail"},
+		{name: "below every threshold falls back to lowest", percent: -1, scale: custom, want: "Fail"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, letterGrade(tt.percent, tt.scale))
+		})
+	}
+}
+
+func TestComputeStudentGrade_WeightedAverage(t *testing.T) {
+	categories := []domain.GradeCategory{
+		{ID: 1, Name: "Homework", Weight: 30, DropLowest: 1},
+		{ID: 2, Name: "Exams", Weight: 50},
+		{ID: 3, Name: "Participation", Weight: 20},
+	}
+	entries := []domain.GradeEntry{
+		entry(1, 10, 10), entry(1, 0, 10), entry(1, 8, 10), // 宿題：0点を除外して90%
+		entry(2, 70, 100), entry(2, 90, 100), // 試験：80%
+		entry(3, 10, 10), // 参加：100%
+	}
+
+	grade := computeStudentGrade(categories, entries, domain.DefaultGradeScale)
+
+	require.NotNil(t, grade.Percent)
+	// 0.3*90 + 0.5*80 + 0.2*100 = 87
+	assert.InDelta(t, 87, *grade.Percent, 1e-9)
+	assert.Equal(t, "B", grade.Letter)
+	require.Len(t, grade.Categories, 3)
+	assert.InDelta(t, 90, *grade.Categories[0].Percent, 1e-9)
+	assert.Equal(t, 1, grade.Categories[0].Dropped)
+	assert.Equal(t, 2, grade.Categories[0].Counted)
+	assert.InDelta(t, 80, *grade.Categories[1].Percent, 1e-9)
+	assert.Len(t, grade.Entries, 6)
+}
+
+func TestComputeStudentGrade_RunningAverageRenormalizesWeights(t *testing.T) {
+	categories := []domain.GradeCategory{
+		{ID: 1, Name: "Homework", Weight: 30},
+		{ID: 2, Name: "Exams", Weight: 50},
+	}
+	// 試験はまだ実施されていない
+	entries := []domain.GradeEntry{entry(1, 18, 20)}
+
+	grade := computeStudentGrade(categories, entries, domain.DefaultGradeScale)
+
+	require.NotNil(t, grade.Percent)
+	assert.InDelta(t, 90, *grade.Percent, 1e-9)
+	assert.Equal(t, "A", grade.Letter)
+	assert.Nil(t, grade.Categories[1].Percent)
+	assert.Equal(t, 0, grade.Categories[1].Counted)
+}
+
+func TestComputeStudentGrade_WeightsBelowHundred(t *testing.T) {
+	categories := []domain.GradeCategory{
+		{ID: 1, Name: "Homework", Weight: 20},
+		{ID: 2, Name: "Exams", Weight: 20},
+	}
+	entries := []domain.GradeEntry{entry(1, 100, 100), entry(2, 50, 100)}
+
+	grade := computeStudentGrade(categories, entries, domain.DefaultGradeScale)
+
+	require.NotNil(t, grade.Percent)
+	assert.InDelta(t, 75, *grade.Percent, 1e-9)
+	assert.Equal(t, "C", grade.Letter)
+}
+
+func TestComputeStudentGrade_NoScores(t *testing.T) {
+	categories := []domain.GradeCategory{{ID: 1, Name: "Homework", Weight: 30}}
+
+	grade := computeStudentGrade(categories, nil, domain.DefaultGradeScale)
+
+	assert.Nil(t, grade.Percent)
+	assert.Empty(t, grade.Letter)
+	assert.NotNil(t, grade.Entries)
+	require.Len(t, grade.Categories, 1)
+	assert.Nil(t, grade.Categories[0].Percent)
+}
+
+func TestComputeStudentGrade_IgnoresEntriesOfUnknownCategories(t *testing.T) {
+	categories := []domain.GradeCategory{{ID: 1, Name: "Homework", Weight: 30}}
+	entries := []domain.GradeEntry{entry(1, 7, 10), entry(99, 0, 10)}
+
+	grade := computeStudentGrade(categories, entries, domain.DefaultGradeScale)
+
+	require.NotNil(t, grade.Percent)
+	assert.InDelta(t, 70, *grade.Percent, 1e-9)
+}
+
+func TestComputeStudentGrade_RoundsPercent(t *testing.T) {
+	categories := []domain.GradeCategory{{ID: 1, Name: "Homework", Weight: 100}}
+	entries := []domain.GradeEntry{entry(1, 2, 3)}
+
+	grade := computeStudentGrade(categories, entries, domain.DefaultGradeScale)
+
+	require.NotNil(t, grade.Percent)
+	assert.Equal(t, 66.67, *grade.Percent)
+	assert.Equal(t, 66.67, *grade.Categories[0].Percent)
+	assert.Equal(t, "D", grade.Letter)
+}
+
+func TestNormalizeGradeScale(t *testing.T) {
+	scale, err := normalizeGradeScale([]domain.GradeThreshold{
+		{Letter: " F ", MinPercent: 0},
+		{Letter: "A", MinPercent: 90},
+		{Letter: "B", MinPercent: 75},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.GradeThreshold{
+		{Letter: "A", MinPercent: 90},
+		{Letter: "B", MinPercent: 75},
+		{Letter: "F", MinPercent: 0},
+	}, scale)
+
+	invalid := map[string][]domain.GradeThreshold{
+		"empty":             {},
+		"missing zero":      {{Letter: "A", MinPercent: 50}},
+		"duplicate letter":  {{Letter: "A", MinPercent: 50}, {Letter: "A", MinPercent: 0}},
+		"duplicate minimum": {{Letter: "A", MinPercent: 0}, {Letter: "B", MinPercent: 0}},
+		"blank letter":      {{Letter: " ", MinPercent: 0}},
+		"out of range":      {{Letter: "A", MinPercent: 120}, {Letter: "F", MinPercent: 0}},
+	}
+	for name, scale := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := normalizeGradeScale(scale)
+			assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		})
+	}
+}
+
+func TestGradebookService_CreateCategory(t *testing.T) {
+	// Setup
+	service, repo, _, _ := newTestGradebookService()
+	ctx := context.Background()
+
+	existing := []domain.GradeCategory{{ID: 1, TeacherID: 7, Name: "Homework", Weight: 30}}
+	created := &domain.GradeCategory{ID: 2, TeacherID: 7, Name: "Exams", Weight: 50, DropLowest: 0}
+
+	// Mock expectations
+	repo.On("GetCategoriesByTeacherID", int64(7)).Return(existing, nil)
+	repo.On("CreateCategory", mock.MatchedBy(func(c *domain.GradeCategory) bool {
+		return c.TeacherID == 7 && c.Name == "Exams" && c.Weight == 50
+	})).Return(int64(2), nil)
+	repo.On("GetCategoryByID", int64(2)).Return(created, nil)
+
+	// Test
+	result, err := service.CreateCategory(ctx, 7, &domain.GradeCategoryCreate{Name: "Exams", Weight: 50})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, created, result)
+	repo.AssertExpectations(t)
+}
+
+func TestGradebookService_CreateCategory_WeightsExceedHundred(t *testing.T) {
+	// Setup
+	service, repo, _, _ := newTestGradebookService()
+	ctx := context.Background()
+
+	existing := []domain.GradeCategory{{ID: 1, Name: "Homework", Weight: 30}, {ID: 2, Name: "Exams", Weight: 50}}
+
+	// Mock expectations
+	repo.On("GetCategoriesByTeacherID", int64(7)).Return(existing, nil)
+
+	// Test
+	_, err := service.CreateCategory(ctx, 7, &domain.GradeCategoryCreate{Name: "Projects", Weight: 25})
+
+	// Assertions
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	repo.AssertNotCalled(t, "CreateCategory", mock.Anything)
+}
+
+func TestGradebookService_CreateCategory_Duplicate(t *testing.T) {
+	// Setup
+	service, repo, _, _ := newTestGradebookService()
+	ctx := context.Background()
+
+	// Mock expectations
+	repo.On("GetCategoriesByTeacherID", int64(7)).Return([]domain.GradeCategory{{ID: 1, Name: "Homework", Weight: 30}}, nil)
+
+	// Test
+	_, err := service.CreateCategory(ctx, 7, &domain.GradeCategoryCreate{Name: "homework", Weight: 10})
+
+	// Assertions
+	assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+}
+
+func TestGradebookService_RecordScore_FromAssignment(t *testing.T) {
+	// Setup
+	service, repo, teacherRepo, assignmentRepo := newTestGradebookService()
+	ctx := context.Background()
+
+	assignmentID := int64(5)
+
+	// Mock expectations
+	repo.On("GetCategoryByID", int64(3)).Return(&domain.GradeCategory{ID: 3, TeacherID: 7}, nil)
+	teacherRepo.On("IsStudentAssigned", int64(7), int64(12)).Return(true, nil)
+	assignmentRepo.On("GetAssignmentByID", assignmentID).
+		Return(&domain.Assignment{ID: 5, TeacherID: 7, Title: "Essay", MaxPoints: 40}, nil)
+	repo.On("SaveGradeEntry", mock.MatchedBy(func(e *domain.GradeEntry) bool {
+		return e.Title == "Essay" && e.MaxPoints == 40 && e.Score == 32 && *e.AssignmentID == 5
+	})).Return(int64(11), nil)
+
+	// Test
+	result, err := service.RecordScore(ctx, 7, &domain.GradeEntryInput{
+		StudentID:    12,
+		CategoryID:   3,
+		AssignmentID: &assignmentID,
+		Score:        32,
+	})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), result.ID)
+	assert.Equal(t, "Essay", result.Title)
+	repo.AssertExpectations(t)
+	assignmentRepo.AssertExpectations(t)
+}
+
+func TestGradebookService_RecordScore_Rejections(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("category of another teacher", func(t *testing.T) {
+		service, repo, _, _ := newTestGradebookService()
+		repo.On("GetCategoryByID", int64(3)).Return(&domain.GradeCategory{ID: 3, TeacherID: 8}, nil)
+
+		_, err := service.RecordScore(ctx, 7, &domain.GradeEntryInput{StudentID: 12, CategoryID: 3, Title: "Quiz", Score: 1, MaxPoints: 2})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("student not assigned", func(t *testing.T) {
+		service, repo, teacherRepo, _ := newTestGradebookService()
+		repo.On("GetCategoryByID", int64(3)).Return(&domain.GradeCategory{ID: 3, TeacherID: 7}, nil)
+		teacherRepo.On("IsStudentAssigned", int64(7), int64(12)).Return(false, nil)
+
+		_, err := service.RecordScore(ctx, 7, &domain.GradeEntryInput{StudentID: 12, CategoryID: 3, Title: "Quiz", Score: 1, MaxPoints: 2})
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("missing max points without assignment", func(t *testing.T) {
+		service, repo, teacherRepo, _ := newTestGradebookService()
+		repo.On("GetCategoryByID", int64(3)).Return(&domain.GradeCategory{ID: 3, TeacherID: 7}, nil)
+		teacherRepo.On("IsStudentAssigned", int64(7), int64(12)).Return(true, nil)
+
+		_, err := service.RecordScore(ctx, 7, &domain.GradeEntryInput{StudentID: 12, CategoryID: 3, Title: "Quiz", Score: 1})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		repo.AssertNotCalled(t, "SaveGradeEntry", mock.Anything)
+	})
+}
+
+func TestGradebookService_GetGradebook(t *testing.T) {
+	// Setup
+	service, repo, teacherRepo, _ := newTestGradebookService()
+	ctx := context.Background()
+
+	categories := []domain.GradeCategory{{ID: 1, Name: "Homework", Weight: 40}, {ID: 2, Name: "Exams", Weight: 60}}
+	students := []domain.Student{{ID: 1, Name: "Ann"}, {ID: 2, Name: "Ben"}, {ID: 3, Name: "Cal"}}
+	entries := []domain.GradeEntry{
+		{StudentID: 1, CategoryID: 1, Score: 10, MaxPoints: 10},
+		{StudentID: 1, CategoryID: 2, Score: 50, MaxPoints: 100},
+		{StudentID: 2, CategoryID: 2, Score: 95, MaxPoints: 100},
+	}
+	scale := []domain.GradeThreshold{{Letter: "Pass", MinPercent: 60}, {Letter: "Fail", MinPercent: 0}}
+
+	// Mock expectations
+	repo.On("GetCategoriesByTeacherID", int64(7)).Return(categories, nil)
+	repo.On("GetGradeScale", int64(7)).Return(scale, nil)
+	teacherRepo.On("GetStudentsByTeacherID", int64(7)).Return(students, nil)
+	repo.On("GetGradeEntriesByTeacherID", int64(7)).Return(entries, nil)
+
+	// Test
+	gradebook, err := service.GetGradebook(ctx, 7)
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, scale, gradebook.Scale)
+	require.Len(t, gradebook.Students, 3)
+
+	ann := gradebook.Students[0]
+	assert.Equal(t, "Ann", ann.StudentName)
+	assert.InDelta(t, 70, *ann.Percent, 1e-9) // 0.4*100 + 0.6*50
+	assert.Equal(t, "Pass", ann.Letter)
+
+	ben := gradebook.Students[1]
+	assert.InDelta(t, 95, *ben.Percent, 1e-9)
+
+	cal := gradebook.Students[2]
+	assert.Nil(t, cal.Percent)
+	assert.Empty(t, cal.Letter)
+	repo.AssertExpectations(t)
+	teacherRepo.AssertExpectations(t)
+}
+
+func TestGradebookService_GetStudentGrades_DefaultScale(t *testing.T) {
+	// Setup
+	service, repo, teacherRepo, _ := newTestGradebookService()
+	ctx := context.Background()
+
+	teachers := []domain.Teacher{{ID: 7, Subject: "Math"}, {ID: 8, Subject: "History"}}
+	entries := []domain.GradeEntry{
+		{TeacherID: 7, StudentID: 12, CategoryID: 1, Score: 17, MaxPoints: 20},
+		{TeacherID: 8, StudentID: 12, CategoryID: 5, Score: 5, MaxPoints: 10},
+	}
+
+	// Mock expectations
+	teacherRepo.On("GetTeachersByStudentID", int64(12)).Return(teachers, nil)
+	repo.On("GetGradeEntriesByStudentID", int64(12)).Return(entries, nil)
+	repo.On("GetCategoriesByTeacherID", int64(7)).Return([]domain.GradeCategory{{ID: 1, Weight: 100}}, nil)
+	repo.On("GetCategoriesByTeacherID", int64(8)).Return([]domain.GradeCategory{{ID: 5, Weight: 100}}, nil)
+	repo.On("GetGradeScale", mock.Anything).Return([]domain.GradeThreshold{}, nil)
+
+	// Test
+	grades, err := service.GetStudentGrades(ctx, 12)
+
+	// Assertions
+	require.NoError(t, err)
+	require.Len(t, grades, 2)
+	assert.Equal(t, "Math", grades[0].Subject)
+	assert.InDelta(t, 85, *grades[0].Percent, 1e-9)
+	assert.Equal(t, "B", grades[0].Letter)
+	assert.Equal(t, "History", grades[1].Subject)
+	assert.Equal(t, "F", grades[1].Letter)
+	repo.AssertExpectations(t)
+}
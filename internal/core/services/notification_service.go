@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+)
+
+// 一度に取得する通知の最大数
+const maxNotifications = 100
+
+// 通知サービス構造体：ユーザーへのアプリ内の通知の取得を実装
+type NotificationService struct {
+	// 通知リポジトリインターフェース
+	repo ports.NotificationRepository
+}
+
+// 新しい通知サービスインスタンスを作成する
+func NewNotificationService(repo ports.NotificationRepository) *NotificationService {
+	return &NotificationService{
+		repo: repo,
+	}
+}
+
+// ユーザーへの通知を新しい順に最大100件取得する
+func (s *NotificationService) ListNotifications(ctx context.Context, role string, userID int64) ([]domain.Notification, error) {
+	return s.repo.GetNotificationsByRecipient(role, userID, maxNotifications)
+}
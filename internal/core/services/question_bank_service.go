@@ -0,0 +1,239 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+)
+
+// QTIパッケージのコンテンツタイプ
+const qtiPackageContentType = "application/zip"
+
+// 取り込めるQTIパッケージの最大サイズ（パッケージは解析のためメモリに読み込む）
+const maxQTIPackageSize = 64 << 20
+
+// 問題バンクサービス構造体：問題バンクとQTIパッケージの取り込み・書き出しを実装
+type QuestionBankService struct {
+	// 問題を保存するドキュメントストレージ
+	documents ports.DocumentStorage
+	// 取り込んだパッケージを保存するファイルストレージ
+	fileStorage ports.FileStorage
+	// QTIパッケージのコーデック
+	codec ports.QuestionPackageCodec
+}
+
+// 新しい問題バンクサービスインスタンスを作成する
+func NewQuestionBankService(documents ports.DocumentStorage, fileStorage ports.FileStorage, codec ports.QuestionPackageCodec) *QuestionBankService {
+	return &QuestionBankService{
+		documents:   documents,
+		fileStorage: fileStorage,
+		codec:       codec,
+	}
+}
+
+// 教師の問題バンクの項目一覧を取得する
+// 項目はドキュメントストレージで教師ごとに絞り込み、解析できないドキュメントは記録して読み飛ばす
+func (s *QuestionBankService) ListItems(ctx context.Context, teacherID int64) ([]domain.QuestionBankItem, error) {
+	query := &domain.DocumentQuery{
+		Type:       domain.QuestionBankDocumentType,
+		Limit:      domain.MaxDocumentPageSize,
+		DataEquals: map[string]interface{}{"teacher_id": teacherID},
+	}
+
+	items := []domain.QuestionBankItem{}
+	for {
+		page, err := s.documents.Query(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for i := range page.Items {
+			item, err := questionBankItemFromDocument(&page.Items[i])
+			if err != nil {
+				slog.Warn("skipping undecodable question bank item", slog.String("document_id", page.Items[i].ID), slog.String("error", err.Error()))
+				continue
+			}
+			items = append(items, *item)
+		}
+		if page.NextCursor == "" {
+			return items, nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+// 教師の問題バンクから項目を削除する
+func (s *QuestionBankService) DeleteItem(ctx context.Context, teacherID int64, itemID string) error {
+	if _, err := s.getOwnedItem(ctx, teacherID, itemID); err != nil {
+		return err
+	}
+	return s.documents.Delete(ctx, itemID)
+}
+
+// QTI 2.1パッケージを取り込む
+// パッケージ自体はファイルストレージに保存し、対応している問題を問題バンクに追加する
+// 未対応のインタラクションや正答が不正なアイテムは理由とともに結果に含める
+func (s *QuestionBankService) ImportQTI(ctx context.Context, teacherID int64, upload *domain.FileUpload) (*domain.QTIImportResult, error) {
+	data, err := io.ReadAll(io.LimitReader(upload.Body, maxQTIPackageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read QTI package: %w", err)
+	}
+	if len(data) > maxQTIPackageSize {
+		return nil, fmt.Errorf("%w: QTI package is larger than %d bytes", domain.ErrInvalidInput, maxQTIPackageSize)
+	}
+
+	pkg, err := s.codec.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkg.Items) == 0 && len(pkg.Unsupported) == 0 {
+		return nil, fmt.Errorf("%w: package contains no assessment items", domain.ErrInvalidInput)
+	}
+
+	// 取り込み元のパッケージを保存
+	file, err := s.fileStorage.Upload(ctx, &domain.FileUpload{
+		Name:         upload.Name,
+		ContentType:  qtiPackageContentType,
+		Body:         bytes.NewReader(data),
+		Size:         int64(len(data)),
+		UploaderID:   teacherID,
+		UploaderRole: domain.RoleTeacher,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.QTIImportResult{
+		PackageFileID: file.ID,
+		Imported:      make([]domain.QuestionBankItem, 0, len(pkg.Items)),
+		Unsupported:   pkg.Unsupported,
+	}
+	if result.Unsupported == nil {
+		result.Unsupported = []domain.QTIUnsupportedItem{}
+	}
+
+	for _, qtiItem := range pkg.Items {
+		question := qtiItem.Question
+		if err := validateQuestion(&question); err != nil {
+			result.Unsupported = append(result.Unsupported, domain.QTIUnsupportedItem{
+				File:       qtiItem.File,
+				Identifier: qtiItem.Identifier,
+				Reason:     err.Error(),
+			})
+			continue
+		}
+		if question.Points == 0 {
+			question.Points = 1
+		}
+
+		item := &domain.QuestionBankItem{
+			TeacherID:        teacherID,
+			Question:         question,
+			SourceIdentifier: qtiItem.Identifier,
+			PackageFileID:    file.ID,
+		}
+		data, err := questionBankDocumentData(item)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := s.documents.Create(ctx, &domain.DocumentCreate{Type: domain.QuestionBankDocumentType, Data: data})
+		if err != nil {
+			return nil, err
+		}
+		created, err := questionBankItemFromDocument(doc)
+		if err != nil {
+			return nil, err
+		}
+		result.Imported = append(result.Imported, *created)
+	}
+
+	return result, nil
+}
+
+// 問題バンクの項目をQTI 2.1パッケージとして書き出す
+// IDが指定されていない場合は教師の全項目を書き出す
+func (s *QuestionBankService) ExportQTI(ctx context.Context, teacherID int64, itemIDs []string) ([]byte, error) {
+	var items []domain.QuestionBankItem
+	if len(itemIDs) == 0 {
+		all, err := s.ListItems(ctx, teacherID)
+		if err != nil {
+			return nil, err
+		}
+		items = all
+	} else {
+		items = make([]domain.QuestionBankItem, 0, len(itemIDs))
+		for _, id := range itemIDs {
+			item, err := s.getOwnedItem(ctx, teacherID, id)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, *item)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: question bank of teacher %d is empty", domain.ErrNotFound, teacherID)
+	}
+	return s.codec.Encode(items)
+}
+
+// 教師の問題バンクの項目を取得する（他の教師の項目の場合はErrForbidden）
+func (s *QuestionBankService) getOwnedItem(ctx context.Context, teacherID int64, itemID string) (*domain.QuestionBankItem, error) {
+	doc, err := s.documents.Get(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Type != domain.QuestionBankDocumentType {
+		return nil, fmt.Errorf("%w: no question bank item found with id: %s", domain.ErrNotFound, itemID)
+	}
+
+	item, err := questionBankItemFromDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	if item.TeacherID != teacherID {
+		return nil, fmt.Errorf("%w: question bank item %s does not belong to teacher %d", domain.ErrForbidden, itemID, teacherID)
+	}
+	return item, nil
+}
+
+// 問題バンク項目をドキュメントのデータに変換する
+// ID、作成日時、更新日時はドキュメント自体が保持するため含めない
+func questionBankDocumentData(item *domain.QuestionBankItem) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode question bank item: %w", err)
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return nil, fmt.Errorf("failed to encode question bank item: %w", err)
+	}
+	delete(data, "id")
+	delete(data, "created_at")
+	delete(data, "updated_at")
+	return data, nil
+}
+
+// ドキュメントを問題バンク項目として解釈する
+func questionBankItemFromDocument(doc *domain.Document) (*domain.QuestionBankItem, error) {
+	encoded, err := json.Marshal(doc.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode question bank item %s: %w", doc.ID, err)
+	}
+
+	var item domain.QuestionBankItem
+	if err := json.Unmarshal(encoded, &item); err != nil {
+		return nil, fmt.Errorf("failed to decode question bank item %s: %w", doc.ID, err)
+	}
+
+	item.ID = doc.ID
+	item.CreatedAt = doc.CreatedAt
+	item.UpdatedAt = doc.UpdatedAt
+	return &item, nil
+}
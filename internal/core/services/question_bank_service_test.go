@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestQuestionBankService() (*QuestionBankService, *mocks.DocumentStorage, *mocks.FileStorage, *mocks.QuestionPackageCodec) {
+	documents := new(mocks.DocumentStorage)
+	fileStorage := new(mocks.FileStorage)
+	codec := new(mocks.QuestionPackageCodec)
+	return NewQuestionBankService(documents, fileStorage, codec), documents, fileStorage, codec
+}
+
+// 問題バンク項目をドキュメントとして返す
+func questionBankDocument(t *testing.T, id string, item *domain.QuestionBankItem) domain.Document {
+	data, err := questionBankDocumentData(item)
+	require.NoError(t, err)
+	return domain.Document{ID: id, Type: domain.QuestionBankDocumentType, Data: data}
+}
+
+func TestQuestionBankService_ImportQTI(t *testing.T) {
+	// Setup
+	service, documents, fileStorage, codec := newTestQuestionBankService()
+	ctx := context.Background()
+	upload := &domain.FileUpload{Name: "bank.zip", ContentType: "application/x-zip-compressed", Body: strings.NewReader("zip"), Size: 3}
+
+	pkg := &domain.QTIPackage{
+		Items: []domain.QTIItem{
+			{File: "items/q1.xml", Identifier: "q1", Question: domain.QuizQuestion{
+				ID: "q1", Type: domain.QuestionMultipleChoice, Prompt: "2 + 2?", Options: []string{"3", "4"}, CorrectOption: intPtr(1),
+			}},
+			{File: "items/q2.xml", Identifier: "q2", Question: domain.QuizQuestion{
+				ID: "q2", Type: domain.QuestionShortText, Prompt: "Capital of France?", AcceptedAnswers: []string{" "},
+			}},
+		},
+		Unsupported: []domain.QTIUnsupportedItem{
+			{File: "items/q3.xml", Identifier: "q3", Interaction: "orderInteraction", Reason: "interaction type orderInteraction is not supported"},
+		},
+	}
+
+	// Mock expectations
+	codec.On("Decode", []byte("zip")).Return(pkg, nil)
+	fileStorage.On("Upload", mock.Anything, mock.MatchedBy(func(f *domain.FileUpload) bool {
+		return f.Name == "bank.zip" && f.ContentType == "application/zip"
+	})).Return(&domain.File{ID: "file-1"}, nil)
+	documents.On("Create", mock.Anything, mock.MatchedBy(func(doc *domain.DocumentCreate) bool {
+		return doc.Type == domain.QuestionBankDocumentType && doc.Data["teacher_id"] == float64(7) && doc.Data["package_file_id"] == "file-1"
+	})).Return(func(_ context.Context, doc *domain.DocumentCreate) *domain.Document {
+		return &domain.Document{ID: "item-1", Type: doc.Type, Data: doc.Data}
+	}, nil).Once()
+
+	// Test
+	result, err := service.ImportQTI(ctx, 7, upload)
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, "file-1", result.PackageFileID)
+	require.Len(t, result.Imported, 1)
+	assert.Equal(t, "item-1", result.Imported[0].ID)
+	assert.Equal(t, "q1", result.Imported[0].SourceIdentifier)
+	assert.Equal(t, 1.0, result.Imported[0].Question.Points)
+	require.Len(t, result.Unsupported, 2)
+	assert.Equal(t, "orderInteraction", result.Unsupported[0].Interaction)
+	assert.Equal(t, "q2", result.Unsupported[1].Identifier)
+	assert.Contains(t, result.Unsupported[1].Reason, "accepted_answers")
+	documents.AssertExpectations(t)
+}
+
+func TestQuestionBankService_ImportQTI_InvalidPackage(t *testing.T) {
+	// Setup
+	service, _, fileStorage, codec := newTestQuestionBankService()
+	upload := &domain.FileUpload{Name: "notes.zip", Body: strings.NewReader("zip"), Size: 3}
+
+	// Mock expectations
+	codec.On("Decode", []byte("zip")).Return(&domain.QTIPackage{}, nil)
+
+	// Test
+	_, err := service.ImportQTI(context.Background(), 7, upload)
+
+	// Assertions
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	fileStorage.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything)
+}
+
+func TestQuestionBankService_ExportQTI(t *testing.T) {
+	// Setup
+	service, documents, _, codec := newTestQuestionBankService()
+	ctx := context.Background()
+
+	first := &domain.QuestionBankItem{TeacherID: 7, Question: domain.QuizQuestion{ID: "q1", Type: domain.QuestionTrueFalse, Prompt: "Sky is blue", CorrectBool: boolPtr(true)}}
+	second := &domain.QuestionBankItem{TeacherID: 7, Question: domain.QuizQuestion{ID: "q2", Type: domain.QuestionTrueFalse, Prompt: "Grass is red", CorrectBool: boolPtr(false)}}
+	undecodable := domain.Document{ID: "item-3", Type: domain.QuestionBankDocumentType, Data: map[string]interface{}{"teacher_id": float64(7), "question": "not an object"}}
+
+	// Mock expectations
+	documents.On("Query", mock.Anything, mock.MatchedBy(func(q *domain.DocumentQuery) bool {
+		return q.Type == domain.QuestionBankDocumentType && q.DataEquals["teacher_id"] == int64(7) && q.Cursor == ""
+	})).Return(&domain.DocumentPage{
+		Items:      []domain.Document{questionBankDocument(t, "item-1", first), undecodable},
+		NextCursor: "page-2",
+	}, nil).Once()
+	documents.On("Query", mock.Anything, mock.MatchedBy(func(q *domain.DocumentQuery) bool {
+		return q.Cursor == "page-2"
+	})).Return(&domain.DocumentPage{Items: []domain.Document{questionBankDocument(t, "item-2", second)}}, nil).Once()
+	codec.On("Encode", mock.MatchedBy(func(items []domain.QuestionBankItem) bool {
+		return len(items) == 2 && items[0].ID == "item-1" && items[1].ID == "item-2"
+	})).Return([]byte("package"), nil)
+
+	// Test
+	data, err := service.ExportQTI(ctx, 7, nil)
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, []byte("package"), data)
+}
+
+func TestQuestionBankService_ExportQTI_ForeignItem(t *testing.T) {
+	// Setup
+	service, documents, _, codec := newTestQuestionBankService()
+	other := questionBankDocument(t, "item-2", &domain.QuestionBankItem{TeacherID: 8})
+
+	// Mock expectations
+	documents.On("Get", mock.Anything, "item-2").Return(&other, nil)
+
+	// Test
+	_, err := service.ExportQTI(context.Background(), 7, []string{"item-2"})
+
+	// Assertions
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	codec.AssertNotCalled(t, "Encode", mock.Anything)
+}
+
+func TestQuestionBankService_ExportQTI_EmptyBank(t *testing.T) {
+	service, documents, _, _ := newTestQuestionBankService()
+	documents.On("Query", mock.Anything, mock.Anything).Return(&domain.DocumentPage{Items: []domain.Document{}}, nil)
+
+	_, err := service.ExportQTI(context.Background(), 7, nil)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestQuestionBankService_DeleteItem(t *testing.T) {
+	// Setup
+	service, documents, _, _ := newTestQuestionBankService()
+	own := questionBankDocument(t, "item-1", &domain.QuestionBankItem{TeacherID: 7})
+
+	// Mock expectations
+	documents.On("Get", mock.Anything, "item-1").Return(&own, nil)
+	documents.On("Delete", mock.Anything, "item-1").Return(nil)
+
+	// Test
+	err := service.DeleteItem(context.Background(), 7, "item-1")
+
+	// Assertions
+	require.NoError(t, err)
+	documents.AssertExpectations(t)
+}
@@ -0,0 +1,723 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+)
+
+// 制限時間を過ぎた提出を受け付ける猶予（通信の遅延を考慮）
+const quizSubmissionGrace = 30 * time.Second
+
+// 小テストサービス構造体：小テストの作成、受験、自動採点に関する業務ロジックを実装
+type QuizService struct {
+	// ドキュメントストレージインターフェース（小テスト定義の保存に使用）
+	documents ports.DocumentStorage
+	// 小テスト受験リポジトリインターフェース
+	repo ports.QuizRepository
+	// 成績簿リポジトリインターフェース（成績カテゴリの確認と結果の記録に使用）
+	gradebookRepo ports.GradebookRepository
+	// 教師リポジトリインターフェース（担当関係の確認に使用）
+	teacherRepo ports.TeacherRepository
+	// 現在時刻を返す関数（テストで差し替え可能）
+	now func() time.Time
+	// 要素の順序をランダムに並べ替える関数（テストで差し替え可能）
+	shuffle func(n int, swap func(i, j int))
+}
+
+// 新しい小テストサービスインスタンスを作成する
+func NewQuizService(documents ports.DocumentStorage, repo ports.QuizRepository, gradebookRepo ports.GradebookRepository, teacherRepo ports.TeacherRepository) *QuizService {
+	return &QuizService{
+		documents:     documents,
+		repo:          repo,
+		gradebookRepo: gradebookRepo,
+		teacherRepo:   teacherRepo,
+		now:           time.Now,
+		shuffle:       rand.Shuffle,
+	}
+}
+
+// 教師の小テストを作成する
+// 問題の形式と正答を検証し、種類"test"のドキュメントとして保存する
+func (s *QuizService) CreateQuiz(ctx context.Context, teacherID int64, input *domain.QuizCreate) (*domain.Quiz, error) {
+	quiz := &domain.Quiz{
+		TeacherID:        teacherID,
+		Title:            strings.TrimSpace(input.Title),
+		Description:      input.Description,
+		CategoryID:       input.CategoryID,
+		TimeLimitMinutes: input.TimeLimitMinutes,
+		MaxAttempts:      input.MaxAttempts,
+		ShuffleQuestions: input.ShuffleQuestions,
+		ShuffleOptions:   input.ShuffleOptions,
+		Questions:        input.Questions,
+	}
+	if err := validateQuiz(quiz); err != nil {
+		return nil, err
+	}
+
+	// 結果を記録する成績カテゴリの所有者を確認
+	category, err := s.gradebookRepo.GetCategoryByID(quiz.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category.TeacherID != teacherID {
+		return nil, fmt.Errorf("%w: category %d does not belong to teacher %d", domain.ErrForbidden, quiz.CategoryID, teacherID)
+	}
+
+	data, err := quizDocumentData(quiz)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := s.documents.Create(ctx, &domain.DocumentCreate{Type: domain.QuizDocumentType, Data: data})
+	if err != nil {
+		return nil, err
+	}
+
+	return quizFromDocument(doc)
+}
+
+// 教師が作成した小テスト一覧を取得する
+func (s *QuizService) ListQuizzesByTeacher(ctx context.Context, teacherID int64) ([]domain.Quiz, error) {
+	quizzes, err := s.listQuizzes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make([]domain.Quiz, 0, len(quizzes))
+	for _, quiz := range quizzes {
+		if quiz.TeacherID == teacherID {
+			owned = append(owned, quiz)
+		}
+	}
+	return owned, nil
+}
+
+// 教師が作成した小テストを正答付きで取得する
+func (s *QuizService) GetQuiz(ctx context.Context, teacherID int64, quizID string) (*domain.Quiz, error) {
+	return s.getOwnedQuiz(ctx, teacherID, quizID)
+}
+
+// 教師の小テストの全学生の受験記録を取得する
+func (s *QuizService) ListAttempts(ctx context.Context, teacherID int64, quizID string) ([]domain.QuizAttempt, error) {
+	if _, err := s.getOwnedQuiz(ctx, teacherID, quizID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAttemptsByQuizID(quizID)
+}
+
+// 学生の担当教師が作成した小テスト一覧を取得する
+func (s *QuizService) ListQuizzesForStudent(ctx context.Context, studentID int64) ([]domain.QuizSummary, error) {
+	teachers, err := s.teacherRepo.GetTeachersByStudentID(studentID)
+	if err != nil {
+		return nil, err
+	}
+	teacherIDs := make(map[int64]bool, len(teachers))
+	for _, teacher := range teachers {
+		teacherIDs[teacher.ID] = true
+	}
+
+	quizzes, err := s.listQuizzes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]domain.QuizSummary, 0, len(quizzes))
+	for _, quiz := range quizzes {
+		if !teacherIDs[quiz.TeacherID] {
+			continue
+		}
+		summaries = append(summaries, domain.QuizSummary{
+			ID:               quiz.ID,
+			TeacherID:        quiz.TeacherID,
+			Title:            quiz.Title,
+			Description:      quiz.Description,
+			TimeLimitMinutes: quiz.TimeLimitMinutes,
+			MaxAttempts:      quiz.MaxAttempts,
+			QuestionCount:    len(quiz.Questions),
+			TotalPoints:      quizTotalPoints(&quiz),
+		})
+	}
+	return summaries, nil
+}
+
+// 学生が小テストの受験を開始する
+// 制限時間内の受験中の記録がある場合はそれを再開し、制限時間を過ぎた受験中の記録は時間切れとして採点する
+func (s *QuizService) StartAttempt(ctx context.Context, studentID int64, quizID string) (*domain.QuizAttemptSession, error) {
+	quiz, err := s.getQuiz(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureStudentOf(quiz, studentID); err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.repo.GetAttemptsByQuizAndStudent(quizID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	for i := range attempts {
+		attempt := &attempts[i]
+		if attempt.Status != domain.AttemptInProgress {
+			continue
+		}
+		if !attemptExpired(attempt, now) {
+			return buildAttemptSession(quiz, attempt), nil
+		}
+		// 同時に提出されて既に終了している受験は、そのまま終了済みとして扱う
+		if err := s.finishAttempt(quiz, attempt, nil, now); err != nil && !errors.Is(err, domain.ErrConflict) {
+			return nil, err
+		}
+	}
+
+	if quiz.MaxAttempts > 0 && len(attempts) >= quiz.MaxAttempts {
+		return nil, fmt.Errorf("%w: attempt limit of %d reached", domain.ErrForbidden, quiz.MaxAttempts)
+	}
+
+	attempt := &domain.QuizAttempt{
+		QuizID:        quiz.ID,
+		StudentID:     studentID,
+		TeacherID:     quiz.TeacherID,
+		Number:        len(attempts) + 1,
+		Status:        domain.AttemptInProgress,
+		StartedAt:     now,
+		MaxScore:      quizTotalPoints(quiz),
+		QuestionOrder: s.questionOrder(quiz),
+		OptionOrder:   s.optionOrder(quiz),
+	}
+	if quiz.TimeLimitMinutes > 0 {
+		deadline := now.Add(time.Duration(quiz.TimeLimitMinutes) * time.Minute)
+		attempt.Deadline = &deadline
+	}
+
+	id, err := s.repo.CreateAttempt(attempt)
+	if err != nil {
+		return nil, err
+	}
+	attempt.ID = id
+
+	return buildAttemptSession(quiz, attempt), nil
+}
+
+// 学生が回答を提出し、自動採点された受験記録を返す
+// 制限時間（猶予を含む）を過ぎた提出は回答を受け付けず、時間切れとして0点で採点する
+// 同じ受験が同時に提出された場合は、先に記録された提出だけを採点し、後の提出はErrConflictを返す
+func (s *QuizService) SubmitAttempt(ctx context.Context, studentID int64, quizID string, attemptID int64, answers []domain.QuizAnswer) (*domain.QuizAttempt, error) {
+	attempt, err := s.repo.GetAttemptByID(attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.QuizID != quizID {
+		return nil, fmt.Errorf("%w: attempt %d does not belong to quiz %s", domain.ErrNotFound, attemptID, quizID)
+	}
+	if attempt.StudentID != studentID {
+		return nil, fmt.Errorf("%w: attempt %d does not belong to student %d", domain.ErrForbidden, attemptID, studentID)
+	}
+	if attempt.Status != domain.AttemptInProgress {
+		return nil, fmt.Errorf("%w: attempt %d has already been %s", domain.ErrInvalidInput, attemptID, attempt.Status)
+	}
+
+	quiz, err := s.getQuiz(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if attemptExpired(attempt, now) {
+		answers = nil
+	} else if err := validateAnswers(quiz, answers); err != nil {
+		return nil, err
+	}
+
+	if err := s.finishAttempt(quiz, attempt, answers, now); err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// 学生自身の小テストの受験記録を取得する
+func (s *QuizService) ListMyAttempts(ctx context.Context, studentID int64, quizID string) ([]domain.QuizAttempt, error) {
+	return s.repo.GetAttemptsByQuizAndStudent(quizID, studentID)
+}
+
+// 受験を採点して終了し、最高得点を成績として記録する
+// 回答がnilの場合は制限時間切れとして扱う
+func (s *QuizService) finishAttempt(quiz *domain.Quiz, attempt *domain.QuizAttempt, answers []domain.QuizAnswer, now time.Time) error {
+	status := domain.AttemptSubmitted
+	if answers == nil && attemptExpired(attempt, now) {
+		status = domain.AttemptExpired
+	}
+
+	score, results := scoreQuiz(quiz, answers)
+	attempt.Status = status
+	attempt.SubmittedAt = &now
+	attempt.Score = &score
+	attempt.MaxScore = quizTotalPoints(quiz)
+	attempt.Answers = answers
+	attempt.Results = results
+	if err := s.repo.UpdateAttempt(attempt); err != nil {
+		return err
+	}
+
+	return s.recordGrade(quiz, attempt.StudentID, now)
+}
+
+// 学生の終了済み受験のうち最高得点を成績記録に書き込む
+func (s *QuizService) recordGrade(quiz *domain.Quiz, studentID int64, now time.Time) error {
+	attempts, err := s.repo.GetAttemptsByQuizAndStudent(quiz.ID, studentID)
+	if err != nil {
+		return err
+	}
+
+	var best *float64
+	for _, attempt := range attempts {
+		if attempt.Status == domain.AttemptInProgress || attempt.Score == nil {
+			continue
+		}
+		if best == nil || *attempt.Score > *best {
+			score := *attempt.Score
+			best = &score
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	quizID := quiz.ID
+	_, err = s.gradebookRepo.SaveGradeEntry(&domain.GradeEntry{
+		TeacherID:  quiz.TeacherID,
+		StudentID:  studentID,
+		CategoryID: quiz.CategoryID,
+		QuizID:     &quizID,
+		Title:      quiz.Title,
+		Score:      *best,
+		MaxPoints:  quizTotalPoints(quiz),
+		GradedAt:   now,
+	})
+	return err
+}
+
+// 教師が作成した小テストを取得する（他の教師の小テストの場合はErrForbidden）
+func (s *QuizService) getOwnedQuiz(ctx context.Context, teacherID int64, quizID string) (*domain.Quiz, error) {
+	quiz, err := s.getQuiz(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+	if quiz.TeacherID != teacherID {
+		return nil, fmt.Errorf("%w: quiz %s does not belong to teacher %d", domain.ErrForbidden, quizID, teacherID)
+	}
+	return quiz, nil
+}
+
+// ドキュメントストレージから小テストを取得する
+func (s *QuizService) getQuiz(ctx context.Context, quizID string) (*domain.Quiz, error) {
+	doc, err := s.documents.Get(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Type != domain.QuizDocumentType {
+		return nil, fmt.Errorf("%w: no quiz found with id: %s", domain.ErrNotFound, quizID)
+	}
+	return quizFromDocument(doc)
+}
+
+// 種類"test"のドキュメントのうち、小テストとして解釈できるものの一覧を取得する
+// 小テストエンジン導入前に保存された自由形式のドキュメントは除外される
+func (s *QuizService) listQuizzes(ctx context.Context) ([]domain.Quiz, error) {
+	docs, err := s.documents.List(ctx, domain.QuizDocumentType)
+	if err != nil {
+		return nil, err
+	}
+
+	quizzes := make([]domain.Quiz, 0, len(docs))
+	for i := range docs {
+		quiz, err := quizFromDocument(&docs[i])
+		if err != nil {
+			continue
+		}
+		quizzes = append(quizzes, *quiz)
+	}
+	return quizzes, nil
+}
+
+// 学生が小テストを作成した教師の担当であることを確認する
+func (s *QuizService) ensureStudentOf(quiz *domain.Quiz, studentID int64) error {
+	assigned, err := s.teacherRepo.IsStudentAssigned(quiz.TeacherID, studentID)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		return fmt.Errorf("%w: student %d is not assigned to the teacher of quiz %s", domain.ErrForbidden, studentID, quiz.ID)
+	}
+	return nil
+}
+
+// 受験ごとの出題順を決定する
+func (s *QuizService) questionOrder(quiz *domain.Quiz) []string {
+	order := make([]string, len(quiz.Questions))
+	for i, question := range quiz.Questions {
+		order[i] = question.ID
+	}
+	if quiz.ShuffleQuestions {
+		s.shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	}
+	return order
+}
+
+// 受験ごとの選択肢の提示順を決定する（選択肢をシャッフルしない場合は空）
+func (s *QuizService) optionOrder(quiz *domain.Quiz) map[string][]int {
+	if !quiz.ShuffleOptions {
+		return nil
+	}
+
+	orders := make(map[string][]int)
+	for _, question := range quiz.Questions {
+		if len(question.Options) == 0 {
+			continue
+		}
+		order := make([]int, len(question.Options))
+		for i := range order {
+			order[i] = i
+		}
+		s.shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+		orders[question.ID] = order
+	}
+	return orders
+}
+
+// 受験の制限時間（猶予を含む）を過ぎているかを判定する
+func attemptExpired(attempt *domain.QuizAttempt, now time.Time) bool {
+	return attempt.Deadline != nil && now.After(attempt.Deadline.Add(quizSubmissionGrace))
+}
+
+// 受験記録から、正答を含まない出題一式を組み立てる
+func buildAttemptSession(quiz *domain.Quiz, attempt *domain.QuizAttempt) *domain.QuizAttemptSession {
+	questions := make(map[string]domain.QuizQuestion, len(quiz.Questions))
+	for _, question := range quiz.Questions {
+		questions[question.ID] = question
+	}
+
+	session := &domain.QuizAttemptSession{
+		Attempt:   *attempt,
+		Title:     quiz.Title,
+		Questions: make([]domain.QuizQuestionView, 0, len(attempt.QuestionOrder)),
+	}
+	for _, id := range attempt.QuestionOrder {
+		question, ok := questions[id]
+		if !ok {
+			// 受験開始後に削除された問題は出題しない
+			continue
+		}
+
+		view := domain.QuizQuestionView{
+			ID:     question.ID,
+			Type:   question.Type,
+			Prompt: question.Prompt,
+			Points: questionPoints(question),
+		}
+		order := attempt.OptionOrder[id]
+		if len(order) != len(question.Options) {
+			order = make([]int, len(question.Options))
+			for i := range order {
+				order[i] = i
+			}
+		}
+		for _, index := range order {
+			view.Options = append(view.Options, domain.QuizOption{ID: index, Text: question.Options[index]})
+		}
+		session.Questions = append(session.Questions, view)
+	}
+	return session
+}
+
+// 小テストの定義を検証し、配点の省略を既定値で補う
+func validateQuiz(quiz *domain.Quiz) error {
+	if quiz.Title == "" {
+		return fmt.Errorf("%w: title is required", domain.ErrInvalidInput)
+	}
+	if quiz.TimeLimitMinutes < 0 || quiz.MaxAttempts < 0 {
+		return fmt.Errorf("%w: time_limit_minutes and max_attempts must not be negative", domain.ErrInvalidInput)
+	}
+	if len(quiz.Questions) == 0 {
+		return fmt.Errorf("%w: a quiz needs at least one question", domain.ErrInvalidInput)
+	}
+
+	ids := make(map[string]bool, len(quiz.Questions))
+	for i := range quiz.Questions {
+		question := &quiz.Questions[i]
+		question.ID = strings.TrimSpace(question.ID)
+		if question.ID == "" {
+			question.ID = fmt.Sprintf("q%d", i+1)
+		}
+		if ids[question.ID] {
+			return fmt.Errorf("%w: duplicate question id %q", domain.ErrInvalidInput, question.ID)
+		}
+		ids[question.ID] = true
+
+		if err := validateQuestion(question); err != nil {
+			return fmt.Errorf("%w: question %q: %s", domain.ErrInvalidInput, question.ID, err)
+		}
+		if question.Points == 0 {
+			question.Points = 1
+		}
+	}
+	return nil
+}
+
+// 問題の種類に応じて選択肢と正答を検証する
+func validateQuestion(question *domain.QuizQuestion) error {
+	if strings.TrimSpace(question.Prompt) == "" {
+		return errors.New("prompt is required")
+	}
+	if question.Points < 0 {
+		return errors.New("points must not be negative")
+	}
+
+	switch question.Type {
+	case domain.QuestionMultipleChoice:
+		if len(question.Options) < 2 {
+			return errors.New("needs at least two options")
+		}
+		if question.CorrectOption == nil || *question.CorrectOption < 0 || *question.CorrectOption >= len(question.Options) {
+			return errors.New("correct_option must be the index of one of the options")
+		}
+	case domain.QuestionMultiSelect:
+		if len(question.Options) < 2 {
+			return errors.New("needs at least two options")
+		}
+		if len(question.CorrectOptions) == 0 {
+			return errors.New("correct_options must not be empty")
+		}
+		seen := make(map[int]bool, len(question.CorrectOptions))
+		for _, index := range question.CorrectOptions {
+			if index < 0 || index >= len(question.Options) || seen[index] {
+				return errors.New("correct_options must be distinct indexes of the options")
+			}
+			seen[index] = true
+		}
+	case domain.QuestionTrueFalse:
+		if question.CorrectBool == nil {
+			return errors.New("correct_bool is required")
+		}
+		question.Options = nil
+	case domain.QuestionNumeric:
+		if question.CorrectNumber == nil {
+			return errors.New("correct_number is required")
+		}
+		if question.Tolerance < 0 {
+			return errors.New("tolerance must not be negative")
+		}
+		question.Options = nil
+	case domain.QuestionShortText:
+		accepted := make([]string, 0, len(question.AcceptedAnswers))
+		for _, answer := range question.AcceptedAnswers {
+			if answer = strings.TrimSpace(answer); answer != "" {
+				accepted = append(accepted, answer)
+			}
+		}
+		if len(accepted) == 0 {
+			return errors.New("accepted_answers must not be empty")
+		}
+		question.AcceptedAnswers = accepted
+		question.Options = nil
+	default:
+		return fmt.Errorf("unsupported question type %q", question.Type)
+	}
+	return nil
+}
+
+// 回答が小テストの問題に対応していることを検証する
+func validateAnswers(quiz *domain.Quiz, answers []domain.QuizAnswer) error {
+	ids := make(map[string]bool, len(quiz.Questions))
+	for _, question := range quiz.Questions {
+		ids[question.ID] = true
+	}
+
+	answered := make(map[string]bool, len(answers))
+	for _, answer := range answers {
+		if !ids[answer.QuestionID] {
+			return fmt.Errorf("%w: unknown question %q", domain.ErrInvalidInput, answer.QuestionID)
+		}
+		if answered[answer.QuestionID] {
+			return fmt.Errorf("%w: question %q answered more than once", domain.ErrInvalidInput, answer.QuestionID)
+		}
+		answered[answer.QuestionID] = true
+	}
+	return nil
+}
+
+// 回答を採点し、合計点と問題ごとの採点結果を返す
+// 回答のない問題と形式が不正な回答は不正解として扱う
+func scoreQuiz(quiz *domain.Quiz, answers []domain.QuizAnswer) (float64, []domain.QuizQuestionResult) {
+	byQuestion := make(map[string]interface{}, len(answers))
+	for _, answer := range answers {
+		byQuestion[answer.QuestionID] = answer.Value
+	}
+
+	var total float64
+	results := make([]domain.QuizQuestionResult, 0, len(quiz.Questions))
+	for _, question := range quiz.Questions {
+		points := questionPoints(question)
+		value, answered := byQuestion[question.ID]
+		correct := answered && answerCorrect(question, value)
+
+		result := domain.QuizQuestionResult{QuestionID: question.ID, Correct: correct, MaxPoints: points}
+		if correct {
+			result.Points = points
+			total += points
+		}
+		results = append(results, result)
+	}
+	return total, results
+}
+
+// 回答が正解かどうかを判定する
+func answerCorrect(question domain.QuizQuestion, value interface{}) bool {
+	switch question.Type {
+	case domain.QuestionMultipleChoice:
+		choice, ok := answerInt(value)
+		return ok && question.CorrectOption != nil && choice == *question.CorrectOption
+	case domain.QuestionMultiSelect:
+		// 正答の選択肢をすべて、かつ正答の選択肢のみを選んだ場合に正解
+		values, ok := value.([]interface{})
+		if !ok || len(values) != len(question.CorrectOptions) {
+			return false
+		}
+		expected := make(map[int]bool, len(question.CorrectOptions))
+		for _, index := range question.CorrectOptions {
+			expected[index] = true
+		}
+		for _, v := range values {
+			choice, ok := answerInt(v)
+			if !ok || !expected[choice] {
+				return false
+			}
+			delete(expected, choice)
+		}
+		return len(expected) == 0
+	case domain.QuestionTrueFalse:
+		b, ok := value.(bool)
+		return ok && question.CorrectBool != nil && b == *question.CorrectBool
+	case domain.QuestionNumeric:
+		n, ok := answerNumber(value)
+		return ok && question.CorrectNumber != nil && math.Abs(n-*question.CorrectNumber) <= question.Tolerance+1e-9
+	case domain.QuestionShortText:
+		text, ok := value.(string)
+		if !ok {
+			return false
+		}
+		text = normalizeShortText(text, question.CaseSensitive)
+		for _, accepted := range question.AcceptedAnswers {
+			if text == normalizeShortText(accepted, question.CaseSensitive) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// 回答の値を選択肢番号として解釈する
+func answerInt(value interface{}) (int, bool) {
+	n, ok := answerNumber(value)
+	if !ok || n != math.Trunc(n) {
+		return 0, false
+	}
+	return int(n), true
+}
+
+// 回答の値を数値として解釈する（数値を表す文字列も受け付ける）
+func answerNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// 短文記述の回答を比較用に正規化する（前後の空白を除き、連続する空白を1つにまとめる）
+func normalizeShortText(text string, caseSensitive bool) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if !caseSensitive {
+		text = strings.ToLower(text)
+	}
+	return text
+}
+
+// 問題の配点を返す（省略時は1点）
+func questionPoints(question domain.QuizQuestion) float64 {
+	if question.Points <= 0 {
+		return 1
+	}
+	return question.Points
+}
+
+// 小テストの満点を返す
+func quizTotalPoints(quiz *domain.Quiz) float64 {
+	var total float64
+	for _, question := range quiz.Questions {
+		total += questionPoints(question)
+	}
+	return total
+}
+
+// 小テストをドキュメントのデータに変換する
+// ID、作成日時、更新日時はドキュメント自体が保持するため含めない
+func quizDocumentData(quiz *domain.Quiz) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(quiz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode quiz: %w", err)
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return nil, fmt.Errorf("failed to encode quiz: %w", err)
+	}
+	delete(data, "id")
+	delete(data, "created_at")
+	delete(data, "updated_at")
+	return data, nil
+}
+
+// ドキュメントを小テストとして解釈する
+func quizFromDocument(doc *domain.Document) (*domain.Quiz, error) {
+	encoded, err := json.Marshal(doc.Data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: document %s is not a valid quiz: %s", domain.ErrInvalidInput, doc.ID, err)
+	}
+
+	var quiz domain.Quiz
+	if err := json.Unmarshal(encoded, &quiz); err != nil {
+		return nil, fmt.Errorf("%w: document %s is not a valid quiz: %s", domain.ErrInvalidInput, doc.ID, err)
+	}
+	if quiz.TeacherID == 0 || len(quiz.Questions) == 0 {
+		return nil, fmt.Errorf("%w: document %s is not a valid quiz", domain.ErrInvalidInput, doc.ID)
+	}
+
+	quiz.ID = doc.ID
+	quiz.CreatedAt = doc.CreatedAt
+	quiz.UpdatedAt = doc.UpdatedAt
+	return &quiz, nil
+}
@@ -0,0 +1,558 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type quizMocks struct {
+	documents     *mocks.DocumentStorage
+	repo          *mocks.QuizRepository
+	gradebookRepo *mocks.GradebookRepository
+	teacherRepo   *mocks.TeacherRepository
+}
+
+func newTestQuizService(now time.Time) (*QuizService, *quizMocks) {
+	m := &quizMocks{
+		documents:     new(mocks.DocumentStorage),
+		repo:          new(mocks.QuizRepository),
+		gradebookRepo: new(mocks.GradebookRepository),
+		teacherRepo:   new(mocks.TeacherRepository),
+	}
+	service := NewQuizService(m.documents, m.repo, m.gradebookRepo, m.teacherRepo)
+	service.now = func() time.Time { return now }
+	// 順序を逆転させる決定的なシャッフル
+	service.shuffle = func(n int, swap func(i, j int)) {
+		for i := 0; i < n/2; i++ {
+			swap(i, n-1-i)
+		}
+	}
+	return service, m
+}
+
+func intPtr(v int) *int              { return &v }
+func boolPtr(v bool) *bool           { return &v }
+func floatPtr(v float64) *float64    { return &v }
+func timePtr(v time.Time) *time.Time { return &v }
+
+// 全種類の問題を含む小テストを返す
+func sampleQuiz() *domain.Quiz {
+	return &domain.Quiz{
+		ID:         "quiz-1",
+		TeacherID:  7,
+		Title:      "Chapter 3 quiz",
+		CategoryID: 3,
+		Questions: []domain.QuizQuestion{
+			{ID: "mc", Type: domain.QuestionMultipleChoice, Prompt: "2 + 2?", Options: []string{"3", "4", "5"}, CorrectOption: intPtr(1), Points: 2},
+			{ID: "ms", Type: domain.QuestionMultiSelect, Prompt: "Primes?", Options: []string{"2", "4", "5", "9"}, CorrectOptions: []int{0, 2}, Points: 2},
+			{ID: "tf", Type: domain.QuestionTrueFalse, Prompt: "The sky is blue", CorrectBool: boolPtr(true), Points: 1},
+			{ID: "num", Type: domain.QuestionNumeric, Prompt: "Pi?", CorrectNumber: floatPtr(3.14), Tolerance: 0.01, Points: 1},
+			{ID: "txt", Type: domain.QuestionShortText, Prompt: "Capital of France?", AcceptedAnswers: []string{"Paris"}, Points: 4},
+		},
+	}
+}
+
+// 小テストをドキュメントとして返す
+func quizDocument(t *testing.T, quiz *domain.Quiz) *domain.Document {
+	data, err := quizDocumentData(quiz)
+	require.NoError(t, err)
+	return &domain.Document{ID: quiz.ID, Type: domain.QuizDocumentType, Data: data}
+}
+
+func TestAnswerCorrect(t *testing.T) {
+	questions := make(map[string]domain.QuizQuestion)
+	for _, q := range sampleQuiz().Questions {
+		questions[q.ID] = q
+	}
+	caseSensitive := domain.QuizQuestion{Type: domain.QuestionShortText, AcceptedAnswers: []string{"NaCl"}, CaseSensitive: true}
+
+	tests := []struct {
+		name     string
+		question domain.QuizQuestion
+		value    interface{}
+		want     bool
+	}{
+		{"multiple choice correct", questions["mc"], float64(1), true},
+		{"multiple choice wrong", questions["mc"], float64(2), false},
+		{"multiple choice fractional", questions["mc"], 1.5, false},
+		{"multiple choice wrong type", questions["mc"], "four", false},
+		{"multi select exact", questions["ms"], []interface{}{float64(2), float64(0)}, true},
+		{"multi select missing one", questions["ms"], []interface{}{float64(0)}, false},
+		{"multi select extra choice", questions["ms"], []interface{}{float64(0), float64(2), float64(3)}, false},
+		{"multi select duplicate", questions["ms"], []interface{}{float64(0), float64(0)}, false},
+		{"true false correct", questions["tf"], true, true},
+		{"true false wrong", questions["tf"], false, false},
+		{"true false string", questions["tf"], "true", false},
+		{"numeric exact", questions["num"], 3.14, true},
+		{"numeric within tolerance", questions["num"], 3.15, true},
+		{"numeric outside tolerance", questions["num"], 3.16, false},
+		{"numeric as string", questions["num"], " 3.139 ", true},
+		{"short text ignores case and spacing", questions["txt"], "  pARis ", true},
+		{"short text wrong", questions["txt"], "Lyon", false},
+		{"short text case sensitive", caseSensitive, "nacl", false},
+		{"short text case sensitive match", caseSensitive, "NaCl", true},
+		{"missing value", questions["mc"], nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, answerCorrect(tt.question, tt.value))
+		})
+	}
+}
+
+func TestScoreQuiz(t *testing.T) {
+	quiz := sampleQuiz()
+
+	score, results := scoreQuiz(quiz, []domain.QuizAnswer{
+		{QuestionID: "mc", Value: float64(1)},
+		{QuestionID: "ms", Value: []interface{}{float64(0)}},
+		{QuestionID: "txt", Value: "paris"},
+	})
+
+	assert.Equal(t, 6.0, score)
+	require.Len(t, results, 5)
+	assert.Equal(t, domain.QuizQuestionResult{QuestionID: "mc", Correct: true, Points: 2, MaxPoints: 2}, results[0])
+	assert.False(t, results[1].Correct)
+	assert.False(t, results[2].Correct) // 未回答
+	assert.Equal(t, 10.0, quizTotalPoints(quiz))
+}
+
+func TestValidateQuiz(t *testing.T) {
+	valid := sampleQuiz()
+	valid.Questions[2].Points = 0
+	valid.Questions[3].ID = ""
+	require.NoError(t, validateQuiz(valid))
+	assert.Equal(t, 1.0, valid.Questions[2].Points)
+	assert.Equal(t, "q4", valid.Questions[3].ID)
+
+	invalid := map[string]func(q *domain.Quiz){
+		"no questions":                 func(q *domain.Quiz) { q.Questions = nil },
+		"duplicate ids":                func(q *domain.Quiz) { q.Questions[1].ID = "mc" },
+		"unknown type":                 func(q *domain.Quiz) { q.Questions[0].Type = "essay" },
+		"choice out of range":          func(q *domain.Quiz) { q.Questions[0].CorrectOption = intPtr(3) },
+		"single option":                func(q *domain.Quiz) { q.Questions[0].Options = []string{"4"} },
+		"multi select without answers": func(q *domain.Quiz) { q.Questions[1].CorrectOptions = nil },
+		"true false without answer":    func(q *domain.Quiz) { q.Questions[2].CorrectBool = nil },
+		"negative tolerance":           func(q *domain.Quiz) { q.Questions[3].Tolerance = -1 },
+		"blank accepted answers":       func(q *domain.Quiz) { q.Questions[4].AcceptedAnswers = []string{" "} },
+		"negative time limit":          func(q *domain.Quiz) { q.TimeLimitMinutes = -5 },
+		"missing prompt":               func(q *domain.Quiz) { q.Questions[0].Prompt = "" },
+	}
+	for name, mutate := range invalid {
+		t.Run(name, func(t *testing.T) {
+			quiz := sampleQuiz()
+			mutate(quiz)
+			assert.ErrorIs(t, validateQuiz(quiz), domain.ErrInvalidInput)
+		})
+	}
+}
+
+func TestQuizService_CreateQuiz(t *testing.T) {
+	// Setup
+	service, m := newTestQuizService(time.Now())
+	ctx := context.Background()
+	source := sampleQuiz()
+
+	// Mock expectations
+	m.gradebookRepo.On("GetCategoryByID", int64(3)).Return(&domain.GradeCategory{ID: 3, TeacherID: 7}, nil)
+	m.documents.On("Create", mock.Anything, mock.MatchedBy(func(doc *domain.DocumentCreate) bool {
+		return doc.Type == "test" && doc.Data["teacher_id"] == float64(7) && doc.Data["id"] == nil
+	})).Return(func(_ context.Context, doc *domain.DocumentCreate) *domain.Document {
+		return &domain.Document{ID: "doc-1", Type: doc.Type, Data: doc.Data}
+	}, nil)
+
+	// Test
+	quiz, err := service.CreateQuiz(ctx, 7, &domain.QuizCreate{
+		Title:      " Chapter 3 quiz ",
+		CategoryID: 3,
+		Questions:  source.Questions,
+	})
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, "doc-1", quiz.ID)
+	assert.Equal(t, "Chapter 3 quiz", quiz.Title)
+	assert.Equal(t, int64(7), quiz.TeacherID)
+	require.Len(t, quiz.Questions, 5)
+	assert.Equal(t, 1, *quiz.Questions[0].CorrectOption)
+	assert.Equal(t, []int{0, 2}, quiz.Questions[1].CorrectOptions)
+	m.documents.AssertExpectations(t)
+}
+
+func TestQuizService_CreateQuiz_ForeignCategory(t *testing.T) {
+	// Setup
+	service, m := newTestQuizService(time.Now())
+
+	// Mock expectations
+	m.gradebookRepo.On("GetCategoryByID", int64(3)).Return(&domain.GradeCategory{ID: 3, TeacherID: 8}, nil)
+
+	// Test
+	_, err := service.CreateQuiz(context.Background(), 7, &domain.QuizCreate{
+		Title:      "Quiz",
+		CategoryID: 3,
+		Questions:  sampleQuiz().Questions,
+	})
+
+	// Assertions
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	m.documents.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestQuizService_StartAttempt_NewShuffledAttempt(t *testing.T) {
+	// Setup
+	now := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	service, m := newTestQuizService(now)
+	ctx := context.Background()
+
+	quiz := sampleQuiz()
+	quiz.TimeLimitMinutes = 15
+	quiz.MaxAttempts = 2
+	quiz.ShuffleQuestions = true
+	quiz.ShuffleOptions = true
+
+	// Mock expectations
+	m.documents.On("Get", mock.Anything, "quiz-1").Return(quizDocument(t, quiz), nil)
+	m.teacherRepo.On("IsStudentAssigned", int64(7), int64(12)).Return(true, nil)
+	m.repo.On("GetAttemptsByQuizAndStudent", "quiz-1", int64(12)).
+		Return([]domain.QuizAttempt{{ID: 1, Number: 1, Status: domain.AttemptSubmitted}}, nil)
+	m.repo.On("CreateAttempt", mock.MatchedBy(func(a *domain.QuizAttempt) bool {
+		return a.Number == 2 && a.Status == domain.AttemptInProgress && a.Deadline.Equal(now.Add(15*time.Minute)) && a.MaxScore == 10
+	})).Return(int64(2), nil)
+
+	// Test
+	session, err := service.StartAttempt(ctx, 12, "quiz-1")
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), session.Attempt.ID)
+	assert.Equal(t, []string{"txt", "num", "tf", "ms", "mc"}, session.Attempt.QuestionOrder)
+	require.Len(t, session.Questions, 5)
+	assert.Equal(t, "txt", session.Questions[0].ID)
+	mc := session.Questions[4]
+	assert.Equal(t, []domain.QuizOption{{ID: 2, Text: "5"}, {ID: 1, Text: "4"}, {ID: 0, Text: "3"}}, mc.Options)
+	assert.Empty(t, session.Questions[2].Options)
+	m.repo.AssertExpectations(t)
+}
+
+func TestQuizService_StartAttempt_ResumesAttemptInProgress(t *testing.T) {
+	// Setup
+	now := time.Date(2024, 5, 10, 9, 10, 0, 0, time.UTC)
+	service, m := newTestQuizService(now)
+
+	quiz := sampleQuiz()
+	quiz.TimeLimitMinutes = 15
+	inProgress := domain.QuizAttempt{
+		ID: 4, QuizID: "quiz-1", StudentID: 12, Number: 1, Status: domain.AttemptInProgress,
+		Deadline:      timePtr(now.Add(5 * time.Minute)),
+		QuestionOrder: []string{"tf", "mc"},
+	}
+
+	// Mock expectations
+	m.documents.On("Get", mock.Anything, "quiz-1").Return(quizDocument(t, quiz), nil)
+	m.teacherRepo.On("IsStudentAssigned", int64(7), int64(12)).Return(true, nil)
+	m.repo.On("GetAttemptsByQuizAndStudent", "quiz-1", int64(12)).Return([]domain.QuizAttempt{inProgress}, nil)
+
+	// Test
+	session, err := service.StartAttempt(context.Background(), 12, "quiz-1")
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), session.Attempt.ID)
+	require.Len(t, session.Questions, 2)
+	assert.Equal(t, "tf", session.Questions[0].ID)
+	m.repo.AssertNotCalled(t, "CreateAttempt", mock.Anything)
+}
+
+func TestQuizService_StartAttempt_ExpiresStaleAttemptAndEnforcesLimit(t *testing.T) {
+	// Setup
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	service, m := newTestQuizService(now)
+
+	quiz := sampleQuiz()
+	quiz.TimeLimitMinutes = 15
+	quiz.MaxAttempts = 1
+	stale := domain.QuizAttempt{
+		ID: 4, QuizID: "quiz-1", StudentID: 12, Number: 1, Status: domain.AttemptInProgress,
+		Deadline: timePtr(now.Add(-time.Hour)),
+	}
+	expiredScore := 0.0
+
+	// Mock expectations
+	m.documents.On("Get", mock.Anything, "quiz-1").Return(quizDocument(t, quiz), nil)
+	m.teacherRepo.On("IsStudentAssigned", int64(7), int64(12)).Return(true, nil)
+	m.repo.On("GetAttemptsByQuizAndStudent", "quiz-1", int64(12)).Return([]domain.QuizAttempt{stale}, nil).Once()
+	m.repo.On("UpdateAttempt", mock.MatchedBy(func(a *domain.QuizAttempt) bool {
+		return a.ID == 4 && a.Status == domain.AttemptExpired && *a.Score == 0
+	})).Return(nil)
+	m.repo.On("GetAttemptsByQuizAndStudent", "quiz-1", int64(12)).
+		Return([]domain.QuizAttempt{{ID: 4, Status: domain.AttemptExpired, Score: &expiredScore}}, nil)
+	m.gradebookRepo.On("SaveGradeEntry", mock.MatchedBy(func(e *domain.GradeEntry) bool {
+		return *e.QuizID == "quiz-1" && e.Score == 0 && e.MaxPoints == 10 && e.CategoryID == 3
+	})).Return(int64(20), nil)
+
+	// Test
+	_, err := service.StartAttempt(context.Background(), 12, "quiz-1")
+
+	// Assertions
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	m.repo.AssertExpectations(t)
+	m.gradebookRepo.AssertExpectations(t)
+	m.repo.AssertNotCalled(t, "CreateAttempt", mock.Anything)
+}
+
+func TestQuizService_StartAttempt_StudentNotAssigned(t *testing.T) {
+	// Setup
+	service, m := newTestQuizService(time.Now())
+
+	// Mock expectations
+	m.documents.On("Get", mock.Anything, "quiz-1").Return(quizDocument(t, sampleQuiz()), nil)
+	m.teacherRepo.On("IsStudentAssigned", int64(7), int64(12)).Return(false, nil)
+
+	// Test
+	_, err := service.StartAttempt(context.Background(), 12, "quiz-1")
+
+	// Assertions
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestQuizService_SubmitAttempt_RecordsBestScore(t *testing.T) {
+	// Setup
+	now := time.Date(2024, 5, 10, 9, 10, 0, 0, time.UTC)
+	service, m := newTestQuizService(now)
+
+	quiz := sampleQuiz()
+	quiz.TimeLimitMinutes = 15
+	attempt := &domain.QuizAttempt{
+		ID: 5, QuizID: "quiz-1", StudentID: 12, TeacherID: 7, Number: 2, Status: domain.AttemptInProgress,
+		Deadline: timePtr(now.Add(time.Minute)),
+	}
+	previousBest := 8.0
+	answers := []domain.QuizAnswer{
+		{QuestionID: "mc", Value: float64(1)},
+		{QuestionID: "tf", Value: true},
+	}
+
+	// Mock expectations
+	m.repo.On("GetAttemptByID", int64(5)).Return(attempt, nil)
+	m.documents.On("Get", mock.Anything, "quiz-1").Return(quizDocument(t, quiz), nil)
+	m.repo.On("UpdateAttempt", mock.MatchedBy(func(a *domain.QuizAttempt) bool {
+		return a.Status == domain.AttemptSubmitted && *a.Score == 3 && a.SubmittedAt.Equal(now)
+	})).Return(nil)
+	m.repo.On("GetAttemptsByQuizAndStudent", "quiz-1", int64(12)).Return([]domain.QuizAttempt{
+		{ID: 3, Status: domain.AttemptSubmitted, Score: &previousBest},
+		{ID: 5, Status: domain.AttemptSubmitted, Score: floatPtr(3)},
+	}, nil)
+	m.gradebookRepo.On("SaveGradeEntry", mock.MatchedBy(func(e *domain.GradeEntry) bool {
+		return e.Score == 8 && e.MaxPoints == 10 && e.TeacherID == 7 && e.Title == "Chapter 3 quiz"
+	})).Return(int64(20), nil)
+
+	// Test
+	result, err := service.SubmitAttempt(context.Background(), 12, "quiz-1", 5, answers)
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, *result.Score)
+	assert.Equal(t, 10.0, result.MaxScore)
+	assert.Len(t, result.Results, 5)
+	m.gradebookRepo.AssertExpectations(t)
+}
+
+func TestQuizService_SubmitAttempt_AfterDeadline(t *testing.T) {
+	// Setup
+	now := time.Date(2024, 5, 10, 9, 30, 0, 0, time.UTC)
+	service, m := newTestQuizService(now)
+
+	attempt := &domain.QuizAttempt{
+		ID: 5, QuizID: "quiz-1", StudentID: 12, Status: domain.AttemptInProgress,
+		Deadline: timePtr(now.Add(-time.Minute)),
+	}
+
+	// Mock expectations
+	m.repo.On("GetAttemptByID", int64(5)).Return(attempt, nil)
+	m.documents.On("Get", mock.Anything, "quiz-1").Return(quizDocument(t, sampleQuiz()), nil)
+	m.repo.On("UpdateAttempt", mock.Anything).Return(nil)
+	m.repo.On("GetAttemptsByQuizAndStudent", "quiz-1", int64(12)).Return([]domain.QuizAttempt{}, nil)
+
+	// Test
+	result, err := service.SubmitAttempt(context.Background(), 12, "quiz-1", 5, []domain.QuizAnswer{{QuestionID: "mc", Value: float64(1)}})
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, domain.AttemptExpired, result.Status)
+	assert.Equal(t, 0.0, *result.Score)
+	assert.Empty(t, result.Answers)
+}
+
+func TestQuizService_SubmitAttempt_WithinGracePeriod(t *testing.T) {
+	// Setup
+	now := time.Date(2024, 5, 10, 9, 30, 0, 0, time.UTC)
+	service, m := newTestQuizService(now)
+
+	attempt := &domain.QuizAttempt{
+		ID: 5, QuizID: "quiz-1", StudentID: 12, Status: domain.AttemptInProgress,
+		Deadline: timePtr(now.Add(-10 * time.Second)),
+	}
+
+	// Mock expectations
+	m.repo.On("GetAttemptByID", int64(5)).Return(attempt, nil)
+	m.documents.On("Get", mock.Anything, "quiz-1").Return(quizDocument(t, sampleQuiz()), nil)
+	m.repo.On("UpdateAttempt", mock.Anything).Return(nil)
+	m.repo.On("GetAttemptsByQuizAndStudent", "quiz-1", int64(12)).Return([]domain.QuizAttempt{}, nil)
+
+	// Test
+	result, err := service.SubmitAttempt(context.Background(), 12, "quiz-1", 5, []domain.QuizAnswer{{QuestionID: "mc", Value: float64(1)}})
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, domain.AttemptSubmitted, result.Status)
+	assert.Equal(t, 2.0, *result.Score)
+}
+
+func TestQuizService_SubmitAttempt_DoubleSubmit(t *testing.T) {
+	// Setup
+	now := time.Date(2024, 5, 10, 9, 10, 0, 0, time.UTC)
+	service, m := newTestQuizService(now)
+
+	// 2つの提出がどちらも受験中の記録を読み込んだ後、先の提出が記録を更新した状態
+	attempt := &domain.QuizAttempt{ID: 5, QuizID: "quiz-1", StudentID: 12, TeacherID: 7, Status: domain.AttemptInProgress}
+
+	// Mock expectations
+	m.repo.On("GetAttemptByID", int64(5)).Return(attempt, nil)
+	m.documents.On("Get", mock.Anything, "quiz-1").Return(quizDocument(t, sampleQuiz()), nil)
+	m.repo.On("UpdateAttempt", mock.Anything).Return(fmt.Errorf("%w: quiz attempt 5 is no longer in progress", domain.ErrConflict))
+
+	// Test
+	result, err := service.SubmitAttempt(context.Background(), 12, "quiz-1", 5, []domain.QuizAnswer{{QuestionID: "mc", Value: float64(1)}})
+
+	// Assertions
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.Nil(t, result)
+	m.repo.AssertNotCalled(t, "GetAttemptsByQuizAndStudent", mock.Anything, mock.Anything)
+	m.gradebookRepo.AssertNotCalled(t, "SaveGradeEntry", mock.Anything)
+}
+
+func TestQuizService_StartAttempt_StaleAttemptSubmittedConcurrently(t *testing.T) {
+	// Setup
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	service, m := newTestQuizService(now)
+
+	quiz := sampleQuiz()
+	quiz.TimeLimitMinutes = 15
+	stale := domain.QuizAttempt{
+		ID: 4, QuizID: "quiz-1", StudentID: 12, Number: 1, Status: domain.AttemptInProgress,
+		Deadline: timePtr(now.Add(-time.Hour)),
+	}
+
+	// Mock expectations
+	m.documents.On("Get", mock.Anything, "quiz-1").Return(quizDocument(t, quiz), nil)
+	m.teacherRepo.On("IsStudentAssigned", int64(7), int64(12)).Return(true, nil)
+	m.repo.On("GetAttemptsByQuizAndStudent", "quiz-1", int64(12)).Return([]domain.QuizAttempt{stale}, nil)
+	m.repo.On("UpdateAttempt", mock.Anything).Return(domain.ErrConflict)
+	m.repo.On("CreateAttempt", mock.MatchedBy(func(a *domain.QuizAttempt) bool {
+		return a.Number == 2
+	})).Return(int64(6), nil)
+
+	// Test
+	session, err := service.StartAttempt(context.Background(), 12, "quiz-1")
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), session.Attempt.ID)
+	m.gradebookRepo.AssertNotCalled(t, "SaveGradeEntry", mock.Anything)
+}
+
+func TestQuizService_SubmitAttempt_Rejections(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("another student's attempt", func(t *testing.T) {
+		service, m := newTestQuizService(time.Now())
+		m.repo.On("GetAttemptByID", int64(5)).Return(&domain.QuizAttempt{ID: 5, QuizID: "quiz-1", StudentID: 13, Status: domain.AttemptInProgress}, nil)
+
+		_, err := service.SubmitAttempt(ctx, 12, "quiz-1", 5, nil)
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+	})
+
+	t.Run("already submitted", func(t *testing.T) {
+		service, m := newTestQuizService(time.Now())
+		m.repo.On("GetAttemptByID", int64(5)).Return(&domain.QuizAttempt{ID: 5, QuizID: "quiz-1", StudentID: 12, Status: domain.AttemptSubmitted}, nil)
+
+		_, err := service.SubmitAttempt(ctx, 12, "quiz-1", 5, nil)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("attempt of another quiz", func(t *testing.T) {
+		service, m := newTestQuizService(time.Now())
+		m.repo.On("GetAttemptByID", int64(5)).Return(&domain.QuizAttempt{ID: 5, QuizID: "quiz-2", StudentID: 12, Status: domain.AttemptInProgress}, nil)
+
+		_, err := service.SubmitAttempt(ctx, 12, "quiz-1", 5, nil)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("unknown question", func(t *testing.T) {
+		service, m := newTestQuizService(time.Now())
+		m.repo.On("GetAttemptByID", int64(5)).Return(&domain.QuizAttempt{ID: 5, QuizID: "quiz-1", StudentID: 12, Status: domain.AttemptInProgress}, nil)
+		m.documents.On("Get", mock.Anything, "quiz-1").Return(quizDocument(t, sampleQuiz()), nil)
+
+		_, err := service.SubmitAttempt(ctx, 12, "quiz-1", 5, []domain.QuizAnswer{{QuestionID: "nope", Value: true}})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		m.repo.AssertNotCalled(t, "UpdateAttempt", mock.Anything)
+	})
+}
+
+func TestQuizService_ListQuizzesForStudent_SkipsFreeFormDocuments(t *testing.T) {
+	// Setup
+	service, m := newTestQuizService(time.Now())
+
+	other := sampleQuiz()
+	other.ID = "quiz-2"
+	other.TeacherID = 9
+
+	// Mock expectations
+	m.teacherRepo.On("GetTeachersByStudentID", int64(12)).Return([]domain.Teacher{{ID: 7}}, nil)
+	m.documents.On("List", mock.Anything, "test").Return([]domain.Document{
+		*quizDocument(t, sampleQuiz()),
+		*quizDocument(t, other),
+		{ID: "legacy", Type: "test", Data: map[string]interface{}{"notes": "free-form test"}},
+	}, nil)
+
+	// Test
+	quizzes, err := service.ListQuizzesForStudent(context.Background(), 12)
+
+	// Assertions
+	require.NoError(t, err)
+	require.Len(t, quizzes, 1)
+	assert.Equal(t, "quiz-1", quizzes[0].ID)
+	assert.Equal(t, 5, quizzes[0].QuestionCount)
+	assert.Equal(t, 10.0, quizzes[0].TotalPoints)
+}
+
+func TestQuizService_GetQuiz_NotOwner(t *testing.T) {
+	service, m := newTestQuizService(time.Now())
+	m.documents.On("Get", mock.Anything, "quiz-1").Return(quizDocument(t, sampleQuiz()), nil)
+
+	_, err := service.GetQuiz(context.Background(), 8, "quiz-1")
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestQuizService_GetQuiz_OtherDocumentType(t *testing.T) {
+	service, m := newTestQuizService(time.Now())
+	m.documents.On("Get", mock.Anything, "doc-1").Return(&domain.Document{ID: "doc-1", Type: "material"}, nil)
+
+	_, err := service.GetQuiz(context.Background(), 7, "doc-1")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
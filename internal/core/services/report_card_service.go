@@ -0,0 +1,324 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+)
+
+// ファイル名に使用できない文字
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// 通知表サービス構造体：通知表の作成とPDF生成に関する業務ロジックを実装
+type ReportCardService struct {
+	// 通知表リポジトリインターフェース
+	repo ports.ReportCardRepository
+	// 学生リポジトリインターフェース（学生の氏名とメールアドレスの取得に使用）
+	studentRepo ports.StudentRepository
+	// 教師リポジトリインターフェース（担当関係と教師名の取得に使用）
+	teacherRepo ports.TeacherRepository
+	// 成績簿サービスインターフェース（計算済み成績の取得に使用）
+	gradebook ports.GradebookService
+	// ファイルストレージインターフェース（生成したPDFの保存に使用）
+	fileStorage ports.FileStorage
+	// 通知表レンダラーインターフェース
+	renderer ports.ReportCardRenderer
+	// 現在時刻を返す関数（テストで差し替え可能）
+	now func() time.Time
+}
+
+// 新しい通知表サービスインスタンスを作成する
+func NewReportCardService(
+	repo ports.ReportCardRepository,
+	studentRepo ports.StudentRepository,
+	teacherRepo ports.TeacherRepository,
+	gradebook ports.GradebookService,
+	fileStorage ports.FileStorage,
+	renderer ports.ReportCardRenderer,
+) *ReportCardService {
+	return &ReportCardService{
+		repo:        repo,
+		studentRepo: studentRepo,
+		teacherRepo: teacherRepo,
+		gradebook:   gradebook,
+		fileStorage: fileStorage,
+		renderer:    renderer,
+		now:         time.Now,
+	}
+}
+
+// 教師が学生の所見（コメントと出欠）を記入する
+func (s *ReportCardService) SaveRemark(ctx context.Context, teacherID int64, input *domain.ReportCardRemarkInput) (*domain.ReportCardRemark, error) {
+	term := strings.TrimSpace(input.Term)
+	if term == "" {
+		return nil, fmt.Errorf("%w: term is required", domain.ErrInvalidInput)
+	}
+	if input.DaysPresent < 0 || input.DaysAbsent < 0 || input.DaysTardy < 0 {
+		return nil, fmt.Errorf("%w: attendance days must not be negative", domain.ErrInvalidInput)
+	}
+	if input.DaysTardy > input.DaysPresent {
+		return nil, fmt.Errorf("%w: days_tardy must not exceed days_present", domain.ErrInvalidInput)
+	}
+	if err := s.ensureAssigned(teacherID, input.StudentID); err != nil {
+		return nil, err
+	}
+
+	remark := &domain.ReportCardRemark{
+		TeacherID:   teacherID,
+		StudentID:   input.StudentID,
+		Term:        term,
+		Comment:     strings.TrimSpace(input.Comment),
+		DaysPresent: input.DaysPresent,
+		DaysAbsent:  input.DaysAbsent,
+		DaysTardy:   input.DaysTardy,
+		UpdatedAt:   s.now(),
+	}
+	id, err := s.repo.SaveRemark(remark)
+	if err != nil {
+		return nil, err
+	}
+	remark.ID = id
+
+	return remark, nil
+}
+
+// 学生1人分の通知表PDFを生成して保存する
+// 同じ学期の通知表が既にある場合は新しいPDFで置き換える
+func (s *ReportCardService) Generate(ctx context.Context, teacherID, studentID int64, term string) (*domain.ReportCardFile, error) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return nil, fmt.Errorf("%w: term is required", domain.ErrInvalidInput)
+	}
+	if err := s.ensureAssigned(teacherID, studentID); err != nil {
+		return nil, err
+	}
+
+	return s.generate(ctx, teacherID, studentID, term)
+}
+
+// 教師の担当学生全員分の通知表PDFを生成して保存する
+// 一部の学生で生成に失敗しても処理を続け、失敗した学生は結果に含めて返す
+func (s *ReportCardService) GenerateForClass(ctx context.Context, teacherID int64, term string) (*domain.ReportCardBatch, error) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return nil, fmt.Errorf("%w: term is required", domain.ErrInvalidInput)
+	}
+
+	students, err := s.teacherRepo.GetStudentsByTeacherID(teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &domain.ReportCardBatch{
+		Term:      term,
+		Generated: make([]domain.ReportCardFile, 0, len(students)),
+		Failed:    []domain.ReportCardFailure{},
+	}
+	for _, student := range students {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		card, err := s.generate(ctx, teacherID, student.ID, term)
+		if err != nil {
+			batch.Failed = append(batch.Failed, domain.ReportCardFailure{StudentID: student.ID, Error: err.Error()})
+			continue
+		}
+		batch.Generated = append(batch.Generated, *card)
+	}
+
+	return batch, nil
+}
+
+// 教師の担当学生の生成済み通知表一覧を取得する
+func (s *ReportCardService) ListByTeacher(ctx context.Context, teacherID int64, term string) ([]domain.ReportCardFile, error) {
+	return s.repo.GetReportCardFilesByTeacherID(teacherID, strings.TrimSpace(term))
+}
+
+// 学生自身の生成済み通知表一覧を取得する
+func (s *ReportCardService) ListForStudent(ctx context.Context, studentID int64) ([]domain.ReportCardFile, error) {
+	return s.repo.GetReportCardFilesByStudentID(studentID)
+}
+
+// 教師が担当学生の通知表PDFをダウンロードする
+func (s *ReportCardService) DownloadForTeacher(ctx context.Context, teacherID, reportCardID int64) (*domain.File, io.ReadCloser, error) {
+	card, err := s.repo.GetReportCardFileByID(reportCardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.ensureAssigned(teacherID, card.StudentID); err != nil {
+		return nil, nil, err
+	}
+
+	return s.fileStorage.Download(ctx, card.FileID)
+}
+
+// 学生が自身の通知表PDFをダウンロードする
+func (s *ReportCardService) DownloadForStudent(ctx context.Context, studentID, reportCardID int64) (*domain.File, io.ReadCloser, error) {
+	card, err := s.repo.GetReportCardFileByID(reportCardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if card.StudentID != studentID {
+		return nil, nil, fmt.Errorf("%w: report card %d does not belong to student %d", domain.ErrForbidden, reportCardID, studentID)
+	}
+
+	return s.fileStorage.Download(ctx, card.FileID)
+}
+
+// 通知表を組み立ててPDFを生成し、ファイルストレージに保存する
+func (s *ReportCardService) generate(ctx context.Context, teacherID, studentID int64, term string) (*domain.ReportCardFile, error) {
+	card, err := s.buildReportCard(ctx, studentID, term)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.renderer.Render(card)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render report card: %w", err)
+	}
+
+	// 置き換え対象の既存の通知表を確認
+	previous, err := s.repo.GetReportCardFile(studentID, term)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	file, err := s.fileStorage.Upload(ctx, &domain.FileUpload{
+		Name:         fmt.Sprintf("report-card-%s-%d.pdf", unsafeFileNameChars.ReplaceAllString(term, "_"), studentID),
+		ContentType:  "application/pdf",
+		Body:         bytes.NewReader(data),
+		Size:         int64(len(data)),
+		UploaderID:   teacherID,
+		UploaderRole: domain.RoleTeacher,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	record := &domain.ReportCardFile{
+		StudentID:   studentID,
+		Term:        term,
+		FileID:      file.ID,
+		GeneratedBy: teacherID,
+		GeneratedAt: card.GeneratedAt,
+	}
+	id, err := s.repo.SaveReportCardFile(record)
+	if err != nil {
+		// 記録できなかったPDFは参照されないため削除する
+		_ = s.fileStorage.Delete(ctx, file.ID)
+		return nil, err
+	}
+	record.ID = id
+
+	// 古いPDFを削除（失敗しても新しい通知表は有効なため無視する）
+	if previous != nil && previous.FileID != file.ID {
+		_ = s.fileStorage.Delete(ctx, previous.FileID)
+	}
+
+	return record, nil
+}
+
+// 学生の情報、全科目の成績、所見から通知表を組み立てる
+func (s *ReportCardService) buildReportCard(ctx context.Context, studentID int64, term string) (*domain.ReportCard, error) {
+	student, err := s.studentRepo.GetStudentByID(studentID)
+	if err != nil {
+		return nil, err
+	}
+	teachers, err := s.teacherRepo.GetTeachersByStudentID(studentID)
+	if err != nil {
+		return nil, err
+	}
+	grades, err := s.gradebook.GetStudentGrades(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	remarks, err := s.repo.GetRemarksByStudentID(studentID, term)
+	if err != nil {
+		return nil, err
+	}
+
+	gradesByTeacher := make(map[int64]domain.StudentGrade, len(grades))
+	for _, grade := range grades {
+		gradesByTeacher[grade.TeacherID] = grade
+	}
+	remarksByTeacher := make(map[int64]domain.ReportCardRemark, len(remarks))
+	for _, remark := range remarks {
+		remarksByTeacher[remark.TeacherID] = remark
+	}
+
+	card := &domain.ReportCard{
+		StudentID:    student.ID,
+		StudentName:  student.Name,
+		StudentEmail: student.Email,
+		Term:         term,
+		Subjects:     make([]domain.ReportCardSubject, 0, len(teachers)),
+		GeneratedAt:  s.now(),
+	}
+
+	var total domain.AttendanceSummary
+	hasAttendance := false
+	for _, teacher := range teachers {
+		subject := domain.ReportCardSubject{
+			TeacherID:   teacher.ID,
+			TeacherName: teacher.Name,
+			Subject:     teacher.Subject,
+		}
+		if grade, ok := gradesByTeacher[teacher.ID]; ok {
+			subject.Percent = grade.Percent
+			subject.Letter = grade.Letter
+		}
+		if remark, ok := remarksByTeacher[teacher.ID]; ok {
+			attendance := attendanceSummary(remark.DaysPresent, remark.DaysAbsent, remark.DaysTardy)
+			subject.Attendance = &attendance
+			subject.Comment = remark.Comment
+
+			total.DaysPresent += remark.DaysPresent
+			total.DaysAbsent += remark.DaysAbsent
+			total.DaysTardy += remark.DaysTardy
+			hasAttendance = true
+		}
+		card.Subjects = append(card.Subjects, subject)
+	}
+	if hasAttendance {
+		total = attendanceSummary(total.DaysPresent, total.DaysAbsent, total.DaysTardy)
+	}
+	card.Attendance = total
+
+	return card, nil
+}
+
+// 学生が教師の担当であることを確認する
+func (s *ReportCardService) ensureAssigned(teacherID, studentID int64) error {
+	assigned, err := s.teacherRepo.IsStudentAssigned(teacherID, studentID)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		return fmt.Errorf("%w: student %d is not assigned to teacher %d", domain.ErrForbidden, studentID, teacherID)
+	}
+	return nil
+}
+
+// 出欠日数から出欠集計を作成する
+// 出席率は出席日数（遅刻を含む）を出席日数と欠席日数の合計で割ったもの
+func attendanceSummary(present, absent, tardy int) domain.AttendanceSummary {
+	summary := domain.AttendanceSummary{
+		DaysPresent: present,
+		DaysAbsent:  absent,
+		DaysTardy:   tardy,
+	}
+	if days := present + absent; days > 0 {
+		rate := roundPercent(float64(present) / float64(days) * 100)
+		summary.Rate = &rate
+	}
+	return summary
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type reportCardMocks struct {
+	repo        *mocks.ReportCardRepository
+	studentRepo *mocks.StudentRepository
+	teacherRepo *mocks.TeacherRepository
+	gradebook   *mocks.GradebookService
+	fileStorage *mocks.FileStorage
+	renderer    *mocks.ReportCardRenderer
+}
+
+func newTestReportCardService(now time.Time) (*ReportCardService, *reportCardMocks) {
+	m := &reportCardMocks{
+		repo:        new(mocks.ReportCardRepository),
+		studentRepo: new(mocks.StudentRepository),
+		teacherRepo: new(mocks.TeacherRepository),
+		gradebook:   new(mocks.GradebookService),
+		fileStorage: new(mocks.FileStorage),
+		renderer:    new(mocks.ReportCardRenderer),
+	}
+	service := NewReportCardService(m.repo, m.studentRepo, m.teacherRepo, m.gradebook, m.fileStorage, m.renderer)
+	service.now = func() time.Time { return now }
+	return service, m
+}
+
+// 学生12の通知表の組み立てに必要なモックを設定する
+func expectReportCardData(m *reportCardMocks, term string) {
+	math := 91.5
+	m.studentRepo.On("GetStudentByID", int64(12)).Return(&domain.Student{ID: 12, Name: "Ann", Email: "ann@example.com"}, nil)
+	m.teacherRepo.On("GetTeachersByStudentID", int64(12)).Return([]domain.Teacher{
+		{ID: 7, Name: "Jane", Subject: "Math"},
+		{ID: 8, Name: "Tom", Subject: "History"},
+	}, nil)
+	m.gradebook.On("GetStudentGrades", mock.Anything, int64(12)).Return([]domain.StudentGrade{
+		{TeacherID: 7, Percent: &math, Letter: "A"},
+		{TeacherID: 8},
+	}, nil)
+	m.repo.On("GetRemarksByStudentID", int64(12), term).Return([]domain.ReportCardRemark{
+		{TeacherID: 7, Comment: "Great work", DaysPresent: 28, DaysAbsent: 2, DaysTardy: 1},
+		{TeacherID: 8, DaysPresent: 29, DaysAbsent: 1},
+	}, nil)
+}
+
+func TestReportCardService_Generate(t *testing.T) {
+	// Setup
+	now := time.Date(2024, 6, 28, 9, 0, 0, 0, time.UTC)
+	service, m := newTestReportCardService(now)
+	ctx := context.Background()
+
+	// Mock expectations
+	m.teacherRepo.On("IsStudentAssigned", int64(7), int64(12)).Return(true, nil)
+	expectReportCardData(m, "2024-spring")
+
+	var rendered *domain.ReportCard
+	m.renderer.On("Render", mock.Anything).Run(func(args mock.Arguments) {
+		rendered = args.Get(0).(*domain.ReportCard)
+	}).Return([]byte("%PDF"), nil)
+	m.repo.On("GetReportCardFile", int64(12), "2024-spring").
+		Return(&domain.ReportCardFile{ID: 3, FileID: "old-file"}, nil)
+	m.fileStorage.On("Upload", mock.Anything, mock.MatchedBy(func(f *domain.FileUpload) bool {
+		return f.Name == "report-card-2024-spring-12.pdf" && f.ContentType == "application/pdf" && f.Size == int64(len("%PDF"))
+	})).Return(&domain.File{ID: "new-file"}, nil)
+	m.repo.On("SaveReportCardFile", mock.MatchedBy(func(c *domain.ReportCardFile) bool {
+		return c.StudentID == 12 && c.FileID == "new-file" && c.GeneratedBy == 7 && c.GeneratedAt.Equal(now)
+	})).Return(int64(3), nil)
+	m.fileStorage.On("Delete", mock.Anything, "old-file").Return(nil)
+
+	// Test
+	result, err := service.Generate(ctx, 7, 12, " 2024-spring ")
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.ID)
+	assert.Equal(t, "new-file", result.FileID)
+
+	require.NotNil(t, rendered)
+	assert.Equal(t, "Ann", rendered.StudentName)
+	assert.Equal(t, "2024-spring", rendered.Term)
+	require.Len(t, rendered.Subjects, 2)
+	assert.Equal(t, "Math", rendered.Subjects[0].Subject)
+	assert.Equal(t, "Jane", rendered.Subjects[0].TeacherName)
+	assert.Equal(t, "A", rendered.Subjects[0].Letter)
+	assert.Equal(t, "Great work", rendered.Subjects[0].Comment)
+	assert.InDelta(t, 93.33, *rendered.Subjects[0].Attendance.Rate, 1e-9)
+	assert.Nil(t, rendered.Subjects[1].Percent)
+	assert.Equal(t, 57, rendered.Attendance.DaysPresent)
+	assert.Equal(t, 3, rendered.Attendance.DaysAbsent)
+	assert.Equal(t, 1, rendered.Attendance.DaysTardy)
+	assert.InDelta(t, 95, *rendered.Attendance.Rate, 1e-9)
+
+	m.repo.AssertExpectations(t)
+	m.fileStorage.AssertExpectations(t)
+}
+
+func TestReportCardService_Generate_NotAssigned(t *testing.T) {
+	// Setup
+	service, m := newTestReportCardService(time.Now())
+	ctx := context.Background()
+
+	// Mock expectations
+	m.teacherRepo.On("IsStudentAssigned", int64(7), int64(12)).Return(false, nil)
+
+	// Test
+	_, err := service.Generate(ctx, 7, 12, "2024-spring")
+
+	// Assertions
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	m.renderer.AssertNotCalled(t, "Render", mock.Anything)
+	m.fileStorage.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything)
+}
+
+func TestReportCardService_Generate_SaveFailureRemovesUpload(t *testing.T) {
+	// Setup
+	service, m := newTestReportCardService(time.Now())
+	ctx := context.Background()
+
+	// Mock expectations
+	m.teacherRepo.On("IsStudentAssigned", int64(7), int64(12)).Return(true, nil)
+	expectReportCardData(m, "2024-spring")
+	m.renderer.On("Render", mock.Anything).Return([]byte("%PDF"), nil)
+	m.repo.On("GetReportCardFile", int64(12), "2024-spring").
+		Return(nil, fmt.Errorf("%w: none", domain.ErrNotFound))
+	m.fileStorage.On("Upload", mock.Anything, mock.Anything).Return(&domain.File{ID: "new-file"}, nil)
+	m.repo.On("SaveReportCardFile", mock.Anything).Return(int64(0), errors.New("db down"))
+	m.fileStorage.On("Delete", mock.Anything, "new-file").Return(nil)
+
+	// Test
+	_, err := service.Generate(ctx, 7, 12, "2024-spring")
+
+	// Assertions
+	assert.Error(t, err)
+	m.fileStorage.AssertExpectations(t)
+}
+
+func TestReportCardService_GenerateForClass_ContinuesAfterFailure(t *testing.T) {
+	// Setup
+	service, m := newTestReportCardService(time.Now())
+	ctx := context.Background()
+
+	// Mock expectations
+	m.teacherRepo.On("GetStudentsByTeacherID", int64(7)).
+		Return([]domain.Student{{ID: 12, Name: "Ann"}, {ID: 13, Name: "Ben"}}, nil)
+	m.studentRepo.On("GetStudentByID", int64(13)).Return(nil, fmt.Errorf("%w: student 13", domain.ErrNotFound))
+	expectReportCardData(m, "2024-spring")
+	m.renderer.On("Render", mock.Anything).Return([]byte("%PDF"), nil)
+	m.repo.On("GetReportCardFile", int64(12), "2024-spring").
+		Return(nil, fmt.Errorf("%w: none", domain.ErrNotFound))
+	m.fileStorage.On("Upload", mock.Anything, mock.Anything).Return(&domain.File{ID: "file-12"}, nil)
+	m.repo.On("SaveReportCardFile", mock.Anything).Return(int64(4), nil)
+
+	// Test
+	batch, err := service.GenerateForClass(ctx, 7, "2024-spring")
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, "2024-spring", batch.Term)
+	require.Len(t, batch.Generated, 1)
+	assert.Equal(t, int64(12), batch.Generated[0].StudentID)
+	require.Len(t, batch.Failed, 1)
+	assert.Equal(t, int64(13), batch.Failed[0].StudentID)
+	m.fileStorage.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestReportCardService_GenerateForClass_TermRequired(t *testing.T) {
+	service, _ := newTestReportCardService(time.Now())
+
+	_, err := service.GenerateForClass(context.Background(), 7, "  ")
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestReportCardService_SaveRemark(t *testing.T) {
+	// Setup
+	now := time.Date(2024, 6, 20, 9, 0, 0, 0, time.UTC)
+	service, m := newTestReportCardService(now)
+	ctx := context.Background()
+
+	// Mock expectations
+	m.teacherRepo.On("IsStudentAssigned", int64(7), int64(12)).Return(true, nil)
+	m.repo.On("SaveRemark", mock.MatchedBy(func(r *domain.ReportCardRemark) bool {
+		return r.TeacherID == 7 && r.Term == "2024-spring" && r.Comment == "Well done" && r.UpdatedAt.Equal(now)
+	})).Return(int64(9), nil)
+
+	// Test
+	remark, err := service.SaveRemark(ctx, 7, &domain.ReportCardRemarkInput{
+		StudentID:   12,
+		Term:        "2024-spring",
+		Comment:     " Well done ",
+		DaysPresent: 30,
+		DaysTardy:   2,
+	})
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), remark.ID)
+	m.repo.AssertExpectations(t)
+}
+
+func TestReportCardService_SaveRemark_TardyExceedsPresent(t *testing.T) {
+	service, m := newTestReportCardService(time.Now())
+
+	_, err := service.SaveRemark(context.Background(), 7, &domain.ReportCardRemarkInput{
+		StudentID:   12,
+		Term:        "2024-spring",
+		DaysPresent: 1,
+		DaysTardy:   2,
+	})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	m.repo.AssertNotCalled(t, "SaveRemark", mock.Anything)
+}
+
+func TestReportCardService_DownloadForStudent(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("own report card", func(t *testing.T) {
+		service, m := newTestReportCardService(time.Now())
+		m.repo.On("GetReportCardFileByID", int64(3)).Return(&domain.ReportCardFile{ID: 3, StudentID: 12, FileID: "file-1"}, nil)
+		m.fileStorage.On("Download", mock.Anything, "file-1").Return(&domain.File{ID: "file-1"}, io.NopCloser(strings.NewReader("%PDF")), nil)
+
+		_, body, err := service.DownloadForStudent(ctx, 12, 3)
+
+		require.NoError(t, err)
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, "%PDF", string(data))
+	})
+
+	t.Run("another student's report card", func(t *testing.T) {
+		service, m := newTestReportCardService(time.Now())
+		m.repo.On("GetReportCardFileByID", int64(3)).Return(&domain.ReportCardFile{ID: 3, StudentID: 13, FileID: "file-1"}, nil)
+
+		_, _, err := service.DownloadForStudent(ctx, 12, 3)
+
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+		m.fileStorage.AssertNotCalled(t, "Download", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAttendanceSummary(t *testing.T) {
+	summary := attendanceSummary(0, 0, 0)
+	assert.Nil(t, summary.Rate)
+
+	summary = attendanceSummary(2, 1, 0)
+	require.NotNil(t, summary.Rate)
+	assert.Equal(t, 66.67, *summary.Rate)
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+)
+
+// 使用量レポートで取得するユーザー数の上限
+const maxTopConsumers = 100
+
+// ストレージ使用量サービス構造体：アップロードしたユーザーごとの保存容量の使用状況の取得を実装
+type StorageUsageService struct {
+	// 使用量を記録するファイルカタログ
+	catalog ports.FileRepository
+	// 役割ごとのクォータを提供するアップロードポリシー
+	policy ports.UploadPolicyService
+}
+
+// 新しいストレージ使用量サービスインスタンスを作成する
+func NewStorageUsageService(catalog ports.FileRepository, policy ports.UploadPolicyService) *StorageUsageService {
+	return &StorageUsageService{
+		catalog: catalog,
+		policy:  policy,
+	}
+}
+
+// ユーザーのストレージ使用量とクォータを取得する
+func (s *StorageUsageService) GetUsage(ctx context.Context, uploaderRole string, uploaderID int64) (*domain.StorageUsage, error) {
+	usage, err := s.catalog.GetStorageUsage(uploaderRole, uploaderID)
+	if err != nil {
+		return nil, err
+	}
+	usage.Quota = s.policy.Quota(uploaderRole)
+	return usage, nil
+}
+
+// ストレージ使用量の多いユーザーを使用量の多い順に取得する
+func (s *StorageUsageService) TopConsumers(ctx context.Context, limit int) ([]domain.StorageUsage, error) {
+	if limit <= 0 || limit > maxTopConsumers {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxTopConsumers)
+	}
+
+	usages, err := s.catalog.ListTopStorageUsage(limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range usages {
+		usages[i].Quota = s.policy.Quota(usages[i].UploaderRole)
+	}
+	return usages, nil
+}
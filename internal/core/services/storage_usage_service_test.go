@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStorageUsageService() (*StorageUsageService, *mocks.FileRepository) {
+	catalog := new(mocks.FileRepository)
+	return NewStorageUsageService(catalog, newTestUploadPolicyService()), catalog
+}
+
+func TestStorageUsageService_GetUsage(t *testing.T) {
+	// Setup
+	service, catalog := newTestStorageUsageService()
+
+	// Mock expectations
+	catalog.On("GetStorageUsage", domain.RoleStudent, int64(12)).
+		Return(&domain.StorageUsage{UploaderID: 12, UploaderRole: domain.RoleStudent, Bytes: 40, Files: 2}, nil)
+
+	// Test
+	usage, err := service.GetUsage(context.Background(), domain.RoleStudent, 12)
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, int64(40), usage.Bytes)
+	assert.Equal(t, domain.StorageQuota{MaxBytes: 64, MaxFiles: 3}, usage.Quota)
+}
+
+func TestStorageUsageService_TopConsumers(t *testing.T) {
+	t.Run("quota is filled in per role", func(t *testing.T) {
+		// Setup
+		service, catalog := newTestStorageUsageService()
+
+		// Mock expectations
+		catalog.On("ListTopStorageUsage", 2).Return([]domain.StorageUsage{
+			{UploaderID: 7, UploaderRole: domain.RoleTeacher, Bytes: 900, Files: 4},
+			{UploaderID: 12, UploaderRole: domain.RoleStudent, Bytes: 60, Files: 3},
+		}, nil)
+
+		// Test
+		usages, err := service.TopConsumers(context.Background(), 2)
+
+		// Assertions
+		require.NoError(t, err)
+		require.Len(t, usages, 2)
+		assert.Equal(t, domain.StorageQuota{}, usages[0].Quota)
+		assert.Equal(t, int64(64), usages[1].Quota.MaxBytes)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		service, catalog := newTestStorageUsageService()
+
+		_, err := service.TopConsumers(context.Background(), 0)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		catalog.AssertNotCalled(t, "ListTopStorageUsage")
+	})
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+)
+
+// サムネイルの作成を待つファイルの最大数（超えた分は取得時に改めて予約する）
+const thumbnailQueueSize = 256
+
+// サムネイルサービス構造体：画像ファイルのサムネイルの非同期の作成と取得を実装
+// サムネイルは内容のチェックサムとサイズごとに派生オブジェクトとしてストレージに保存する
+type ThumbnailService struct {
+	// ファイルカタログ
+	catalog ports.FileRepository
+	// 元の内容の読み込みとサムネイルの保存に使用するストレージ（IDはオブジェクトキー）
+	objects ports.FileStorage
+	// サムネイルリポジトリインターフェース
+	repo ports.ThumbnailRepository
+	// サムネイルレンダラーインターフェース
+	renderer ports.ThumbnailRenderer
+	// 作成するサムネイルのサイズ（長辺のピクセル数、小さい順）
+	sizes []int
+	// サムネイルを作成する画像の最大サイズ（バイト、0の場合は無制限）
+	maxSourceSize int64
+	// サムネイルの作成を待つファイル
+	queue chan domain.File
+	// 作成を予約済みのチェックサムへのアクセスを保護するロック
+	mu sync.Mutex
+	// 作成を予約済みのチェックサム（同じ内容の重複した作成を防ぐ）
+	pending map[string]bool
+	// 現在時刻を返す関数（テストで差し替え可能）
+	now func() time.Time
+}
+
+// 新しいサムネイルサービスインスタンスを作成する
+// サイズが指定されていない場合、サムネイルは作成しない
+func NewThumbnailService(catalog ports.FileRepository, objects ports.FileStorage, repo ports.ThumbnailRepository, renderer ports.ThumbnailRenderer, sizes []int, maxSourceSize int64) *ThumbnailService {
+	normalized := make([]int, 0, len(sizes))
+	for _, size := range sizes {
+		if size > 0 {
+			normalized = append(normalized, size)
+		}
+	}
+	slices.Sort(normalized)
+
+	return &ThumbnailService{
+		catalog:       catalog,
+		objects:       objects,
+		repo:          repo,
+		renderer:      renderer,
+		sizes:         slices.Compact(normalized),
+		maxSourceSize: maxSourceSize,
+		queue:         make(chan domain.File, thumbnailQueueSize),
+		pending:       make(map[string]bool),
+		now:           time.Now,
+	}
+}
+
+// ファイルのサムネイルの作成を予約する
+// 画像でないファイル、大きすぎるファイル、同じ内容の作成を予約済みのファイルは無視する
+// 待ちが上限に達している場合は予約せず、サムネイルの取得時に改めて予約する
+func (s *ThumbnailService) Enqueue(file *domain.File) {
+	if !s.eligible(file) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending[file.Checksum] {
+		return
+	}
+
+	select {
+	case s.queue <- *file:
+		s.pending[file.Checksum] = true
+	default:
+		slog.Warn("thumbnail queue is full", slog.String("file_id", file.ID))
+	}
+}
+
+// 指定された数のワーカーで予約されたサムネイルを作成する
+// コンテキストが終了するまで処理を続けるため、ゴルーチンで実行する
+func (s *ThumbnailService) Run(ctx context.Context, workers int) {
+	var wg sync.WaitGroup
+	for i := 0; i < max(workers, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case file := <-s.queue:
+					if err := s.generate(ctx, &file); err != nil {
+						slog.Error("failed to generate thumbnails", slog.String("file_id", file.ID), slog.String("error", err.Error()))
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// ファイルの指定されたサイズのサムネイルを取得し、サムネイルの情報と内容を読み込むストリームを返す
+// サムネイルがまだ作成されていない場合は作成を予約し、ErrNotFoundを返す
+func (s *ThumbnailService) Open(ctx context.Context, fileID string, size int) (*domain.Thumbnail, io.ReadCloser, error) {
+	if len(s.sizes) == 0 {
+		return nil, nil, fmt.Errorf("%w: thumbnails are disabled", domain.ErrNotSupported)
+	}
+	if size == 0 {
+		size = s.sizes[0]
+	}
+	if !slices.Contains(s.sizes, size) {
+		return nil, nil, fmt.Errorf("%w: thumbnail size must be one of %v", domain.ErrInvalidInput, s.sizes)
+	}
+
+	file, err := s.catalog.GetFileByID(fileID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !s.eligible(file) {
+		return nil, nil, fmt.Errorf("%w: file %s has no thumbnail", domain.ErrNotFound, fileID)
+	}
+
+	thumbnails, err := s.repo.GetThumbnailsByChecksum(file.Checksum)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, thumbnail := range thumbnails {
+		if thumbnail.Size != size {
+			continue
+		}
+		_, body, err := s.objects.Download(ctx, thumbnail.ObjectKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &thumbnail, body, nil
+	}
+
+	s.Enqueue(file)
+	return nil, nil, fmt.Errorf("%w: thumbnail of file %s is not ready yet", domain.ErrNotFound, fileID)
+}
+
+// ファイルのまだ作成されていないサイズのサムネイルを作成し、保存する
+func (s *ThumbnailService) generate(ctx context.Context, file *domain.File) error {
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, file.Checksum)
+		s.mu.Unlock()
+	}()
+
+	existing, err := s.repo.GetThumbnailsByChecksum(file.Checksum)
+	if err != nil {
+		return err
+	}
+	var missing []int
+	for _, size := range s.sizes {
+		if !slices.ContainsFunc(existing, func(t domain.Thumbnail) bool { return t.Size == size }) {
+			missing = append(missing, size)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	// 予約後に新しいバージョンが追加された場合は、新しいバージョンの予約に任せる
+	record, err := s.catalog.GetFileByID(file.ID)
+	if err != nil {
+		return err
+	}
+	if record.Checksum != file.Checksum || record.CheckServable() != nil {
+		return nil
+	}
+	_, body, err := s.objects.Download(ctx, record.ObjectKey)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	rendered, err := s.renderer.Render(body, missing)
+	if err != nil {
+		return err
+	}
+	for _, thumbnail := range rendered {
+		if err := s.store(ctx, file.Checksum, thumbnail); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 描画したサムネイルをストレージに保存し、リポジトリに登録する
+// 登録できなかった場合（同時に作成された場合や元の内容が削除された場合）は保存したオブジェクトを削除する
+func (s *ThumbnailService) store(ctx context.Context, checksum string, rendered domain.RenderedThumbnail) error {
+	stored, err := s.objects.Upload(ctx, &domain.FileUpload{
+		Name:        fmt.Sprintf("thumbnail-%d.jpg", rendered.Size),
+		Body:        bytes.NewReader(rendered.Data),
+		Size:        int64(len(rendered.Data)),
+		ContentType: rendered.ContentType,
+	})
+	if err != nil {
+		return err
+	}
+
+	saved, err := s.repo.SaveThumbnail(&domain.Thumbnail{
+		Checksum:    checksum,
+		Size:        rendered.Size,
+		ObjectKey:   stored.ID,
+		ContentType: rendered.ContentType,
+		ByteSize:    int64(len(rendered.Data)),
+		CreatedAt:   s.now().UTC(),
+	})
+	if err != nil || !saved {
+		if deleteErr := s.objects.Delete(ctx, stored.ID); deleteErr != nil {
+			slog.Error("failed to remove unused thumbnail", slog.String("key", stored.ID), slog.String("error", deleteErr.Error()))
+		}
+	}
+	return err
+}
+
+// ファイルのサムネイルを作成できるかどうかを判定する（マルウェア検査で問題がなかった内容のみ）
+func (s *ThumbnailService) eligible(file *domain.File) bool {
+	if len(s.sizes) == 0 || file.Checksum == "" || !domain.HasThumbnail(file.ContentType) || file.CheckServable() != nil {
+		return false
+	}
+	return s.maxSourceSize <= 0 || file.Size <= s.maxSourceSize
+}
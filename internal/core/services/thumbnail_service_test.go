@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestThumbnailService(now time.Time) (*ThumbnailService, *mocks.FileRepository, *mocks.FileStorage, *mocks.ThumbnailRepository, *mocks.ThumbnailRenderer) {
+	catalog := new(mocks.FileRepository)
+	objects := new(mocks.FileStorage)
+	repo := new(mocks.ThumbnailRepository)
+	renderer := new(mocks.ThumbnailRenderer)
+	service := NewThumbnailService(catalog, objects, repo, renderer, []int{512, 128, 256, 128, 0}, 1<<20)
+	service.now = func() time.Time { return now }
+	return service, catalog, objects, repo, renderer
+}
+
+func TestThumbnailService_Enqueue(t *testing.T) {
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+
+	t.Run("sizes are sorted and deduplicated", func(t *testing.T) {
+		service, _, _, _, _ := newTestThumbnailService(now)
+
+		assert.Equal(t, []int{128, 256, 512}, service.sizes)
+	})
+
+	t.Run("same content is queued once", func(t *testing.T) {
+		// Setup
+		service, _, _, _, _ := newTestThumbnailService(now)
+		file := &domain.File{ID: "file-1", ContentType: "image/png", Size: 100, Checksum: "abc"}
+
+		// Test
+		service.Enqueue(file)
+		service.Enqueue(&domain.File{ID: "file-2", ContentType: "image/png", Size: 100, Checksum: "abc"})
+
+		// Assertions
+		assert.Len(t, service.queue, 1)
+	})
+
+	t.Run("non-image and oversized files are ignored", func(t *testing.T) {
+		service, _, _, _, _ := newTestThumbnailService(now)
+
+		service.Enqueue(&domain.File{ID: "file-1", ContentType: "application/pdf", Size: 100, Checksum: "abc"})
+		service.Enqueue(&domain.File{ID: "file-2", ContentType: "image/jpeg", Size: 2 << 20, Checksum: "def"})
+
+		assert.Empty(t, service.queue)
+	})
+
+	t.Run("content waiting for a malware scan is ignored", func(t *testing.T) {
+		service, _, _, _, _ := newTestThumbnailService(now)
+
+		service.Enqueue(&domain.File{ID: "file-1", ContentType: "image/png", Size: 100, Checksum: "abc", ScanStatus: domain.ScanPending})
+
+		assert.Empty(t, service.queue)
+	})
+}
+
+func TestThumbnailService_Generate(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+	file := &domain.File{ID: "file-1", ContentType: "image/png", Size: 100, Checksum: "abc", ObjectKey: "object-1", ScanStatus: domain.ScanClean}
+
+	t.Run("only missing sizes are rendered and stored", func(t *testing.T) {
+		// Setup
+		service, catalog, objects, repo, renderer := newTestThumbnailService(now)
+
+		// Mock expectations
+		repo.On("GetThumbnailsByChecksum", "abc").Return([]domain.Thumbnail{{Checksum: "abc", Size: 128}}, nil)
+		catalog.On("GetFileByID", "file-1").Return(file, nil)
+		objects.On("Download", ctx, "object-1").Return(&domain.File{ID: "object-1"}, io.NopCloser(strings.NewReader("png")), nil)
+		renderer.On("Render", mock.Anything, []int{256, 512}).Return([]domain.RenderedThumbnail{
+			{Size: 256, ContentType: "image/jpeg", Data: []byte("small")},
+			{Size: 512, ContentType: "image/jpeg", Data: []byte("large")},
+		}, nil)
+		objects.On("Upload", ctx, mock.MatchedBy(func(u *domain.FileUpload) bool {
+			return u.Name == "thumbnail-256.jpg" && u.Size == 5
+		})).Return(&domain.File{ID: "key-256"}, nil)
+		objects.On("Upload", ctx, mock.MatchedBy(func(u *domain.FileUpload) bool {
+			return u.Name == "thumbnail-512.jpg"
+		})).Return(&domain.File{ID: "key-512"}, nil)
+		repo.On("SaveThumbnail", mock.MatchedBy(func(t *domain.Thumbnail) bool {
+			return t.ObjectKey == "key-256" && t.Size == 256 && t.CreatedAt.Equal(now)
+		})).Return(true, nil)
+		repo.On("SaveThumbnail", mock.MatchedBy(func(t *domain.Thumbnail) bool {
+			return t.ObjectKey == "key-512"
+		})).Return(false, nil)
+		objects.On("Delete", ctx, "key-512").Return(nil)
+
+		// Test
+		err := service.generate(ctx, file)
+
+		// Assertions
+		require.NoError(t, err)
+		objects.AssertExpectations(t)
+		objects.AssertNotCalled(t, "Delete", ctx, "key-256")
+	})
+
+	t.Run("replaced content is skipped", func(t *testing.T) {
+		service, catalog, objects, repo, renderer := newTestThumbnailService(now)
+		repo.On("GetThumbnailsByChecksum", "abc").Return([]domain.Thumbnail{}, nil)
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1", Checksum: "def", ScanStatus: domain.ScanClean}, nil)
+
+		err := service.generate(ctx, file)
+
+		require.NoError(t, err)
+		objects.AssertNotCalled(t, "Download", mock.Anything, mock.Anything)
+		renderer.AssertNotCalled(t, "Render", mock.Anything, mock.Anything)
+	})
+
+	t.Run("pending entry is cleared afterwards", func(t *testing.T) {
+		service, _, _, repo, _ := newTestThumbnailService(now)
+		service.pending["abc"] = true
+		repo.On("GetThumbnailsByChecksum", "abc").Return([]domain.Thumbnail{{Size: 128}, {Size: 256}, {Size: 512}}, nil)
+
+		err := service.generate(ctx, file)
+
+		require.NoError(t, err)
+		assert.Empty(t, service.pending)
+	})
+}
+
+func TestThumbnailService_Open(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+	file := &domain.File{ID: "file-1", ContentType: "image/png", Size: 100, Checksum: "abc", ScanStatus: domain.ScanClean}
+
+	t.Run("default size is the smallest one", func(t *testing.T) {
+		// Setup
+		service, catalog, objects, repo, _ := newTestThumbnailService(now)
+
+		// Mock expectations
+		catalog.On("GetFileByID", "file-1").Return(file, nil)
+		repo.On("GetThumbnailsByChecksum", "abc").Return([]domain.Thumbnail{
+			{Checksum: "abc", Size: 256, ObjectKey: "key-256"},
+			{Checksum: "abc", Size: 128, ObjectKey: "key-128"},
+		}, nil)
+		objects.On("Download", ctx, "key-128").Return(&domain.File{ID: "key-128"}, io.NopCloser(strings.NewReader("jpeg")), nil)
+
+		// Test
+		thumbnail, body, err := service.Open(ctx, "file-1", 0)
+
+		// Assertions
+		require.NoError(t, err)
+		defer body.Close()
+		assert.Equal(t, 128, thumbnail.Size)
+	})
+
+	t.Run("unknown size is rejected", func(t *testing.T) {
+		service, catalog, _, _, _ := newTestThumbnailService(now)
+
+		_, _, err := service.Open(ctx, "file-1", 100)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		catalog.AssertNotCalled(t, "GetFileByID", mock.Anything)
+	})
+
+	t.Run("missing thumbnail is queued", func(t *testing.T) {
+		service, catalog, _, repo, _ := newTestThumbnailService(now)
+		catalog.On("GetFileByID", "file-1").Return(file, nil)
+		repo.On("GetThumbnailsByChecksum", "abc").Return([]domain.Thumbnail{}, nil)
+
+		_, _, err := service.Open(ctx, "file-1", 256)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Len(t, service.queue, 1)
+	})
+
+	t.Run("non-image file has no thumbnail", func(t *testing.T) {
+		service, catalog, _, _, _ := newTestThumbnailService(now)
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1", ContentType: "application/pdf", Checksum: "abc"}, nil)
+
+		_, _, err := service.Open(ctx, "file-1", 128)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+)
+
+// 完全削除の間隔が指定されていない場合に使用する間隔
+const defaultTrashPurgeInterval = time.Hour
+
+// ゴミ箱サービス構造体：ファイルとドキュメントの論理削除、復元、完全削除を実装
+// ゴミ箱に移動した項目は一覧や取得の対象外になり、保持期間を過ぎると完全に削除される
+type TrashService struct {
+	// オブジェクトを保存するバックエンドのストレージ（完全削除時に参照がなくなったオブジェクトを削除するために使用）
+	objects ports.FileStorage
+	// ファイルカタログ
+	catalog ports.FileRepository
+	// ドキュメントストレージ
+	documents ports.DocumentStorage
+	// ゴミ箱に移動した項目を保持する期間
+	retention time.Duration
+	// 現在時刻を返す関数（テストで差し替え可能）
+	now func() time.Time
+}
+
+// 新しいゴミ箱サービスインスタンスを作成する
+func NewTrashService(objects ports.FileStorage, catalog ports.FileRepository, documents ports.DocumentStorage, retention time.Duration) *TrashService {
+	return &TrashService{
+		objects:   objects,
+		catalog:   catalog,
+		documents: documents,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// 教師がアップロードしたファイルをゴミ箱に移動する
+func (s *TrashService) TrashFile(ctx context.Context, teacherID int64, id string) error {
+	if _, err := teacherFile(s.catalog, teacherID, id); err != nil {
+		return err
+	}
+	return s.catalog.TrashFile(id, s.now().UTC())
+}
+
+// 教師がアップロードしたゴミ箱にあるファイルを元に戻す
+func (s *TrashService) RestoreFile(ctx context.Context, teacherID int64, id string) error {
+	file, err := s.catalog.GetTrashedFileByID(id)
+	if err != nil {
+		return err
+	}
+	if file.UploaderRole != domain.RoleTeacher || file.UploaderID != teacherID {
+		return fmt.Errorf("%w: file %s was not uploaded by teacher %d", domain.ErrForbidden, id, teacherID)
+	}
+	return s.catalog.RestoreFile(id)
+}
+
+// 教師がアップロードしたゴミ箱にあるファイルの一覧を取得する
+func (s *TrashService) ListTrashedFiles(ctx context.Context, teacherID int64) ([]domain.File, error) {
+	return s.catalog.ListTrashedFilesByUploader(domain.RoleTeacher, teacherID)
+}
+
+// ドキュメントをゴミ箱に移動する
+func (s *TrashService) TrashDocument(ctx context.Context, id string) error {
+	return s.documents.Trash(ctx, id, s.now().UTC())
+}
+
+// ゴミ箱にあるドキュメントを元に戻す
+func (s *TrashService) RestoreDocument(ctx context.Context, id string) error {
+	return s.documents.Restore(ctx, id)
+}
+
+// ゴミ箱にあるドキュメントの一覧を取得する
+func (s *TrashService) ListTrashedDocuments(ctx context.Context) ([]domain.Document, error) {
+	return s.documents.ListTrashed(ctx)
+}
+
+// 保持期間を過ぎたファイルとドキュメントを完全に削除する
+// 一覧の取得後に元に戻された、またはゴミ箱に移動し直された項目は削除せずに読み飛ばす
+// 一部の項目の削除に失敗しても残りの項目の削除を続け、失敗した項目のエラーをまとめて返す
+func (s *TrashService) PurgeExpired(ctx context.Context) (*domain.TrashPurgeResult, error) {
+	cutoff := s.now().Add(-s.retention)
+	result := &domain.TrashPurgeResult{}
+	var errs []error
+
+	files, err := s.catalog.ListTrashedFiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if file.DeletedAt == nil || file.DeletedAt.After(cutoff) {
+			continue
+		}
+		purged, err := s.purgeFile(ctx, file.ID, cutoff)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("file %s: %w", file.ID, err))
+			continue
+		}
+		if purged {
+			result.Files++
+		}
+	}
+
+	documents, err := s.documents.ListTrashed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, document := range documents {
+		if document.DeletedAt == nil || document.DeletedAt.After(cutoff) {
+			continue
+		}
+		if err := s.documents.PurgeTrashed(ctx, document.ID, *document.DeletedAt); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("document %s: %w", document.ID, err))
+			continue
+		}
+		result.Documents++
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// ゴミ箱に移動した日時がcutoff以前のファイルをカタログから削除し、参照がなくなったオブジェクトを削除する
+// ファイルが既にゴミ箱にない場合は削除せずfalseを返す
+func (s *TrashService) purgeFile(ctx context.Context, id string, cutoff time.Time) (bool, error) {
+	released, err := s.catalog.PurgeTrashedFile(id, cutoff)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, key := range released {
+		if err := s.objects.Delete(ctx, key); err != nil {
+			return false, fmt.Errorf("file was removed from the catalog but its object was not deleted: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// 指定された間隔で保持期間を過ぎた項目を完全に削除する（間隔が正でない場合は既定の間隔）
+// コンテキストが終了するまで処理を続けるため、ゴルーチンで実行する
+func (s *TrashService) RunPurger(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultTrashPurgeInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.PurgeExpired(ctx)
+		if err != nil {
+			slog.Error("failed to purge trash", slog.String("error", err.Error()))
+		}
+		if result != nil && (result.Files > 0 || result.Documents > 0) {
+			slog.Info("purged trash", slog.Int("files", result.Files), slog.Int("documents", result.Documents))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTrashService(now time.Time) (*TrashService, *mocks.FileStorage, *mocks.FileRepository, *mocks.DocumentStorage) {
+	objects := new(mocks.FileStorage)
+	catalog := new(mocks.FileRepository)
+	documents := new(mocks.DocumentStorage)
+	service := NewTrashService(objects, catalog, documents, 30*24*time.Hour)
+	service.now = func() time.Time { return now }
+	return service, objects, catalog, documents
+}
+
+func TestTrashService_TrashFile(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+
+	t.Run("uploader can trash the file", func(t *testing.T) {
+		// Setup
+		service, _, catalog, _ := newTestTrashService(now)
+
+		// Mock expectations
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1", UploaderID: 1, UploaderRole: "teacher"}, nil)
+		catalog.On("TrashFile", "file-1", now).Return(nil)
+
+		// Test
+		err := service.TrashFile(ctx, 1, "file-1")
+
+		// Assertions
+		require.NoError(t, err)
+		catalog.AssertExpectations(t)
+	})
+
+	t.Run("file of another teacher is forbidden", func(t *testing.T) {
+		// Setup
+		service, _, catalog, _ := newTestTrashService(now)
+
+		// Mock expectations
+		catalog.On("GetFileByID", "file-1").Return(&domain.File{ID: "file-1", UploaderID: 2, UploaderRole: "teacher"}, nil)
+
+		// Test
+		err := service.TrashFile(ctx, 1, "file-1")
+
+		// Assertions
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+		catalog.AssertNotCalled(t, "TrashFile", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTrashService_RestoreFile(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+
+	t.Run("uploader can restore the file", func(t *testing.T) {
+		// Setup
+		service, _, catalog, _ := newTestTrashService(now)
+
+		// Mock expectations
+		catalog.On("GetTrashedFileByID", "file-1").Return(&domain.File{ID: "file-1", UploaderID: 1, UploaderRole: "teacher"}, nil)
+		catalog.On("RestoreFile", "file-1").Return(nil)
+
+		// Test
+		err := service.RestoreFile(ctx, 1, "file-1")
+
+		// Assertions
+		require.NoError(t, err)
+		catalog.AssertExpectations(t)
+	})
+
+	t.Run("student submission is forbidden", func(t *testing.T) {
+		// Setup
+		service, _, catalog, _ := newTestTrashService(now)
+
+		// Mock expectations
+		catalog.On("GetTrashedFileByID", "file-1").Return(&domain.File{ID: "file-1", UploaderID: 1, UploaderRole: "student"}, nil)
+
+		// Test
+		err := service.RestoreFile(ctx, 1, "file-1")
+
+		// Assertions
+		assert.ErrorIs(t, err, domain.ErrForbidden)
+		catalog.AssertNotCalled(t, "RestoreFile", mock.Anything)
+	})
+}
+
+func TestTrashService_ListTrashedFiles(t *testing.T) {
+	// Setup
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+	service, _, catalog, _ := newTestTrashService(now)
+
+	// Mock expectations
+	catalog.On("ListTrashedFilesByUploader", "teacher", int64(1)).Return([]domain.File{{ID: "file-1"}}, nil)
+
+	// Test
+	files, err := service.ListTrashedFiles(context.Background(), 1)
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+	catalog.AssertNotCalled(t, "ListTrashedFiles")
+}
+
+func TestTrashService_PurgeExpired(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 21, 15, 30, 0, 0, time.UTC)
+	expired := now.Add(-31 * 24 * time.Hour)
+	recent := now.Add(-time.Hour)
+
+	t.Run("only items past the retention are purged", func(t *testing.T) {
+		// Setup
+		service, objects, catalog, documents := newTestTrashService(now)
+		cutoff := now.Add(-30 * 24 * time.Hour)
+
+		// Mock expectations
+		catalog.On("ListTrashedFiles").Return([]domain.File{
+			{ID: "file-old", DeletedAt: &expired},
+			{ID: "file-new", DeletedAt: &recent},
+		}, nil)
+		catalog.On("PurgeTrashedFile", "file-old", cutoff).Return([]string{"object-old"}, nil)
+		objects.On("Delete", mock.Anything, "object-old").Return(nil)
+		documents.On("ListTrashed", mock.Anything).Return([]domain.Document{
+			{ID: "doc-old", DeletedAt: &expired},
+			{ID: "doc-new", DeletedAt: &recent},
+		}, nil)
+		documents.On("PurgeTrashed", mock.Anything, "doc-old", expired).Return(nil)
+
+		// Test
+		result, err := service.PurgeExpired(ctx)
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, &domain.TrashPurgeResult{Files: 1, Documents: 1}, result)
+		catalog.AssertNotCalled(t, "PurgeTrashedFile", "file-new", mock.Anything)
+		documents.AssertNotCalled(t, "PurgeTrashed", mock.Anything, "doc-new", mock.Anything)
+		objects.AssertExpectations(t)
+	})
+
+	t.Run("items restored after the listing are skipped", func(t *testing.T) {
+		// Setup
+		service, objects, catalog, documents := newTestTrashService(now)
+
+		// Mock expectations
+		catalog.On("ListTrashedFiles").Return([]domain.File{{ID: "file-1", DeletedAt: &expired}}, nil)
+		catalog.On("PurgeTrashedFile", "file-1", mock.Anything).Return(nil, domain.ErrNotFound)
+		documents.On("ListTrashed", mock.Anything).Return([]domain.Document{{ID: "doc-1", DeletedAt: &expired}}, nil)
+		documents.On("PurgeTrashed", mock.Anything, "doc-1", expired).Return(domain.ErrNotFound)
+
+		// Test
+		result, err := service.PurgeExpired(ctx)
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, &domain.TrashPurgeResult{}, result)
+		objects.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("a failed deletion does not stop the purge", func(t *testing.T) {
+		// Setup
+		service, objects, catalog, documents := newTestTrashService(now)
+
+		// Mock expectations
+		catalog.On("ListTrashedFiles").Return([]domain.File{
+			{ID: "file-1", DeletedAt: &expired},
+			{ID: "file-2", DeletedAt: &expired},
+		}, nil)
+		catalog.On("PurgeTrashedFile", "file-1", mock.Anything).Return([]string{"object-1"}, nil)
+		catalog.On("PurgeTrashedFile", "file-2", mock.Anything).Return([]string{"object-2"}, nil)
+		objects.On("Delete", mock.Anything, "object-1").Return(errors.New("storage unavailable"))
+		objects.On("Delete", mock.Anything, "object-2").Return(nil)
+		documents.On("ListTrashed", mock.Anything).Return([]domain.Document{}, nil)
+
+		// Test
+		result, err := service.PurgeExpired(ctx)
+
+		// Assertions
+		assert.ErrorContains(t, err, "file-1")
+		assert.Equal(t, 1, result.Files)
+	})
+}
+
+func TestTrashService_RunPurger_NonPositiveInterval(t *testing.T) {
+	// Setup
+	service, _, catalog, documents := newTestTrashService(time.Now())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Mock expectations
+	catalog.On("ListTrashedFiles").Return([]domain.File{}, nil)
+	documents.On("ListTrashed", mock.Anything).Return([]domain.Document{}, nil)
+
+	// Test
+	for _, interval := range []time.Duration{0, -time.Second} {
+		assert.NotPanics(t, func() { service.RunPurger(ctx, interval) })
+	}
+}
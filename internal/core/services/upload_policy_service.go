@@ -0,0 +1,169 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
+)
+
+// MIMEタイプの判定に使用する先頭のバイト数
+const sniffLength = 512
+
+// アップロードポリシーサービス構造体：役割ごとのアップロードポリシーの適用を実装
+// MIMEタイプはクライアントが送信したヘッダーではなく、内容の先頭から判定する
+type UploadPolicyService struct {
+	// 役割ごとのポリシー（ポリシーがない役割は制限しない）
+	policies map[string]domain.UploadPolicy
+	// ストレージ使用量を記録するファイルカタログ（nilの場合は使用量を事前に検証しない）
+	catalog ports.FileRepository
+}
+
+// 新しいアップロードポリシーサービスインスタンスを作成する
+func NewUploadPolicyService(policies map[string]domain.UploadPolicy, catalog ports.FileRepository) *UploadPolicyService {
+	return &UploadPolicyService{
+		policies: policies,
+		catalog:  catalog,
+	}
+}
+
+// 宣言されたファイル名とサイズがポリシーを満たしているか検証する
+func (s *UploadPolicyService) CheckDeclared(role string, name string, size int64) error {
+	policy, ok := s.policies[role]
+	if !ok {
+		return nil
+	}
+
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, blocked := range policy.BlockedExtensions {
+		if ext != "" && ext == strings.ToLower(blocked) {
+			return fmt.Errorf("%w: files with extension %s are not allowed", domain.ErrUnsupportedMedia, ext)
+		}
+	}
+	if policy.MaxSize > 0 && size > policy.MaxSize {
+		return fmt.Errorf("%w: file is %d bytes, the limit is %d bytes", domain.ErrFileTooLarge, size, policy.MaxSize)
+	}
+	return nil
+}
+
+// 役割ごとの保存容量の上限を返す（ポリシーがない役割は無制限）
+func (s *UploadPolicyService) Quota(role string) domain.StorageQuota {
+	return s.policies[role].Quota
+}
+
+// 現在の使用量に指定されたサイズのファイルを追加してもクォータを超えないか検証する
+// ストレージへ転送する前に明らかな超過を拒否するためのもので、上限の厳密な判定はカタログへの登録時に行う
+func (s *UploadPolicyService) CheckQuota(role string, uploaderID int64, size int64) error {
+	quota := s.Quota(role)
+	if s.catalog == nil || uploaderID == 0 || (quota.MaxBytes <= 0 && quota.MaxFiles <= 0) {
+		return nil
+	}
+
+	usage, err := s.catalog.GetStorageUsage(role, uploaderID)
+	if err != nil {
+		return err
+	}
+	if quota.MaxFiles > 0 && usage.Files+1 > quota.MaxFiles {
+		return fmt.Errorf("%w: %d of %d files are already stored", domain.ErrQuotaExceeded, usage.Files, quota.MaxFiles)
+	}
+	if size < 0 {
+		size = 0
+	}
+	if quota.MaxBytes > 0 && usage.Bytes+size > quota.MaxBytes {
+		return fmt.Errorf("%w: %d of %d bytes are already stored", domain.ErrQuotaExceeded, usage.Bytes, quota.MaxBytes)
+	}
+	return nil
+}
+
+// アップロードにポリシーを適用する
+// 先頭の内容からMIMEタイプを判定してアップロードのMIMEタイプとし、本文は最大サイズを超えると
+// ErrFileTooLargeを返すリーダーに置き換える
+func (s *UploadPolicyService) Apply(upload *domain.FileUpload) (*domain.FileUpload, error) {
+	if err := s.CheckDeclared(upload.UploaderRole, upload.Name, upload.Size); err != nil {
+		return nil, err
+	}
+	if err := s.CheckQuota(upload.UploaderRole, upload.UploaderID, upload.Size); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(upload.Body, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	head = head[:n]
+
+	contentType, err := s.DetectContentType(upload.UploaderRole, head)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := *upload
+	applied.ContentType = contentType
+	applied.Body = io.MultiReader(bytes.NewReader(head), upload.Body)
+	if policy, ok := s.policies[upload.UploaderRole]; ok && policy.MaxSize > 0 {
+		applied.Body = &sizeLimitReader{reader: applied.Body, remaining: policy.MaxSize}
+	}
+	return &applied, nil
+}
+
+// 内容の先頭からMIMEタイプを判定し、ポリシーで許可されているか検証する
+func (s *UploadPolicyService) DetectContentType(role string, head []byte) (string, error) {
+	contentType := http.DetectContentType(head)
+
+	policy, ok := s.policies[role]
+	if !ok || len(policy.AllowedTypes) == 0 {
+		return contentType, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	for _, allowed := range policy.AllowedTypes {
+		if mediaTypeMatches(allowed, mediaType) {
+			return contentType, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s files are not allowed", domain.ErrUnsupportedMedia, mediaType)
+}
+
+// 許可するMIMEタイプ（"image/*"のようなワイルドカードを含む）にMIMEタイプが一致するか判定する
+func mediaTypeMatches(allowed, mediaType string) bool {
+	allowed = strings.ToLower(strings.TrimSpace(allowed))
+	if prefix, ok := strings.CutSuffix(allowed, "/*"); ok {
+		return strings.HasPrefix(mediaType, prefix+"/")
+	}
+	return allowed == "*" || allowed == mediaType
+}
+
+// 最大サイズを超えて読み込もうとするとErrFileTooLargeを返すリーダー
+type sizeLimitReader struct {
+	// 元のリーダー
+	reader io.Reader
+	// 読み込み可能な残りのバイト数
+	remaining int64
+}
+
+func (r *sizeLimitReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, fmt.Errorf("%w: file exceeds the upload size limit", domain.ErrFileTooLarge)
+	}
+	// 上限を1バイト超えて読み込み、上限ちょうどのファイルと超えるファイルを区別する
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return 0, fmt.Errorf("%w: file exceeds the upload size limit", domain.ErrFileTooLarge)
+	}
+	return n, err
+}
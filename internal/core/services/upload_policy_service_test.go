@@ -0,0 +1,150 @@
+package services
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestUploadPolicyService() *UploadPolicyService {
+	return NewUploadPolicyService(map[string]domain.UploadPolicy{
+		domain.RoleStudent: {
+			MaxSize:           16,
+			AllowedTypes:      []string{"application/pdf", "image/*"},
+			BlockedExtensions: []string{".exe"},
+			Quota:             domain.StorageQuota{MaxBytes: 64, MaxFiles: 3},
+		},
+	}, nil)
+}
+
+func TestUploadPolicyService_Apply(t *testing.T) {
+	service := newTestUploadPolicyService()
+
+	t.Run("content type is sniffed from the content", func(t *testing.T) {
+		upload := &domain.FileUpload{Name: "essay.pdf", ContentType: "text/html", Body: strings.NewReader("%PDF-1.4 essay"), Size: -1, UploaderRole: domain.RoleStudent}
+
+		applied, err := service.Apply(upload)
+
+		require.NoError(t, err)
+		assert.Equal(t, "application/pdf", applied.ContentType)
+		data, err := io.ReadAll(applied.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "%PDF-1.4 essay", string(data))
+	})
+
+	t.Run("type not allowed", func(t *testing.T) {
+		upload := &domain.FileUpload{Name: "page.pdf", ContentType: "application/pdf", Body: strings.NewReader("<html><body>x</body></html>"), Size: -1, UploaderRole: domain.RoleStudent}
+
+		_, err := service.Apply(upload)
+
+		assert.ErrorIs(t, err, domain.ErrUnsupportedMedia)
+	})
+
+	t.Run("blocked extension", func(t *testing.T) {
+		upload := &domain.FileUpload{Name: "setup.EXE", Body: strings.NewReader("MZ"), Size: 2, UploaderRole: domain.RoleStudent}
+
+		_, err := service.Apply(upload)
+
+		assert.ErrorIs(t, err, domain.ErrUnsupportedMedia)
+	})
+
+	t.Run("declared size over the limit", func(t *testing.T) {
+		upload := &domain.FileUpload{Name: "scan.pdf", Body: strings.NewReader("%PDF"), Size: 17, UploaderRole: domain.RoleStudent}
+
+		_, err := service.Apply(upload)
+
+		assert.ErrorIs(t, err, domain.ErrFileTooLarge)
+	})
+
+	t.Run("streamed content over the limit", func(t *testing.T) {
+		upload := &domain.FileUpload{Name: "scan.pdf", Body: strings.NewReader("%PDF-1.4 " + strings.Repeat("x", 16)), Size: -1, UploaderRole: domain.RoleStudent}
+
+		applied, err := service.Apply(upload)
+		require.NoError(t, err)
+		_, err = io.ReadAll(applied.Body)
+
+		assert.ErrorIs(t, err, domain.ErrFileTooLarge)
+	})
+
+	t.Run("content exactly at the limit", func(t *testing.T) {
+		upload := &domain.FileUpload{Name: "scan.pdf", Body: strings.NewReader("%PDF-1.4 " + strings.Repeat("x", 7)), Size: -1, UploaderRole: domain.RoleStudent}
+
+		applied, err := service.Apply(upload)
+		require.NoError(t, err)
+		data, err := io.ReadAll(applied.Body)
+
+		require.NoError(t, err)
+		assert.Len(t, data, 16)
+	})
+
+	t.Run("role without a policy", func(t *testing.T) {
+		upload := &domain.FileUpload{Name: "tool.exe", Body: strings.NewReader("MZ\x90\x00"), Size: -1, UploaderRole: domain.RoleTeacher}
+
+		applied, err := service.Apply(upload)
+
+		require.NoError(t, err)
+		assert.Equal(t, "application/octet-stream", applied.ContentType)
+	})
+}
+
+func TestUploadPolicyService_CheckQuota(t *testing.T) {
+	tests := []struct {
+		name    string
+		usage   domain.StorageUsage
+		size    int64
+		wantErr error
+	}{
+		{name: "within the quota", usage: domain.StorageUsage{Bytes: 48, Files: 2}, size: 16},
+		{name: "bytes over the quota", usage: domain.StorageUsage{Bytes: 60, Files: 1}, size: 8, wantErr: domain.ErrQuotaExceeded},
+		{name: "file count over the quota", usage: domain.StorageUsage{Bytes: 3, Files: 3}, size: 1, wantErr: domain.ErrQuotaExceeded},
+		{name: "unknown size checks the stored bytes only", usage: domain.StorageUsage{Bytes: 64, Files: 1}, size: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			catalog := new(mocks.FileRepository)
+			service := newTestUploadPolicyService()
+			service.catalog = catalog
+
+			// Mock expectations
+			usage := tt.usage
+			catalog.On("GetStorageUsage", domain.RoleStudent, int64(12)).Return(&usage, nil)
+
+			// Test
+			err := service.CheckQuota(domain.RoleStudent, 12, tt.size)
+
+			// Assertions
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("role without a quota", func(t *testing.T) {
+		catalog := new(mocks.FileRepository)
+		service := newTestUploadPolicyService()
+		service.catalog = catalog
+
+		err := service.CheckQuota(domain.RoleTeacher, 7, 1<<40)
+
+		assert.NoError(t, err)
+		catalog.AssertNotCalled(t, "GetStorageUsage", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMediaTypeMatches(t *testing.T) {
+	assert.True(t, mediaTypeMatches("image/*", "image/png"))
+	assert.True(t, mediaTypeMatches("Application/PDF", "application/pdf"))
+	assert.True(t, mediaTypeMatches("*", "text/plain"))
+	assert.False(t, mediaTypeMatches("image/*", "application/pdf"))
+	assert.False(t, mediaTypeMatches("text/plain", "text/html"))
+}
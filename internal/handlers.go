@@ -1,13 +1,21 @@
 package internal
 
 import (
+	"fmt"
+
 	"github.com/OICjangirrahul/students/internal/adapters/http"
+	"github.com/OICjangirrahul/students/internal/adapters/pdf"
+	"github.com/OICjangirrahul/students/internal/adapters/qti"
 	"github.com/OICjangirrahul/students/internal/adapters/repositories"
+	"github.com/OICjangirrahul/students/internal/adapters/scanner"
+	"github.com/OICjangirrahul/students/internal/adapters/schema"
 	"github.com/OICjangirrahul/students/internal/adapters/storage"
+	"github.com/OICjangirrahul/students/internal/adapters/thumbnail"
 	"github.com/OICjangirrahul/students/internal/config"
+	"github.com/OICjangirrahul/students/internal/core/domain"
+	"github.com/OICjangirrahul/students/internal/core/ports"
 	"github.com/OICjangirrahul/students/internal/core/services"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/aws"
 )
 
 // アプリケーションハンドラー構造体：全てのHTTPハンドラーを管理
@@ -18,6 +26,34 @@ type AppHandlers struct {
 	Teacher *http.TeacherHandler
 	// ストレージ関連のHTTPハンドラー
 	Storage *http.StorageHandler
+	// 課題関連のHTTPハンドラー
+	Assignment *http.AssignmentHandler
+	// 成績簿関連のHTTPハンドラー
+	Gradebook *http.GradebookHandler
+	// 通知表関連のHTTPハンドラー
+	ReportCard *http.ReportCardHandler
+	// 小テスト関連のHTTPハンドラー
+	Quiz *http.QuizHandler
+	// 問題バンク関連のHTTPハンドラー
+	QuestionBank *http.QuestionBankHandler
+	// ストレージ使用量関連のHTTPハンドラー
+	StorageUsage *http.StorageUsageHandler
+	// フォルダとタグ関連のHTTPハンドラー
+	Folder *http.FolderHandler
+	// ファイル共有関連のHTTPハンドラー
+	FileShare *http.FileShareHandler
+	// ファイルアーカイブ関連のHTTPハンドラー
+	FileArchive *http.FileArchiveHandler
+	// 通知と隔離ファイル関連のHTTPハンドラー
+	Notification *http.NotificationHandler
+	// ドキュメントスキーマ関連のHTTPハンドラー
+	DocumentSchema *http.DocumentSchemaHandler
+	// ゴミ箱サービス（バックグラウンドで保持期間を過ぎた項目を完全に削除する）
+	Trash *services.TrashService
+	// サムネイルサービス（バックグラウンドで画像ファイルのサムネイルを作成する）
+	Thumbnails *services.ThumbnailService
+	// コンテンツ検査サービス（バックグラウンドでアップロードされた内容のマルウェア検査を行う）
+	ContentScans *services.ContentScanService
 }
 
 // アプリケーションハンドラーを初期化する
@@ -33,11 +69,21 @@ func InitializeAppHandlers(cfg *config.Config) (*AppHandlers, error) {
 	// データベースとの対話を担当するコンポーネントを作成
 	studentRepo := repositories.NewStudentRepository(db, cfg)
 	teacherRepo := repositories.NewTeacherRepository(db, cfg)
+	assignmentRepo := repositories.NewAssignmentRepository(db)
+	gradebookRepo := repositories.NewGradebookRepository(db)
+	reportCardRepo := repositories.NewReportCardRepository(db)
+	quizRepo := repositories.NewQuizRepository(db)
+	fileRepo := repositories.NewFileRepository(db)
+	folderRepo := repositories.NewFolderRepository(db)
+	fileShareRepo := repositories.NewFileShareRepository(db)
+	thumbnailRepo := repositories.NewThumbnailRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
 
 	// サービスを初期化
 	// ビジネスロジックを実装するコンポーネントを作成
 	studentService := services.NewStudentService(studentRepo)
 	teacherService := services.NewTeacherService(teacherRepo)
+	gradebookService := services.NewGradebookService(gradebookRepo, teacherRepo, assignmentRepo)
 
 	// AWSクライアントを初期化
 	// S3とDynamoDBへのアクセスを設定
@@ -46,19 +92,105 @@ func InitializeAppHandlers(cfg *config.Config) (*AppHandlers, error) {
 		return nil, err
 	}
 
-	s3Client := s3.NewFromConfig(awsCfg)
-	dynamoClient := dynamodb.NewFromConfig(awsCfg)
+	dynamoClient := config.NewDynamoDBClient(cfg, awsCfg)
 
 	// ストレージサービスを初期化
 	// ファイルとドキュメントの保存を担当するコンポーネントを作成
-	fileStorage := storage.NewS3Storage(s3Client, cfg.AWS.S3Bucket)
+	// ファイルはカタログを介して操作し、実データは設定されたドライバーのストレージに保存する
+	// （S3は署名付きURLによる直接転送にも対応する）
+	fileBackend, err := newFileBackend(cfg, awsCfg)
+	if err != nil {
+		return nil, err
+	}
+	contentScanner, err := newContentScanner(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// サムネイルは内容の派生オブジェクトとして、ファイルと同じストレージに保存する
+	thumbnailService := services.NewThumbnailService(fileRepo, fileBackend, thumbnailRepo, thumbnail.NewRenderer(), cfg.Storage.ThumbnailSizes, cfg.Storage.ThumbnailMaxSourceSize)
+	// 新しい内容はマルウェア検査が終わるまでダウンロードできず、検査で問題がなかった画像のサムネイルを作成する
+	contentScanService := services.NewContentScanService(fileBackend, fileRepo, contentScanner, notificationRepo, thumbnailService)
+	uploadPolicyService := services.NewUploadPolicyService(uploadPolicies(cfg), fileRepo)
+	storageUsageService := services.NewStorageUsageService(fileRepo, uploadPolicyService)
+	fileStorage := storage.NewCatalogFileStorage(fileBackend, fileRepo, uploadPolicyService, contentScanService, cfg.Storage.VersionRetention)
+	fileTransferService := services.NewFileTransferService(fileBackend, fileRepo, uploadPolicyService, contentScanService, cfg.AWS.PresignExpiry)
 	documentStorage := storage.NewDynamoDBStorage(dynamoClient, cfg.AWS.DynamoTable)
+	documentSchemaRepo := repositories.NewDocumentSchemaRepository(db)
+	documentService := services.NewDocumentService(documentStorage, documentSchemaRepo, schema.NewJSONSchemaValidator())
+	trashService := services.NewTrashService(fileBackend, fileRepo, documentStorage, cfg.Storage.TrashRetention)
+	folderService := services.NewFolderService(folderRepo, fileRepo)
+	fileSharingService := services.NewFileSharingService(fileShareRepo, fileRepo, teacherRepo)
+	fileArchiveService := services.NewFileArchiveService(fileStorage, fileRepo, folderRepo, fileShareRepo)
+	notificationService := services.NewNotificationService(notificationRepo)
+
+	// ファイル・ドキュメントストレージを利用するサービスを初期化
+	assignmentService := services.NewAssignmentService(assignmentRepo, teacherRepo, fileStorage)
+	reportCardService := services.NewReportCardService(reportCardRepo, studentRepo, teacherRepo, gradebookService, fileStorage, pdf.NewReportCardRenderer())
+	quizService := services.NewQuizService(documentStorage, quizRepo, gradebookRepo, teacherRepo)
+	questionBankService := services.NewQuestionBankService(documentStorage, fileStorage, qti.NewPackageCodec())
 
 	// ハンドラーを初期化して返す
 	// 各種サービスを利用してHTTPリクエストを処理するハンドラーを作成
 	return &AppHandlers{
-		Student: http.NewStudentHandler(studentService),
-		Teacher: http.NewTeacherHandler(teacherService),
-		Storage: http.NewStorageHandler(fileStorage, documentStorage),
+		Student:        http.NewStudentHandler(studentService),
+		Teacher:        http.NewTeacherHandler(teacherService),
+		Storage:        http.NewStorageHandler(fileStorage, fileTransferService, documentStorage, documentService, trashService, fileSharingService, thumbnailService),
+		Assignment:     http.NewAssignmentHandler(assignmentService),
+		Gradebook:      http.NewGradebookHandler(gradebookService),
+		ReportCard:     http.NewReportCardHandler(reportCardService),
+		Quiz:           http.NewQuizHandler(quizService),
+		QuestionBank:   http.NewQuestionBankHandler(questionBankService),
+		StorageUsage:   http.NewStorageUsageHandler(storageUsageService),
+		Folder:         http.NewFolderHandler(folderService),
+		FileShare:      http.NewFileShareHandler(fileSharingService),
+		FileArchive:    http.NewFileArchiveHandler(fileArchiveService),
+		Notification:   http.NewNotificationHandler(notificationService, contentScanService),
+		DocumentSchema: http.NewDocumentSchemaHandler(documentService),
+		Trash:          trashService,
+		Thumbnails:     thumbnailService,
+		ContentScans:   contentScanService,
 	}, nil
 }
+
+// 設定されたドライバーに従って、ファイルの実データを保存するストレージを作成する
+func newFileBackend(cfg *config.Config, awsCfg aws.Config) (ports.FileStorage, error) {
+	switch cfg.Storage.Driver {
+	case config.StorageDriverS3, "":
+		return storage.NewS3Storage(config.NewS3Client(cfg, awsCfg), cfg.AWS.S3Bucket, cfg.AWS.S3Endpoint, cfg.AWS.S3PublicBaseURL), nil
+	case config.StorageDriverLocal:
+		return storage.NewLocalFileStorage(cfg.Storage.LocalPath)
+	case config.StorageDriverMemory:
+		return storage.NewMemoryFileStorage(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %q", cfg.Storage.Driver)
+	}
+}
+
+// 設定されたドライバーに従って、アップロードされた内容のマルウェアを検査するスキャナーを作成する
+func newContentScanner(cfg *config.Config) (ports.ContentScanner, error) {
+	switch cfg.Storage.ScanDriver {
+	case config.ScanDriverNone, "":
+		return scanner.NewNoopScanner(), nil
+	case config.ScanDriverClamAV:
+		return scanner.NewClamAVScanner(cfg.Storage.ClamAVAddress, cfg.Storage.ScanTimeout), nil
+	default:
+		return nil, fmt.Errorf("unknown scan driver: %q", cfg.Storage.ScanDriver)
+	}
+}
+
+// 設定から役割ごとのアップロードポリシーを作成する
+func uploadPolicies(cfg *config.Config) map[string]domain.UploadPolicy {
+	policies := make(map[string]domain.UploadPolicy, len(cfg.Storage.UploadPolicies))
+	for role, policy := range cfg.Storage.UploadPolicies {
+		policies[role] = domain.UploadPolicy{
+			MaxSize:           policy.MaxSize,
+			AllowedTypes:      policy.AllowedTypes,
+			BlockedExtensions: policy.BlockedExtensions,
+			Quota: domain.StorageQuota{
+				MaxBytes: policy.QuotaBytes,
+				MaxFiles: policy.QuotaFiles,
+			},
+		}
+	}
+	return policies
+}
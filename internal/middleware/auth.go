@@ -3,6 +3,7 @@ package middleware
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/OICjangirrahul/students/internal/config"
@@ -44,6 +45,29 @@ func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
 	}
 }
 
+// 管理者アクセス制御ミドルウェアを作成
+// 教師の役割を持ち、メールアドレスが管理者として設定されているユーザーのみがアクセスを許可される
+func AdminMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Get("role")
+		email, _ := c.Get("email")
+		roleStr, _ := role.(string)
+		emailStr, _ := email.(string)
+
+		if roleStr == "teacher" && emailStr != "" {
+			for _, admin := range cfg.Admin.Emails {
+				if strings.EqualFold(admin, emailStr) {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		c.JSON(http.StatusForbidden, response.GeneralError(fmt.Errorf("access denied: administrator privileges required")))
+		c.Abort()
+	}
+}
+
 // JWT認証ミドルウェアを作成
 // リクエストヘッダーからJWTトークンを検証し、ユーザー情報をコンテキストに追加
 func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
@@ -126,3 +150,42 @@ func ResourceOwnershipMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// 本人確認ミドルウェアを作成
+// URLパラメータのIDがトークンのユーザーIDと一致する場合のみアクセスを許可する
+// 教師として操作するルートで、他の教師になりすまして操作できないようにする
+func SelfOnlyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, response.GeneralError(fmt.Errorf("user ID not found in token")))
+			c.Abort()
+			return
+		}
+
+		// JWTのsubクレームは数値としてデコードされるため、数値として比較する
+		resourceID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || !sameUserID(userID, resourceID) {
+			c.JSON(http.StatusForbidden, response.GeneralError(fmt.Errorf("access denied: you can only act as yourself")))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// トークンのユーザーIDが指定されたIDと一致するかを確認する
+func sameUserID(userID interface{}, id int64) bool {
+	switch v := userID.(type) {
+	case float64:
+		return v == float64(id)
+	case int64:
+		return v == id
+	case string:
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		return err == nil && parsed == id
+	default:
+		return false
+	}
+}
@@ -11,11 +11,11 @@ func CorsMiddleware() gin.HandlerFunc {
 		// 全てのオリジンからのアクセスを許可
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		// 許可するHTTPメソッドを指定
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		// 許可するHTTPヘッダーを指定
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-CSRF-Token")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, If-Match, X-CSRF-Token")
 		// クライアントに公開するレスポンスヘッダーを指定
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "Link")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Link, ETag")
 		// クレデンシャル（認証情報）の送信を許可
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		// プリフライトリクエストの結果をキャッシュする時間（秒）
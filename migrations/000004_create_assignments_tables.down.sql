@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS submission_files;
+DROP TABLE IF EXISTS submissions;
+DROP TABLE IF EXISTS assignment_files;
+DROP TABLE IF EXISTS assignments;
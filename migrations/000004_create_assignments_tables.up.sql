@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS assignments (
+    id SERIAL PRIMARY KEY,
+    teacher_id INTEGER NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    instructions TEXT NOT NULL DEFAULT '',
+    due_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    max_points NUMERIC(10, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignments_teacher_id ON assignments (teacher_id);
+
+CREATE TABLE IF NOT EXISTS assignment_files (
+    assignment_id INTEGER REFERENCES assignments(id) ON DELETE CASCADE,
+    file_id VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (assignment_id, file_id)
+);
+
+CREATE TABLE IF NOT EXISTS submissions (
+    id SERIAL PRIMARY KEY,
+    assignment_id INTEGER NOT NULL REFERENCES assignments(id) ON DELETE CASCADE,
+    student_id INTEGER NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    text TEXT NOT NULL DEFAULT '',
+    late BOOLEAN NOT NULL DEFAULT FALSE,
+    attempt INTEGER NOT NULL DEFAULT 1,
+    submitted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (assignment_id, student_id)
+);
+
+CREATE TABLE IF NOT EXISTS submission_files (
+    submission_id INTEGER REFERENCES submissions(id) ON DELETE CASCADE,
+    file_id VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (submission_id, file_id)
+);
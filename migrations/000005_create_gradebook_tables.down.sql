@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS grade_scales;
+DROP TABLE IF EXISTS grade_entries;
+DROP TABLE IF EXISTS grade_categories;
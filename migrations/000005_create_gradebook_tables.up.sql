@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS grade_categories (
+    id SERIAL PRIMARY KEY,
+    teacher_id INTEGER NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    weight NUMERIC(5, 2) NOT NULL CHECK (weight > 0 AND weight <= 100),
+    drop_lowest INTEGER NOT NULL DEFAULT 0 CHECK (drop_lowest >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (teacher_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS grade_entries (
+    id SERIAL PRIMARY KEY,
+    teacher_id INTEGER NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    student_id INTEGER NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES grade_categories(id) ON DELETE CASCADE,
+    assignment_id INTEGER REFERENCES assignments(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    score NUMERIC(10, 2) NOT NULL CHECK (score >= 0),
+    max_points NUMERIC(10, 2) NOT NULL CHECK (max_points > 0),
+    graded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_grade_entries_teacher_id ON grade_entries (teacher_id);
+CREATE INDEX IF NOT EXISTS idx_grade_entries_student_id ON grade_entries (student_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_grade_entries_assignment
+    ON grade_entries (teacher_id, student_id, assignment_id)
+    WHERE assignment_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS grade_scales (
+    teacher_id INTEGER REFERENCES teachers(id) ON DELETE CASCADE,
+    letter VARCHAR(16) NOT NULL,
+    min_percent NUMERIC(5, 2) NOT NULL CHECK (min_percent >= 0 AND min_percent <= 100),
+    PRIMARY KEY (teacher_id, letter)
+);
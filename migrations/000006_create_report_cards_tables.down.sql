@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS report_cards;
+DROP TABLE IF EXISTS report_card_remarks;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS report_card_remarks (
+    id SERIAL PRIMARY KEY,
+    teacher_id INTEGER NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    student_id INTEGER NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    term VARCHAR(64) NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    days_present INTEGER NOT NULL DEFAULT 0 CHECK (days_present >= 0),
+    days_absent INTEGER NOT NULL DEFAULT 0 CHECK (days_absent >= 0),
+    days_tardy INTEGER NOT NULL DEFAULT 0 CHECK (days_tardy >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (teacher_id, student_id, term)
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_card_remarks_student_term ON report_card_remarks (student_id, term);
+
+CREATE TABLE IF NOT EXISTS report_cards (
+    id SERIAL PRIMARY KEY,
+    student_id INTEGER NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    term VARCHAR(64) NOT NULL,
+    file_id VARCHAR(255) NOT NULL,
+    generated_by INTEGER NOT NULL,
+    generated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (student_id, term)
+);
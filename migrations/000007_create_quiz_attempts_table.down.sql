@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_grade_entries_quiz;
+ALTER TABLE grade_entries DROP COLUMN IF EXISTS quiz_id;
+DROP TABLE IF EXISTS quiz_attempts;
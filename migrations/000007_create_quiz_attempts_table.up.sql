@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS quiz_attempts (
+    id SERIAL PRIMARY KEY,
+    quiz_id VARCHAR(64) NOT NULL,
+    student_id INTEGER NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    teacher_id INTEGER NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL CHECK (number > 0),
+    status VARCHAR(16) NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    deadline TIMESTAMP WITH TIME ZONE,
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    score NUMERIC(10, 2),
+    max_score NUMERIC(10, 2) NOT NULL,
+    question_order JSONB NOT NULL,
+    option_order JSONB NOT NULL DEFAULT '{}',
+    answers JSONB NOT NULL DEFAULT '[]',
+    results JSONB NOT NULL DEFAULT '[]',
+    UNIQUE (quiz_id, student_id, number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_quiz_id ON quiz_attempts (quiz_id);
+
+ALTER TABLE grade_entries ADD COLUMN IF NOT EXISTS quiz_id VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_grade_entries_quiz
+    ON grade_entries (teacher_id, student_id, quiz_id)
+    WHERE quiz_id IS NOT NULL;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS files (
+    id VARCHAR(64) PRIMARY KEY,
+    object_key VARCHAR(1024) NOT NULL UNIQUE,
+    original_name VARCHAR(1024) NOT NULL,
+    size BIGINT NOT NULL,
+    content_type VARCHAR(255) NOT NULL DEFAULT '',
+    checksum CHAR(64) NOT NULL,
+    uploader_id INTEGER,
+    uploader_role VARCHAR(16) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_files_uploader ON files (uploader_role, uploader_id);
+CREATE INDEX IF NOT EXISTS idx_files_created_at ON files (created_at);
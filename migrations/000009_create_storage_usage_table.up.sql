@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS storage_usage (
+    uploader_role VARCHAR(16) NOT NULL,
+    uploader_id INTEGER NOT NULL,
+    bytes BIGINT NOT NULL DEFAULT 0,
+    files BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (uploader_role, uploader_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_storage_usage_bytes ON storage_usage (bytes DESC);
+
+INSERT INTO storage_usage (uploader_role, uploader_id, bytes, files)
+SELECT uploader_role, uploader_id, SUM(size), COUNT(*)
+FROM files
+WHERE uploader_id IS NOT NULL
+GROUP BY uploader_role, uploader_id
+ON CONFLICT (uploader_role, uploader_id) DO NOTHING;
@@ -0,0 +1,12 @@
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM files GROUP BY object_key HAVING COUNT(*) > 1) THEN
+        RAISE EXCEPTION 'cannot restore UNIQUE(object_key): deduplicated files share stored objects';
+    END IF;
+END
+$$;
+
+DROP INDEX IF EXISTS idx_files_object_key;
+ALTER TABLE files ADD CONSTRAINT files_object_key_key UNIQUE (object_key);
+DROP TABLE IF EXISTS orphaned_objects;
+DROP TABLE IF EXISTS blobs;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS blobs (
+    checksum CHAR(64) PRIMARY KEY,
+    object_key VARCHAR(1024) NOT NULL UNIQUE,
+    size BIGINT NOT NULL,
+    ref_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE files DROP CONSTRAINT IF EXISTS files_object_key_key;
+CREATE INDEX IF NOT EXISTS idx_files_object_key ON files (object_key);
+
+INSERT INTO blobs (checksum, object_key, size, ref_count, created_at)
+SELECT checksum, MIN(object_key), MAX(size), COUNT(*), MIN(created_at)
+FROM files
+GROUP BY checksum
+ON CONFLICT (checksum) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS orphaned_objects (
+    object_key VARCHAR(1024) PRIMARY KEY,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO orphaned_objects (object_key)
+SELECT DISTINCT files.object_key
+FROM files
+JOIN blobs ON files.checksum = blobs.checksum
+WHERE files.object_key <> blobs.object_key
+ON CONFLICT (object_key) DO NOTHING;
+
+UPDATE files
+SET object_key = blobs.object_key
+FROM blobs
+WHERE files.checksum = blobs.checksum AND files.object_key <> blobs.object_key;
@@ -0,0 +1,22 @@
+ALTER TABLE files ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS file_versions (
+    file_id VARCHAR(64) NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    object_key VARCHAR(1024) NOT NULL,
+    original_name VARCHAR(1024) NOT NULL,
+    size BIGINT NOT NULL,
+    content_type VARCHAR(255) NOT NULL DEFAULT '',
+    checksum CHAR(64) NOT NULL,
+    uploader_id INTEGER,
+    uploader_role VARCHAR(16) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (file_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_versions_object_key ON file_versions (object_key);
+
+INSERT INTO file_versions (file_id, version, object_key, original_name, size, content_type, checksum, uploader_id, uploader_role, created_at)
+SELECT id, version, object_key, original_name, size, content_type, checksum, uploader_id, uploader_role, created_at
+FROM files
+ON CONFLICT (file_id, version) DO NOTHING;
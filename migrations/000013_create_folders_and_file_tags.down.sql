@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS file_tags;
+DROP INDEX IF EXISTS idx_files_folder_id;
+ALTER TABLE files DROP COLUMN IF EXISTS folder_id;
+DROP TABLE IF EXISTS folders;
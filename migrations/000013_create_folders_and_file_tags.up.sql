@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS folders (
+    id VARCHAR(64) PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    parent_id VARCHAR(64) REFERENCES folders(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_folders_owner_parent_name ON folders (owner_id, COALESCE(parent_id, ''), LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_folders_parent_id ON folders (parent_id);
+
+ALTER TABLE files ADD COLUMN IF NOT EXISTS folder_id VARCHAR(64) REFERENCES folders(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_files_folder_id ON files (folder_id);
+
+CREATE TABLE IF NOT EXISTS file_tags (
+    file_id VARCHAR(64) NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    tag VARCHAR(64) NOT NULL,
+    PRIMARY KEY (file_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_tags_tag ON file_tags (tag);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS file_shares (
+    id SERIAL PRIMARY KEY,
+    file_id VARCHAR(64) NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('student', 'roster', 'link')),
+    teacher_id INTEGER NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    student_id INTEGER REFERENCES students(id) ON DELETE CASCADE,
+    token_hash CHAR(64) UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((kind = 'student') = (student_id IS NOT NULL)),
+    CHECK ((kind = 'link') = (token_hash IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_shares_file_id ON file_shares (file_id);
+CREATE INDEX IF NOT EXISTS idx_file_shares_student_id ON file_shares (student_id) WHERE student_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_file_shares_teacher_id ON file_shares (teacher_id) WHERE kind = 'roster';
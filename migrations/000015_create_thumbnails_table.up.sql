@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS thumbnails (
+    checksum CHAR(64) NOT NULL REFERENCES blobs(checksum) ON DELETE CASCADE,
+    size INTEGER NOT NULL CHECK (size > 0),
+    object_key VARCHAR(1024) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    byte_size BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (checksum, size)
+);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS notifications;
+
+DROP INDEX IF EXISTS idx_blobs_scan_status;
+ALTER TABLE blobs DROP COLUMN IF EXISTS scanned_at;
+ALTER TABLE blobs DROP COLUMN IF EXISTS scan_signature;
+ALTER TABLE blobs DROP COLUMN IF EXISTS scan_status;
@@ -0,0 +1,19 @@
+ALTER TABLE blobs ADD COLUMN IF NOT EXISTS scan_status VARCHAR(16) NOT NULL DEFAULT 'clean'
+    CHECK (scan_status IN ('pending_scan', 'clean', 'infected'));
+ALTER TABLE blobs ALTER COLUMN scan_status SET DEFAULT 'pending_scan';
+ALTER TABLE blobs ADD COLUMN IF NOT EXISTS scan_signature VARCHAR(255);
+ALTER TABLE blobs ADD COLUMN IF NOT EXISTS scanned_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_blobs_scan_status ON blobs (scan_status) WHERE scan_status <> 'clean';
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    recipient_role VARCHAR(16) NOT NULL CHECK (recipient_role IN ('teacher', 'student')),
+    recipient_id INTEGER NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    message TEXT NOT NULL,
+    file_id VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_recipient ON notifications (recipient_role, recipient_id, created_at DESC);
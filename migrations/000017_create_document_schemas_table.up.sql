@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS document_schemas (
+    type VARCHAR(255) NOT NULL,
+    version INTEGER NOT NULL CHECK (version > 0),
+    schema JSONB NOT NULL,
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (type, version)
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS completed_uploads (
+    upload_key VARCHAR(1024) PRIMARY KEY,
+    file_id VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO completed_uploads (upload_key, file_id, created_at)
+SELECT object_key, MIN(id), MIN(created_at)
+FROM files
+GROUP BY object_key
+ON CONFLICT (upload_key) DO NOTHING;